@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/verbosity"
+)
+
+// runBench измеряет загрузку словаря, память индекса и скорость Lookup на
+// каждом расстоянии редактирования от 0 до --max-distance:
+//
+//	symspell bench --dict en_full.txt --queries queries.txt
+func runBench(args []string) {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	queriesPath := fs.String("queries", "", "файл запросов, по одному слову на строку")
+	rounds := fs.Int("rounds", 3, "сколько раз прогонять запросы на каждом расстоянии")
+	var cfg config
+	cfg.registerFlags(fs)
+	cfg.parse(fs, args)
+	if *queriesPath == "" {
+		log.Fatal("Укажите файл запросов через --queries")
+	}
+	queries, err := readQueries(*queriesPath)
+	if err != nil {
+		log.Fatalf("Ошибка чтения запросов: %v", err)
+	}
+	if len(queries) == 0 {
+		log.Fatal("Файл запросов пуст")
+	}
+
+	heapBefore := heapAlloc()
+	start := time.Now()
+	spellChecker, err := cfg.loadSpellChecker()
+	if err != nil {
+		log.Fatalf("Ошибка при загрузке словаря: %v", err)
+	}
+	loadTime := time.Since(start)
+	heapAfter := heapAlloc()
+	stats := spellChecker.Stats()
+
+	fmt.Printf("Загрузка словаря:  %v\n", loadTime.Round(time.Millisecond))
+	fmt.Printf("Слов:              %d\n", stats.Words)
+	fmt.Printf("Ключей удалений:   %d\n", stats.DeleteKeys)
+	fmt.Printf("Память (оценка):   %.1f МБ\n", megabytes(stats.EstimatedBytes))
+	fmt.Printf("Память (куча):     %.1f МБ\n", megabytes(int(heapAfter)-int(heapBefore)))
+	fmt.Println()
+
+	if err := writeBenchTable(os.Stdout, spellChecker, queries, cfg.maxEditDistance, *rounds); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// writeBenchTable пишет в out строку таблицы для каждого расстояния от 0 до
+// maxEditDistance
+func writeBenchTable(out io.Writer, spellChecker symspell.SymSpell, queries []string, maxEditDistance, rounds int) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Расстояние\tЗапросов/с\tЗадержка\tАллокаций/запрос\tБайт/запрос\t")
+	for distance := 0; distance <= maxEditDistance; distance++ {
+		r := benchLookups(spellChecker, queries, distance, rounds)
+		fmt.Fprintf(w, "%d\t%.0f\t%v\t%.1f\t%.0f\t\n", distance, r.qps(), r.latency(), r.allocsPerOp(), r.bytesPerOp())
+	}
+	return w.Flush()
+}
+
+type benchResult struct {
+	lookups int
+	elapsed time.Duration
+	mallocs uint64
+	bytes   uint64
+}
+
+func (r benchResult) qps() float64 {
+	return float64(r.lookups) / r.elapsed.Seconds()
+}
+
+func (r benchResult) latency() time.Duration {
+	return (r.elapsed / time.Duration(r.lookups)).Round(10 * time.Nanosecond)
+}
+
+func (r benchResult) allocsPerOp() float64 {
+	return float64(r.mallocs) / float64(r.lookups)
+}
+
+func (r benchResult) bytesPerOp() float64 {
+	return float64(r.bytes) / float64(r.lookups)
+}
+
+// benchLookups прогоняет запросы rounds раз после прогревочного прохода.
+// verbosity.Closest не использует кэш Top, так что каждый запрос - полный поиск.
+func benchLookups(spellChecker symspell.SymSpell, queries []string, distance, rounds int) benchResult {
+	for _, query := range queries {
+		spellChecker.Lookup(query, verbosity.Closest, distance)
+	}
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for range rounds {
+		for _, query := range queries {
+			spellChecker.Lookup(query, verbosity.Closest, distance)
+		}
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	return benchResult{
+		lookups: rounds * len(queries),
+		elapsed: elapsed,
+		mallocs: after.Mallocs - before.Mallocs,
+		bytes:   after.TotalAlloc - before.TotalAlloc,
+	}
+}
+
+func readQueries(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var queries []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if query := strings.TrimSpace(scanner.Text()); query != "" {
+			queries = append(queries, query)
+		}
+	}
+	return queries, scanner.Err()
+}
+
+func heapAlloc() uint64 {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.HeapAlloc
+}
+
+func megabytes(n int) float64 {
+	return float64(n) / (1 << 20)
+}
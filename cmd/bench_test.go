@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestBench(t *testing.T) {
+	cfg := newTestConfig(t, testDictionary)
+	spellChecker := loadTestSpellChecker(t, cfg)
+	queriesPath := filepath.Join(t.TempDir(), "queries.txt")
+	if err := os.WriteFile(queriesPath, []byte("helo\n\n  wrld \nhello\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	queries, err := readQueries(queriesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(queries, ",") != "helo,wrld,hello" {
+		t.Fatalf("readQueries = %q", queries)
+	}
+
+	if r := benchLookups(spellChecker, queries, 1, 2); r.lookups != 6 || r.elapsed <= 0 {
+		t.Errorf("benchLookups = %+v, want 6 lookups", r)
+	}
+	var out strings.Builder
+	if err := writeBenchTable(&out, spellChecker, queries, cfg.maxEditDistance, 1); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 1+cfg.maxEditDistance+1 || !strings.Contains(lines[0], "Запросов/с") {
+		t.Fatalf("bench table:\n%s\nwant a header and a row per distance 0..%d", out.String(), cfg.maxEditDistance)
+	}
+	for distance, line := range lines[1:] {
+		if fields := strings.Fields(line); len(fields) != 5 || fields[0] != strconv.Itoa(distance) {
+			t.Errorf("row %d = %q", distance, line)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/options"
+)
+
+// runBuildDict считает частоты слов корпусов и пишет частотный словарь в
+// формате "слово частота", отсортированный по убыванию частоты:
+//
+//	symspell build-dict corpus.txt [corpus2.txt ...] -o freq.txt -min-count 2
+func runBuildDict(args []string) {
+	fs := flag.NewFlagSet("build-dict", flag.ExitOnError)
+	outPath := fs.String("o", "", "выходной файл (по умолчанию stdout)")
+	minCount := fs.Int("min-count", 1, "минимальная частота слова")
+	fs.Parse(args)
+	// флаги могут идти после имен корпусов
+	var corpora []string
+	for fs.NArg() > 0 {
+		corpora = append(corpora, fs.Arg(0))
+		fs.Parse(fs.Args()[1:])
+	}
+	if len(corpora) == 0 {
+		log.Fatal("Укажите хотя бы один файл корпуса")
+	}
+
+	spellChecker, err := countCorpora(corpora, *minCount)
+	if err != nil {
+		log.Fatalf("Ошибка чтения корпуса: %v", err)
+	}
+
+	out := os.Stdout
+	if *outPath != "" {
+		if out, err = os.Create(*outPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+	n, err := writeFrequencies(spellChecker, out)
+	if err == nil && out != os.Stdout {
+		err = out.Close()
+	}
+	if err != nil {
+		log.Fatalf("Ошибка записи словаря: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "Записано слов: %d\n", n)
+}
+
+// countCorpora считает частоты слов корпусов; слова реже minCount не попадают
+// в словарь
+func countCorpora(corpora []string, minCount int) (symspell.SymSpell, error) {
+	// Индекс для подсказок не нужен, поэтому расстояние 0 делает его минимальным
+	spellChecker, err := symspell.New(
+		options.WithMaxDictionaryEditDistance(0),
+		options.WithPrefixLength(1),
+		options.WithCountThreshold(minCount),
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range corpora {
+		if err := addCorpus(spellChecker, path); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return spellChecker, nil
+}
+
+func addCorpus(spellChecker symspell.SymSpell, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = spellChecker.CreateDictionary(file)
+	return err
+}
+
+func writeFrequencies(spellChecker symspell.SymSpell, w io.Writer) (int, error) {
+	type entry struct {
+		term  string
+		count uint64
+	}
+	entries := make([]entry, 0, spellChecker.WordCount())
+	for term, count := range spellChecker.Entries() {
+		entries = append(entries, entry{term, count})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].count != entries[j].count {
+			return entries[i].count > entries[j].count
+		}
+		return entries[i].term < entries[j].term
+	})
+	writer := bufio.NewWriter(w)
+	for _, e := range entries {
+		if _, err := fmt.Fprintf(writer, "%s %d\n", e.term, e.count); err != nil {
+			return 0, err
+		}
+	}
+	return len(entries), writer.Flush()
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildDict(t *testing.T) {
+	dir := t.TempDir()
+	corpora := []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")}
+	if err := os.WriteFile(corpora[0], []byte("The cat sat on the mat.\nThe cat ran.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(corpora[1], []byte("A cat and a dog, the end!\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	spellChecker, err := countCorpora(corpora, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	n, err := writeFrequencies(spellChecker, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// слова, встретившиеся один раз, отброшены -min-count
+	want := "the 4\ncat 3\na 2\n"
+	if out.String() != want || n != 3 {
+		t.Errorf("build-dict wrote %d words:\n%s\nwant:\n%s", n, out.String(), want)
+	}
+
+	if _, err := countCorpora([]string{filepath.Join(dir, "missing.txt")}, 1); err == nil {
+		t.Error("countCorpora accepted a missing corpus")
+	}
+}
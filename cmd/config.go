@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/dictionaries"
+	"symspell/pkg/metrics"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+// config - общие настройки всех режимов. Значения по умолчанию берутся из
+// переменных окружения SYMSPELL_DICT, SYMSPELL_LANG, SYMSPELL_MAX_DISTANCE,
+// SYMSPELL_PREFIX_LENGTH, SYMSPELL_VERBOSITY и SYMSPELL_CONFIG, флаги их
+// переопределяют.
+type config struct {
+	configPath      string
+	dictionaryPath  string
+	lang            string
+	maxEditDistance int
+	prefixLength    int
+	verbosityName   string
+	verbosity       verbosity.Verbosity
+}
+
+func (c *config) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.configPath, "config", os.Getenv("SYMSPELL_CONFIG"), "файл настроек symspell.yaml или .json, переопределяет флаги")
+	fs.StringVar(&c.dictionaryPath, "dict", os.Getenv("SYMSPELL_DICT"), "путь к частотному словарю (по умолчанию <lang>_full.txt)")
+	fs.StringVar(&c.lang, "lang", envString("SYMSPELL_LANG", "en"), "язык словаря по умолчанию")
+	fs.IntVar(&c.maxEditDistance, "max-distance", envInt("SYMSPELL_MAX_DISTANCE", 2), "максимальное расстояние редактирования")
+	fs.IntVar(&c.prefixLength, "prefix-length", envInt("SYMSPELL_PREFIX_LENGTH", 4), "длина префикса индекса")
+	fs.StringVar(&c.verbosityName, "verbosity", envString("SYMSPELL_VERBOSITY", "top"), "top, closest или all")
+}
+
+// parse разбирает флаги и проверяет значения
+func (c *config) parse(fs *flag.FlagSet, args []string) {
+	fs.Parse(args)
+	v, err := parseVerbosity(c.verbosityName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	c.verbosity = v
+	if c.dictionaryPath == "" {
+		c.dictionaryPath = c.lang + "_full.txt"
+	}
+}
+
+// loadSpellChecker создает SymSpell и загружает частотный словарь. Если
+// словари перечислены в файле настроек, -dict не используется.
+func (c *config) loadSpellChecker(opts ...options.Options) (symspell.SymSpell, error) {
+	defaults := []options.Options{
+		options.WithMaxDictionaryEditDistance(c.maxEditDistance),
+		options.WithPrefixLength(c.prefixLength),
+		options.WithCountThreshold(1),
+		options.WithSmartFrequencyCorrection(),
+		options.WithLoadWorkers(runtime.NumCPU()),
+	}
+	if c.configPath != "" {
+		fileOpts, err := options.FromFile(c.configPath)
+		if err != nil {
+			return nil, err
+		}
+		defaults = append(defaults, fileOpts...)
+	}
+	spellChecker, err := symspell.New(append(defaults, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	if spellChecker.WordCount() > 0 {
+		spellChecker.ClearTransformData()
+		return spellChecker, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Загружаем словарь из файла: %s\n", c.dictionaryPath)
+	// Ctrl+C прерывает загрузку большого словаря
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ok, err := spellChecker.LoadDictionaryContext(ctx, c.dictionaryPath, 0, 1, " ", printLoadProgress)
+	if err == nil {
+		fmt.Fprintln(os.Stderr)
+	}
+	if errors.Is(err, symspell.ErrDictionaryNotFound) && slices.Contains(dictionaries.Languages(), c.lang) {
+		// файла нет, но словарь языка встроен в бинарник
+		fmt.Fprintf(os.Stderr, "Файл не найден, используем встроенный словарь %s\n", c.lang)
+		data, _ := dictionaries.Open(c.lang)
+		ok, err = spellChecker.LoadDictionaryStream(data, 0, 1, " ")
+	}
+	if errors.Is(err, symspell.ErrDictionaryNotFound) {
+		return nil, fmt.Errorf("словарь %s не найден, укажите его через -dict или SYMSPELL_DICT", c.dictionaryPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("не удалось загрузить словарь %s", c.dictionaryPath)
+	}
+	spellChecker.ClearTransformData()
+	return spellChecker, nil
+}
+
+// loadDictionaries загружает словарь языка по умолчанию и словари языков из
+// langs (через запятую) и регистрирует их в DictionaryManager. Словарь
+// дополнительного языка читается из <lang>_full.txt или берется встроенный.
+// Метрики каждого словаря регистрируются в reg с меткой lang.
+func (c *config) loadDictionaries(langs string, reg prometheus.Registerer, opts ...options.Options) (*symspell.DictionaryManager, error) {
+	manager := symspell.NewDictionaryManager(c.lang)
+	for _, lang := range c.languages(langs) {
+		cfg := *c
+		if lang != c.lang {
+			cfg.lang = lang
+			cfg.dictionaryPath = lang + "_full.txt"
+		}
+		spellChecker, err := cfg.loadSpellChecker(opts...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", lang, err)
+		}
+		manager.Register(lang, metrics.InstrumentLanguage(spellChecker, lang, reg))
+	}
+	return manager, nil
+}
+
+// languages возвращает язык по умолчанию и языки из langs без повторов
+func (c *config) languages(langs string) []string {
+	result := []string{c.lang}
+	for lang := range strings.SplitSeq(langs, ",") {
+		lang = strings.TrimSpace(lang)
+		if lang != "" && !slices.Contains(result, lang) {
+			result = append(result, lang)
+		}
+	}
+	return result
+}
+
+// printLoadProgress выводит ход загрузки словаря в одну строку stderr
+func printLoadProgress(linesRead, wordsAdded int) {
+	fmt.Fprintf(os.Stderr, "\rПрочитано строк: %d, добавлено слов: %d", linesRead, wordsAdded)
+}
+
+// parseVerbosity разбирает "top", "closest" или "all"; пустая строка означает top.
+func parseVerbosity(name string) (verbosity.Verbosity, error) {
+	switch name {
+	case "", "top":
+		return verbosity.Top, nil
+	case "closest":
+		return verbosity.Closest, nil
+	case "all":
+		return verbosity.All, nil
+	}
+	return verbosity.Top, fmt.Errorf("неизвестный verbosity %q", name)
+}
+
+func envString(name, fallback string) string {
+	if value, ok := os.LookupEnv(name); ok {
+		return value
+	}
+	return fallback
+}
+
+func envInt(name string, fallback int) int {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Некорректное значение %s: %v", name, err)
+	}
+	return n
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"symspell/pkg/verbosity"
+)
+
+func TestConfigFlags(t *testing.T) {
+	dir := t.TempDir()
+	dictPath := filepath.Join(dir, "words.txt")
+	if err := os.WriteFile(dictPath, []byte(testDictionary), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// флаги переопределяют переменные окружения
+	t.Setenv("SYMSPELL_MAX_DISTANCE", "2")
+	t.Setenv("SYMSPELL_VERBOSITY", "all")
+	t.Setenv("SYMSPELL_LANG", "de")
+
+	var cfg config
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg.registerFlags(fs)
+	cfg.parse(fs, []string{"-dict", dictPath, "-max-distance", "1", "-prefix-length", "5"})
+	if cfg.dictionaryPath != dictPath || cfg.maxEditDistance != 1 || cfg.prefixLength != 5 || cfg.verbosity != verbosity.All || cfg.lang != "de" {
+		t.Fatalf("config = %+v", cfg)
+	}
+
+	spellChecker := loadTestSpellChecker(t, cfg)
+	if got := spellChecker.WordCount(); got != 3 {
+		t.Errorf("WordCount() = %d, want 3 from -dict", got)
+	}
+	if got, _ := spellChecker.Lookup("helo", cfg.verbosity, cfg.maxEditDistance); len(got) != 1 || got[0].Term != "hello" {
+		t.Errorf("Lookup(helo) = %v, want hello", got)
+	}
+	if _, err := spellChecker.Lookup("helo", cfg.verbosity, 2); err == nil {
+		t.Error("Lookup accepted a distance above -max-distance")
+	}
+}
+
+func TestConfigDefaultDictionary(t *testing.T) {
+	t.Setenv("SYMSPELL_DICT", "")
+	var cfg config
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg.registerFlags(fs)
+	cfg.parse(fs, []string{"-lang", "ru", "-verbosity", "closest"})
+	if cfg.dictionaryPath != "ru_full.txt" || cfg.verbosity != verbosity.Closest {
+		t.Errorf("config = %+v, want ru_full.txt and closest", cfg)
+	}
+	if _, err := parseVerbosity("best"); err == nil {
+		t.Error("parseVerbosity accepted an unknown verbosity")
+	}
+}
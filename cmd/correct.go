@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/options"
+)
+
+// runCorrect исправляет документ по предложениям через LookupCompound,
+// сохраняя пунктуацию, пробелы и переводы строк.
+func runCorrect(args []string) {
+	fs := flag.NewFlagSet("correct", flag.ExitOnError)
+	inPath := fs.String("in", "", "входной файл (по умолчанию stdin)")
+	outPath := fs.String("out", "", "выходной файл (по умолчанию stdout)")
+	var cfg config
+	cfg.registerFlags(fs)
+	cfg.parse(fs, args)
+
+	spellChecker, err := cfg.loadSpellChecker(options.WithPreserveCase())
+	if err != nil {
+		log.Fatalf("Ошибка при загрузке словаря: %v", err)
+	}
+
+	in := os.Stdin
+	if *inPath != "" {
+		if in, err = os.Open(*inPath); err != nil {
+			log.Fatal(err)
+		}
+		defer in.Close()
+	}
+	out := os.Stdout
+	if *outPath != "" {
+		if out, err = os.Create(*outPath); err != nil {
+			log.Fatal(err)
+		}
+		defer out.Close()
+	}
+
+	changed, total, err := correctDocument(spellChecker, in, out, cfg.maxEditDistance)
+	if err != nil {
+		log.Fatalf("Ошибка при исправлении: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "Исправлено токенов: %d из %d\n", changed, total)
+}
+
+// correctDocument построчно копирует r в w, заменяя только исправленные
+// слова. Возвращает число измененных и всех токенов.
+func correctDocument(spellChecker symspell.SymSpell, r io.Reader, w io.Writer, maxEditDistance int) (changed, total int, err error) {
+	reader := bufio.NewReader(r)
+	writer := bufio.NewWriter(w)
+	for {
+		line, readErr := reader.ReadString('\n')
+		for _, sentence := range splitSentences(line) {
+			corrected, sentenceChanged, sentenceTotal := correctSentence(spellChecker, sentence, maxEditDistance)
+			changed += sentenceChanged
+			total += sentenceTotal
+			if _, err := writer.WriteString(corrected); err != nil {
+				return changed, total, err
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return changed, total, readErr
+		}
+	}
+	return changed, total, writer.Flush()
+}
+
+// correctSentence заменяет исправленные участки предложения по смещениям
+// из LookupCompoundDetailed; текст между словами остается как есть.
+func correctSentence(spellChecker symspell.SymSpell, sentence string, maxEditDistance int) (string, int, int) {
+	result := spellChecker.LookupCompoundDetailed(sentence, maxEditDistance)
+	if result == nil {
+		return sentence, 0, 0
+	}
+	var b strings.Builder
+	changed, last := 0, 0
+	for _, token := range result.Tokens {
+		b.WriteString(sentence[last:token.Start])
+		b.WriteString(token.Replacement)
+		if token.Replacement != token.Original {
+			changed++
+		}
+		last = token.End
+	}
+	b.WriteString(sentence[last:])
+	return b.String(), changed, len(result.Tokens)
+}
+
+// splitSentences режет строку после знаков .!? и следующих за ними пробелов.
+// Склейка частей дает исходную строку.
+func splitSentences(line string) []string {
+	var sentences []string
+	start := 0
+	for i := 0; i < len(line); i++ {
+		if !strings.ContainsRune(".!?", rune(line[i])) {
+			continue
+		}
+		j := i + 1
+		for j < len(line) && strings.ContainsRune(".!?", rune(line[j])) {
+			j++
+		}
+		if j < len(line) && !strings.ContainsRune(" \t\r\n", rune(line[j])) {
+			i = j - 1
+			continue
+		}
+		for j < len(line) && strings.ContainsRune(" \t\r\n", rune(line[j])) {
+			j++
+		}
+		sentences = append(sentences, line[start:j])
+		start = j
+		i = j - 1
+	}
+	if start < len(line) {
+		sentences = append(sentences, line[start:])
+	}
+	return sentences
+}
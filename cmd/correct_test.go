@@ -0,0 +1,39 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"symspell/pkg/options"
+)
+
+func TestCorrectDocument(t *testing.T) {
+	// частоты выше FrequencyThreshold, чтобы точные совпадения не заменялись
+	cfg := newTestConfig(t, "the 9000\nis 8000\nhello 7000\nworld 6000\nnew 5000\nline 4000\nword 3000\n")
+	spellChecker, err := cfg.loadSpellChecker(options.WithPreserveCase())
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := "Helo wrld! The world is new.\n\nA new lnie, helo?\n"
+	var out strings.Builder
+	changed, total, err := correctDocument(spellChecker, strings.NewReader(in), &out, cfg.maxEditDistance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Hello world! The world is new.\n\nA new line, hello?\n"
+	if out.String() != want {
+		t.Errorf("correctDocument(%q) = %q, want %q", in, out.String(), want)
+	}
+	if changed != 4 || total != 10 {
+		t.Errorf("correctDocument changed %d of %d tokens, want 4 of 10", changed, total)
+	}
+}
+
+func TestSplitSentences(t *testing.T) {
+	line := "Hi there... Is it 3.14? Yes!\n"
+	got := splitSentences(line)
+	want := []string{"Hi there... ", "Is it 3.14? ", "Yes!\n"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("splitSentences(%q) = %q, want %q", line, got, want)
+	}
+}
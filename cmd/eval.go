@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/verbosity"
+)
+
+// runEval оценивает качество исправлений на размеченных парах
+// "опечатка<TAB>правильное слово":
+//
+//	symspell eval --pairs typos.tsv -k 5
+func runEval(args []string) {
+	fs := flag.NewFlagSet("eval", flag.ExitOnError)
+	pairsPath := fs.String("pairs", "", "TSV-файл с парами опечатка/правильное слово")
+	k := fs.Int("k", 5, "число вариантов для accuracy@k")
+	var cfg config
+	cfg.registerFlags(fs)
+	cfg.parse(fs, args)
+	if *pairsPath == "" {
+		log.Fatal("Укажите файл с парами через --pairs")
+	}
+	pairs, err := readPairs(*pairsPath)
+	if err != nil {
+		log.Fatalf("Ошибка чтения пар: %v", err)
+	}
+
+	spellChecker, err := cfg.loadSpellChecker()
+	if err != nil {
+		log.Fatalf("Ошибка при загрузке словаря: %v", err)
+	}
+	result := evaluate(spellChecker, pairs, *k, cfg.maxEditDistance)
+
+	fmt.Printf("Пар:           %d\n", result.pairs)
+	fmt.Printf("accuracy@1:    %.4f\n", result.accuracy(result.hitsAt1))
+	fmt.Printf("%-15s%.4f\n", fmt.Sprintf("accuracy@%d:", *k), result.accuracy(result.hitsAtK))
+	fmt.Printf("Задержка:      %v в среднем\n", result.averageLatency())
+	fmt.Printf("Пропускная:    %.0f запросов/с\n", result.throughput())
+}
+
+type evalPair struct {
+	misspelling string
+	expected    string
+}
+
+type evalResult struct {
+	pairs   int
+	hitsAt1 int
+	hitsAtK int
+	elapsed time.Duration // только время Top-запросов
+}
+
+func (r evalResult) accuracy(hits int) float64 {
+	if r.pairs == 0 {
+		return 0
+	}
+	return float64(hits) / float64(r.pairs)
+}
+
+func (r evalResult) averageLatency() time.Duration {
+	if r.pairs == 0 {
+		return 0
+	}
+	return r.elapsed / time.Duration(r.pairs)
+}
+
+func (r evalResult) throughput() float64 {
+	if r.elapsed == 0 {
+		return 0
+	}
+	return float64(r.pairs) / r.elapsed.Seconds()
+}
+
+func readPairs(path string) ([]evalPair, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var pairs []evalPair
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		parts := strings.Split(text, "\t")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("строка %d: ожидается \"опечатка<TAB>слово\", получено %q", line, text)
+		}
+		pairs = append(pairs, evalPair{misspelling: parts[0], expected: parts[1]})
+	}
+	return pairs, scanner.Err()
+}
+
+// evaluate измеряет accuracy@1 и задержку по Top-запросам, а accuracy@k -
+// по первым k вариантам verbosity.All.
+func evaluate(spellChecker symspell.SymSpell, pairs []evalPair, k, maxEditDistance int) evalResult {
+	result := evalResult{pairs: len(pairs)}
+	for _, pair := range pairs {
+		start := time.Now()
+		top, err := spellChecker.Lookup(pair.misspelling, verbosity.Top, maxEditDistance)
+		result.elapsed += time.Since(start)
+		if err != nil {
+			log.Printf("Ошибка при поиске исправлений для '%s': %v", pair.misspelling, err)
+			continue
+		}
+		if len(top) > 0 && top[0].Term == pair.expected {
+			result.hitsAt1++
+		}
+
+		all, _ := spellChecker.Lookup(pair.misspelling, verbosity.All, maxEditDistance)
+		for i := 0; i < len(all) && i < k; i++ {
+			if all[i].Term == pair.expected {
+				result.hitsAtK++
+				break
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	cfg := newTestConfig(t, "hello 7000\nworld 6000\nword 3000\n")
+	spellChecker := loadTestSpellChecker(t, cfg)
+	pairsPath := filepath.Join(t.TempDir(), "typos.tsv")
+	// worl ближе к world, так что word находится только среди k вариантов
+	tsv := "# опечатка\tслово\nhelo\thello\nwrld\tworld\n\nworl\tword\nxyzzy\thello\n"
+	if err := os.WriteFile(pairsPath, []byte(tsv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pairs, err := readPairs(pairsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := evaluate(spellChecker, pairs, 5, cfg.maxEditDistance)
+	if result.pairs != 4 || result.hitsAt1 != 2 || result.hitsAtK != 3 {
+		t.Errorf("evaluate = %+v, want 4 pairs, 2 hits at 1 and 3 at k", result)
+	}
+	if got := result.accuracy(result.hitsAt1); got != 0.5 {
+		t.Errorf("accuracy@1 = %v, want 0.5", got)
+	}
+	if result.averageLatency() <= 0 || result.throughput() <= 0 {
+		t.Errorf("latency %v, throughput %v", result.averageLatency(), result.throughput())
+	}
+
+	if err := os.WriteFile(pairsPath, []byte("helo hello\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readPairs(pairsPath); err == nil {
+		t.Error("readPairs accepted a line without a tab")
+	}
+}
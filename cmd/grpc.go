@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+
+	"symspell/pkg/grpcserver"
+	"symspell/pkg/grpcserver/symspellpb"
+	"symspell/pkg/options"
+)
+
+// runGRPC запускает gRPC-сервис SymSpellService (pkg/grpcserver/symspellpb/symspell.proto)
+// и отдает метрики Prometheus по HTTP на --metrics-port. Запрос без lang
+// обслуживает словарь языка -lang, кроме него загружаются словари из -langs.
+func runGRPC(args []string) {
+	fs := flag.NewFlagSet("grpc", flag.ExitOnError)
+	port := fs.Int("port", 9090, "порт gRPC-сервера")
+	metricsPort := fs.Int("metrics-port", 9091, "порт HTTP для /metrics, 0 - не отдавать метрики")
+	langs := fs.String("langs", os.Getenv("SYMSPELL_LANGS"), "дополнительные языки через запятую")
+	var cfg config
+	cfg.registerFlags(fs)
+	cfg.parse(fs, args)
+
+	dictionaries, err := cfg.loadDictionaries(*langs, prometheus.DefaultRegisterer, options.WithThreadSafe())
+	if err != nil {
+		log.Fatalf("Ошибка при загрузке словаря: %v", err)
+	}
+	if *metricsPort != 0 {
+		go serveMetrics(*metricsPort)
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", *port))
+	if err != nil {
+		log.Fatal(err)
+	}
+	server := grpc.NewServer()
+	symspellpb.RegisterSymSpellServiceServer(server, grpcserver.NewMultilingual(dictionaries, cfg.maxEditDistance))
+	log.Printf("gRPC-сервер слушает %s", listener.Addr())
+	if err := server.Serve(listener); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func serveMetrics(port int) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", promhttp.Handler())
+	addr := fmt.Sprintf(":%d", port)
+	log.Printf("Метрики доступны на %s/metrics", addr)
+	if err := newHTTPServer(addr, mux).ListenAndServe(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -2,48 +2,73 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"strings"
 
 	symspell "symspell/pkg"
-	"symspell/pkg/options"
 	"symspell/pkg/verbosity"
 )
 
 func main() {
-	// Настройки SymSpell
-	maxEditDistance := 2
-
-	spellChecker := symspell.NewSymSpell(
-		options.WithMaxDictionaryEditDistance(maxEditDistance),
-		options.WithPrefixLength(4),
-		options.WithCountThreshold(1),
-		options.WithSmartFrequencyCorrection(),
-	)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "serve":
+			runServe(os.Args[2:])
+			return
+		case "grpc":
+			runGRPC(os.Args[2:])
+			return
+		case "correct":
+			runCorrect(os.Args[2:])
+			return
+		case "build-dict":
+			runBuildDict(os.Args[2:])
+			return
+		case "eval":
+			runEval(os.Args[2:])
+			return
+		case "bench":
+			runBench(os.Args[2:])
+			return
+		}
+	}
 
-	dictionaryPath := "en_full.txt"
-	fmt.Printf("Загружаем словарь из файла: %s\n", dictionaryPath)
+	var cfg config
+	cfg.registerFlags(flag.CommandLine)
+	jsonOutput := flag.Bool("json", false, "выводить результаты в JSON, по одному объекту на строку")
+	userDictPath := flag.String("user-dict", "", "пользовательский словарь: загружается при старте, в него сохраняет :save")
+	cfg.parse(flag.CommandLine, os.Args[1:])
+	maxEditDistance := cfg.maxEditDistance
 
-	ok, err := spellChecker.LoadDictionary(dictionaryPath, 0, 1, " ")
+	spellChecker, err := cfg.loadSpellChecker()
 	if err != nil {
 		log.Fatalf("Ошибка при загрузке словаря: %v", err)
 	}
-	if !ok {
-		log.Fatal("Не удалось загрузить словарь")
+	session := &replSession{spellChecker: spellChecker, userDictPath: *userDictPath}
+	if err := session.loadUserDictionary(); err != nil {
+		log.Fatalf("Ошибка при загрузке пользовательского словаря: %v", err)
 	}
-	spellChecker.ClearTransformData()
 
-	fmt.Println("Словарь успешно загружен!")
-	fmt.Println("Введите слова для проверки (каждое слово или фразу на отдельной строке).")
-	fmt.Println("Для выхода введите 'quit' или нажмите Ctrl+C")
-	fmt.Println()
+	if !*jsonOutput {
+		fmt.Println("Словарь успешно загружен!")
+		fmt.Println("Введите слова для проверки (каждое слово или фразу на отдельной строке).")
+		fmt.Println("Команды редактирования словаря начинаются с ':', см. :help")
+		fmt.Println("Для выхода введите 'quit' или нажмите Ctrl+C")
+		fmt.Println()
+	}
 
 	scanner := bufio.NewScanner(os.Stdin)
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
 
 	for {
-		fmt.Print("Введите слова: ")
+		if !*jsonOutput {
+			fmt.Print("Введите слова: ")
+		}
 		if !scanner.Scan() {
 			break
 		}
@@ -55,9 +80,27 @@ func main() {
 		if strings.ToLower(input) == "quit" {
 			break
 		}
+		if strings.HasPrefix(input, ":") {
+			message, err := session.execute(input)
+			if err != nil {
+				message = "Ошибка: " + err.Error()
+			}
+			if *jsonOutput {
+				fmt.Fprintln(os.Stderr, message)
+			} else {
+				fmt.Println(message)
+			}
+			continue
+		}
 
-		correctedWords := correctWords(spellChecker, input, maxEditDistance)
+		correctedWords := correctWords(spellChecker, input, cfg.verbosity, maxEditDistance)
 
+		if *jsonOutput {
+			if err := encoder.Encode(newCorrectionJSON(spellChecker, input, correctedWords, cfg.verbosity, maxEditDistance)); err != nil {
+				log.Fatalf("Ошибка записи результата: %v", err)
+			}
+			continue
+		}
 		fmt.Printf("Исходный текст: %s\n", input)
 		fmt.Printf("Исправленный:   %s\n", correctedWords)
 		fmt.Println("---")
@@ -68,8 +111,37 @@ func main() {
 	}
 }
 
+// correctionJSON - результат проверки одной строки в режиме --json
+type correctionJSON struct {
+	Original  string     `json:"original"`
+	Corrected string     `json:"corrected"`
+	Words     []wordJSON `json:"words"`
+}
+
+type wordJSON struct {
+	Word        string           `json:"word"`
+	Suggestions []suggestionJSON `json:"suggestions"`
+}
+
+// newCorrectionJSON собирает результат вместе с вариантами для каждого слова
+func newCorrectionJSON(spellChecker symspell.SymSpell, input, corrected string, v verbosity.Verbosity, maxEditDistance int) correctionJSON {
+	result := correctionJSON{Original: input, Corrected: corrected, Words: []wordJSON{}}
+	for _, word := range strings.Fields(input) {
+		suggestions, err := spellChecker.Lookup(word, v, maxEditDistance)
+		if err != nil {
+			log.Printf("Ошибка при поиске исправлений для '%s': %v", word, err)
+		}
+		entry := wordJSON{Word: word, Suggestions: make([]suggestionJSON, len(suggestions))}
+		for i, suggestion := range suggestions {
+			entry.Suggestions[i] = toSuggestionJSON(suggestion)
+		}
+		result.Words = append(result.Words, entry)
+	}
+	return result
+}
+
 // correctWords исправляет слова в строке
-func correctWords(spellChecker symspell.SymSpell, input string, maxEditDistance int) string {
+func correctWords(spellChecker symspell.SymSpell, input string, v verbosity.Verbosity, maxEditDistance int) string {
 	// Сначала пытаемся исправить всю фразу как составное слово
 	if strings.Contains(input, " ") {
 		compoundResult := spellChecker.LookupCompound(input, maxEditDistance)
@@ -83,7 +155,7 @@ func correctWords(spellChecker symspell.SymSpell, input string, maxEditDistance
 	correctedWords := make([]string, 0, len(words))
 
 	for _, word := range words {
-		correctedWord := correctSingleWord(spellChecker, word, maxEditDistance)
+		correctedWord := correctSingleWord(spellChecker, word, v, maxEditDistance)
 		correctedWords = append(correctedWords, correctedWord)
 	}
 
@@ -91,9 +163,9 @@ func correctWords(spellChecker symspell.SymSpell, input string, maxEditDistance
 }
 
 // correctSingleWord исправляет одно слово
-func correctSingleWord(spellChecker symspell.SymSpell, word string, maxEditDistance int) string {
-	// Используем verbosity.Top для получения лучшего варианта
-	suggestions, err := spellChecker.Lookup(word, verbosity.Top, maxEditDistance)
+func correctSingleWord(spellChecker symspell.SymSpell, word string, v verbosity.Verbosity, maxEditDistance int) string {
+	// Лучший вариант всегда первый при любом verbosity
+	suggestions, err := spellChecker.Lookup(word, v, maxEditDistance)
 	if err != nil {
 		log.Printf("Ошибка при поиске исправлений для '%s': %v", word, err)
 		return word
@@ -114,55 +186,3 @@ func correctSingleWord(spellChecker symspell.SymSpell, word string, maxEditDista
 
 	return bestSuggestion.Term
 }
-
-// createSampleDictionary создает пример файла словаря
-func createSampleDictionary() {
-	fmt.Println("Создание примера словаря...")
-
-	sampleWords := []string{
-		"привет 1000",
-		"мир 800",
-		"программирование 500",
-		"компьютер 750",
-		"код 600",
-		"разработка 450",
-		"алгоритм 300",
-		"данные 900",
-		"система 850",
-		"проект 400",
-		"функция 350",
-		"переменная 250",
-		"массив 200",
-		"строка 300",
-		"число 400",
-		"файл 500",
-		"папка 150",
-		"документ 300",
-		"текст 450",
-		"слово 600",
-	}
-
-	file, err := os.Create("dictionary.txt")
-	if err != nil {
-		log.Printf("Ошибка создания файла словаря: %v", err)
-		return
-	}
-	defer file.Close()
-
-	writer := bufio.NewWriter(file)
-	for _, word := range sampleWords {
-		writer.WriteString(word + "\n")
-	}
-	writer.Flush()
-
-	fmt.Println("Пример словаря создан в файле dictionary.txt")
-}
-
-func init() {
-	// Проверяем, существует ли файл словаря
-	if _, err := os.Stat("dictionary.txt"); os.IsNotExist(err) {
-		fmt.Println("Файл словаря не найден. Создаем пример...")
-		createSampleDictionary()
-		fmt.Println()
-	}
-}
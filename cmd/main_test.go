@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/verbosity"
+)
+
+// newTestConfig пишет частотный словарь во временный файл и возвращает
+// настройки, указывающие на него
+func newTestConfig(t *testing.T, dictionary string) config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "en_full.txt")
+	if err := os.WriteFile(path, []byte(dictionary), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return config{dictionaryPath: path, lang: "en", maxEditDistance: 2, prefixLength: 7, verbosity: verbosity.Top}
+}
+
+func loadTestSpellChecker(t *testing.T, cfg config) symspell.SymSpell {
+	t.Helper()
+	spellChecker, err := cfg.loadSpellChecker()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spellChecker
+}
+
+const testDictionary = "hello 1000\nworld 800\nword 300\n"
+
+func TestCorrectionJSON(t *testing.T) {
+	cfg := newTestConfig(t, testDictionary)
+	spellChecker := loadTestSpellChecker(t, cfg)
+	input := "helo wrld"
+	corrected := correctWords(spellChecker, input, cfg.verbosity, cfg.maxEditDistance)
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(newCorrectionJSON(spellChecker, input, corrected, verbosity.Closest, cfg.maxEditDistance)); err != nil {
+		t.Fatal(err)
+	}
+	var got correctionJSON
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("decoding %s: %v", buf.Bytes(), err)
+	}
+	if got.Original != input || got.Corrected != "hello world" || len(got.Words) != 2 {
+		t.Fatalf("--json output = %s", buf.Bytes())
+	}
+	if w := got.Words[0]; w.Word != "helo" || len(w.Suggestions) != 1 || w.Suggestions[0].Term != "hello" || w.Suggestions[0].Distance != 1 || w.Suggestions[0].Count != 1000 {
+		t.Errorf("suggestions for helo = %+v", w)
+	}
+	// word на расстоянии 2 от wrld, ближе только world
+	if w := got.Words[1]; w.Word != "wrld" || len(w.Suggestions) != 1 || w.Suggestions[0].Term != "world" {
+		t.Errorf("suggestions for wrld = %+v", w)
+	}
+}
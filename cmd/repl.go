@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	symspell "symspell/pkg"
+)
+
+const replHelp = `Команды:
+  :add слово [частота]  добавить слово в пользовательский словарь
+  :forget слово         удалить слово из пользовательского словаря
+  :freq слово           показать частоту слова
+  :save [файл]          сохранить пользовательский словарь
+  :help                 эта справка`
+
+// replSession - изменяемый пользовательский словарь поверх основного
+type replSession struct {
+	spellChecker symspell.SymSpell
+	userDictPath string
+}
+
+// loadUserDictionary загружает пользовательский словарь, если файл существует
+func (r *replSession) loadUserDictionary() error {
+	if r.userDictPath == "" {
+		return nil
+	}
+	file, err := os.Open(r.userDictPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return r.spellChecker.LoadUserDictionary(file)
+}
+
+// execute выполняет команду вида ":add слово 500" и возвращает сообщение
+func (r *replSession) execute(line string) (string, error) {
+	fields := strings.Fields(strings.TrimPrefix(line, ":"))
+	if len(fields) == 0 {
+		return replHelp, nil
+	}
+	command, args := fields[0], fields[1:]
+	switch command {
+	case "add":
+		if len(args) < 1 || len(args) > 2 {
+			return "", errors.New("использование: :add слово [частота]")
+		}
+		count := uint64(1)
+		if len(args) == 2 {
+			var err error
+			if count, err = strconv.ParseUint(args[1], 10, 64); err != nil {
+				return "", fmt.Errorf("некорректная частота %q", args[1])
+			}
+		}
+		word := strings.ToLower(args[0])
+		if _, err := r.spellChecker.AddUserWord(word, count); err != nil {
+			return "", err
+		}
+		frequency, _ := r.spellChecker.WordFrequency(word)
+		return fmt.Sprintf("Добавлено: %s (частота %d)", word, frequency), nil
+	case "forget":
+		if len(args) != 1 {
+			return "", errors.New("использование: :forget слово")
+		}
+		word := strings.ToLower(args[0])
+		if !r.spellChecker.RemoveUserWord(word) {
+			return "", fmt.Errorf("слова %s нет в пользовательском словаре", word)
+		}
+		return "Удалено: " + word, nil
+	case "freq":
+		if len(args) != 1 {
+			return "", errors.New("использование: :freq слово")
+		}
+		word := strings.ToLower(args[0])
+		frequency, ok := r.spellChecker.WordFrequency(word)
+		if !ok {
+			return fmt.Sprintf("%s: нет в словаре", word), nil
+		}
+		if r.spellChecker.IsUserWord(word) {
+			return fmt.Sprintf("%s: %d (пользовательский словарь)", word, frequency), nil
+		}
+		return fmt.Sprintf("%s: %d", word, frequency), nil
+	case "save":
+		path := r.userDictPath
+		if len(args) == 1 {
+			path = args[0]
+		}
+		if path == "" || len(args) > 1 {
+			return "", errors.New("использование: :save файл")
+		}
+		if err := r.save(path); err != nil {
+			return "", err
+		}
+		r.userDictPath = path
+		return "Сохранено в " + path, nil
+	case "help":
+		return replHelp, nil
+	}
+	return "", fmt.Errorf("неизвестная команда :%s, см. :help", command)
+}
+
+func (r *replSession) save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := r.spellChecker.SaveUserDictionary(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
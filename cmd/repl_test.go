@@ -0,0 +1,47 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReplSession(t *testing.T) {
+	cfg := newTestConfig(t, testDictionary)
+	userDictPath := filepath.Join(t.TempDir(), "user.dict")
+	session := &replSession{spellChecker: loadTestSpellChecker(t, cfg)}
+
+	for _, tc := range []struct {
+		line string
+		want string
+	}{
+		{":add Gopher 500", "Добавлено: gopher (частота 500)"},
+		{":freq gopher", "gopher: 500 (пользовательский словарь)"},
+		{":freq hello", "hello: 1000"},
+		{":save " + userDictPath, "Сохранено в " + userDictPath},
+		{":forget gopher", "Удалено: gopher"},
+		{":freq gopher", "gopher: нет в словаре"},
+	} {
+		got, err := session.execute(tc.line)
+		if err != nil || got != tc.want {
+			t.Errorf("execute(%q) = %q, %v, want %q", tc.line, got, err, tc.want)
+		}
+	}
+	for _, line := range []string{":add", ":add word many", ":forget gopher", ":unknown"} {
+		if _, err := session.execute(line); err == nil {
+			t.Errorf("execute(%q) succeeded", line)
+		}
+	}
+	if got, _ := session.execute(":help"); !strings.Contains(got, ":save") {
+		t.Errorf(":help = %q", got)
+	}
+
+	// сохраненный словарь загружается в новую сессию
+	reloaded := &replSession{spellChecker: loadTestSpellChecker(t, cfg), userDictPath: userDictPath}
+	if err := reloaded.loadUserDictionary(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := reloaded.execute(":freq gopher"); got != "gopher: 500 (пользовательский словарь)" {
+		t.Errorf("after reload :freq gopher = %q", got)
+	}
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+// runServe запускает HTTP-сервер с JSON API:
+//
+//	POST /lookup   {"term": "helo", "max_edit_distance": 2, "verbosity": "top", "lang": "en"}
+//	POST /compound {"text": "helo wrld", "max_edit_distance": 2, "lang": "en"}
+//	POST /segment  {"text": "helloworld", "max_edit_distance": 2, "max_segmentation_word_length": 0, "lang": "en"}
+//	GET  /health
+//	GET  /metrics  метрики Prometheus с меткой lang
+//
+// Запрос без lang обслуживает словарь языка -lang, кроме него загружаются
+// словари языков из -langs.
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	port := fs.Int("port", 8080, "порт HTTP-сервера")
+	langs := fs.String("langs", os.Getenv("SYMSPELL_LANGS"), "дополнительные языки через запятую")
+	var cfg config
+	cfg.registerFlags(fs)
+	cfg.parse(fs, args)
+
+	dictionaries, err := cfg.loadDictionaries(*langs, prometheus.DefaultRegisterer, options.WithThreadSafe())
+	if err != nil {
+		log.Fatalf("Ошибка при загрузке словаря: %v", err)
+	}
+
+	addr := fmt.Sprintf(":%d", *port)
+	log.Printf("Сервер слушает %s, языки: %s", addr, strings.Join(dictionaries.Languages(), ", "))
+	if err := newHTTPServer(addr, newServer(dictionaries, cfg.verbosity, cfg.maxEditDistance)).ListenAndServe(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Ограничения HTTP-сервера: медленные клиенты не удерживают соединения, а
+// большие тела запросов не читаются в память
+const (
+	maxRequestBytes   = 1 << 20
+	readHeaderTimeout = 5 * time.Second
+	readTimeout       = 10 * time.Second
+	writeTimeout      = 30 * time.Second
+	idleTimeout       = 2 * time.Minute
+)
+
+func newHTTPServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+	}
+}
+
+type server struct {
+	dictionaries    *symspell.DictionaryManager
+	verbosity       verbosity.Verbosity
+	maxEditDistance int
+}
+
+func newServer(dictionaries *symspell.DictionaryManager, v verbosity.Verbosity, maxEditDistance int) http.Handler {
+	s := &server{dictionaries: dictionaries, verbosity: v, maxEditDistance: maxEditDistance}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /lookup", s.handleLookup)
+	mux.HandleFunc("POST /compound", s.handleCompound)
+	mux.HandleFunc("POST /segment", s.handleSegment)
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.Handle("GET /metrics", promhttp.Handler())
+	return mux
+}
+
+type lookupRequest struct {
+	Term            string `json:"term"`
+	MaxEditDistance *int   `json:"max_edit_distance"`
+	Verbosity       string `json:"verbosity"`
+	Lang            string `json:"lang"`
+}
+
+type textRequest struct {
+	Text                      string `json:"text"`
+	MaxEditDistance           *int   `json:"max_edit_distance"`
+	MaxSegmentationWordLength int    `json:"max_segmentation_word_length"`
+	Lang                      string `json:"lang"`
+}
+
+type suggestionJSON struct {
+	Term     string `json:"term"`
+	Distance int    `json:"distance"`
+	Count    int    `json:"count"`
+}
+
+type tokenJSON struct {
+	Original    string `json:"original"`
+	Replacement string `json:"replacement"`
+	Start       int    `json:"start"`
+	End         int    `json:"end"`
+	Distance    int    `json:"distance"`
+}
+
+func (s *server) handleLookup(w http.ResponseWriter, r *http.Request) {
+	var req lookupRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	v := s.verbosity
+	if req.Verbosity != "" {
+		var err error
+		if v, err = parseVerbosity(req.Verbosity); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	spellChecker, ok := s.dictionary(w, req.Lang)
+	if !ok {
+		return
+	}
+	ctx, cancel := requestContext(r)
+	defer cancel()
+	suggestions, err := spellChecker.LookupContext(ctx, req.Term, v, s.distance(req.MaxEditDistance))
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	result := make([]suggestionJSON, len(suggestions))
+	for i, suggestion := range suggestions {
+		result[i] = toSuggestionJSON(suggestion)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"suggestions": result})
+}
+
+func (s *server) handleCompound(w http.ResponseWriter, r *http.Request) {
+	var req textRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	spellChecker, ok := s.dictionary(w, req.Lang)
+	if !ok {
+		return
+	}
+	ctx, cancel := requestContext(r)
+	defer cancel()
+	result, err := spellChecker.LookupCompoundContext(ctx, req.Text, s.distance(req.MaxEditDistance))
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	if result == nil {
+		writeError(w, http.StatusBadRequest, errors.New("некорректный текст"))
+		return
+	}
+	tokens := make([]tokenJSON, len(result.Tokens))
+	for i, token := range result.Tokens {
+		tokens[i] = tokenJSON(token)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"suggestion": toSuggestionJSON(result.Suggestion),
+		"tokens":     tokens,
+	})
+}
+
+func (s *server) handleSegment(w http.ResponseWriter, r *http.Request) {
+	var req textRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	spellChecker, ok := s.dictionary(w, req.Lang)
+	if !ok {
+		return
+	}
+	ctx, cancel := requestContext(r)
+	defer cancel()
+	composition, err := spellChecker.WordSegmentationContext(ctx, req.Text, s.distance(req.MaxEditDistance), req.MaxSegmentationWordLength)
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"segmented":    composition.SegmentedString,
+		"corrected":    composition.CorrectedString,
+		"distance_sum": composition.DistanceSum,
+		"log_prob_sum": composition.LogProbSum,
+	})
+}
+
+// dictionary возвращает словарь языка lang или отвечает 404
+func (s *server) dictionary(w http.ResponseWriter, lang string) (symspell.SymSpell, bool) {
+	spellChecker, err := s.dictionaries.Get(lang)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return nil, false
+	}
+	return spellChecker, true
+}
+
+func (s *server) distance(requested *int) int {
+	if requested == nil {
+		return s.maxEditDistance
+	}
+	return *requested
+}
+
+// requestContext отменяется, когда клиент отключился или истек writeTimeout:
+// после него ответ все равно не будет записан
+func requestContext(r *http.Request) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(r.Context(), writeTimeout)
+}
+
+// statusClientClosedRequest - код nginx для запроса, брошенного клиентом
+const statusClientClosedRequest = 499
+
+// errorStatus отличает прерванный запрос от некорректного: поиск падает
+// только на плохих входных данных или по контексту
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		return statusClientClosedRequest
+	}
+	return http.StatusBadRequest
+}
+
+func toSuggestionJSON(item items.SuggestItem) suggestionJSON {
+	return suggestionJSON{Term: item.Term, Distance: item.Distance, Count: item.Count}
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBytes)
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		writeError(w, status, err)
+		return false
+	}
+	return true
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Ошибка записи ответа: %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/verbosity"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	dictionaries := symspell.NewDictionaryManager("en")
+	for lang, words := range map[string][]string{"en": {"hello", "world"}, "de": {"hallo", "welt"}} {
+		spellChecker, err := symspell.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, word := range words {
+			spellChecker.CreateDictionaryEntry(word, 100)
+		}
+		dictionaries.Register(lang, spellChecker)
+	}
+	server := httptest.NewServer(newServer(dictionaries, verbosity.Top, 2))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func post(t *testing.T, url, body string, v any) int {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode
+}
+
+func TestServeLookup(t *testing.T) {
+	server := newTestServer(t)
+	for _, tc := range []struct {
+		body string
+		want string
+	}{
+		{`{"term": "helo"}`, "hello"},
+		{`{"term": "halo", "lang": "de"}`, "hallo"},
+	} {
+		var resp struct {
+			Suggestions []suggestionJSON `json:"suggestions"`
+		}
+		if status := post(t, server.URL+"/lookup", tc.body, &resp); status != http.StatusOK {
+			t.Fatalf("POST /lookup %s: status %d", tc.body, status)
+		}
+		if len(resp.Suggestions) != 1 || resp.Suggestions[0].Term != tc.want {
+			t.Errorf("POST /lookup %s = %v, want %s", tc.body, resp.Suggestions, tc.want)
+		}
+	}
+
+	var resp struct {
+		Error string `json:"error"`
+	}
+	if status := post(t, server.URL+"/lookup", `{"term": "helo", "lang": "fr"}`, &resp); status != http.StatusNotFound {
+		t.Errorf("POST /lookup for an unknown language: status %d, want 404", status)
+	}
+}
+
+func TestServeCompound(t *testing.T) {
+	server := newTestServer(t)
+	var resp struct {
+		Suggestion suggestionJSON `json:"suggestion"`
+		Tokens     []tokenJSON    `json:"tokens"`
+	}
+	if status := post(t, server.URL+"/compound", `{"text": "helo wrld"}`, &resp); status != http.StatusOK {
+		t.Fatalf("POST /compound: status %d", status)
+	}
+	if resp.Suggestion.Term != "hello world" || len(resp.Tokens) != 2 {
+		t.Errorf("POST /compound = %+v", resp)
+	}
+}
+
+func TestServeLimitsRequestBody(t *testing.T) {
+	server := newTestServer(t)
+	body := `{"text": "` + strings.Repeat("a", maxRequestBytes) + `"}`
+	var resp struct {
+		Error string `json:"error"`
+	}
+	if status := post(t, server.URL+"/segment", body, &resp); status != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized POST /segment: status %d, want 413", status)
+	}
+}
+
+func TestHTTPServerTimeouts(t *testing.T) {
+	s := newHTTPServer(":0", http.NotFoundHandler())
+	if s.ReadHeaderTimeout <= 0 || s.ReadTimeout <= 0 || s.WriteTimeout <= 0 || s.IdleTimeout <= 0 {
+		t.Errorf("server without timeouts: %+v", s)
+	}
+}
+
+func TestServeCancelledRequest(t *testing.T) {
+	dictionaries := symspell.NewDictionaryManager("en")
+	spellChecker, err := symspell.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	spellChecker.CreateDictionaryEntry("hello", 100)
+	dictionaries.Register("en", spellChecker)
+	handler := newServer(dictionaries, verbosity.Top, 2)
+
+	for _, tc := range []struct {
+		path, body string
+		cause      error
+		want       int
+	}{
+		{"/lookup", `{"term": "helo"}`, context.Canceled, statusClientClosedRequest},
+		{"/compound", `{"text": "helo wrld"}`, context.Canceled, statusClientClosedRequest},
+		{"/segment", `{"text": "helloworld"}`, context.DeadlineExceeded, http.StatusGatewayTimeout},
+	} {
+		ctx, cancel := context.WithCancel(context.Background())
+		if tc.cause == context.DeadlineExceeded {
+			ctx, cancel = context.WithDeadline(context.Background(), time.Now())
+		}
+		cancel()
+		req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body)).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("POST %s after %v: status %d, want %d", tc.path, tc.cause, rec.Code, tc.want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"context"
+
+	"symspell/pkg/items"
+	verbositypkg "symspell/pkg/verbosity"
+)
+
+// Annotate returns standoff annotations for the tokens of text that
+// LookupCompoundDetailed would change, leaving text itself untouched. Tokens
+// and offsets are those of LookupCompoundDetailed: byte offsets into text as
+// passed in, before InvalidUTF8Sanitize and Unicode normalization. Words
+// shorter than MinimumCharToChange are left alone, as by Lookup.
+//
+// Confidence is the share of the chosen replacement's count among all
+// suggestions at the same distance, scaled down linearly with the distance.
+// Exact-transform replacements have confidence 1.
+func (s *SymSpell) Annotate(text string, maxEditDistance int) ([]items.Annotation, error) {
+	result, err := s.LookupCompoundContext(context.Background(), text, maxEditDistance)
+	if err != nil {
+		return nil, err
+	}
+	annotations := make([]items.Annotation, 0)
+	cm := s.caseMapping()
+	for _, token := range result.Tokens {
+		// the original as it was looked up, sanitized and normalized
+		term, _ := s.checkUTF8(token.Original)
+		term = cm.lower(term)
+		if runeLen(term) < s.MinimumCharToChange {
+			continue
+		}
+		annotation := items.Annotation{
+			Start:       token.Start,
+			End:         token.End,
+			Original:    token.Original,
+			Replacement: token.Replacement,
+			Distance:    token.Distance,
+		}
+		if exact, found := s.ExactTransform[term]; found && exact != term {
+			annotation.Replacement = exact
+			if s.PreserveCase {
+				annotation.Replacement = cm.transferCasing(token.Original, exact)
+			}
+			annotation.Distance = s.distanceComparer.Distance(term, exact)
+			annotation.Confidence = 1
+		} else if replacement := cm.lower(token.Replacement); replacement != term {
+			annotation.Confidence = s.tokenConfidence(term, replacement, token.Distance, maxEditDistance)
+		} else if runeLen(term) == s.MinimumCharToChange {
+			// LookupCompound leaves words of this length alone, Lookup does not
+			suggestions, err := s.lookupCached(context.Background(), term, verbositypkg.Closest, maxEditDistance)
+			if err != nil {
+				return nil, err
+			}
+			if len(suggestions) == 0 || suggestions[0].Distance == 0 {
+				continue
+			}
+			annotation.Replacement = suggestions[0].Term
+			if s.PreserveCase {
+				annotation.Replacement = cm.transferCasing(token.Original, annotation.Replacement)
+			}
+			annotation.Distance = suggestions[0].Distance
+			annotation.Confidence = annotationConfidence(suggestions, maxEditDistance)
+		} else {
+			continue
+		}
+		annotations = append(annotations, annotation)
+	}
+	return annotations, nil
+}
+
+// tokenConfidence rates the replacement of term. Merged or split words,
+// whose replacement is not a suggestion for term, only count the distance.
+func (s *SymSpell) tokenConfidence(term, replacement string, distance, maxEditDistance int) float64 {
+	suggestions, err := s.lookupCached(context.Background(), term, verbositypkg.Closest, maxEditDistance)
+	if err != nil || len(suggestions) == 0 || suggestions[0].Term != replacement {
+		return 1 - float64(distance)/float64(maxEditDistance+1)
+	}
+	return annotationConfidence(suggestions, maxEditDistance)
+}
+
+func annotationConfidence(suggestions []items.SuggestItem, maxEditDistance int) float64 {
+	best := suggestions[0]
+	total := 0.0
+	for _, suggestion := range suggestions {
+		if suggestion.Distance == best.Distance {
+			total += float64(suggestion.Count)
+		}
+	}
+	share := 1.0
+	if total > 0 {
+		share = float64(best.Count) / total
+	}
+	return share * (1 - float64(best.Distance)/float64(maxEditDistance+1))
+}
@@ -0,0 +1,23 @@
+package internal
+
+// AddToBlacklist adds words that must never be offered as corrections.
+func (s *SymSpell) AddToBlacklist(words ...string) {
+	for _, word := range words {
+		s.blacklist[word] = struct{}{}
+	}
+	s.topCache.Clear()
+}
+
+// RemoveFromBlacklist allows previously blacklisted words to be suggested again.
+func (s *SymSpell) RemoveFromBlacklist(words ...string) {
+	for _, word := range words {
+		delete(s.blacklist, word)
+	}
+	s.topCache.Clear()
+}
+
+// IsBlacklisted reports whether word is on the suggestion blacklist.
+func (s *SymSpell) IsBlacklisted(word string) bool {
+	_, ok := s.blacklist[word]
+	return ok
+}
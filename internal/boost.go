@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"errors"
+	"sort"
+
+	"symspell/pkg/items"
+	verbositypkg "symspell/pkg/verbosity"
+)
+
+// boostList is a named set of terms whose counts are multiplied at ranking time.
+type boostList struct {
+	multiplier float64
+	terms      map[string]struct{}
+}
+
+// RegisterBoostList registers (or replaces) a named boost list. Terms of the list
+// have their counts multiplied by multiplier when ranking suggestions of
+// LookupWithBoost; the shared index itself is not modified.
+func (s *SymSpell) RegisterBoostList(name string, terms []string, multiplier float64) error {
+	if name == "" {
+		return errors.New("boost list name cannot be empty")
+	}
+	if multiplier <= 0 {
+		return errors.New("boost multiplier must be positive")
+	}
+	list := &boostList{multiplier: multiplier, terms: make(map[string]struct{}, len(terms))}
+	for _, term := range terms {
+		list.terms[term] = struct{}{}
+	}
+	if s.boostLists == nil {
+		s.boostLists = make(map[string]*boostList)
+	}
+	s.boostLists[name] = list
+	return nil
+}
+
+// RemoveBoostList unregisters a boost list.
+func (s *SymSpell) RemoveBoostList(name string) {
+	delete(s.boostLists, name)
+}
+
+// LookupWithBoost works like Lookup but ranks suggestions with the counts of the
+// named boost list multiplied. An empty name disables boosting.
+func (s *SymSpell) LookupWithBoost(
+	phrase string,
+	verbosity verbositypkg.Verbosity,
+	maxEditDistance int,
+	boostListName string,
+) ([]items.SuggestItem, error) {
+	if boostListName == "" {
+		return s.Lookup(phrase, verbosity, maxEditDistance)
+	}
+	list, ok := s.boostLists[boostListName]
+	if !ok {
+		return nil, errors.New("unknown boost list: " + boostListName)
+	}
+	// Top keeps a single candidate inside the loop, so collect the closest ones
+	// and pick the best after boosting.
+	lookupVerbosity := verbosity
+	if verbosity == verbositypkg.Top {
+		lookupVerbosity = verbositypkg.Closest
+	}
+	suggestions, err := s.Lookup(phrase, lookupVerbosity, maxEditDistance)
+	if err != nil {
+		return nil, err
+	}
+	list.sort(suggestions)
+	if verbosity == verbositypkg.Top && len(suggestions) > 1 {
+		suggestions = suggestions[:1]
+	}
+	return suggestions, nil
+}
+
+func (b *boostList) count(item items.SuggestItem) float64 {
+	if _, ok := b.terms[item.Term]; ok {
+		return float64(item.Count) * b.multiplier
+	}
+	return float64(item.Count)
+}
+
+func (b *boostList) sort(suggestions []items.SuggestItem) {
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Distance == suggestions[j].Distance {
+			return b.count(suggestions[i]) > b.count(suggestions[j])
+		}
+		return suggestions[i].Distance < suggestions[j].Distance
+	})
+}
@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"sort"
+
+	"symspell/pkg/items"
+	verbositypkg "symspell/pkg/verbosity"
+)
+
+// mergeSuggestions adds the suggestions of an extra candidate source to
+// result. Words found by both keep the smaller distance. It reports whether
+// result changed.
+func mergeSuggestions(result, extra []items.SuggestItem) ([]items.SuggestItem, bool) {
+	changed := false
+	for _, item := range extra {
+		i := 0
+		for i < len(result) && result[i].Term != item.Term {
+			i++
+		}
+		if i == len(result) {
+			result = append(result, item)
+			changed = true
+		} else if item.Distance < result[i].Distance {
+			result[i].Distance = item.Distance
+			changed = true
+		}
+	}
+	return result, changed
+}
+
+// rankMerged restores the lookup order of merged suggestions and applies the
+// verbosity cut again.
+func (s *SymSpell) rankMerged(result []items.SuggestItem, verbosity verbositypkg.Verbosity) []items.SuggestItem {
+	if len(result) < 2 {
+		return result
+	}
+	if s.Ranker != nil {
+		sort.SliceStable(result, func(i, j int) bool { return s.Ranker(result[i], result[j]) })
+	} else {
+		sort.SliceStable(result, func(i, j int) bool {
+			if result[i].Distance == result[j].Distance {
+				return result[i].Count > result[j].Count
+			}
+			return result[i].Distance < result[j].Distance
+		})
+	}
+	switch verbosity {
+	case verbositypkg.Top:
+		result = result[:1]
+	case verbositypkg.Closest:
+		n := 1
+		for n < len(result) && result[n].Distance == result[0].Distance {
+			n++
+		}
+		result = result[:n]
+	}
+	return result
+}
@@ -0,0 +1,167 @@
+package internal
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// caseMapping lowercases and uppercases strings, by the rules of CaseLocale
+// when CaseFolding is on. It is safe for concurrent use.
+type caseMapping struct {
+	lower, upper func(string) string
+}
+
+// newCaseMapping builds the case mapping of an instance once. A cases.Caser
+// keeps state between calls, so the locale-aware mappings take one from a
+// pool per call.
+func newCaseMapping(folding bool, locale language.Tag) caseMapping {
+	if !folding {
+		return caseMapping{lower: strings.ToLower, upper: strings.ToUpper}
+	}
+	return caseMapping{
+		lower: pooledCaser(func() cases.Caser { return cases.Lower(locale) }),
+		upper: pooledCaser(func() cases.Caser { return cases.Upper(locale) }),
+	}
+}
+
+func pooledCaser(newCaser func() cases.Caser) func(string) string {
+	pool := &sync.Pool{New: func() any {
+		caser := newCaser()
+		return &caser
+	}}
+	return func(str string) string {
+		caser := pool.Get().(*cases.Caser)
+		defer pool.Put(caser)
+		return caser.String(str)
+	}
+}
+
+func (s *SymSpell) caseMapping() caseMapping {
+	return s.casing
+}
+
+// dictionaryKey folds the case of a dictionary word when CaseFolding is on.
+func (s *SymSpell) dictionaryKey(key string) string {
+	if !s.CaseFolding {
+		return key
+	}
+	return s.caseMapping().lower(key)
+}
+
+// transferCasing applies the letter case of withCasing to withoutCasing, a
+// lowercase correction of it. The strings are aligned by edit distance:
+// aligned characters take the case of their source, inserted characters are
+// uppercased only if their aligned neighbours are. "Helllo" + "hello" gives
+// "Hello", "HELLLO" + "hello" gives "HELLO".
+func (cm caseMapping) transferCasing(withCasing, withoutCasing string) string {
+	if withCasing == withoutCasing || withoutCasing == "" {
+		return withoutCasing
+	}
+	source := []rune(withCasing)
+	target := []rune(withoutCasing)
+	if cm.lower(withCasing) == withCasing {
+		return withoutCasing
+	}
+	if cm.upper(withCasing) == withCasing && len(source) > 1 {
+		return cm.upper(withoutCasing)
+	}
+
+	// aligned[j] is the index of the source rune target[j] was aligned to,
+	// or -1 for inserted runes.
+	aligned := alignRunes(source, target)
+	var result strings.Builder
+	for j, r := range target {
+		upper := false
+		if i := aligned[j]; i >= 0 {
+			upper = unicode.IsUpper(source[i])
+		} else {
+			prev, next := neighbourAligned(aligned, j)
+			upper = (prev >= 0 || next >= 0) &&
+				(prev < 0 || unicode.IsUpper(source[prev])) &&
+				(next < 0 || unicode.IsUpper(source[next]))
+		}
+		if upper {
+			result.WriteString(cm.upper(string(r)))
+		} else {
+			result.WriteRune(r)
+		}
+	}
+	return result.String()
+}
+
+// TransferCasing is transferCasing with the Unicode default case mapping,
+// for corrections made outside an instance.
+func TransferCasing(withCasing, withoutCasing string) string {
+	return newCaseMapping(false, language.Und).transferCasing(withCasing, withoutCasing)
+}
+
+// alignRunes computes a case-insensitive Levenshtein alignment of a and b and
+// returns, for every rune of b, the index of the rune of a it is matched or
+// substituted with, or -1 if it was inserted.
+func alignRunes(a, b []rune) []int {
+	m, n := len(a), len(b)
+	width := n + 1
+	dist := make([]int, (m+1)*width)
+	for i := 0; i <= m; i++ {
+		dist[i*width] = i
+	}
+	for j := 0; j <= n; j++ {
+		dist[j] = j
+	}
+	for i := 1; i <= m; i++ {
+		for j := 1; j <= n; j++ {
+			cost := 1
+			if unicode.ToLower(a[i-1]) == unicode.ToLower(b[j-1]) {
+				cost = 0
+			}
+			dist[i*width+j] = min(dist[(i-1)*width+j]+1, dist[i*width+j-1]+1, dist[(i-1)*width+j-1]+cost)
+		}
+	}
+
+	aligned := make([]int, n)
+	i, j := m, n
+	for j > 0 {
+		switch {
+		case i > 0 && dist[i*width+j] == dist[(i-1)*width+j-1]+boolToInt(unicode.ToLower(a[i-1]) != unicode.ToLower(b[j-1])):
+			aligned[j-1] = i - 1
+			i--
+			j--
+		case dist[i*width+j] == dist[i*width+j-1]+1:
+			aligned[j-1] = -1
+			j--
+		default:
+			i--
+		}
+	}
+	return aligned
+}
+
+// neighbourAligned returns the source indexes of the closest aligned runes
+// before and after position j, or -1 where there is none.
+func neighbourAligned(aligned []int, j int) (prev, next int) {
+	prev, next = -1, -1
+	for k := j - 1; k >= 0; k-- {
+		if aligned[k] >= 0 {
+			prev = aligned[k]
+			break
+		}
+	}
+	for k := j + 1; k < len(aligned); k++ {
+		if aligned[k] >= 0 {
+			next = aligned[k]
+			break
+		}
+	}
+	return prev, next
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"context"
+	"math"
+	"strings"
+	"unicode"
+
+	"symspell/pkg/items"
+	verbositypkg "symspell/pkg/verbosity"
+)
+
+// defaultCJKSegmentLength covers most Chinese and Japanese dictionary words.
+const defaultCJKSegmentLength = 4
+
+// isCJK reports whether r belongs to a script written without spaces between
+// words: a Han ideograph, kana or the prolonged sound mark.
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) || r == 'ー'
+}
+
+// isCJKRun reports whether term is a run of CJK characters handled by the CJK
+// segmentation mode.
+func (s *SymSpell) isCJKRun(term string) bool {
+	if s.cjkSegmentLength == 0 || term == "" {
+		return false
+	}
+	for _, r := range term {
+		if !isCJK(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitCJKWords splits the words parsed from phrase where CJK characters meet
+// other characters, so that every CJK run is a word of its own.
+func (s *SymSpell) splitCJKWords(phrase string, words []string, spans []wordSpan) ([]string, []wordSpan) {
+	var splitWords []string
+	var splitSpans []wordSpan
+	for i, span := range spans {
+		start := span.start
+		var prevCJK bool
+		for j, r := range phrase[span.start:span.end] {
+			cjk := isCJK(r)
+			if j > 0 && cjk != prevCJK {
+				splitSpans = append(splitSpans, wordSpan{start: start, end: span.start + j})
+				start = span.start + j
+			}
+			prevCJK = cjk
+		}
+		if start == span.start {
+			splitWords = append(splitWords, words[i])
+			splitSpans = append(splitSpans, span)
+			continue
+		}
+		splitSpans = append(splitSpans, wordSpan{start: start, end: span.end})
+		for _, part := range splitSpans[len(splitWords):] {
+			splitWords = append(splitWords, s.caseMapping().lower(phrase[part.start:part.end]))
+		}
+	}
+	return splitWords, splitSpans
+}
+
+// correctCJKSegment corrects seg, a part of a CJK run, by substituting at
+// most one character per two. It reports false if the part is too long to be
+// a word.
+func (s *SymSpell) correctCJKSegment(seg *segment, maxEditDistance int) bool {
+	length := runeLen(seg.part)
+	if length > s.cjkSegmentLength {
+		return false
+	}
+	suggestions, _ := s.lookupCached(context.Background(), seg.part, verbositypkg.All, min(maxEditDistance, length/2))
+	for _, suggestion := range suggestions {
+		if runeLen(suggestion.Term) == length {
+			seg.corrected = suggestion.Term
+			seg.distance += suggestion.Distance
+			seg.logProb = math.Log10(float64(suggestion.Count) / s.N)
+			return true
+		}
+	}
+	seg.corrected = seg.part
+	seg.distance += length
+	seg.logProb = math.Log10(10.0 / s.N / math.Pow(10.0, float64(length)))
+	return true
+}
+
+// correctCJKRun corrects a CJK run of a LookupCompound phrase as a whole by
+// segmenting it, and returns the correction without spaces.
+func (s *SymSpell) correctCJKRun(run string, maxEditDistance int) items.SuggestItem {
+	compositions, _ := s.WordSegmentationNBest(run, maxEditDistance, 0, 1)
+	if len(compositions) == 0 {
+		return createWithProbability(run, maxEditDistance+1)
+	}
+	c := compositions[0]
+	return items.SuggestItem{
+		Term:     strings.ReplaceAll(c.CorrectedString, " ", ""),
+		Distance: c.DistanceSum,
+		Count:    itemCount(floatCount(s.N * math.Pow(10, c.LogProbSum))),
+	}
+}
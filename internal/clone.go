@@ -0,0 +1,84 @@
+package internal
+
+import (
+	"maps"
+	"slices"
+
+	"symspell/pkg/options"
+)
+
+// Clone returns an independent copy of s that can be updated without
+// affecting s. The word strings, including the CompactStorage arena, and the
+// deletes index are shared, since neither is ever written in place; updates
+// of either side build new ones. The maps and per-word slices that updates
+// modify in place are copied, and posting lists are clipped so that appends
+// reallocate. The clone starts with an empty lookup cache and zero skip
+// counters. A mapped index stays shared as well, so its closer must not be
+// closed while a clone uses it. The store of StorageDisk is not shared: the
+// clone of a disk-backed instance builds its deletes index in memory.
+func (s *SymSpell) Clone() *SymSpell {
+	c := *s
+	c.Words = maps.Clone(s.Words)
+	c.BelowThresholdWords = maps.Clone(s.BelowThresholdWords)
+	c.ExactTransform = maps.Clone(s.ExactTransform)
+	c.Bigrams = maps.Clone(s.Bigrams)
+	c.words = slices.Clone(s.words)
+	if s.arena != nil {
+		c.arena = s.arena.clone()
+	}
+	c.counts = slices.Clone(s.counts)
+	c.deleted = slices.Clone(s.deleted)
+	c.boostLists = maps.Clone(s.boostLists)
+	c.blacklist = maps.Clone(s.blacklist)
+	c.protected = maps.Clone(s.protected)
+	c.userWords = maps.Clone(s.userWords)
+	c.phoneticIdx = clipPostings(s.phoneticIdx)
+	c.accentOriginals = clipPostings(s.accentOriginals)
+	c.deltaIdx = clipPostings(s.deltaIdx)
+	if s.accentFree != nil {
+		c.accentFree = s.accentFree.Clone()
+	}
+	if s.userIdx != nil {
+		c.userIdx = s.userIdx.Clone()
+	}
+	c.topCache = newTopCache(s.topCache.capacity, s.topCache.ttl)
+	c.skipStats = new(skipCounters)
+	if s.lookupGroup != nil {
+		c.lookupGroup = new(lookupGroup)
+	}
+	if s.disk != nil {
+		c.disk = nil
+		c.StorageBackend = options.StorageMemory
+		c.buildIndex()
+	}
+	return &c
+}
+
+// Freeze returns a clone of s with the pending index work done, so that its
+// read methods never modify it: deleted words are compacted, the delta index
+// is merged and the frequency order of TopWords is built.
+func (s *SymSpell) Freeze() *SymSpell {
+	c := s.Clone()
+	if c.deletedCount > 0 {
+		c.Compact()
+	}
+	c.mergeDelta()
+	c.buildFrequencyIndex()
+	if c.accentFree != nil {
+		c.accentFree = c.accentFree.Freeze()
+	}
+	return c
+}
+
+// clipPostings copies a map of posting lists, capping every list at its
+// length so that appending to it in either map does not touch the other.
+func clipPostings[K comparable](postings map[K][]uint32) map[K][]uint32 {
+	if postings == nil {
+		return nil
+	}
+	clipped := make(map[K][]uint32, len(postings))
+	for key, list := range postings {
+		clipped[key] = slices.Clip(list)
+	}
+	return clipped
+}
@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"symspell/pkg/stats"
+)
+
+// Compact removes deleted words and rebuilds the deletes index from the live
+// words, so that no posting outlives the word it was generated for, whatever
+// sequence of runtime changes left it behind. Postings of words added at
+// runtime are merged in as well. ReclaimedBytes is the drop in
+// Stats().EstimatedBytes.
+//
+// Compact works in place. The thread-safe instance runs it on a clone without
+// holding its lock and swaps the result in, see CompactClone and CompactSwap.
+func (s *SymSpell) Compact() stats.CompactStats {
+	before := s.Stats()
+	result := stats.CompactStats{
+		WordsRemoved:   s.deletedCount,
+		PostingsBefore: before.Postings + before.DeltaPostings,
+	}
+	s.compactWords()
+	s.deleted = nil
+	s.deletedCount = 0
+	s.byFrequency, s.byTerm = nil, nil
+	s.buildIndex()
+
+	after := s.Stats()
+	result.PostingsAfter = after.Postings
+	result.DeleteKeysFreed = max(before.DeleteKeys-after.DeleteKeys, 0)
+	result.ReclaimedBytes = max(before.EstimatedBytes-after.EstimatedBytes, 0)
+	return result
+}
+
+// CompactSwap replaces the dictionary and index of s with those of c, a
+// compacted clone of s. The caller must make sure that s has not been
+// modified since c was cloned; state that Compact does not touch, like the
+// caches and the user dictionary, stays with s.
+func (s *SymSpell) CompactSwap(c *SymSpell) {
+	s.takeIndex(c)
+	s.deleteFilter = c.deleteFilter
+	s.phoneticIdx = c.phoneticIdx
+	s.accentFree, s.accentOriginals = c.accentFree, c.accentOriginals
+	s.byFrequency = nil
+	s.topCache.Clear()
+}
+
+// takeIndex moves the words, counts and deletes index of c into s.
+func (s *SymSpell) takeIndex(c *SymSpell) {
+	s.Words, s.words, s.arena, s.counts = c.Words, c.words, c.arena, c.counts
+	s.deleted, s.deletedCount = c.deleted, c.deletedCount
+	s.maxLength = c.maxLength
+	s.byTerm = c.byTerm
+	s.DeletesIdx, s.DeletesData = c.DeletesIdx, c.DeletesData
+	s.hashedDeletes, s.trieDeletes, s.mappedDeletes = c.hashedDeletes, c.trieDeletes, c.mappedDeletes
+	s.deltaIdx, s.deltaPostings = c.deltaIdx, c.deltaPostings
+}
+
+// CompactClone returns a clone of s for Compact to run on off to the side,
+// or nil if s has to be compacted in place because its index is on disk.
+func (s *SymSpell) CompactClone() *SymSpell {
+	if s.disk != nil {
+		return nil
+	}
+	return s.Clone()
+}
+
+// compactWords drops deleted words from words and counts.
+func (s *SymSpell) compactWords() {
+	if s.deletedCount == 0 {
+		return
+	}
+	if s.arena != nil {
+		s.compactArena()
+		return
+	}
+	words := make([]string, 0, len(s.words)-s.deletedCount)
+	counts := make([]uint64, 0, len(s.words)-s.deletedCount)
+	// a fresh map, since maps never release the buckets of deleted keys
+	wordIndex := make(map[string]uint32, len(s.words)-s.deletedCount)
+	for i, word := range s.words {
+		if !s.isLiveIndex(uint32(i)) {
+			continue
+		}
+		wordIndex[word] = uint32(len(words))
+		words = append(words, word)
+		counts = append(counts, s.counts[i])
+	}
+	s.Words = wordIndex
+	s.words = words
+	s.counts = counts
+}
+
+// compactArena repacks the live words into a new arena, leaving the bytes of
+// deleted words behind.
+func (s *SymSpell) compactArena() {
+	live := len(s.arena.refs) - s.deletedCount
+	arena := newWordArena(len(s.arena.bytes), live, live)
+	counts := make([]uint64, 0, live)
+	for i := range s.arena.refs {
+		if !s.isLiveIndex(uint32(i)) {
+			continue
+		}
+		word := s.arena.word(uint32(i))
+		arena.insert(word, arena.append(word))
+		counts = append(counts, s.counts[i])
+	}
+	s.arena = arena
+	s.counts = counts
+}
+
+// isLiveIndex reports whether a posting still refers to a dictionary word.
+func (s *SymSpell) isLiveIndex(index uint32) bool {
+	if int(index) >= s.wordSlots() {
+		return false
+	}
+	return int(index) >= len(s.deleted) || !s.deleted[index]
+}
@@ -0,0 +1,263 @@
+package internal
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"symspell/pkg/items"
+	verbositypkg "symspell/pkg/verbosity"
+)
+
+// compoundHypothesis is a correction of the first words of a phrase kept by
+// the beam search of LookupCompound, see options.WithCompoundBeamWidth.
+type compoundHypothesis struct {
+	parts      []items.SuggestItem
+	partTokens [][2]int // first and last input word of every part
+	words      []string // words of the parts, kept for the language model
+	distance   int
+	logProb    float64
+}
+
+// before reports whether h is a better correction than o: one with fewer
+// edits, or a more probable one with as many.
+func (h *compoundHypothesis) before(o *compoundHypothesis) bool {
+	if h.distance != o.distance {
+		return h.distance < o.distance
+	}
+	return h.logProb > o.logProb
+}
+
+// compoundAlternative is a correction of the input words first..last.
+type compoundAlternative struct {
+	item        items.SuggestItem
+	first, last int
+}
+
+// LookupCompoundNBest returns up to n corrections of phrase found by the beam
+// search of options.WithCompoundBeamWidth, best first, with a beam at least n
+// wide. Corrections are ordered by the summed edit distance of their parts,
+// where a word left uncorrected counts as maxEditDistance+1, and then by log10
+// probability. It returns nil if the phrase is rejected.
+func (s *SymSpell) LookupCompoundNBest(phrase string, maxEditDistance, n int) []items.CompoundResult {
+	if n <= 0 {
+		return nil
+	}
+	input := phrase
+	phrase, offsets, err := s.checkUTF8Offsets(phrase)
+	if err != nil {
+		return nil
+	}
+	words, spans := s.compoundWords(phrase)
+	results := s.compoundResults(context.Background(), phrase, words, spans, maxEditDistance, max(n, s.CompoundBeamWidth))
+	results = results[:min(n, len(results))]
+	for i := range results {
+		offsets.tokens(input, results[i].Tokens)
+	}
+	return results
+}
+
+// compoundResults turns the final beam into distinct corrections in beam
+// order.
+func (s *SymSpell) compoundResults(ctx context.Context, phrase string, words []string, spans []wordSpan, maxEditDistance, width int) []items.CompoundResult {
+	beam := s.compoundBeam(ctx, phrase, words, spans, maxEditDistance, width)
+	results := make([]items.CompoundResult, 0, len(beam))
+	seen := make(map[string]bool, len(beam))
+	for _, h := range beam {
+		cp := compoundProcessor{suggestionParts: h.parts, partTokens: h.partTokens}
+		suggestion := s.finalizeAnswer(phrase, cp.suggestionParts)
+		if seen[suggestion.Term] {
+			continue
+		}
+		seen[suggestion.Term] = true
+		results = append(results, items.CompoundResult{
+			Suggestion: *suggestion,
+			Tokens:     s.tokenCorrections(phrase, spans, &cp),
+			LogProb:    h.logProb,
+		})
+	}
+	return results
+}
+
+// compoundBeam returns the width best corrections of all words, best first.
+// beams[i] holds the hypotheses correcting words[:i]; a hypothesis reaches
+// beams[i+1] by correcting or splitting words[i] and beams[i+2] by merging
+// words[i] with words[i+1]. With several CompoundWorkers the word and pair
+// lookups are prefetched in parallel.
+func (s *SymSpell) compoundBeam(ctx context.Context, phrase string, words []string, spans []wordSpan, maxEditDistance, width int) []compoundHypothesis {
+	var prefetched map[string][]items.SuggestItem
+	if s.CompoundWorkers > 1 && len(words) > 1 {
+		prefetched = s.prefetchCompound(ctx, words, verbositypkg.Closest, maxEditDistance)
+	}
+	memo := newDistanceMemo(s.CompoundDistanceMemo)
+	ctx = withDistanceMemo(ctx, memo)
+	beams := make([][]compoundHypothesis, len(words)+1)
+	beams[0] = []compoundHypothesis{{}}
+	for i := range words {
+		beams[i] = pruneBeam(beams[i], width)
+		alternatives := s.compoundAlternatives(ctx, memo, prefetched, phrase, words, spans, i, maxEditDistance)
+		for _, h := range beams[i] {
+			for _, alt := range alternatives {
+				beams[alt.last+1] = append(beams[alt.last+1], s.extendHypothesis(h, alt))
+			}
+		}
+	}
+	return pruneBeam(beams[len(words)], width)
+}
+
+// pruneBeam keeps the width best hypotheses with distinct corrections, so
+// that a correction reached by several splits or merges takes one place.
+func pruneBeam(beam []compoundHypothesis, width int) []compoundHypothesis {
+	sort.SliceStable(beam, func(i, j int) bool {
+		return beam[i].before(&beam[j])
+	})
+	kept := beam[:0]
+	seen := make(map[string]bool, width)
+	for _, h := range beam {
+		if len(kept) == width {
+			break
+		}
+		if term := h.term(); !seen[term] {
+			seen[term] = true
+			kept = append(kept, h)
+		}
+	}
+	return kept
+}
+
+// term returns the words of the correction separated by spaces.
+func (h *compoundHypothesis) term() string {
+	var b strings.Builder
+	for i, part := range h.parts {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(part.Term)
+	}
+	return b.String()
+}
+
+// compoundAlternatives returns the corrections of words[i] considered by the
+// beam: its closest suggestions, its splits into two words that are not
+// farther from it, and the correction of words[i] merged with words[i+1].
+// Lookups found in prefetched are not repeated.
+func (s *SymSpell) compoundAlternatives(ctx context.Context, memo *distanceMemo, prefetched map[string][]items.SuggestItem, phrase string, words []string, spans []wordSpan, i, maxEditDistance int) []compoundAlternative {
+	if item, ok := s.verbatimItem(phrase[spans[i].start:spans[i].end]); ok {
+		return []compoundAlternative{{item: item, first: i, last: i}}
+	}
+	if s.isCJKRun(words[i]) {
+		return []compoundAlternative{{item: s.correctCJKRun(words[i], maxEditDistance), first: i, last: i}}
+	}
+	term := words[i]
+	if i != len(words)-1 || runeLen(term) > s.MinimumCharToChange {
+		if result, found := s.ExactTransform[term]; found {
+			term = result
+		}
+	}
+	if runeLen(term) <= s.MinimumCharToChange {
+		return []compoundAlternative{{item: items.SuggestItem{Term: term, Count: math.MaxInt}, first: i, last: i}}
+	}
+
+	var alternatives []compoundAlternative
+	suggestions, ok := prefetched[term]
+	if !ok {
+		suggestions, _ = s.lookupCached(ctx, term, verbositypkg.Closest, maxEditDistance)
+	}
+	for _, suggestion := range suggestions {
+		alternatives = append(alternatives, compoundAlternative{item: suggestion, first: i, last: i})
+	}
+	if len(suggestions) == 0 {
+		alternatives = append(alternatives, compoundAlternative{item: createWithProbability(term, maxEditDistance+1), first: i, last: i})
+	}
+
+	if runes := []rune(term); len(runes) > 1 && (len(suggestions) == 0 || suggestions[0].Distance > 0) {
+		closest := alternatives[0].item.Distance
+		cp := compoundProcessor{suggestions: suggestions, terms1: term}
+		var splits map[string][]items.SuggestItem
+		if s.CompoundWorkers > 1 {
+			splits = s.prefetchSplits(ctx, runes, maxEditDistance)
+		}
+		for j := 1; j < len(runes); j++ {
+			suggestion1, suggestion2, ok := s.getSuggestions(ctx, splits, runes, j, maxEditDistance)
+			if !ok {
+				continue
+			}
+			cp.suggestion1, cp.suggestion2 = *suggestion1, *suggestion2
+			distance := memo.compare(s, term, cp.tempTerm(), maxEditDistance)
+			if distance < 0 {
+				distance = maxEditDistance + 1
+			}
+			if distance > closest {
+				continue
+			}
+			split := items.SuggestItem{Term: cp.tempTerm(), Distance: distance, Count: s.checkForBigram(&cp)}
+			alternatives = append(alternatives, compoundAlternative{item: split, first: i, last: i})
+		}
+	}
+
+	if i+1 < len(words) && !s.isVerbatim(phrase[spans[i+1].start:spans[i+1].end]) && !s.isCJKRun(words[i+1]) {
+		pair := words[i] + " " + words[i+1]
+		combined, ok := prefetched[pair]
+		if !ok {
+			combined, _ = s.lookupCached(ctx, pair, verbositypkg.Top, maxEditDistance)
+		}
+		if len(combined) > 0 {
+			merged := combined[0]
+			merged.Distance++
+			alternatives = append(alternatives, compoundAlternative{item: merged, first: i, last: i + 1})
+		}
+	}
+	return alternatives
+}
+
+// extendHypothesis returns h followed by alt.
+func (s *SymSpell) extendHypothesis(h compoundHypothesis, alt compoundAlternative) compoundHypothesis {
+	next := compoundHypothesis{
+		parts:      append(slices.Clip(h.parts), alt.item),
+		partTokens: append(slices.Clip(h.partTokens), [2]int{alt.first, alt.last}),
+		distance:   h.distance + alt.item.Distance,
+		logProb:    h.logProb,
+	}
+	if s.languageModel != nil {
+		words := strings.Fields(alt.item.Term)
+		next.logProb = s.extendScore(h.words, h.logProb, words...)
+		next.words = append(slices.Clip(h.words), words...)
+		return next
+	}
+	var prev string
+	if len(h.parts) > 0 {
+		fields := strings.Fields(h.parts[len(h.parts)-1].Term)
+		prev = fields[len(fields)-1]
+	}
+	for _, word := range strings.Fields(alt.item.Term) {
+		next.logProb += s.wordLogProb(prev, word)
+		prev = word
+	}
+	return next
+}
+
+// stupidBackoff weights the unigram probability of a word that does not
+// follow prev in the bigram dictionary (Brants et al., 2007).
+const stupidBackoff = 0.4
+
+// wordLogProb returns the log10 score of word following prev, which is empty
+// at the start of a sentence: the bigram frequency of the pair, or the
+// unigram probability of word with stupid backoff. Unknown words get the
+// Naive Bayes estimate of createWithProbability.
+func (s *SymSpell) wordLogProb(prev, word string) float64 {
+	count := float64(s.wordCount(word))
+	if count == 0 {
+		return math.Log10(10 / s.N / math.Pow(10, float64(runeLen(word))))
+	}
+	if bigram, ok := s.Bigrams[prev+" "+word]; ok && prev != "" {
+		if prevCount := s.wordCount(prev); prevCount > 0 {
+			return math.Log10(float64(bigram) / float64(prevCount))
+		}
+	}
+	if prev == "" {
+		return math.Log10(count / s.N)
+	}
+	return math.Log10(stupidBackoff * count / s.N)
+}
@@ -0,0 +1,135 @@
+package internal
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"symspell/pkg/items"
+)
+
+// defaultCompoundMinPartLength keeps short dictionary entries, often
+// abbreviations, from splitting arbitrary words.
+const defaultCompoundMinPartLength = 3
+
+// compoundSplitItem accepts phrase as an exact match if it splits into
+// dictionary words, see options.WithCompoundSplitting. It is only tried when
+// the lookup found no suggestions within distance 1, so a typo of a
+// dictionary word that happens to split is still corrected.
+func (s *SymSpell) compoundSplitItem(phrase string, suggestions []items.SuggestItem) (items.SuggestItem, bool) {
+	if s.compoundMinPartLength == 0 {
+		return items.SuggestItem{}, false
+	}
+	for _, suggestion := range suggestions {
+		if suggestion.Distance <= 1 {
+			return items.SuggestItem{}, false
+		}
+	}
+	parts, logCount := s.splitCompound(phrase)
+	if parts == nil {
+		return items.SuggestItem{}, false
+	}
+	count := math.Pow(10, logCount/float64(len(parts)))
+	return items.SuggestItem{Term: phrase, Distance: 0, Count: itemCount(floatCount(count))}, true
+}
+
+// SplitCompound returns the dictionary words that word is composed of,
+// without linking morphemes, or nil if it is not such a compound or compound
+// splitting is disabled. Of several splits it picks the one with the highest
+// geometric mean of the part counts.
+func (s *SymSpell) SplitCompound(word string) []string {
+	if s.compoundMinPartLength == 0 {
+		return nil
+	}
+	parts, _ := s.splitCompound(word)
+	return parts
+}
+
+// compoundSplit is the best split of a prefix of a word into k parts.
+type compoundSplit struct {
+	ok       bool
+	parts    []string
+	logCount float64 // sum of log10 of the part counts
+}
+
+// splitCompound returns the best split of word into at least two parts and
+// the sum of log10 of their counts. best[i][k] is the split of runes[:i] into
+// k parts, followed by a linking morpheme unless i is the end of a part.
+func (s *SymSpell) splitCompound(word string) ([]string, float64) {
+	runes := []rune(word)
+	minLength := s.compoundMinPartLength
+	if len(runes) < 2*minLength {
+		return nil, 0
+	}
+	maxParts := len(runes) / minLength
+	best := make([][]compoundSplit, len(runes)+1)
+	for i := range best {
+		best[i] = make([]compoundSplit, maxParts+1)
+	}
+	best[0][0].ok = true
+	for start := 0; start < len(runes); start++ {
+		for k, prefix := range best[start][:maxParts] {
+			if !prefix.ok {
+				continue
+			}
+			for end := start + minLength; end <= len(runes); end++ {
+				part := string(runes[start:end])
+				count := s.constituentCount(part)
+				if count == 0 {
+					continue
+				}
+				split := compoundSplit{
+					ok:       true,
+					parts:    append(slices.Clip(prefix.parts), part),
+					logCount: prefix.logCount + math.Log10(float64(count)),
+				}
+				keepBetterSplit(&best[end][k+1], split)
+				if end == len(runes) {
+					continue
+				}
+				rest := string(runes[end:])
+				for _, link := range s.linkingMorphemes {
+					if len(link) < len(rest) && strings.HasPrefix(rest, link) {
+						keepBetterSplit(&best[end+runeLen(link)][k+1], split)
+					}
+				}
+			}
+		}
+	}
+
+	var result []string
+	var resultLogCount, resultMean float64
+	for k := 2; k <= maxParts; k++ {
+		split := best[len(runes)][k]
+		if !split.ok {
+			continue
+		}
+		if mean := split.logCount / float64(k); result == nil || mean > resultMean {
+			result, resultLogCount, resultMean = split.parts, split.logCount, mean
+		}
+	}
+	return result, resultLogCount
+}
+
+func keepBetterSplit(dst *compoundSplit, split compoundSplit) {
+	if !dst.ok || split.logCount > dst.logCount {
+		*dst = split
+	}
+}
+
+// constituentCount returns the count of a part of a compound. Inner parts of
+// German compounds are written in lower case, so the part is also looked up
+// lowercased and with an upper-case first letter, the spelling of nouns.
+func (s *SymSpell) constituentCount(part string) uint64 {
+	if count := s.wordCount(part); count > 0 {
+		return count
+	}
+	lower := s.caseMapping().lower(part)
+	if count := s.wordCount(lower); count > 0 {
+		return count
+	}
+	runes := []rune(lower)
+	runes[0] = unicode.ToUpper(runes[0])
+	return s.wordCount(string(runes))
+}
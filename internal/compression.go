@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// decompress returns a reader of the decompressed data if r holds a gzip or
+// zstd stream, recognised by its magic bytes, and r itself otherwise. The
+// returned function releases the decoder.
+func decompress(r io.Reader) (io.Reader, func(), error) {
+	buffered := bufio.NewReader(r)
+	magic, _ := buffered.Peek(len(zstdMagic))
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		decoder, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading gzip: %w", err)
+		}
+		return decoder, func() { decoder.Close() }, nil
+	case bytes.Equal(magic, zstdMagic):
+		decoder, err := zstd.NewReader(buffered, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading zstd: %w", err)
+		}
+		return decoder, decoder.Close, nil
+	}
+	return buffered, func() {}, nil
+}
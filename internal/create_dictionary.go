@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// CreateDictionary builds the dictionary from running text: every word found
+// by the compound tokenizer is lowercased and counted, then the counts are
+// added to the dictionary and the index is rebuilt.
+func (s *SymSpell) CreateDictionary(corpus io.Reader) (bool, error) {
+	counts := make(map[string]uint64)
+	lower := s.caseMapping().lower
+	scanner := s.newLineScanner(corpus)
+	for scanner.Scan() {
+		line, err := s.checkUTF8(scanner.Text())
+		if err != nil {
+			return false, fmt.Errorf("line %d: %w", scanner.line, err)
+		}
+		for _, word := range reSplit.FindAllString(line, -1) {
+			word = lower(word)
+			counts[word] = incrementCount(1, counts[word])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, err
+	}
+
+	// Add words in a fixed order so that word indexes are reproducible.
+	words := make([]string, 0, len(counts))
+	for word := range counts {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	for _, word := range words {
+		s.addWordEntry(word, counts[word])
+	}
+	s.buildIndex()
+	return true, nil
+}
@@ -0,0 +1,36 @@
+package internal
+
+// DeleteDictionaryEntry removes term from the dictionary. Its postings are
+// tombstoned and skipped by lookups; once a quarter of the words are deleted
+// the index is compacted automatically. It returns false if term is unknown.
+func (s *SymSpell) DeleteDictionaryEntry(term string) bool {
+	delete(s.BelowThresholdWords, term)
+	if word, found := s.userWords[term]; found && word.added {
+		return s.RemoveUserWord(term)
+	}
+	idx, found := s.wordIndex(term)
+	if !found {
+		return false
+	}
+	s.unindexWord(term)
+	if _, user := s.userWords[term]; user {
+		delete(s.userWords, term)
+		s.userVersion++
+	}
+	if len(s.deleted) < s.wordSlots() {
+		s.deleted = append(s.deleted, make([]bool, s.wordSlots()-len(s.deleted))...)
+	}
+	s.deleted[idx] = true
+	s.deletedCount++
+	if s.disk != nil {
+		if err := s.disk.deleteWord(idx); err != nil {
+			s.logger.Error("deleting word from disk storage", "path", s.StoragePath, "word", term, "err", err)
+		}
+	}
+	s.topCache.Clear()
+
+	if s.deletedCount*4 > s.wordSlots() {
+		s.Compact()
+	}
+	return true
+}
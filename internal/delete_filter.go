@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// deleteFilter is a Bloom filter of the xxhash of every delete key in the
+// main deletes index, see options.WithDeleteKeyFilter. All probes of a key
+// fall into one 64-bit word, so a check costs a single memory access; this
+// raises the false positive rate a little over a classic Bloom filter. The
+// filter is immutable and rebuilt with the index, so clones share it.
+type deleteFilter struct {
+	words  []uint64
+	probes int
+}
+
+// newDeleteFilter sizes a filter for keys delete keys at bitsPerKey bits each.
+func newDeleteFilter(keys, bitsPerKey int) *deleteFilter {
+	words := max(1, (keys*bitsPerKey+63)/64)
+	probes := min(max(int(math.Round(float64(bitsPerKey)*math.Ln2)), 1), 10)
+	return &deleteFilter{words: make([]uint64, words), probes: probes}
+}
+
+func (f *deleteFilter) add(h uint64) {
+	f.words[f.word(h)] |= f.mask(h)
+}
+
+// mayContain reports false if no delete key has hash h.
+func (f *deleteFilter) mayContain(h uint64) bool {
+	mask := f.mask(h)
+	return f.words[f.word(h)]&mask == mask
+}
+
+// word picks a word from the high 32 bits of h.
+func (f *deleteFilter) word(h uint64) uint64 {
+	return (h >> 32) * uint64(len(f.words)) >> 32
+}
+
+// mask sets one bit per probe, taking 6 bits at a time from a remix of h so
+// that they are independent of the word choice.
+func (f *deleteFilter) mask(h uint64) uint64 {
+	x := h * 0x9e3779b97f4a7c15
+	var mask uint64
+	for range f.probes {
+		mask |= 1 << (x >> 58)
+		x <<= 6
+	}
+	return mask
+}
+
+func (f *deleteFilter) bytes() int {
+	return len(f.words) * 8
+}
+
+// buildDeleteFilter rebuilds the filter from the keys of the main deletes
+// index. Delete keys of the delta index are not covered and are always
+// probed.
+func (s *SymSpell) buildDeleteFilter() {
+	if s.deleteFilterBits == 0 {
+		s.deleteFilter = nil
+		return
+	}
+	f := newDeleteFilter(s.deleteKeys(), s.deleteFilterBits)
+	switch {
+	case s.disk != nil:
+		s.disk.eachDelete(func(del string) {
+			f.add(xxhash.Sum64String(del))
+		})
+	case s.mappedDeletes != nil:
+		t := s.mappedDeletes
+		for i := 0; i < len(t.slots); i += mappedSlotSize {
+			if binary.LittleEndian.Uint64(t.slots[i+8:]) != 0 {
+				f.add(binary.LittleEndian.Uint64(t.slots[i:]))
+			}
+		}
+	case s.hashedDeletes != nil:
+		for h := range s.hashedDeletes {
+			f.add(h)
+		}
+	default:
+		s.eachDelete(func(del string, _, _ uint32) {
+			f.add(xxhash.Sum64String(del))
+		})
+	}
+	s.deleteFilter = f
+}
@@ -0,0 +1,264 @@
+package internal
+
+import (
+	"slices"
+)
+
+// deleteTrie holds the delete keys in a minimal acyclic automaton (DAWG) that
+// shares both the prefixes and the suffixes of the keys. Keys are numbered by
+// their rank in byte order, counted on the way down from the number of keys
+// below each node, and the postings of the key with rank r are
+// data[offsets[r]:offsets[r+1]].
+type deleteTrie struct {
+	firstEdge []uint32 // edges of node n are firstEdge[n]:firstEdge[n+1], sorted by label
+	final     []bool
+	below     []uint32 // keys accepted from each node
+	labels    []byte
+	targets   []uint32
+	offsets   []uint32
+}
+
+// newDeleteTrie builds the automaton of the delete keys and appends their
+// postings to data in key order.
+func newDeleteTrie(deletes map[string][]uint32, data []uint32) (*deleteTrie, []uint32) {
+	keys := make([]string, 0, len(deletes))
+	for del := range deletes {
+		keys = append(keys, del)
+	}
+	slices.Sort(keys)
+
+	b := trieBuilder{nodes: make([]trieBuilderNode, 1), register: make(map[string]uint32)}
+	t := &deleteTrie{offsets: make([]uint32, 0, len(keys)+1)}
+	for _, key := range keys {
+		b.insert(key)
+		t.offsets = append(t.offsets, uint32(len(data)))
+		data = append(data, deletes[key]...)
+	}
+	t.offsets = append(t.offsets, uint32(len(data)))
+	b.minimize(0)
+	b.flatten(t)
+	return t, data
+}
+
+// keys returns the number of delete keys.
+func (t *deleteTrie) keys() int {
+	return len(t.offsets) - 1
+}
+
+// lookup returns the rank of key.
+func (t *deleteTrie) lookup(key string) (uint32, bool) {
+	node, rank := uint32(0), uint32(0)
+	for i := 0; i < len(key); i++ {
+		if t.final[node] {
+			rank++
+		}
+		next, found := uint32(0), false
+		for e := t.firstEdge[node]; e < t.firstEdge[node+1]; e++ {
+			if t.labels[e] >= key[i] {
+				next, found = t.targets[e], t.labels[e] == key[i]
+				break
+			}
+			rank += t.below[t.targets[e]]
+		}
+		if !found {
+			return 0, false
+		}
+		node = next
+	}
+	return rank, t.final[node]
+}
+
+// postings returns the postings of key.
+func (t *deleteTrie) postings(key string, data []uint32) ([]uint32, bool) {
+	rank, found := t.lookup(key)
+	if !found {
+		return nil, false
+	}
+	return data[t.offsets[rank]:t.offsets[rank+1]], true
+}
+
+// each calls fn for every key in byte order with the key's rank.
+func (t *deleteTrie) each(fn func(key string, rank uint32)) {
+	rank := uint32(0)
+	var walk func(node uint32, key []byte)
+	walk = func(node uint32, key []byte) {
+		if t.final[node] {
+			fn(string(key), rank)
+			rank++
+		}
+		for e := t.firstEdge[node]; e < t.firstEdge[node+1]; e++ {
+			walk(t.targets[e], append(key, t.labels[e]))
+		}
+	}
+	walk(0, nil)
+}
+
+// deletes returns the postings of every key, passed through remap. Keys left
+// without postings are dropped and counted.
+func (t *deleteTrie) deletes(data []uint32, remap func(uint32) (uint32, bool)) (map[string][]uint32, int) {
+	deletes := make(map[string][]uint32, t.keys())
+	dropped := 0
+	t.each(func(key string, rank uint32) {
+		var postings []uint32
+		for _, idx := range data[t.offsets[rank]:t.offsets[rank+1]] {
+			if newIndex, ok := remap(idx); ok {
+				postings = append(postings, newIndex)
+			}
+		}
+		if len(postings) > 0 {
+			deletes[key] = postings
+		} else {
+			dropped++
+		}
+	})
+	return deletes, dropped
+}
+
+// bytes estimates the memory held by the automaton.
+func (t *deleteTrie) bytes() int {
+	return cap(t.firstEdge)*4 + cap(t.final) + cap(t.below)*4 + cap(t.labels) + cap(t.targets)*4 + cap(t.offsets)*4
+}
+
+type trieBuilderEdge struct {
+	label  byte
+	target uint32
+}
+
+type trieBuilderNode struct {
+	final bool
+	edges []trieBuilderEdge
+}
+
+type trieUncheckedEdge struct {
+	parent, child uint32
+}
+
+// trieBuilder builds a minimal automaton from keys inserted in sorted order,
+// following Daciuk et al.: once a key is inserted, the nodes of the previous
+// key below the common prefix can no longer change and are merged with an
+// equivalent registered node or registered themselves.
+type trieBuilder struct {
+	nodes     []trieBuilderNode
+	free      []uint32
+	register  map[string]uint32
+	unchecked []trieUncheckedEdge
+	previous  string
+}
+
+func (b *trieBuilder) insert(key string) {
+	common := 0
+	for common < len(key) && common < len(b.previous) && key[common] == b.previous[common] {
+		common++
+	}
+	b.minimize(common)
+	node := uint32(0)
+	if len(b.unchecked) > 0 {
+		node = b.unchecked[len(b.unchecked)-1].child
+	}
+	for i := common; i < len(key); i++ {
+		child := b.newNode()
+		b.nodes[node].edges = append(b.nodes[node].edges, trieBuilderEdge{label: key[i], target: child})
+		b.unchecked = append(b.unchecked, trieUncheckedEdge{parent: node, child: child})
+		node = child
+	}
+	b.nodes[node].final = true
+	b.previous = key
+}
+
+func (b *trieBuilder) newNode() uint32 {
+	if n := len(b.free); n > 0 {
+		node := b.free[n-1]
+		b.free = b.free[:n-1]
+		return node
+	}
+	b.nodes = append(b.nodes, trieBuilderNode{})
+	return uint32(len(b.nodes) - 1)
+}
+
+// minimize merges or registers the unchecked nodes deeper than depth.
+func (b *trieBuilder) minimize(depth int) {
+	for i := len(b.unchecked) - 1; i >= depth; i-- {
+		edge := b.unchecked[i]
+		signature := b.signature(edge.child)
+		if existing, found := b.register[signature]; found {
+			edges := b.nodes[edge.parent].edges
+			edges[len(edges)-1].target = existing
+			b.nodes[edge.child] = trieBuilderNode{}
+			b.free = append(b.free, edge.child)
+		} else {
+			b.register[signature] = edge.child
+		}
+	}
+	b.unchecked = b.unchecked[:depth]
+}
+
+// signature identifies a node by its finality and outgoing edges; children
+// are already minimal, so equal signatures mean equal sub-automata.
+func (b *trieBuilder) signature(node uint32) string {
+	n := b.nodes[node]
+	sig := make([]byte, 0, 1+len(n.edges)*5)
+	if n.final {
+		sig = append(sig, 1)
+	} else {
+		sig = append(sig, 0)
+	}
+	for _, e := range n.edges {
+		sig = append(sig, e.label, byte(e.target), byte(e.target>>8), byte(e.target>>16), byte(e.target>>24))
+	}
+	return string(sig)
+}
+
+// flatten numbers the reachable nodes and lays out their edges in flat
+// arrays.
+func (b *trieBuilder) flatten(t *deleteTrie) {
+	ids := make(map[uint32]uint32)
+	order := []uint32{0}
+	ids[0] = 0
+	edgeCount := 0
+	for i := 0; i < len(order); i++ {
+		for _, e := range b.nodes[order[i]].edges {
+			edgeCount++
+			if _, seen := ids[e.target]; !seen {
+				ids[e.target] = uint32(len(order))
+				order = append(order, e.target)
+			}
+		}
+	}
+
+	t.firstEdge = make([]uint32, 0, len(order)+1)
+	t.final = make([]bool, len(order))
+	t.labels = make([]byte, 0, edgeCount)
+	t.targets = make([]uint32, 0, edgeCount)
+	for i, node := range order {
+		t.firstEdge = append(t.firstEdge, uint32(len(t.labels)))
+		t.final[i] = b.nodes[node].final
+		for _, e := range b.nodes[node].edges {
+			t.labels = append(t.labels, e.label)
+			t.targets = append(t.targets, ids[e.target])
+		}
+	}
+	t.firstEdge = append(t.firstEdge, uint32(len(t.labels)))
+
+	t.below = make([]uint32, len(order))
+	var count func(n uint32) uint32
+	count = func(n uint32) uint32 {
+		if t.below[n] > 0 {
+			return t.below[n]
+		}
+		below := uint32(0)
+		if t.final[n] {
+			below = 1
+		}
+		for e := t.firstEdge[n]; e < t.firstEdge[n+1]; e++ {
+			below += count(t.targets[e])
+		}
+		t.below[n] = below
+		return below
+	}
+	count(0)
+}
+
+// keepIndex is the identity remap of deleteTrie.deletes.
+func keepIndex(index uint32) (uint32, bool) {
+	return index, true
+}
@@ -0,0 +1,67 @@
+package internal
+
+// deltaMergeMin is the number of delta postings below which the delta index
+// is never merged, so that a few runtime additions do not copy DeletesData.
+const deltaMergeMin = 1 << 16
+
+// addDeletesForIndex records the deletes of a word added at runtime in the
+// delta index. Inserting into the packed DeletesData would shift every
+// posting after the insertion point, so the delta is merged in bulk once it
+// exceeds an eighth of the main index.
+func (s *SymSpell) addDeletesForIndex(key string, index uint32) {
+	if s.disk != nil {
+		s.addDiskWord(key, index)
+		return
+	}
+	if s.deltaIdx == nil {
+		s.deltaIdx = make(map[string][]uint32)
+	}
+	for deleteWord := range s.editsPrefix(key) {
+		s.deltaIdx[deleteWord] = append(s.deltaIdx[deleteWord], index)
+		s.deltaPostings++
+	}
+	if s.deltaPostings > max(deltaMergeMin, len(s.DeletesData)/8) {
+		s.mergeDelta()
+	}
+}
+
+// mergeDelta rebuilds DeletesData with the delta postings appended to the
+// postings of each delete key.
+func (s *SymSpell) mergeDelta() {
+	if len(s.deltaIdx) == 0 {
+		return
+	}
+	switch {
+	case s.mappedDeletes != nil:
+		// the mapped postings are copied into memory with the delta merged in
+		s.hashedDeletes, s.DeletesData = mergePostings(s.mappedDeletes.entries(), s.DeletesData, hashDeleteKeys(s.deltaIdx), s.deltaPostings)
+		s.mappedDeletes = nil
+	case s.trieDeletes != nil:
+		deletes, _ := s.trieDeletes.deletes(s.DeletesData, keepIndex)
+		for del, postings := range s.deltaIdx {
+			deletes[del] = append(deletes[del], postings...)
+		}
+		s.trieDeletes, s.DeletesData = newDeleteTrie(deletes, nil)
+	case s.hashedDeletes != nil:
+		s.hashedDeletes, s.DeletesData = mergePostings(s.hashedDeletes, s.DeletesData, hashDeleteKeys(s.deltaIdx), s.deltaPostings)
+	default:
+		s.DeletesIdx, s.DeletesData = mergePostings(s.DeletesIdx, s.DeletesData, s.deltaIdx, s.deltaPostings)
+	}
+	s.clearDelta()
+	s.buildDeleteFilter()
+}
+
+func (s *SymSpell) clearDelta() {
+	s.deltaIdx = nil
+	s.deltaPostings = 0
+}
+
+// AddWord adds term to a loaded dictionary, or increments its count, without
+// rebuilding the index. It returns true if a new word was added.
+func (s *SymSpell) AddWord(term string, count uint64) (bool, error) {
+	term, err := s.checkUTF8(term)
+	if err != nil {
+		return false, err
+	}
+	return s.CreateDictionaryEntry(term, count), nil
+}
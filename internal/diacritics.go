@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+	verbositypkg "symspell/pkg/verbosity"
+)
+
+// foldDiacritics strips combining marks: "résumé" -> "resume".
+func foldDiacritics(word string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), word)
+	if err != nil {
+		return word
+	}
+	return folded
+}
+
+// buildDiacriticIndex indexes the accent-free forms of all words that carry
+// diacritics. The forms get their own deletes index, so candidates for them
+// are generated exactly like for dictionary words.
+func (s *SymSpell) buildDiacriticIndex() {
+	if !s.ignoreDiacritics {
+		return
+	}
+	s.accentFree = s.newAccentFreeIndex()
+	s.accentOriginals = make(map[string][]uint32)
+	for idx := range s.wordSlots() {
+		word := s.word(uint32(idx))
+		folded := foldDiacritics(word)
+		if folded == word {
+			continue
+		}
+		if _, found := s.accentOriginals[folded]; !found {
+			s.accentFree.appendWord(folded, 1)
+		}
+		s.accentOriginals[folded] = append(s.accentOriginals[folded], uint32(idx))
+	}
+	s.accentFree.buildIndex()
+}
+
+func (s *SymSpell) addDiacriticForIndex(key string, index uint32) {
+	if !s.ignoreDiacritics {
+		return
+	}
+	folded := foldDiacritics(key)
+	if folded == key {
+		return
+	}
+	if s.accentFree == nil {
+		s.accentFree = s.newAccentFreeIndex()
+		s.accentOriginals = make(map[string][]uint32)
+	}
+	if _, found := s.accentOriginals[folded]; !found {
+		s.accentFree.CreateDictionaryEntry(folded, 1)
+	}
+	s.accentOriginals[folded] = append(s.accentOriginals[folded], index)
+}
+
+func (s *SymSpell) newAccentFreeIndex() *SymSpell {
+	accentFree, _ := NewSymSpell(
+		options.WithMaxDictionaryEditDistance(s.MaxDictionaryEditDistance),
+		options.WithPrefixLength(s.PrefixLength),
+	)
+	accentFree.distanceComparer = s.distanceComparer
+	accentFree.customDistance = s.customDistance
+	accentFree.weightedComparer = s.weightedComparer
+	return accentFree
+}
+
+// mergeDiacritics merges into result the words whose accent-free form is
+// close to the accent-free phrase, at the distance between the two forms, and
+// the words close to the accent-free phrase itself.
+func (s *SymSpell) mergeDiacritics(ctx context.Context, phrase string, verbosity verbositypkg.Verbosity, maxEditDistance int, frequencyGate bool, result []items.SuggestItem) []items.SuggestItem {
+	lower := strings.ToLower(phrase)
+	folded := foldDiacritics(lower)
+	merged := false
+	if folded != lower {
+		result, merged = mergeSuggestions(result, s.lookupStaged(ctx, folded, verbosity, maxEditDistance, frequencyGate))
+	}
+	if s.accentFree != nil {
+		var extra []items.SuggestItem
+		for _, form := range s.accentFree.lookupAppend(ctx, nil, folded, verbositypkg.All, maxEditDistance, false) {
+			for _, idx := range s.accentOriginals[form.Term] {
+				if !s.isLiveIndex(idx) {
+					continue
+				}
+				word := s.word(idx)
+				if _, ok := s.blacklist[word]; ok {
+					continue
+				}
+				item := items.SuggestItem{Term: word, Distance: form.Distance, Count: itemCount(s.counts[idx])}
+				if s.SuggestionFilter != nil && !s.SuggestionFilter(item) {
+					continue
+				}
+				extra = append(extra, item)
+			}
+		}
+		var changed bool
+		result, changed = mergeSuggestions(result, extra)
+		merged = merged || changed
+	}
+	if !merged {
+		return result
+	}
+	return s.rankMerged(result, verbosity)
+}
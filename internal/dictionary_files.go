@@ -0,0 +1,45 @@
+package internal
+
+import (
+	"fmt"
+
+	"symspell/pkg/options"
+)
+
+// loadDictionaryFiles loads the dictionaries listed in the options: word
+// dictionaries first, then bigram and exact dictionaries.
+func (s *SymSpell) loadDictionaryFiles(opts options.SymspellOptions) error {
+	for _, file := range opts.Dictionaries {
+		loadOpts := []options.LoadOption{options.WithFrequencyFormat(file.Format)}
+		if file.Weight != 0 {
+			loadOpts = append(loadOpts, options.WithDictionarySourceWeight(file.Weight))
+		}
+		ok, err := s.LoadDictionary(file.Path, file.TermIndex, file.CountIndex, file.Separator, loadOpts...)
+		if err := dictionaryFileError(file.Path, ok, err); err != nil {
+			return err
+		}
+	}
+	for _, file := range opts.BigramDictionaries {
+		ok, err := s.LoadBigramDictionary(file.Path, file.TermIndex, file.CountIndex, file.Separator)
+		if err := dictionaryFileError(file.Path, ok, err); err != nil {
+			return err
+		}
+	}
+	for _, file := range opts.ExactDictionaries {
+		ok, err := s.LoadExactDictionary(file.Path, file.Separator)
+		if err := dictionaryFileError(file.Path, ok, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dictionaryFileError(path string, ok bool, err error) error {
+	if err != nil {
+		return fmt.Errorf("loading %s: %w", path, err)
+	}
+	if !ok {
+		return fmt.Errorf("loading %s failed", path)
+	}
+	return nil
+}
@@ -0,0 +1,335 @@
+package internal
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	bolt "go.etcd.io/bbolt"
+)
+
+// diskStore keeps the dictionary of options.StorageDisk in a bbolt database:
+// every word with its count under its word index, and the postings of every
+// delete key. Lookups read the postings of their candidates from the
+// database, so the deletes index, by far the largest part of a dictionary,
+// is never held in memory. The words and counts are loaded at startup for
+// the features that scan them. Every change is written through.
+type diskStore struct {
+	db *bolt.DB
+}
+
+var (
+	diskMetaBucket    = []byte("meta")
+	diskWordsBucket   = []byte("words")
+	diskDeletesBucket = []byte("deletes")
+	// maxDictionaryEditDistance, prefixLength, maxLength and the number of
+	// word indexes in use, present once the store holds a complete index
+	diskMetaKey = []byte("index")
+)
+
+// diskBuildBatch is the number of words whose deletes are merged into the
+// store per transaction when it is rebuilt.
+const diskBuildBatch = 1 << 14
+
+func openDiskStore(path string) (*diskStore, error) {
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{diskMetaBucket, diskWordsBucket, diskDeletesBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &diskStore{db: db}, nil
+}
+
+// load replaces the words and counts of s with the ones in the store. It
+// reports false, leaving s unchanged, if the store holds no complete index.
+func (d *diskStore) load(s *SymSpell) (bool, error) {
+	loaded := false
+	err := d.db.View(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(diskMetaBucket).Get(diskMetaKey)
+		if meta == nil {
+			return nil
+		}
+		if len(meta) != 16 {
+			return errors.New("corrupt disk store: bad index record")
+		}
+		be := binary.BigEndian
+		maxEditDistance, prefixLength := int(be.Uint32(meta)), int(be.Uint32(meta[4:]))
+		if maxEditDistance != s.MaxDictionaryEditDistance || prefixLength != s.PrefixLength {
+			return fmt.Errorf("index built with maxDictionaryEditDistance=%d prefixLength=%d, instance uses %d and %d",
+				maxEditDistance, prefixLength, s.MaxDictionaryEditDistance, s.PrefixLength)
+		}
+		slots := be.Uint32(meta[12:])
+		words := make([]string, slots)
+		counts := make([]uint64, slots)
+		deleted := make([]bool, slots)
+		for i := range deleted {
+			deleted[i] = true
+		}
+		wordIndex := make(map[string]uint32, slots)
+		c := tx.Bucket(diskWordsBucket).Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			if len(k) != 4 || len(v) < 8 || be.Uint32(k) >= slots {
+				return errors.New("corrupt disk store: bad word record")
+			}
+			idx := be.Uint32(k)
+			words[idx] = string(v[8:])
+			counts[idx] = be.Uint64(v)
+			deleted[idx] = false
+			wordIndex[words[idx]] = idx
+		}
+
+		s.words, s.counts, s.Words, s.arena = words, counts, wordIndex, nil
+		s.deleted, s.deletedCount = nil, int(slots)-len(wordIndex)
+		if s.deletedCount > 0 {
+			s.deleted = deleted
+		}
+		s.maxLength = int(be.Uint32(meta[8:]))
+		loaded = true
+		return nil
+	})
+	return loaded, err
+}
+
+// diskDeleteKey returns the database key of a delete; bbolt does not allow
+// the empty key, which is the delete of every short word.
+func diskDeleteKey(del string) []byte {
+	return append([]byte{0}, del...)
+}
+
+func (d *diskStore) postings(del string) ([]uint32, bool) {
+	var postings []uint32
+	d.db.View(func(tx *bolt.Tx) error {
+		if v := tx.Bucket(diskDeletesBucket).Get(diskDeleteKey(del)); v != nil {
+			postings = make([]uint32, len(v)/4)
+			for i := range postings {
+				postings[i] = binary.LittleEndian.Uint32(v[i*4:])
+			}
+		}
+		return nil
+	})
+	return postings, postings != nil
+}
+
+// eachDelete calls fn with every delete key in the store.
+func (d *diskStore) eachDelete(fn func(del string)) {
+	d.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(diskDeletesBucket).ForEach(func(k, _ []byte) error {
+			fn(string(k[1:]))
+			return nil
+		})
+	})
+}
+
+func (d *diskStore) deleteKeys() int {
+	keys := 0
+	d.db.View(func(tx *bolt.Tx) error {
+		keys = tx.Bucket(diskDeletesBucket).Stats().KeyN
+		return nil
+	})
+	return keys
+}
+
+// addWord stores a word added at runtime and appends its index to the
+// postings of its deletes.
+func (d *diskStore) addWord(s *SymSpell, index uint32, deletes map[string]bool) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		if err := putDiskWord(tx, index, s.word(index), s.counts[index]); err != nil {
+			return err
+		}
+		b := tx.Bucket(diskDeletesBucket)
+		for del := range deletes {
+			key := diskDeleteKey(del)
+			if err := b.Put(key, appendDiskPostings(b.Get(key), index)); err != nil {
+				return err
+			}
+		}
+		return putDiskMeta(tx, s)
+	})
+}
+
+// putCount stores the count of the word at index.
+func (d *diskStore) putCount(s *SymSpell, index uint32) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		return putDiskWord(tx, index, s.word(index), s.counts[index])
+	})
+}
+
+// deleteWord removes the word at index. Its postings stay until the store
+// is rebuilt; the word index is not reused before then.
+func (d *diskStore) deleteWord(index uint32) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(diskWordsBucket).Delete(binary.BigEndian.AppendUint32(nil, index))
+	})
+}
+
+// rewrite replaces the contents of the store with the live words of s and
+// their deletes, merging the deletes of diskBuildBatch words at a time so
+// that the index is never built in memory as a whole. The index record is
+// removed first and written last, so that an interrupted rewrite leaves a
+// store that load ignores.
+func (d *diskStore) rewrite(s *SymSpell) error {
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(diskMetaBucket).Delete(diskMetaKey); err != nil {
+			return err
+		}
+		for _, name := range [][]byte{diskWordsBucket, diskDeletesBucket} {
+			if err := tx.DeleteBucket(name); err != nil {
+				return err
+			}
+			if _, err := tx.CreateBucket(name); err != nil {
+				return err
+			}
+		}
+		for i := range s.wordSlots() {
+			if s.isLiveIndex(uint32(i)) {
+				if err := putDiskWord(tx, uint32(i), s.word(uint32(i)), s.counts[i]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for start := 0; start < s.wordSlots(); start += diskBuildBatch {
+		batch := make(map[string][]uint32)
+		for idx := start; idx < min(start+diskBuildBatch, s.wordSlots()); idx++ {
+			if !s.isLiveIndex(uint32(idx)) {
+				continue
+			}
+			for del := range s.editsPrefix(s.word(uint32(idx))) {
+				batch[del] = append(batch[del], uint32(idx))
+			}
+		}
+		err := d.db.Update(func(tx *bolt.Tx) error {
+			b := tx.Bucket(diskDeletesBucket)
+			for del, postings := range batch {
+				key := diskDeleteKey(del)
+				if err := b.Put(key, appendDiskPostings(b.Get(key), postings...)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return d.db.Update(func(tx *bolt.Tx) error {
+		return putDiskMeta(tx, s)
+	})
+}
+
+func (d *diskStore) close() error {
+	return d.db.Close()
+}
+
+func putDiskWord(tx *bolt.Tx, index uint32, word string, count uint64) error {
+	value := binary.BigEndian.AppendUint64(make([]byte, 0, 8+len(word)), count)
+	return tx.Bucket(diskWordsBucket).Put(binary.BigEndian.AppendUint32(nil, index), append(value, word...))
+}
+
+func putDiskMeta(tx *bolt.Tx, s *SymSpell) error {
+	meta := make([]byte, 0, 16)
+	for _, v := range []int{s.MaxDictionaryEditDistance, s.PrefixLength, s.maxLength, s.wordSlots()} {
+		meta = binary.BigEndian.AppendUint32(meta, uint32(v))
+	}
+	return tx.Bucket(diskMetaBucket).Put(diskMetaKey, meta)
+}
+
+// appendDiskPostings returns a copy of the packed postings with indexes
+// appended; values read from bbolt must not be modified.
+func appendDiskPostings(postings []byte, indexes ...uint32) []byte {
+	merged := make([]byte, len(postings), len(postings)+4*len(indexes))
+	copy(merged, postings)
+	for _, idx := range indexes {
+		merged = binary.LittleEndian.AppendUint32(merged, idx)
+	}
+	return merged
+}
+
+// openDiskStorage opens the store of options.StorageDisk and loads the
+// dictionary it holds. It reports whether there was one.
+func (s *SymSpell) openDiskStorage() (bool, error) {
+	store, err := openDiskStore(s.StoragePath)
+	if err != nil {
+		return false, err
+	}
+	loaded, err := store.load(s)
+	if err != nil {
+		store.close()
+		return false, err
+	}
+	s.disk = store
+	if loaded {
+		s.packWords()
+		s.buildDeleteFilter()
+		s.buildPhoneticIndex()
+		s.buildDiacriticIndex()
+	}
+	return loaded, nil
+}
+
+// rebuildDiskStorage writes the dictionary to the store of
+// options.StorageDisk in place of the deletes index. If that fails the error
+// is logged and the instance falls back to an in-memory index.
+func (s *SymSpell) rebuildDiskStorage() bool {
+	s.DeletesIdx, s.DeletesData = make(map[string]uint64), nil
+	s.hashedDeletes, s.trieDeletes, s.mappedDeletes = nil, nil, nil
+	s.clearDelta()
+	if err := s.disk.rewrite(s); err != nil {
+		s.logger.Error("keeping the deletes index in memory", "path", s.StoragePath, "err", err)
+		s.disk.close()
+		s.disk = nil
+		return false
+	}
+	return true
+}
+
+// addDiskWord writes a word added at runtime through to the store.
+func (s *SymSpell) addDiskWord(key string, index uint32) {
+	deletes := s.editsPrefix(key)
+	if err := s.disk.addWord(s, index, deletes); err != nil {
+		s.logger.Error("writing word to disk storage", "path", s.StoragePath, "word", key, "err", err)
+	}
+	if s.deleteFilter != nil {
+		for del := range deletes {
+			s.deleteFilter.add(xxhash.Sum64String(del))
+		}
+	}
+}
+
+// persistCount writes the count of the word at index through to the store.
+func (s *SymSpell) persistCount(index uint32) {
+	if s.disk == nil {
+		return
+	}
+	if err := s.disk.putCount(s, index); err != nil {
+		s.logger.Error("writing count to disk storage", "path", s.StoragePath, "word", s.word(index), "err", err)
+	}
+}
+
+// Close releases the store of options.StorageDisk. The instance must not be
+// used afterwards. It does nothing for in-memory instances.
+func (s *SymSpell) Close() error {
+	if s.disk == nil {
+		return nil
+	}
+	err := s.disk.close()
+	s.disk = nil
+	return err
+}
@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"context"
+)
+
+// distanceMemo caches the distances computed during one LookupCompound call,
+// where the same pairs come up again for every split of a long token and for
+// repeated words. It is not safe for concurrent use.
+type distanceMemo struct {
+	entries map[[2]string]memoEntry
+	limit   int
+}
+
+type memoEntry struct {
+	distance    int // -1 if above maxDistance
+	maxDistance int
+}
+
+// newDistanceMemo returns a memo holding at most limit pairs, or nil, which
+// computes every distance, if limit is not positive.
+func newDistanceMemo(limit int) *distanceMemo {
+	if limit <= 0 {
+		return nil
+	}
+	return &distanceMemo{entries: make(map[[2]string]memoEntry), limit: limit}
+}
+
+// compare works like SymSpell.distanceCompare. A cached distance answers any
+// maxDistance, a cached cutoff only the same or a smaller one.
+func (m *distanceMemo) compare(s *SymSpell, a, b string, maxDistance int) int {
+	if m == nil {
+		return s.distanceCompare(a, b, maxDistance)
+	}
+	key := [2]string{a, b}
+	entry, found := m.entries[key]
+	if found {
+		switch {
+		case entry.distance >= 0 && entry.distance <= maxDistance:
+			return entry.distance
+		case entry.distance >= 0 || entry.maxDistance >= maxDistance:
+			return -1
+		}
+	}
+	distance := s.distanceCompare(a, b, maxDistance)
+	if found || len(m.entries) < m.limit {
+		m.entries[key] = memoEntry{distance: distance, maxDistance: maxDistance}
+	}
+	return distance
+}
+
+type distanceMemoKey struct{}
+
+// withDistanceMemo attaches memo to the lookups run with the returned context.
+func withDistanceMemo(ctx context.Context, memo *distanceMemo) context.Context {
+	if memo == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, distanceMemoKey{}, memo)
+}
+
+func distanceMemoFrom(ctx context.Context) *distanceMemo {
+	memo, _ := ctx.Value(distanceMemoKey{}).(*distanceMemo)
+	return memo
+}
@@ -0,0 +1,35 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+var (
+	// ErrDistanceTooLarge is returned when a lookup asks for a larger edit
+	// distance than the index was built with.
+	ErrDistanceTooLarge = errors.New("distance too large")
+	// ErrDictionaryNotFound is returned when a dictionary file does not exist.
+	ErrDictionaryNotFound = errors.New("dictionary not found")
+	// ErrInvalidOptions wraps every validation error of instance and per-call options.
+	ErrInvalidOptions = errors.New("invalid options")
+	// ErrFrozen is returned by the updating methods of a frozen snapshot.
+	ErrFrozen = errors.New("instance is frozen")
+	// ErrInvalidUTF8 is wrapped by every InvalidUTF8Error.
+	ErrInvalidUTF8 = errors.New("invalid UTF-8")
+)
+
+// openDictionary opens a dictionary file, reporting a missing file as
+// ErrDictionaryNotFound wrapped together with the underlying *fs.PathError.
+func openDictionary(path string) (*os.File, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %w", ErrDictionaryNotFound, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening dictionary: %w", err)
+	}
+	return file, nil
+}
@@ -0,0 +1,131 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"symspell/pkg/options"
+)
+
+// zipfCount converts a Zipf value to a frequency per billion words.
+func zipfCount(value string) (uint64, error) {
+	zipf, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	return floatCount(math.Round(math.Pow(10, zipf))), nil
+}
+
+// loadSummedFrequencies loads the formats whose counts need the whole file
+// to be read first: counts are summed per term, saturating at the maximum
+// uint64. Nothing is added when ctx is done before the file is read.
+func (s *SymSpell) loadSummedFrequencies(ctx context.Context, corpusStream io.Reader, termIndex, countIndex int, separator string, progress func(linesRead, wordsAdded int), loadOptions options.LoadOptions) (bool, error) {
+	tracker := loadTracker{ctx: ctx, progress: progress}
+	totals := make(map[string]uint64)
+	scanner := s.newLineScanner(corpusStream)
+	for scanner.Scan() {
+		if err := tracker.check(scanner.line, len(totals)); err != nil {
+			return false, err
+		}
+		var term string
+		var count uint64
+		var ok bool
+		if loadOptions.Format == options.FrequencyGoogleBooks {
+			term, count, ok = parseGoogleBooksLine(scanner.Text())
+		} else {
+			var value string
+			term, value, ok = splitDictionaryLine(scanner.Text(), termIndex, countIndex, separator)
+			if ok {
+				var err error
+				count, err = strconv.ParseUint(value, 10, 64)
+				ok = err == nil
+			}
+		}
+		if !ok {
+			continue
+		}
+		term, err := s.checkUTF8(term)
+		if err != nil {
+			return false, fmt.Errorf("line %d: %w", scanner.line, err)
+		}
+		term = s.dictionaryKey(term)
+		total := totals[term] + count
+		if total < count {
+			total = math.MaxUint64
+		}
+		totals[term] = total
+	}
+	if err := scanner.Err(); err != nil {
+		return false, err
+	}
+
+	// Add words in a fixed order so that word indexes are reproducible.
+	words := make([]string, 0, len(totals))
+	for word := range totals {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	for _, word := range words {
+		s.addWordEntry(word, weightCount(totals[word], loadOptions.SourceWeight))
+	}
+	s.buildIndex()
+	tracker.done(scanner.line, len(totals))
+	return true, nil
+}
+
+// parseGoogleBooksLine returns the term and the match count of a Google Books
+// 1-gram line.
+func parseGoogleBooksLine(line string) (string, uint64, bool) {
+	fields := strings.Split(line, "\t")
+	if len(fields) < 2 {
+		return "", 0, false
+	}
+	term := stripPartOfSpeech(fields[0])
+	if term == "" {
+		return "", 0, false
+	}
+	if !strings.Contains(fields[1], ",") {
+		// ngram year match_count volume_count
+		if len(fields) < 3 {
+			return "", 0, false
+		}
+		count, err := strconv.ParseUint(fields[2], 10, 64)
+		return term, count, err == nil
+	}
+	var total uint64
+	for _, yearly := range fields[1:] {
+		parts := strings.Split(yearly, ",")
+		if len(parts) < 2 {
+			return "", 0, false
+		}
+		count, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return "", 0, false
+		}
+		total += count
+	}
+	return term, total, true
+}
+
+// stripPartOfSpeech removes a tag like "_NOUN" from a Google Books ngram.
+// Bare tags like "_NOUN_" give "".
+func stripPartOfSpeech(ngram string) string {
+	if len(ngram) > 1 && strings.HasPrefix(ngram, "_") && strings.HasSuffix(ngram, "_") {
+		return ""
+	}
+	idx := strings.LastIndexByte(ngram, '_')
+	if idx <= 0 {
+		return ngram
+	}
+	tag := ngram[idx+1:]
+	if tag == "" || strings.IndexFunc(tag, func(r rune) bool { return !unicode.IsUpper(r) }) >= 0 {
+		return ngram
+	}
+	return ngram[:idx]
+}
@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"github.com/cespare/xxhash/v2"
+)
+
+// hashDeleteKeys converts a deletes map keyed by strings into one keyed by
+// their xxhash. Postings of colliding keys are merged; lookups verify every
+// posting against the candidate, see isDeleteOf.
+func hashDeleteKeys(deletes map[string][]uint32) map[uint64][]uint32 {
+	hashed := make(map[uint64][]uint32, len(deletes))
+	for del, postings := range deletes {
+		h := xxhash.Sum64String(del)
+		hashed[h] = append(hashed[h], postings...)
+	}
+	return hashed
+}
+
+// hashPostings rekeys a string keyed deletes index by the hashes of its keys.
+func (s *SymSpell) hashPostings() (map[uint64]uint64, []uint32) {
+	deletes := make(map[uint64][]uint32)
+	s.eachDelete(func(del string, offset, length uint32) {
+		h := xxhash.Sum64String(del)
+		deletes[h] = append(deletes[h], s.DeletesData[offset:offset+length]...)
+	})
+	return packPostings(deletes, make([]uint32, 0, len(s.DeletesData)))
+}
+
+// hashedKeys reports whether the deletes index is keyed by hashes, so that
+// its postings may belong to other delete keys.
+func (s *SymSpell) hashedKeys() bool {
+	return s.hashedDeletes != nil || s.mappedDeletes != nil
+}
+
+// isDeleteOf reports whether candidate can be produced by deleting at most
+// MaxDictionaryEditDistance runes from the prefix of word, the way
+// editsPrefix generates delete keys.
+func (s *SymSpell) isDeleteOf(candidate, word string) bool {
+	prefix := []rune(word)
+	if len(prefix) > s.PrefixLength {
+		prefix = prefix[:s.PrefixLength]
+	}
+	deletes := len(prefix)
+	i := 0
+	for _, r := range candidate {
+		for i < len(prefix) && prefix[i] != r {
+			i++
+		}
+		if i == len(prefix) {
+			return false
+		}
+		i++
+		deletes--
+	}
+	return deletes <= s.MaxDictionaryEditDistance
+}
+
+// packPostings lays out the postings of every delete key contiguously.
+func packPostings[K comparable](deletes map[K][]uint32, data []uint32) (map[K]uint64, []uint32) {
+	idx := make(map[K]uint64, len(deletes))
+	for del, postings := range deletes {
+		offset := uint32(len(data))
+		data = append(data, postings...)
+		idx[del] = uint64(offset)<<32 | uint64(len(postings))
+	}
+	return idx, data
+}
+
+// mergePostings appends the delta postings to the postings of each key.
+func mergePostings[K comparable](idx map[K]uint64, data []uint32, delta map[K][]uint32, deltaPostings int) (map[K]uint64, []uint32) {
+	merged := make([]uint32, 0, len(data)+deltaPostings)
+	mergedIdx := make(map[K]uint64, len(idx)+len(delta))
+	for del, v := range idx {
+		offset := uint32(v >> 32)
+		length := uint32(v)
+		start := uint32(len(merged))
+		merged = append(merged, data[offset:offset+length]...)
+		merged = append(merged, delta[del]...)
+		mergedIdx[del] = uint64(start)<<32 | uint64(uint32(len(merged))-start)
+	}
+	for del, postings := range delta {
+		if _, found := idx[del]; found {
+			continue
+		}
+		start := uint32(len(merged))
+		merged = append(merged, postings...)
+		mergedIdx[del] = uint64(start)<<32 | uint64(len(postings))
+	}
+	return mergedIdx, merged
+}
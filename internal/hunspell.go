@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"symspell/pkg/hunspell"
+	"symspell/pkg/options"
+)
+
+// Synthetic counts of Hunspell words: dictionary stems rank above the forms
+// generated from them by affix rules.
+const (
+	hunspellRootCount = 2
+	hunspellFormCount = 1
+)
+
+// LoadHunspell loads a Hunspell dictionary, expanding its affix rules into
+// surface forms, and builds the index.
+func (s *SymSpell) LoadHunspell(dicPath, affPath string, opts ...options.LoadOption) (bool, error) {
+	if dicPath == "" || affPath == "" {
+		return false, errors.New("dictionary and affix paths cannot be empty")
+	}
+	dic, err := openDictionary(dicPath)
+	if err != nil {
+		return false, err
+	}
+	defer dic.Close()
+	aff, err := openDictionary(affPath)
+	if err != nil {
+		return false, err
+	}
+	defer aff.Close()
+	return s.LoadHunspellStream(dic, aff, opts...)
+}
+
+// LoadHunspellStream works like LoadHunspell but reads from readers.
+func (s *SymSpell) LoadHunspellStream(dic, aff io.Reader, opts ...options.LoadOption) (bool, error) {
+	loadOptions := options.LoadOptions{SourceWeight: 1}
+	for _, opt := range opts {
+		opt(&loadOptions)
+	}
+	if !(loadOptions.SourceWeight > 0) {
+		return false, fmt.Errorf("%w: source weight must be positive", ErrInvalidOptions)
+	}
+	dic, closeDic, err := decompress(dic)
+	if err != nil {
+		return false, err
+	}
+	defer closeDic()
+	aff, closeAff, err := decompress(aff)
+	if err != nil {
+		return false, err
+	}
+	defer closeAff()
+	dictionary, err := hunspell.Parse(dic, aff)
+	if err != nil {
+		return false, err
+	}
+
+	counts := make(map[string]uint64)
+	var expandErr error
+	dictionary.Expand(func(form string, root bool) {
+		if expandErr != nil {
+			return
+		}
+		form, expandErr = s.checkUTF8(form)
+		count := uint64(hunspellFormCount)
+		if root {
+			count = hunspellRootCount
+		}
+		form = s.dictionaryKey(form)
+		counts[form] = max(counts[form], count)
+	})
+	if expandErr != nil {
+		return false, expandErr
+	}
+
+	// Add words in a fixed order so that word indexes are reproducible.
+	words := make([]string, 0, len(counts))
+	for word := range counts {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	for _, word := range words {
+		s.addWordEntry(word, weightCount(uint64(counts[word]), loadOptions.SourceWeight))
+	}
+	s.buildIndex()
+	return true, nil
+}
@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"strings"
+	"unicode"
+
+	"symspell/pkg/items"
+)
+
+func (s *SymSpell) isIgnored(token string) bool {
+	for _, classifier := range s.ignoreTokens {
+		if classifier(token) {
+			return true
+		}
+	}
+	return false
+}
+
+// verbatimItem returns phrase unchanged if it is protected or ignored.
+func (s *SymSpell) verbatimItem(phrase string) (items.SuggestItem, bool) {
+	if !s.isVerbatim(phrase) {
+		return items.SuggestItem{}, false
+	}
+	return items.SuggestItem{Term: phrase, Distance: 0, Count: itemCount(s.wordCount(strings.ToLower(phrase)))}, true
+}
+
+func (s *SymSpell) isVerbatim(phrase string) bool {
+	return (len(s.protected) > 0 && s.IsProtected(phrase)) || s.isIgnored(phrase)
+}
+
+// mergeIgnoredFields replaces the words of every whitespace-separated field
+// of phrase that is ignored with a single word holding the field as written.
+// Punctuation around a field, such as a trailing full stop after a URL, is
+// not part of it.
+func (s *SymSpell) mergeIgnoredFields(phrase string, words []string, spans []wordSpan) ([]string, []wordSpan) {
+	if len(s.ignoreTokens) == 0 {
+		return words, spans
+	}
+	var ignored []wordSpan
+	start := -1
+	for i, r := range phrase + " " {
+		if !unicode.IsSpace(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			if field, ok := s.ignoredField(phrase, wordSpan{start: start, end: i}); ok {
+				ignored = append(ignored, field)
+			}
+			start = -1
+		}
+	}
+	if len(ignored) == 0 {
+		return words, spans
+	}
+
+	mergedWords := make([]string, 0, len(words))
+	mergedSpans := make([]wordSpan, 0, len(spans))
+	next := 0
+	for i, span := range spans {
+		for next < len(ignored) && ignored[next].end <= span.start {
+			next++
+		}
+		if next < len(ignored) && span.start >= ignored[next].start && span.end <= ignored[next].end {
+			field := ignored[next]
+			if len(mergedSpans) == 0 || mergedSpans[len(mergedSpans)-1] != field {
+				mergedWords = append(mergedWords, phrase[field.start:field.end])
+				mergedSpans = append(mergedSpans, field)
+			}
+			continue
+		}
+		mergedWords = append(mergedWords, words[i])
+		mergedSpans = append(mergedSpans, span)
+	}
+	return mergedWords, mergedSpans
+}
+
+func (s *SymSpell) ignoredField(phrase string, field wordSpan) (wordSpan, bool) {
+	text := phrase[field.start:field.end]
+	trimmed := strings.TrimLeft(text, "([{\"'«")
+	field.start += len(text) - len(trimmed)
+	text = trimmed
+	trimmed = strings.TrimRight(text, ".,;:!?)]}\"'»")
+	field.end -= len(text) - len(trimmed)
+	if trimmed == "" || !s.isIgnored(trimmed) {
+		return field, false
+	}
+	return field, true
+}
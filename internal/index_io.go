@@ -0,0 +1,289 @@
+package internal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"symspell/pkg/options"
+)
+
+var indexMagic = [4]byte{'S', 'Y', 'M', 'I'}
+
+const indexVersion = 1
+
+// SaveIndex writes the words, counts and deletes index in a compact versioned
+// binary format so that LoadIndex can restore it without rebuilding. Pending
+// deletions are compacted and runtime additions merged first. Instances with
+// hashed delete keys no longer have the keys and must use SaveMappedIndex.
+func (s *SymSpell) SaveIndex(w io.Writer) error {
+	if s.disk != nil {
+		return s.Clone().SaveIndex(w)
+	}
+	if s.hashDeletes {
+		return errors.New("writing index: hashed delete keys are only supported by SaveMappedIndex")
+	}
+	if s.mappedDeletes != nil || s.hashedDeletes != nil {
+		// the delete keys of a mapped index are only known by hash
+		s.buildIndex()
+	}
+	if s.deletedCount > 0 {
+		s.Compact()
+	}
+	s.mergeDelta()
+	bw := bufio.NewWriter(w)
+	iw := indexWriter{w: bw}
+	iw.bytes(indexMagic[:])
+	iw.uvarint(indexVersion)
+	iw.uvarint(uint64(s.MaxDictionaryEditDistance))
+	iw.uvarint(uint64(s.PrefixLength))
+	iw.uvarint(uint64(s.maxLength))
+
+	iw.uvarint(uint64(s.wordSlots()))
+	for i := range s.wordSlots() {
+		iw.string(s.word(uint32(i)))
+		iw.uvarint(s.counts[i])
+	}
+	iw.uvarint(uint64(len(s.DeletesData)))
+	for _, idx := range s.DeletesData {
+		iw.uvarint(uint64(idx))
+	}
+	iw.uvarint(uint64(s.deleteKeys()))
+	s.eachDelete(func(del string, offset, length uint32) {
+		iw.string(del)
+		iw.uvarint(uint64(offset))
+		iw.uvarint(uint64(length))
+	})
+	if iw.err == nil {
+		iw.err = bw.Flush()
+	}
+	if iw.err != nil {
+		return fmt.Errorf("writing index: %w", iw.err)
+	}
+	return nil
+}
+
+// LoadIndex replaces the dictionary with an index written by SaveIndex. The
+// index must have been built with the same MaxDictionaryEditDistance and
+// PrefixLength as this instance.
+func (s *SymSpell) LoadIndex(r io.Reader) error {
+	ir := newIndexReader(r)
+	var magic [4]byte
+	ir.bytes(magic[:])
+	if ir.err == nil && magic != indexMagic {
+		return errors.New("not a symspell index")
+	}
+	if version := ir.uvarint(); ir.err == nil && version != indexVersion {
+		return fmt.Errorf("unsupported index version %d", version)
+	}
+	maxEditDistance := int(ir.uvarint())
+	prefixLength := int(ir.uvarint())
+	if ir.err == nil && (maxEditDistance != s.MaxDictionaryEditDistance || prefixLength != s.PrefixLength) {
+		return fmt.Errorf("index built with maxDictionaryEditDistance=%d prefixLength=%d, instance uses %d and %d",
+			maxEditDistance, prefixLength, s.MaxDictionaryEditDistance, s.PrefixLength)
+	}
+	maxLength := int(ir.uvarint())
+
+	wordCount := ir.length(2) // length of the word and its count
+	words := make([]string, 0, ir.capacity(wordCount))
+	counts := make([]uint64, 0, ir.capacity(wordCount))
+	for i := 0; i < wordCount && ir.err == nil; i++ {
+		words = append(words, ir.string())
+		counts = append(counts, ir.uvarint())
+	}
+	dataLength := ir.length(1)
+	data := make([]uint32, 0, ir.capacity(dataLength))
+	for i := 0; i < dataLength && ir.err == nil; i++ {
+		idx := ir.uvarint()
+		if idx >= uint64(wordCount) {
+			return errors.New("corrupt index: posting out of range")
+		}
+		data = append(data, uint32(idx))
+	}
+	keyCount := ir.length(3) // length of the key, offset and length
+	deletes := make(map[string]uint64, ir.capacity(keyCount))
+	for i := 0; i < keyCount && ir.err == nil; i++ {
+		del := ir.string()
+		offset, length := ir.uvarint(), ir.uvarint()
+		if offset+length > uint64(dataLength) {
+			return errors.New("corrupt index: postings out of range")
+		}
+		deletes[del] = offset<<32 | length
+	}
+	if ir.err != nil {
+		return fmt.Errorf("reading index: %w", ir.err)
+	}
+
+	s.replaceIndex(words, counts, data, deletes, maxLength)
+	s.packWords()
+	if s.disk != nil {
+		s.buildIndex()
+	}
+	return nil
+}
+
+// replaceIndex swaps in a loaded dictionary and deletes index.
+func (s *SymSpell) replaceIndex(words []string, counts []uint64, data []uint32, deletes map[string]uint64, maxLength int) {
+	s.words = words
+	s.arena = nil
+	s.counts = counts
+	s.Words = make(map[string]uint32, len(words))
+	for i, word := range words {
+		s.Words[word] = uint32(i)
+	}
+	s.DeletesData = data
+	s.DeletesIdx = deletes
+	s.mappedDeletes = nil
+	s.hashedDeletes = nil
+	s.trieDeletes = nil
+	if len(deletes) > 0 {
+		switch {
+		case s.IndexBackend == options.IndexBackendTrie:
+			trieDeletes := make(map[string][]uint32, len(deletes))
+			s.eachDelete(func(del string, offset, length uint32) {
+				trieDeletes[del] = data[offset : offset+length]
+			})
+			s.trieDeletes, s.DeletesData = newDeleteTrie(trieDeletes, nil)
+			s.DeletesIdx = make(map[string]uint64)
+		case s.hashDeletes:
+			s.hashedDeletes, s.DeletesData = s.hashPostings()
+			s.DeletesIdx = make(map[string]uint64)
+		}
+	}
+	s.clearDelta()
+	s.buildDeleteFilter()
+	s.maxLength = maxLength
+	s.deleted = nil
+	s.deletedCount = 0
+	s.byFrequency, s.byTerm = nil, nil
+	s.syncUserWords()
+	s.buildPhoneticIndex()
+	s.buildDiacriticIndex()
+	s.topCache.Clear()
+}
+
+type indexWriter struct {
+	w   *bufio.Writer
+	buf [binary.MaxVarintLen64]byte
+	err error
+}
+
+func (iw *indexWriter) bytes(b []byte) {
+	if iw.err == nil {
+		_, iw.err = iw.w.Write(b)
+	}
+}
+
+func (iw *indexWriter) uvarint(v uint64) {
+	n := binary.PutUvarint(iw.buf[:], v)
+	iw.bytes(iw.buf[:n])
+}
+
+func (iw *indexWriter) string(v string) {
+	iw.uvarint(uint64(len(v)))
+	if iw.err == nil {
+		_, iw.err = iw.w.WriteString(v)
+	}
+}
+
+// maxIndexPrealloc caps the capacity preallocated for a length read from an
+// input of unknown size, so that a corrupt length cannot exhaust memory
+// before the input runs out.
+const maxIndexPrealloc = 1 << 16
+
+type indexReader struct {
+	r    *bufio.Reader
+	size int64 // input size, -1 if unknown
+	read int64
+	err  error
+}
+
+// newIndexReader reads r, taking the input size from readers that report the
+// unread length or can seek.
+func newIndexReader(r io.Reader) *indexReader {
+	ir := &indexReader{r: bufio.NewReader(r), size: -1}
+	switch r := r.(type) {
+	case interface{ Len() int }:
+		ir.size = int64(r.Len())
+	case io.Seeker:
+		if cur, err := r.Seek(0, io.SeekCurrent); err == nil {
+			if end, err := r.Seek(0, io.SeekEnd); err == nil {
+				if _, err := r.Seek(cur, io.SeekStart); err == nil {
+					ir.size = end - cur
+				}
+			}
+		}
+	}
+	return ir
+}
+
+func (ir *indexReader) ReadByte() (byte, error) {
+	b, err := ir.r.ReadByte()
+	if err == nil {
+		ir.read++
+	}
+	return b, err
+}
+
+func (ir *indexReader) bytes(b []byte) {
+	if ir.err == nil {
+		var n int
+		n, ir.err = io.ReadFull(ir.r, b)
+		ir.read += int64(n)
+	}
+}
+
+func (ir *indexReader) uvarint() uint64 {
+	if ir.err != nil {
+		return 0
+	}
+	var v uint64
+	v, ir.err = binary.ReadUvarint(ir)
+	return v
+}
+
+// length reads a collection length, rejecting values that cannot be indexed
+// by uint32 postings or whose elements, of at least minSize bytes each, do
+// not fit in the rest of the input.
+func (ir *indexReader) length(minSize int) int {
+	v := ir.uvarint()
+	if ir.err == nil && v > uint64(maxUint32) {
+		ir.err = errors.New("corrupt index: length out of range")
+	}
+	if ir.err == nil && ir.size >= 0 && v*uint64(minSize) > uint64(ir.size-ir.read) {
+		ir.err = errors.New("corrupt index: length exceeds the input")
+	}
+	if ir.err != nil {
+		return 0
+	}
+	return int(v)
+}
+
+// capacity returns the capacity to preallocate for n elements.
+func (ir *indexReader) capacity(n int) int {
+	if ir.size < 0 {
+		return min(n, maxIndexPrealloc)
+	}
+	return n
+}
+
+func (ir *indexReader) string() string {
+	n := ir.length(1)
+	if ir.err != nil {
+		return ""
+	}
+	if n <= ir.capacity(n) {
+		b := make([]byte, n)
+		ir.bytes(b)
+		return string(b)
+	}
+	b, err := io.ReadAll(io.LimitReader(ir.r, int64(n)))
+	ir.read += int64(len(b))
+	if err == nil && len(b) < n {
+		err = io.ErrUnexpectedEOF
+	}
+	ir.err = err
+	return string(b)
+}
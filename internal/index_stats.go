@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"symspell/pkg/stats"
+)
+
+const (
+	stringHeaderBytes = 16
+	sliceHeaderBytes  = 24
+)
+
+// Stats reports the size of the dictionary and the deletes index.
+func (s *SymSpell) Stats() stats.IndexStats {
+	result := stats.IndexStats{
+		Words:            s.liveWords(),
+		DeleteKeys:       s.deleteKeys(),
+		Postings:         len(s.DeletesData),
+		DeltaPostings:    s.deltaPostings,
+		MaxWordLength:    s.maxLength,
+		TopCacheSize:     s.topCache.Len(),
+		TopCacheCapacity: s.topCache.capacity,
+	}
+
+	size := cap(s.words)*stringHeaderBytes + cap(s.counts)*4 + cap(s.deleted) + cap(s.DeletesData)*4
+	for _, word := range s.words {
+		size += len(word)
+	}
+	// map keys share the backing arrays of words
+	size += mapBytes(len(s.Words), stringHeaderBytes+4)
+	if s.arena != nil {
+		size += cap(s.arena.bytes) + cap(s.arena.refs)*8 + cap(s.arena.slots)*4
+	}
+	size += mapBytes(len(s.BelowThresholdWords), stringHeaderBytes+4)
+	for term := range s.BelowThresholdWords {
+		size += len(term)
+	}
+	size += mapBytes(len(s.DeletesIdx), stringHeaderBytes+8)
+	for del := range s.DeletesIdx {
+		size += len(del)
+	}
+	size += mapBytes(len(s.hashedDeletes), 8+8)
+	if s.trieDeletes != nil {
+		size += s.trieDeletes.bytes()
+	}
+	if s.deleteFilter != nil {
+		size += s.deleteFilter.bytes()
+	}
+	size += mapBytes(len(s.deltaIdx), stringHeaderBytes+sliceHeaderBytes) + s.deltaPostings*4
+	for del := range s.deltaIdx {
+		if _, found := s.DeletesIdx[del]; !found {
+			size += len(del)
+		}
+	}
+	size += mapBytes(len(s.phoneticIdx), stringHeaderBytes+sliceHeaderBytes)
+	for code, postings := range s.phoneticIdx {
+		size += len(code) + cap(postings)*4
+	}
+	result.EstimatedBytes = size
+	return result
+}
+
+// InvalidateCache drops all cached lookup results. Changes made through
+// SymSpell methods invalidate the cache on their own; this is for state the
+// results depend on that changes elsewhere, like a ContextScorer or Ranker.
+func (s *SymSpell) InvalidateCache() {
+	s.topCache.Clear()
+}
+
+// deleteKeys returns the number of delete keys in the deletes index.
+func (s *SymSpell) deleteKeys() int {
+	switch {
+	case s.disk != nil:
+		return s.disk.deleteKeys()
+	case s.mappedDeletes != nil:
+		return s.mappedDeletes.keys
+	case s.trieDeletes != nil:
+		return s.trieDeletes.keys()
+	case s.hashedDeletes != nil:
+		return len(s.hashedDeletes)
+	}
+	return len(s.DeletesIdx)
+}
+
+// CacheStats reports hits and misses of the Top lookup cache. Unlike Stats it
+// is cheap enough to be polled on every metrics scrape.
+func (s *SymSpell) CacheStats() stats.CacheStats {
+	return stats.CacheStats{
+		Hits:     s.topCache.hits.Load(),
+		Misses:   s.topCache.misses.Load(),
+		Size:     s.topCache.Len(),
+		Capacity: s.topCache.capacity,
+	}
+}
+
+// mapBytes estimates the memory of a Go map with n entries of entryBytes,
+// assuming slots are three quarters full and carry a byte of hash metadata.
+func mapBytes(n, entryBytes int) int {
+	return n * (entryBytes + 1) * 8 / 6
+}
@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"slices"
+	"strings"
+
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+	verbositypkg "symspell/pkg/verbosity"
+)
+
+// sequenceScore returns the language model score of the parts of a compound
+// answer followed by terms. Parts and terms may hold several words each.
+func (s *SymSpell) sequenceScore(parts []items.SuggestItem, terms ...string) float64 {
+	tokens := make([]string, 0, len(parts)+len(terms)+1)
+	for _, part := range parts {
+		tokens = append(tokens, strings.Fields(part.Term)...)
+	}
+	for _, term := range terms {
+		tokens = append(tokens, strings.Fields(term)...)
+	}
+	return s.extendScore(nil, 0, tokens...)
+}
+
+// closestInContext looks up a word of a compound phrase and returns the
+// suggestion at the smallest edit distance that the language model scores
+// highest after the answer so far.
+func (s *SymSpell) closestInContext(cp *compoundProcessor, term string, maxEditDistance int) []items.SuggestItem {
+	suggestions, _ := s.lookupCached(cp.ctx, term, verbositypkg.Closest, maxEditDistance)
+	if len(suggestions) < 2 {
+		return suggestions
+	}
+	best, bestScore := suggestions[0], s.sequenceScore(cp.suggestionParts, suggestions[0].Term)
+	for _, suggestion := range suggestions[1:] {
+		if score := s.sequenceScore(cp.suggestionParts, suggestion.Term); score > bestScore {
+			best, bestScore = suggestion, score
+		}
+	}
+	return []items.SuggestItem{best}
+}
+
+// compoundRanksBefore reports whether a should replace b as the correction
+// following the answer so far. Both are at the same edit distance.
+func (s *SymSpell) compoundRanksBefore(cp *compoundProcessor, a, b items.SuggestItem) bool {
+	if s.languageModel == nil {
+		return s.ranksBefore(a, b)
+	}
+	return s.sequenceScore(cp.suggestionParts, a.Term) > s.sequenceScore(cp.suggestionParts, b.Term)
+}
+
+// combinationMorePlausible reports whether the merged correction combined is
+// more likely than best1 followed by best2, the last part of the answer and the
+// correction of the current word, at equal edit distance.
+func (s *SymSpell) combinationMorePlausible(cp *compoundProcessor, best1, best2, combined items.SuggestItem) bool {
+	if s.languageModel == nil {
+		return float64(combined.Count) > (float64(best1.Count)/s.N)*float64(best2.Count)
+	}
+	before := cp.suggestionParts[:len(cp.suggestionParts)-1]
+	return s.sequenceScore(before, combined.Term) > s.sequenceScore(before, best1.Term, best2.Term)
+}
+
+// extendScore returns the language model score of tokens followed by words,
+// where score is the score of tokens. An IncrementalLanguageModel scores only
+// the new words.
+func (s *SymSpell) extendScore(tokens []string, score float64, words ...string) float64 {
+	tokens = slices.Clip(tokens)
+	model, ok := s.languageModel.(options.IncrementalLanguageModel)
+	if !ok {
+		return s.languageModel.ScoreSequence(append(tokens, words...))
+	}
+	for _, word := range words {
+		score += model.ScoreNext(tokens, word)
+		tokens = append(tokens, word)
+	}
+	return score
+}
+
+// segmentation is a WordSegmentation candidate. With a language model it also
+// holds the words of the corrected string, so that extending it does not
+// split the string again; with bigrams it holds the last word.
+type segmentation struct {
+	items.Composition
+	words []string
+	last  string
+}
+
+// segmentLogProb returns LogProbSum of prev extended by seg. prev is nil for
+// the first segment.
+func (s *SymSpell) segmentLogProb(prev *segmentation, seg segment) float64 {
+	if s.languageModel == nil {
+		if len(s.Bigrams) > 0 {
+			return s.segmentBigramLogProb(prev, seg)
+		}
+		if prev == nil {
+			return seg.logProb
+		}
+		return prev.LogProbSum + seg.logProb
+	}
+	if prev == nil {
+		return s.extendScore(nil, 0, strings.Fields(seg.corrected)...)
+	}
+	return s.extendScore(prev.words, prev.LogProbSum, strings.Fields(seg.corrected)...)
+}
+
+// segmentBigramLogProb scores the words of seg after the last word of prev
+// with wordLogProb, the bigram model with backoff of the beam search.
+func (s *SymSpell) segmentBigramLogProb(prev *segmentation, seg segment) float64 {
+	logProb, last := 0.0, ""
+	if prev != nil {
+		logProb, last = prev.LogProbSum, prev.last
+	}
+	for _, word := range strings.Fields(seg.corrected) {
+		logProb += s.wordLogProb(last, word)
+		last = word
+	}
+	return logProb
+}
@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+)
+
+// newLayoutMaps builds a rune mapping per layout pair, covering both cases
+// of letters.
+func newLayoutMaps(pairs []options.LayoutPair) ([]map[rune]rune, error) {
+	maps := make([]map[rune]rune, len(pairs))
+	for i, pair := range pairs {
+		if utf8.RuneCountInString(pair.From) != utf8.RuneCountInString(pair.To) {
+			return nil, fmt.Errorf("%w: layout pair %q -> %q differs in length", ErrInvalidOptions, pair.From, pair.To)
+		}
+		m := make(map[rune]rune, 2*len(pair.From))
+		to := []rune(pair.To)
+		j := 0
+		for _, r := range pair.From {
+			m[r] = to[j]
+			m[unicode.ToUpper(r)] = unicode.ToUpper(to[j])
+			j++
+		}
+		maps[i] = m
+	}
+	return maps, nil
+}
+
+// layoutSwitchItem returns the dictionary word that phrase, an unknown word,
+// spells when converted through one of the layout pairs.
+func (s *SymSpell) layoutSwitchItem(phrase string) (items.SuggestItem, bool) {
+	if len(s.layouts) == 0 {
+		return items.SuggestItem{}, false
+	}
+	lower := strings.ToLower(phrase)
+	if _, found := s.wordIndex(lower); found {
+		return items.SuggestItem{}, false
+	}
+	for _, layout := range s.layouts {
+		converted := strings.Map(func(r rune) rune {
+			if to, ok := layout[r]; ok {
+				return to
+			}
+			return r
+		}, lower)
+		if converted == lower {
+			continue
+		}
+		if idx, found := s.wordIndex(converted); found {
+			return items.SuggestItem{Term: converted, Distance: 0, Count: itemCount(s.counts[idx])}, true
+		}
+	}
+	return items.SuggestItem{}, false
+}
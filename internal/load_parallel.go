@@ -0,0 +1,133 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// loadBatchSize is the number of lines a load worker parses at a time.
+const loadBatchSize = 1024
+
+type lineBatch struct {
+	seq       int
+	firstLine int
+	lines     []string
+}
+
+type parsedBatch struct {
+	seq       int
+	firstLine int
+	entries   []dictionaryLine
+	err       error
+	errLine   int
+}
+
+// loadDictionaryParallel reads lines on one goroutine, parses batches of them
+// on LoadWorkers goroutines and adds the words on the calling goroutine in
+// input order, so word indexes and error reporting match a sequential load.
+func (s *SymSpell) loadDictionaryParallel(scanner *lineScanner, parse func(string) (dictionaryLine, error), tracker loadTracker) (bool, error) {
+	ctx, cancel := context.WithCancel(tracker.ctx)
+	defer cancel()
+
+	batches := make(chan lineBatch, s.LoadWorkers)
+	go func() {
+		defer close(batches)
+		seq := 0
+		batch := lineBatch{firstLine: 1}
+		for scanner.Scan() {
+			batch.lines = append(batch.lines, scanner.Text())
+			if len(batch.lines) < loadBatchSize {
+				continue
+			}
+			select {
+			case batches <- batch:
+			case <-ctx.Done():
+				return
+			}
+			seq++
+			batch = lineBatch{seq: seq, firstLine: scanner.line + 1, lines: make([]string, 0, loadBatchSize)}
+		}
+		if len(batch.lines) > 0 {
+			select {
+			case batches <- batch:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	results := make(chan parsedBatch, s.LoadWorkers)
+	var wg sync.WaitGroup
+	for range s.LoadWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				parsed := parsedBatch{seq: batch.seq, firstLine: batch.firstLine, entries: make([]dictionaryLine, len(batch.lines))}
+				for i, line := range batch.lines {
+					entry, err := parse(line)
+					if err != nil {
+						parsed.err, parsed.errLine = err, batch.firstLine+i
+						parsed.entries = parsed.entries[:i]
+						break
+					}
+					parsed.entries[i] = entry
+				}
+				select {
+				case results <- parsed:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// abort stops the pipeline and waits for its goroutines to exit.
+	abort := func() {
+		cancel()
+		for range results {
+		}
+	}
+	pending := make(map[int]parsedBatch)
+	next, wordsAdded := 0, 0
+	for batch := range results {
+		pending[batch.seq] = batch
+		for {
+			batch, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			for i, entry := range batch.entries {
+				if err := tracker.check(batch.firstLine+i, wordsAdded); err != nil {
+					abort()
+					s.buildIndex()
+					return false, err
+				}
+				if entry.ok && s.addWordEntry(entry.term, entry.count) {
+					wordsAdded++
+				}
+			}
+			if batch.err != nil {
+				abort()
+				return false, fmt.Errorf("line %d: %w", batch.errLine, batch.err)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return false, err
+	}
+	if err := tracker.ctx.Err(); err != nil {
+		s.buildIndex()
+		return false, err
+	}
+	s.buildIndex()
+	tracker.done(scanner.line, wordsAdded)
+	return true, nil
+}
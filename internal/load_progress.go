@@ -0,0 +1,32 @@
+package internal
+
+import "context"
+
+// loadCheckInterval is the number of lines between progress reports and
+// cancellation checks of a dictionary load.
+const loadCheckInterval = 4096
+
+// loadTracker reports the progress of a dictionary load and its cancellation.
+type loadTracker struct {
+	ctx      context.Context
+	progress func(linesRead, wordsAdded int)
+}
+
+// check reports progress and returns ctx.Err() every loadCheckInterval lines,
+// and checks ctx on the first line too so a done ctx reads nothing.
+func (t loadTracker) check(linesRead, wordsAdded int) error {
+	report := linesRead%loadCheckInterval == 0
+	if !report && linesRead != 1 {
+		return nil
+	}
+	if report && t.progress != nil {
+		t.progress(linesRead, wordsAdded)
+	}
+	return t.ctx.Err()
+}
+
+func (t loadTracker) done(linesRead, wordsAdded int) {
+	if t.progress != nil {
+		t.progress(linesRead, wordsAdded)
+	}
+}
@@ -0,0 +1,25 @@
+package internal
+
+import (
+	"math"
+
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+)
+
+// logProbRanker ranks suggestions by log10(count/N) minus editPenalty per
+// edit, see options.WithLogProbRanking. N is the same for every suggestion,
+// so it is left out of the comparison.
+func logProbRanker(editPenalty float64) options.Ranker {
+	return func(a, b items.SuggestItem) bool {
+		scoreA := math.Log10(float64(a.Count)) - editPenalty*float64(a.Distance)
+		scoreB := math.Log10(float64(b.Count)) - editPenalty*float64(b.Distance)
+		if scoreA != scoreB {
+			return scoreA > scoreB
+		}
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		return a.Count > b.Count
+	}
+}
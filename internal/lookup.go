@@ -1,13 +1,16 @@
 package internal
 
 import (
-	"errors"
+	"context"
+	"log/slog"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
 	"unicode/utf8"
 
 	"symspell/pkg/items"
+	"symspell/pkg/options"
 	verbositypkg "symspell/pkg/verbosity"
 )
 
@@ -16,54 +19,304 @@ func (s *SymSpell) Lookup(
 	verbosity verbositypkg.Verbosity,
 	maxEditDistance int,
 ) ([]items.SuggestItem, error) {
+	return s.LookupContext(context.Background(), phrase, verbosity, maxEditDistance)
+}
+
+// LookupContext works like Lookup but aborts candidate processing and returns
+// ctx.Err() once ctx is done.
+func (s *SymSpell) LookupContext(
+	ctx context.Context,
+	phrase string,
+	verbosity verbositypkg.Verbosity,
+	maxEditDistance int,
+) ([]items.SuggestItem, error) {
+	return s.lookupTerm(ctx, phrase, verbosity, maxEditDistance, true)
+}
+
+// LookupAppend works like Lookup but appends the suggestions to dst and
+// returns the extended slice, so that loops over many tokens can reuse one
+// buffer instead of allocating a result per call. On error dst is returned
+// unchanged.
+func (s *SymSpell) LookupAppend(
+	dst []items.SuggestItem,
+	phrase string,
+	verbosity verbositypkg.Verbosity,
+	maxEditDistance int,
+) ([]items.SuggestItem, error) {
+	return s.lookupTermAppend(context.Background(), dst, phrase, verbosity, maxEditDistance, true)
+}
+
+// lookupTerm applies the word-level policies of the public lookups (minimum
+// length, case transfer, unknown words) around lookupChecked.
+func (s *SymSpell) lookupTerm(
+	ctx context.Context,
+	phrase string,
+	verbosity verbositypkg.Verbosity,
+	maxEditDistance int,
+	frequencyGate bool,
+) ([]items.SuggestItem, error) {
+	result, err := s.lookupTermAppend(ctx, nil, phrase, verbosity, maxEditDistance, frequencyGate)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *SymSpell) lookupTermAppend(
+	ctx context.Context,
+	dst []items.SuggestItem,
+	phrase string,
+	verbosity verbositypkg.Verbosity,
+	maxEditDistance int,
+	frequencyGate bool,
+) ([]items.SuggestItem, error) {
+	// Empty input has no suggestions; words shorter than MinimumCharToChange
+	// are never corrected.
+	if phrase == "" {
+		s.countLookup()
+		return dst, nil
+	}
+	if runeLen(phrase) < s.MinimumCharToChange && maxEditDistance <= s.MaxDictionaryEditDistance {
+		s.countLookup()
+		return append(dst, items.SuggestItem{Term: phrase, Distance: 0, Count: itemCount(s.wordCount(phrase))}), nil
+	}
+	n := len(dst)
+	term := phrase
+	var cm caseMapping
+	if s.PreserveCase {
+		cm = s.caseMapping()
+		term = cm.lower(phrase)
+	}
+	dst, err := s.lookupCheckedAppend(ctx, dst, term, verbosity, maxEditDistance, frequencyGate)
+	if err != nil {
+		return dst, err
+	}
+	if s.PreserveCase {
+		for i := n; i < len(dst); i++ {
+			dst[i].Term = cm.transferCasing(phrase, dst[i].Term)
+		}
+	}
+	if len(dst) == n && s.IncludeUnknown && suggestionSinkFrom(ctx).empty() {
+		dst = append(dst, items.SuggestItem{Term: phrase, Distance: maxEditDistance + 1, Count: 0})
+	}
+	return dst, nil
+}
+
+// lookupCached is the lookup used by the compound and segmentation
+// algorithms: it consults the Top cache but never reports unknown words.
+func (s *SymSpell) lookupCached(
+	ctx context.Context,
+	phrase string,
+	verbosity verbositypkg.Verbosity,
+	maxEditDistance int,
+) ([]items.SuggestItem, error) {
+	return s.lookupChecked(ctx, phrase, verbosity, maxEditDistance, true)
+}
+
+// lookupChecked validates the input and runs the lookup. The Top cache only
+// holds results computed with the frequency gate enabled.
+func (s *SymSpell) lookupChecked(
+	ctx context.Context,
+	phrase string,
+	verbosity verbositypkg.Verbosity,
+	maxEditDistance int,
+	frequencyGate bool,
+) ([]items.SuggestItem, error) {
+	result, err := s.lookupCheckedAppend(ctx, nil, phrase, verbosity, maxEditDistance, frequencyGate)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *SymSpell) lookupCheckedAppend(
+	ctx context.Context,
+	dst []items.SuggestItem,
+	phrase string,
+	verbosity verbositypkg.Verbosity,
+	maxEditDistance int,
+	frequencyGate bool,
+) ([]items.SuggestItem, error) {
+	if err := ctx.Err(); err != nil {
+		return dst, err
+	}
 	if maxEditDistance > s.MaxDictionaryEditDistance {
-		return nil, errors.New("distance too large")
+		return dst, ErrDistanceTooLarge
+	}
+	phrase, err := s.checkUTF8(phrase)
+	if err != nil {
+		return dst, err
+	}
+	if item, ok := s.verbatimItem(phrase); ok {
+		s.countLookup()
+		return append(dst, item), nil
+	}
+	if item, ok := s.layoutSwitchItem(phrase); ok {
+		s.countLookup()
+		return append(dst, item), nil
+	}
+	if maxEditDistance > 1 && runeLen(phrase) < s.ShortWordLength {
+		maxEditDistance = 1
+	}
+	cacheable := verbosity == verbositypkg.Top && frequencyGate && s.topCache.enabled()
+	key := cacheKey{phrase: phrase, verbosity: verbosity, maxEditDistance: maxEditDistance}
+	if cacheable {
+		if item, ok := s.topCache.Get(key); ok {
+			s.countLookup()
+			return append(dst, item), nil
+		}
+	}
+
+	n := len(dst)
+	dst = s.lookupShared(ctx, dst, phrase, verbosity, maxEditDistance, frequencyGate)
+	if err := ctx.Err(); err != nil {
+		return dst[:n], err
 	}
+	if item, ok := s.compoundSplitItem(phrase, dst[n:]); ok {
+		if verbosity == verbositypkg.All {
+			dst = slices.Insert(dst, n, item)
+		} else {
+			dst = append(dst[:n], item)
+		}
+	}
+	if cacheable && len(dst) > n {
+		s.topCache.Add(key, dst[n])
+	}
+	return dst, nil
+}
+
+// lookupSuggestions computes the suggestions for a validated phrase and
+// appends them to dst.
+func (s *SymSpell) lookupSuggestions(ctx context.Context, dst []items.SuggestItem, phrase string, verbosity verbositypkg.Verbosity, maxEditDistance int, frequencyGate bool) []items.SuggestItem {
+	if !s.ignoreDiacritics && len(s.transliterators) == 0 {
+		return s.lookupStagedAppend(ctx, dst, phrase, verbosity, maxEditDistance, frequencyGate)
+	}
+	result := s.lookupStaged(ctx, phrase, verbosity, maxEditDistance, frequencyGate)
+	if s.ignoreDiacritics {
+		result = s.mergeDiacritics(ctx, phrase, verbosity, maxEditDistance, frequencyGate, result)
+	}
+	if len(s.transliterators) > 0 {
+		result = s.mergeTransliterations(ctx, phrase, verbosity, maxEditDistance, frequencyGate, result)
+	}
+	return append(dst, result...)
+}
+
+// lookupStaged runs a cheap coarse pass first when an escalation policy is
+// configured and only repeats the lookup with maxEditDistance if the policy
+// asks for it.
+func (s *SymSpell) lookupStaged(ctx context.Context, phrase string, verbosity verbositypkg.Verbosity, maxEditDistance int, frequencyGate bool) []items.SuggestItem {
+	return s.lookupStagedAppend(ctx, nil, phrase, verbosity, maxEditDistance, frequencyGate)
+}
+
+func (s *SymSpell) lookupStagedAppend(ctx context.Context, dst []items.SuggestItem, phrase string, verbosity verbositypkg.Verbosity, maxEditDistance int, frequencyGate bool) []items.SuggestItem {
+	if s.EscalationPolicy != nil && maxEditDistance > s.CoarseEditDistance {
+		n := len(dst)
+		dst = s.rankedAppend(ctx, dst, phrase, verbosity, s.CoarseEditDistance, frequencyGate)
+		if ctx.Err() != nil || !s.EscalationPolicy(dst[n:]) {
+			return dst
+		}
+		dst = dst[:n]
+	}
+	return s.rankedAppend(ctx, dst, phrase, verbosity, maxEditDistance, frequencyGate)
+}
+
+// rankedAppend runs lookupAppend with the ranking modes that may prefer a
+// suggestion farther than the closest one: Top lookups of words missing from
+// the dictionary collect every suggestion and keep the best ranked. Top
+// lookups of dictionary words are answered as without ranking, which spares
+// the full search for correctly spelled text. Each pass of a two-stage lookup
+// goes through here, so the coarse pass is ranked before the EscalationPolicy
+// sees it.
+func (s *SymSpell) rankedAppend(ctx context.Context, dst []items.SuggestItem, phrase string, verbosity verbositypkg.Verbosity, maxEditDistance int, frequencyGate bool) []items.SuggestItem {
+	if !s.logProbRanking && s.channelModel == nil {
+		return s.lookupAppend(ctx, dst, phrase, verbosity, maxEditDistance, frequencyGate)
+	}
+	search := verbosity
 	if verbosity == verbositypkg.Top {
-		if item, ok := s.topCache.Get(phrase); ok {
-			return []items.SuggestItem{item}, nil
+		if _, found := s.dictionaryCount(phrase); found {
+			return s.lookupAppend(ctx, dst, phrase, verbosity, maxEditDistance, frequencyGate)
 		}
+		search = verbositypkg.All
 	}
+	n := len(dst)
+	dst = s.lookupAppend(ctx, dst, phrase, search, maxEditDistance, frequencyGate)
+	if s.channelModel != nil {
+		s.sortByChannel(phrase, dst[n:])
+	}
+	if verbosity == verbositypkg.Top {
+		dst = dst[:min(len(dst), n+1)]
+	}
+	return dst
+}
+
+// lookupAppend runs the candidate search and appends the suggestions to dst.
+func (s *SymSpell) lookupAppend(ctx context.Context, dst []items.SuggestItem, phrase string, verbosity verbositypkg.Verbosity, maxEditDistance int, frequencyGate bool) []items.SuggestItem {
 	cp := acquireCandidateProcessor(maxEditDistance, verbosity, phrase)
-	// Early exit - word too big to match any words
-	if cp.phraseLen-maxEditDistance > s.maxLength {
-		res := append([]items.SuggestItem(nil), cp.suggestions...)
+	cp.done = ctx.Done()
+	cp.memo = distanceMemoFrom(ctx)
+	cp.frequencyGate = frequencyGate
+	// every lookup counts, including those that end before the search
+	defer func() {
+		s.recordSkips(cp)
 		releaseCandidateProcessor(cp)
-		return res, nil
+	}()
+	if verbosity == verbositypkg.All {
+		cp.sink = suggestionSinkFrom(ctx)
+	}
+	// Early exit - word too big to match any words
+	if cp.phraseLen-maxEditDistance > s.maxWordLength() {
+		return append(dst, cp.suggestions...)
 	}
 
 	exactMatch := s.checkExactMatch(phrase, verbosity, cp)
 
-	if exactMatch.shouldStop {
-		res := append([]items.SuggestItem(nil), cp.suggestions...)
-		releaseCandidateProcessor(cp)
-		return res, nil
+	if exactMatch.shouldStop || cp.stopped {
+		return append(dst, cp.suggestions...)
 	}
 
 	if maxEditDistance == 0 {
-		res := append([]items.SuggestItem(nil), cp.suggestions...)
-		releaseCandidateProcessor(cp)
-		return res, nil
+		return append(dst, cp.suggestions...)
 	}
 	cp.consideredSuggestions[phrase] = struct{}{}
 	// Add original prefix
 	phrasePrefix := s.getOriginPrefix(cp)
 	cp.candidates = append(cp.candidates, phrasePrefix)
 	// Process candidates
-	s.processCandidate(maxEditDistance, cp)
+	if s.useParallelLookup(maxEditDistance, cp) {
+		s.processCandidateParallel(maxEditDistance, cp)
+	} else {
+		s.processCandidate(maxEditDistance, cp)
+	}
+	if ctx.Err() != nil || cp.sink != nil {
+		// With a sink everything has been streamed already.
+		return dst
+	}
+	if s.phoneticEncoder != nil {
+		s.mergePhoneticCandidates(maxEditDistance, cp)
+	}
+
+	if s.logger.Enabled(ctx, slog.LevelDebug) {
+		s.logger.DebugContext(ctx, "candidates processed",
+			"phrase", phrase,
+			"candidates", len(cp.candidates),
+			"suggestions", len(cp.suggestions),
+			"stopped", cp.stopped)
+	}
 
 	// Финальная обработка с учетом относительной частотности
 	s.finalizeWithFrequencyCheck(cp, exactMatch.exactItem)
 
-	cp.sortCandidate()
-
-	result := append([]items.SuggestItem(nil), cp.suggestions...)
-	if verbosity == verbositypkg.Top && len(result) > 0 {
-		s.topCache.Add(phrase, result[0])
+	// the Ranker sorts last, so that the orders before it only break its ties
+	cp.sortCandidate(nil)
+	s.sortWeighted(cp)
+	s.sortUserWords(cp)
+	s.sortPhonetic(cp)
+	if s.Ranker != nil {
+		cp.sortCandidate(s.Ranker)
 	}
-	releaseCandidateProcessor(cp)
+	s.cutPhonetic(cp)
 
-	return result, nil
+	return append(dst, cp.suggestions...)
 }
 
 type ExactMatchResult struct {
@@ -84,12 +337,16 @@ func (s *SymSpell) getOriginPrefix(cp *candidateProcessor) string {
 }
 
 func (s *SymSpell) checkExactMatch(phrase string, verbosity verbositypkg.Verbosity, cp *candidateProcessor) ExactMatchResult {
-	if idx, found := s.Words[phrase]; found {
-		count := s.counts[idx]
-		exactItem := items.SuggestItem{Term: phrase, Distance: 0, Count: int(count)}
-		cp.suggestions = append(cp.suggestions, exactItem)
+	if count, found := s.dictionaryCount(phrase); found {
+		exactItem := items.SuggestItem{Term: phrase, Distance: 0, Count: itemCount(count)}
+		if s.SuggestionFilter != nil && !s.SuggestionFilter(exactItem) {
+			return ExactMatchResult{shouldStop: false, exactItem: nil}
+		}
+		cp.addSuggestion(exactItem)
 
-		if verbosity != verbositypkg.All && int(count) >= s.FrequencyThreshold {
+		// without the frequency gate nothing may replace the exact match,
+		// which is the only suggestion at distance 0
+		if verbosity != verbositypkg.All && (!cp.frequencyGate || count >= uint64(s.FrequencyThreshold)) {
 			return ExactMatchResult{shouldStop: true, exactItem: &exactItem}
 		}
 
@@ -99,7 +356,7 @@ func (s *SymSpell) checkExactMatch(phrase string, verbosity verbositypkg.Verbosi
 }
 
 func (s *SymSpell) finalizeWithFrequencyCheck(cp *candidateProcessor, exactMatch *items.SuggestItem) {
-	if exactMatch == nil || len(cp.suggestions) <= 1 {
+	if !cp.frequencyGate || exactMatch == nil || len(cp.suggestions) <= 1 || s.IsUserWord(exactMatch.Term) {
 		return
 	}
 
@@ -116,10 +373,9 @@ func (s *SymSpell) finalizeWithFrequencyCheck(cp *candidateProcessor, exactMatch
 
 		// Учитываем только близкие варианты (расстояние 1-2)
 		if suggestion.Distance <= 2 {
-			// Используем настройки частотности
-			requiredFrequency := exactMatch.Count * s.FrequencyMultiplier
-
-			if suggestion.Count >= requiredFrequency {
+			// Используем настройки частотности; деление вместо умножения
+			// Count * FrequencyMultiplier, которое может переполниться
+			if suggestion.Count/s.FrequencyMultiplier >= exactMatch.Count {
 				if bestAlternative == nil ||
 					suggestion.Count > bestAlternative.Count ||
 					(suggestion.Count == bestAlternative.Count && suggestion.Distance < bestAlternative.Distance) {
@@ -131,9 +387,11 @@ func (s *SymSpell) finalizeWithFrequencyCheck(cp *candidateProcessor, exactMatch
 
 	// Если нашли лучшую альтернативу, удаляем точное совпадение
 	if bestAlternative != nil {
-		// Опциональный лог для отладки
-		// fmt.Printf("Заменяем '%s' (%d) на '%s' (%d) из-за низкой частотности\n",
-		//     exactMatch.Term, exactMatch.Count, bestAlternative.Term, bestAlternative.Count)
+		s.logger.Debug("exact match replaced by more frequent suggestion",
+			"term", exactMatch.Term,
+			"count", exactMatch.Count,
+			"replacement", bestAlternative.Term,
+			"replacement_count", bestAlternative.Count)
 
 		// Удаляем точное совпадение из результатов
 		newSuggestions := make([]items.SuggestItem, 0, len(cp.suggestions))
@@ -147,10 +405,19 @@ func (s *SymSpell) finalizeWithFrequencyCheck(cp *candidateProcessor, exactMatch
 }
 
 func (s *SymSpell) processCandidate(maxEditDistance int, cp *candidateProcessor) {
-	for cp.candidatePointer < len(cp.candidates) {
+	for cp.candidatePointer < len(cp.candidates) && !cp.stopped {
+		if cp.done != nil {
+			select {
+			case <-cp.done:
+				cp.stopped = true
+				return
+			default:
+			}
+		}
 		candidate := s.preProcessCandidate(cp)
 
 		if cp.lenDiff > cp.maxEditDistance2 {
+			cp.skip(skipLengthDiff)
 			if cp.verbosity == verbositypkg.All {
 				continue
 			}
@@ -158,47 +425,16 @@ func (s *SymSpell) processCandidate(maxEditDistance int, cp *candidateProcessor)
 		}
 
 		// Check suggestions for the candidate
-		if v, found := s.DeletesIdx[candidate]; found {
-			offset := uint32(v >> 32)
-			length := uint32(v)
-			for i := offset; i < offset+length; i++ {
-				idx := s.DeletesData[i]
-				suggestion := s.words[idx]
-				if suggestion == cp.phrase {
-					continue
-				}
-				cp.updateSuggestion(suggestion)
-				skip := s.checkSuggestionToSkip(cp, suggestion, candidate)
-				if skip {
-					continue
-				}
-				cp.resetDistance()
-				if cp.candidateLen == 0 {
-					cp.distance = max(cp.phraseLen, cp.suggestionLen)
-					if cp.distance > cp.maxEditDistance2 {
-						continue
-					}
-					if _, ok := cp.consideredSuggestions[suggestion]; ok {
-						continue
-					}
-				} else if cp.suggestionLen == 1 {
-					skip = s.checkFirstRuneDistance(cp, suggestion)
-					if skip {
-						continue
-					}
-				} else {
-					s.updateMinDistance(maxEditDistance, cp)
-					skip = s.checkDistanceToSkip(maxEditDistance, cp, suggestion)
-					if skip {
-						continue
-					}
-				}
-				if cp.distance <= cp.maxEditDistance2 {
-					s.updateSuggestions(idx, suggestion, cp)
-				}
-			}
+		if postings, found := s.postings(candidate); found {
+			s.processPostings(s, postings, candidate, maxEditDistance, cp)
+		}
+		if postings, found := s.deltaIdx[candidate]; found {
+			s.processPostings(s, postings, candidate, maxEditDistance, cp)
 		}
-		if cp.lenDiff <= maxEditDistance && cp.candidateLen <= s.PrefixLength {
+		if s.userIdx != nil {
+			s.processUserPostings(candidate, maxEditDistance, cp)
+		}
+		if !cp.pregenerated && cp.lenDiff <= maxEditDistance && cp.candidateLen <= s.PrefixLength {
 			if cp.verbosity != verbositypkg.All && cp.lenDiff >= cp.maxEditDistance2 {
 				continue
 			}
@@ -207,11 +443,62 @@ func (s *SymSpell) processCandidate(maxEditDistance int, cp *candidateProcessor)
 	}
 }
 
+// processPostings checks the words of dict at postings, dict being s or its
+// user word index.
+func (s *SymSpell) processPostings(dict *SymSpell, postings []uint32, candidate string, maxEditDistance int, cp *candidateProcessor) {
+	for _, idx := range postings {
+		if cp.stopped {
+			return
+		}
+		if !dict.isLiveIndex(idx) {
+			continue
+		}
+		suggestion := dict.word(idx)
+		if suggestion == cp.phrase {
+			continue
+		}
+		if dict.hashedKeys() && !dict.isDeleteOf(candidate, suggestion) {
+			continue
+		}
+		cp.updateSuggestion(suggestion)
+		skip := s.checkSuggestionToSkip(cp, suggestion, candidate)
+		if skip {
+			continue
+		}
+		cp.resetDistance()
+		if cp.candidateLen == 0 && !s.customDistance {
+			cp.distance = max(cp.phraseLen, cp.suggestionLen)
+			if cp.distance > cp.maxEditDistance2 {
+				cp.skip(skipDistanceCutoff)
+				continue
+			}
+			if _, ok := cp.consideredSuggestions[suggestion]; ok {
+				cp.skip(skipAlreadyConsidered)
+				continue
+			}
+		} else if cp.suggestionLen == 1 && !s.customDistance {
+			skip = s.checkFirstRuneDistance(cp, suggestion)
+			if skip {
+				continue
+			}
+		} else {
+			s.updateMinDistance(maxEditDistance, cp)
+			skip = s.checkDistanceToSkip(maxEditDistance, cp, suggestion)
+			if skip {
+				continue
+			}
+		}
+		if cp.distance <= cp.maxEditDistance2 {
+			s.updateSuggestions(suggestion, s.userCount(suggestion, dict.counts[idx]), cp)
+		}
+	}
+}
+
 func (s *SymSpell) preProcessCandidate(cp *candidateProcessor) string {
 	candidate := cp.candidates[cp.candidatePointer]
 	cp.candidatePointer++
 	if cp.unicode {
-		cp.candidateRunes = []rune(candidate)
+		cp.candidateRunes = appendRunes(cp.candidateRunes[:0], candidate)
 		cp.candidateLen = len(cp.candidateRunes)
 	} else {
 		cp.candidateLen = len(candidate)
@@ -223,30 +510,38 @@ func (s *SymSpell) preProcessCandidate(cp *candidateProcessor) string {
 func (s *SymSpell) checkSuggestionToSkip(cp *candidateProcessor, suggestion string, candidate string) bool {
 	if abs(cp.suggestionLen-cp.phraseLen) > cp.maxEditDistance2 || cp.suggestionLen < cp.candidateLen ||
 		(cp.suggestionLen == cp.candidateLen && suggestion != candidate) {
+		cp.skip(skipLengthDiff)
 		return true
 	}
 	suggestionPrefixLen := min(cp.suggestionLen, s.PrefixLength)
 	if suggestionPrefixLen > cp.phraseLen && suggestionPrefixLen-cp.candidateLen > cp.maxEditDistance2 {
+		cp.skip(skipPrefixMismatch)
 		return true
 	}
 	return false
 }
 
 func (s *SymSpell) checkDistanceToSkip(maxEditDistance int, cp *candidateProcessor, suggestion string) bool {
-	if s.PrefixLength-maxEditDistance == cp.candidateLen {
+	if s.PrefixLength-maxEditDistance == cp.candidateLen && !s.customDistance {
 		skip := s.checkProcessShouldSkip(cp, suggestion)
 		if skip {
+			cp.skip(skipPrefixMismatch)
 			return true
 		}
 	}
 	// delete in suggestion prefix is somewhat expensive, and
 	// only pays off when verbosity is TOP or CLOSEST
 	if _, ok := cp.consideredSuggestions[suggestion]; ok {
+		cp.skip(skipAlreadyConsidered)
 		return true
 	}
 	cp.consideredSuggestions[suggestion] = struct{}{}
-	cp.distance = s.distanceCompare(cp.phrase, suggestion, cp.maxEditDistance2)
-	return cp.distance < 0
+	cp.distance = cp.memo.compare(s, cp.phrase, suggestion, cp.maxEditDistance2)
+	if cp.distance < 0 {
+		cp.skip(skipDistanceCutoff)
+		return true
+	}
+	return false
 }
 
 func (s *SymSpell) updateMinDistance(maxEditDistance int, cp *candidateProcessor) {
@@ -259,7 +554,7 @@ func (s *SymSpell) updateMinDistance(maxEditDistance int, cp *candidateProcessor
 
 func (s *SymSpell) checkFirstRuneDistance(cp *candidateProcessor, suggestion string) bool {
 	if cp.unicode {
-		first := cp.suggestionRunes[0]
+		first, _ := utf8.DecodeRuneInString(suggestion)
 		found := false
 		for _, r := range cp.phraseRunes {
 			if r == first {
@@ -273,9 +568,11 @@ func (s *SymSpell) checkFirstRuneDistance(cp *candidateProcessor, suggestion str
 			cp.distance = cp.phraseLen
 		}
 		if cp.distance > cp.maxEditDistance2 {
+			cp.skip(skipDistanceCutoff)
 			return true
 		}
 		if _, ok := cp.consideredSuggestions[suggestion]; ok {
+			cp.skip(skipAlreadyConsidered)
 			return true
 		}
 		return false
@@ -287,9 +584,11 @@ func (s *SymSpell) checkFirstRuneDistance(cp *candidateProcessor, suggestion str
 		cp.distance = cp.phraseLen
 	}
 	if cp.distance > cp.maxEditDistance2 {
+		cp.skip(skipDistanceCutoff)
 		return true
 	}
 	if _, ok := cp.consideredSuggestions[suggestion]; ok {
+		cp.skip(skipAlreadyConsidered)
 		return true
 	}
 	return false
@@ -298,7 +597,7 @@ func (s *SymSpell) checkFirstRuneDistance(cp *candidateProcessor, suggestion str
 func (s *SymSpell) checkProcessShouldSkip(cp *candidateProcessor, suggestion string) bool {
 	if cp.unicode {
 		pr := cp.phraseRunes
-		sr := cp.suggestionRunes
+		sr := cp.decodeSuggestion(suggestion)
 		if cp.minDistance > 1 && !runesEqual(pr[cp.phraseLen+1-cp.minDistance:], sr[cp.suggestionLen+1-cp.minDistance:]) {
 			return true
 		}
@@ -323,22 +622,52 @@ func (s *SymSpell) checkProcessShouldSkip(cp *candidateProcessor, suggestion str
 	return false
 }
 
-func (s *SymSpell) updateSuggestions(idx uint32, suggestion string, cp *candidateProcessor) {
-	suggestionCount := s.counts[idx]
-	item := items.SuggestItem{Term: suggestion, Distance: cp.distance, Count: int(suggestionCount)}
+func (s *SymSpell) updateSuggestions(suggestion string, suggestionCount uint64, cp *candidateProcessor) {
+	if _, ok := s.blacklist[suggestion]; ok {
+		return
+	}
+	item := items.SuggestItem{Term: suggestion, Distance: cp.distance, Count: itemCount(suggestionCount)}
+	if s.SuggestionFilter != nil && !s.SuggestionFilter(item) {
+		return
+	}
 
 	if len(cp.suggestions) > 0 {
-		if shouldContinue := s.updateBestSuggestion(cp, int(suggestionCount), item); shouldContinue {
+		if shouldContinue := s.updateBestSuggestion(cp, item); shouldContinue {
+			s.checkEarlyTermination(cp)
 			return
 		}
 	}
 	if cp.verbosity != verbositypkg.All {
 		cp.maxEditDistance2 = cp.distance
 	}
-	cp.suggestions = append(cp.suggestions, items.SuggestItem{Term: suggestion, Distance: cp.distance, Count: int(suggestionCount)})
+	cp.addSuggestion(item)
+	s.checkEarlyTermination(cp)
 }
 
-func (s *SymSpell) updateBestSuggestion(cp *candidateProcessor, suggestionCount int, item items.SuggestItem) bool {
+// checkEarlyTermination stops a Top lookup once the configured number of
+// candidates at the current best distance has been seen, or once a distance-1
+// suggestion reaches the configured count.
+func (s *SymSpell) checkEarlyTermination(cp *candidateProcessor) {
+	if cp.verbosity != verbositypkg.Top || (s.EarlyStopCandidates == 0 && s.EarlyStopCount == 0) {
+		return
+	}
+	best := cp.suggestions[0]
+	if best.Distance != cp.bestDistance {
+		cp.bestDistance = best.Distance
+		cp.atBestDistance = 0
+	}
+	if cp.distance == best.Distance {
+		cp.atBestDistance++
+	}
+	if s.EarlyStopCandidates > 0 && cp.atBestDistance >= s.EarlyStopCandidates {
+		cp.stopped = true
+	}
+	if s.EarlyStopCount > 0 && best.Distance <= 1 && best.Count >= s.EarlyStopCount {
+		cp.stopped = true
+	}
+}
+
+func (s *SymSpell) updateBestSuggestion(cp *candidateProcessor, item items.SuggestItem) bool {
 	if cp.verbosity == verbositypkg.Closest {
 		// Keep only the closest suggestions
 		if cp.distance < cp.maxEditDistance2 {
@@ -346,7 +675,7 @@ func (s *SymSpell) updateBestSuggestion(cp *candidateProcessor, suggestionCount
 		}
 	} else if cp.verbosity == verbositypkg.Top {
 		// Keep the top suggestion based on count or distance
-		if cp.distance < cp.maxEditDistance2 || suggestionCount > cp.suggestions[0].Count {
+		if cp.distance < cp.maxEditDistance2 || s.outranksTop(cp, item) {
 			cp.maxEditDistance2 = cp.distance
 			cp.suggestions[0] = item
 		}
@@ -355,6 +684,54 @@ func (s *SymSpell) updateBestSuggestion(cp *candidateProcessor, suggestionCount
 	return false
 }
 
+// ranksBefore orders two suggestions at the same distance with the configured
+// Ranker, or by count.
+func (s *SymSpell) ranksBefore(a, b items.SuggestItem) bool {
+	if s.Ranker != nil {
+		return s.Ranker(a, b)
+	}
+	return a.Count > b.Count
+}
+
+// outranksTop reports whether item, which is at the same distance as the
+// current Top suggestion, should replace it.
+func (s *SymSpell) outranksTop(cp *candidateProcessor, item items.SuggestItem) bool {
+	best := cp.suggestions[0]
+	if s.Ranker != nil {
+		return s.Ranker(item, best)
+	}
+	if before, ok := s.preferUser(item.Term, best.Term); ok {
+		return before
+	}
+	if s.weightedComparer != nil {
+		itemWeight := s.weightedComparer.WeightedDistance(cp.phrase, item.Term)
+		bestWeight := s.weightedComparer.WeightedDistance(cp.phrase, best.Term)
+		if itemWeight != bestWeight {
+			return itemWeight < bestWeight
+		}
+	}
+	return item.Count > best.Count
+}
+
+// sortWeighted orders suggestions sharing an integer distance by the weighted
+// distance of a fractional-cost comparer.
+func (s *SymSpell) sortWeighted(cp *candidateProcessor) {
+	if s.weightedComparer == nil || len(cp.suggestions) < 2 {
+		return
+	}
+	weights := make(map[string]float64, len(cp.suggestions))
+	for _, suggestion := range cp.suggestions {
+		weights[suggestion.Term] = s.weightedComparer.WeightedDistance(cp.phrase, suggestion.Term)
+	}
+	sort.SliceStable(cp.suggestions, func(i, j int) bool {
+		a, b := cp.suggestions[i], cp.suggestions[j]
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		return weights[a.Term] < weights[b.Term]
+	})
+}
+
 func (s *SymSpell) addEditDistance(candidate string, cp *candidateProcessor) {
 	if !cp.unicode {
 		for i := 0; i < len(candidate); i++ {
@@ -366,16 +743,38 @@ func (s *SymSpell) addEditDistance(candidate string, cp *candidateProcessor) {
 		}
 		return
 	}
-	runes := cp.candidateRunes
-	for i := 0; i < len(runes); i++ {
-		deleteItem := string(runes[:i]) + string(runes[i+1:])
-		if _, ok := cp.consideredDeletes[deleteItem]; !ok {
+	for i := 0; i < len(candidate); {
+		_, size := utf8.DecodeRuneInString(candidate[i:])
+		head, tail := candidate[:i], candidate[i+size:]
+		i += size
+		if head == "" || tail == "" {
+			// a delete of the first or last rune is a substring of candidate
+			deleteItem := head + tail
+			if _, ok := cp.consideredDeletes[deleteItem]; !ok {
+				cp.consideredDeletes[deleteItem] = struct{}{}
+				cp.candidates = append(cp.candidates, deleteItem)
+			}
+			continue
+		}
+		cp.deleteBuf = append(append(cp.deleteBuf[:0], head...), tail...)
+		// the lookup with string(deleteBuf) does not allocate
+		if _, ok := cp.consideredDeletes[string(cp.deleteBuf)]; !ok {
+			deleteItem := string(cp.deleteBuf)
 			cp.consideredDeletes[deleteItem] = struct{}{}
 			cp.candidates = append(cp.candidates, deleteItem)
 		}
 	}
 }
 
+// appendRunes appends the runes of s to dst, like []rune(s) without the
+// allocation once dst has grown.
+func appendRunes(dst []rune, s string) []rune {
+	for _, r := range s {
+		dst = append(dst, r)
+	}
+	return dst
+}
+
 func (s *SymSpell) distanceCompare(a, b string, maxDistance int) int {
 	distance := s.distanceComparer.DistanceMax(a, b, maxDistance)
 	if distance > maxDistance {
@@ -429,8 +828,19 @@ type candidateProcessor struct {
 	minDistance           int
 	suggestions           []items.SuggestItem
 	suggestionLen         int
-	suggestionRunes       []rune
+	suggestionRunes       []rune // decoded lazily, see decodeSuggestion
+	deleteBuf             []byte
 	lenDiff               int
+	phoneticMatches       map[string]struct{}
+	skips                 [skipReasonCount]uint64
+	stopped               bool
+	done                  <-chan struct{} // closed when the caller gives up on the lookup
+	memo                  *distanceMemo
+	sink                  *suggestionSink // set when All suggestions are streamed
+	pregenerated          bool            // candidates are not expanded, see processCandidateParallel
+	frequencyGate         bool
+	bestDistance          int
+	atBestDistance        int
 }
 
 var candidateProcessorPool = sync.Pool{
@@ -440,6 +850,7 @@ var candidateProcessorPool = sync.Pool{
 			consideredDeletes:     make(map[string]struct{}),
 			consideredSuggestions: make(map[string]struct{}),
 			suggestions:           make([]items.SuggestItem, 0),
+			phoneticMatches:       make(map[string]struct{}),
 		}
 	},
 }
@@ -452,54 +863,94 @@ func acquireCandidateProcessor(maxEditDistance int, verbosity verbositypkg.Verbo
 	cp.phrase = phrase
 	cp.unicode = !isASCII(phrase)
 	if cp.unicode {
-		cp.phraseRunes = []rune(phrase)
+		cp.phraseRunes = appendRunes(cp.phraseRunes[:0], phrase)
 		cp.phraseLen = len(cp.phraseRunes)
 	} else {
-		cp.phraseRunes = nil
+		cp.phraseRunes = cp.phraseRunes[:0]
 		cp.phraseLen = len(phrase)
 	}
 	cp.candidateLen = 0
-	cp.candidateRunes = nil
+	cp.candidateRunes = cp.candidateRunes[:0]
 	cp.distance = 0
 	cp.minDistance = 0
 	cp.suggestions = cp.suggestions[:0]
 	cp.suggestionLen = 0
-	cp.suggestionRunes = nil
+	cp.suggestionRunes = cp.suggestionRunes[:0]
 	cp.lenDiff = 0
+	cp.skips = [skipReasonCount]uint64{}
+	cp.stopped = false
+	cp.done = nil
+	cp.memo = nil
+	cp.sink = nil
+	cp.pregenerated = false
+	cp.frequencyGate = true
+	cp.bestDistance = -1
+	cp.atBestDistance = 0
 	cp.candidates = cp.candidates[:0]
 	clear(cp.consideredDeletes)
 	clear(cp.consideredSuggestions)
+	clear(cp.phoneticMatches)
 	return cp
 }
 
 func releaseCandidateProcessor(cp *candidateProcessor) {
 	cp.phrase = ""
-	cp.phraseRunes = nil
-	cp.candidateRunes = nil
-	cp.suggestionRunes = nil
+	cp.memo = nil
+	cp.sink = nil
 	candidateProcessorPool.Put(cp)
 }
 
+func (c *candidateProcessor) skip(reason skipReason) {
+	c.skips[reason]++
+}
+
+// addSuggestion collects item, or streams it when a sink is set.
+func (c *candidateProcessor) addSuggestion(item items.SuggestItem) {
+	if c.sink == nil {
+		c.suggestions = append(c.suggestions, item)
+		return
+	}
+	if !c.sink.emit(item) {
+		c.stopped = true
+	}
+}
+
 func (c *candidateProcessor) resetDistance() {
 	c.distance, c.minDistance = 0, 0
 }
 
+// updateSuggestion measures the next suggestion. Its runes are only decoded
+// when a check needs them, into a buffer reused across suggestions.
 func (c *candidateProcessor) updateSuggestion(suggestion string) {
 	if c.unicode {
-		c.suggestionRunes = []rune(suggestion)
-		c.suggestionLen = len(c.suggestionRunes)
+		c.suggestionRunes = c.suggestionRunes[:0]
+		c.suggestionLen = utf8.RuneCountInString(suggestion)
 	} else {
 		c.suggestionLen = len(suggestion)
 	}
 }
 
-func (c *candidateProcessor) sortCandidate() {
-	if len(c.suggestions) > 1 {
-		sort.Slice(c.suggestions, func(i, j int) bool {
-			if c.suggestions[i].Distance == c.suggestions[j].Distance {
-				return c.suggestions[i].Count > c.suggestions[j].Count
-			}
-			return c.suggestions[i].Distance < c.suggestions[j].Distance
+func (c *candidateProcessor) decodeSuggestion(suggestion string) []rune {
+	if len(c.suggestionRunes) == 0 {
+		c.suggestionRunes = appendRunes(c.suggestionRunes, suggestion)
+	}
+	return c.suggestionRunes
+}
+
+func (c *candidateProcessor) sortCandidate(ranker options.Ranker) {
+	if len(c.suggestions) < 2 {
+		return
+	}
+	if ranker != nil {
+		sort.SliceStable(c.suggestions, func(i, j int) bool {
+			return ranker(c.suggestions[i], c.suggestions[j])
 		})
+		return
 	}
+	sort.Slice(c.suggestions, func(i, j int) bool {
+		if c.suggestions[i].Distance == c.suggestions[j].Distance {
+			return c.suggestions[i].Count > c.suggestions[j].Count
+		}
+		return c.suggestions[i].Distance < c.suggestions[j].Distance
+	})
 }
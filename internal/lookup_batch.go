@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"symspell/pkg/items"
+	verbositypkg "symspell/pkg/verbosity"
+)
+
+// LookupBatch looks up every term on GOMAXPROCS goroutines and returns the
+// suggestions in input order. Lookups of different terms only read the index,
+// so no locking is needed as long as the dictionary is not modified
+// concurrently. The first per-term error is returned together with the
+// results of the remaining terms.
+func (s *SymSpell) LookupBatch(
+	terms []string,
+	verbosity verbositypkg.Verbosity,
+	maxEditDistance int,
+) ([][]items.SuggestItem, error) {
+	if maxEditDistance > s.MaxDictionaryEditDistance {
+		return nil, ErrDistanceTooLarge
+	}
+	results := make([][]items.SuggestItem, len(terms))
+	errs := make([]error, len(terms))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(runtime.GOMAXPROCS(0), len(terms)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = s.Lookup(terms[i], verbosity, maxEditDistance)
+			}
+		}()
+	}
+	for i := range terms {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return results, fmt.Errorf("term %d: %w", i, err)
+		}
+	}
+	return results, nil
+}
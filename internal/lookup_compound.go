@@ -1,10 +1,10 @@
 package internal
 
 import (
-	"bufio"
+	"context"
 	"fmt"
+	"io"
 	"math"
-	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -16,42 +16,132 @@ import (
 	"symspell/pkg/items"
 )
 
-func parseWords(phrase string, preserveCase, splitBySpace, splitNumber bool) []string {
-	if !preserveCase {
-		phrase = strings.ToLower(phrase)
-	}
+// wordSpan is the byte range of a parsed word in the original phrase.
+type wordSpan struct {
+	start, end int
+}
 
+// parseWords splits phrase into lowercase words and returns where each of them
+// starts and ends in phrase.
+func parseWords(phrase string, splitBySpace, splitNumber bool, lower func(string) string) ([]string, []wordSpan) {
+	var spans []wordSpan
 	if splitBySpace {
-		if splitNumber {
-			return separateNumbers(strings.Split(phrase, " "))
+		start := 0
+		for _, field := range strings.Split(phrase, " ") {
+			span := wordSpan{start: start, end: start + len(field)}
+			start = span.end + 1
+			if !splitNumber {
+				spans = append(spans, span)
+				continue
+			}
+			if len(field) == 0 {
+				continue
+			}
+			for _, part := range splitWordAndNumber(field) {
+				spans = append(spans, wordSpan{start: span.start, end: span.start + len(part)})
+				span.start += len(part)
+			}
+		}
+	} else {
+		// Regex pattern to match words, including handling apostrophes
+		for _, loc := range reSplit.FindAllStringIndex(phrase, -1) {
+			spans = append(spans, wordSpan{start: loc[0], end: loc[1]})
 		}
-		return strings.Split(phrase, " ")
 	}
 
-	// Regex pattern to match words, including handling apostrophes
-	return reSplit.FindAllString(phrase, -1)
+	words := make([]string, len(spans))
+	for i, span := range spans {
+		words[i] = lower(phrase[span.start:span.end])
+	}
+	return words, spans
 }
 
 var reSplit = regexp.MustCompile(`([\p{L}\d]+(?:['’][\p{L}\d]+)?)`)
 
+// compoundWords parses the words of a LookupCompound phrase.
+func (s *SymSpell) compoundWords(phrase string) ([]string, []wordSpan) {
+	words, spans := parseWords(phrase, s.SplitWordBySpace, s.SplitWordAndNumber, s.caseMapping().lower)
+	if s.cjkSegmentLength > 0 {
+		words, spans = s.splitCJKWords(phrase, words, spans)
+	}
+	return s.mergeIgnoredFields(phrase, words, spans)
+}
+
 func (s *SymSpell) LookupCompound(phrase string, maxEditDistance int) *items.SuggestItem {
-	terms1 := parseWords(phrase, s.PreserveCase, s.SplitWordBySpace, s.SplitWordAndNumber)
+	result := s.LookupCompoundDetailed(phrase, maxEditDistance)
+	if result == nil {
+		return nil
+	}
+	return &result.Suggestion
+}
+
+// LookupCompoundDetailed works like LookupCompound and also reports which
+// span of the phrase every part of the correction replaces. Offsets and
+// originals refer to phrase as passed in, before InvalidUTF8Sanitize and
+// Unicode normalization.
+func (s *SymSpell) LookupCompoundDetailed(phrase string, maxEditDistance int) *items.CompoundResult {
+	result, _ := s.LookupCompoundContext(context.Background(), phrase, maxEditDistance)
+	return result
+}
+
+// LookupCompoundContext works like LookupCompoundDetailed but reports why a
+// phrase is rejected: ErrInvalidUTF8 under InvalidUTF8Reject,
+// ErrDistanceTooLarge, or ctx.Err() once ctx is done.
+func (s *SymSpell) LookupCompoundContext(ctx context.Context, phrase string, maxEditDistance int) (*items.CompoundResult, error) {
+	if maxEditDistance > s.MaxDictionaryEditDistance {
+		return nil, ErrDistanceTooLarge
+	}
+	input := phrase
+	phrase, offsets, err := s.checkUTF8Offsets(phrase)
+	if err != nil {
+		return nil, err
+	}
+	terms1, spans := s.compoundWords(phrase)
+	if s.CompoundBeamWidth > 1 {
+		results := s.compoundResults(ctx, phrase, terms1, spans, maxEditDistance, s.CompoundBeamWidth)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		offsets.tokens(input, results[0].Tokens)
+		return &results[0], nil
+	}
 	cp := compoundProcessor{
 		suggestions:     make([]items.SuggestItem, 0),
 		suggestionParts: make([]items.SuggestItem, 0),
 		replacedWords:   make(map[string]items.SuggestItem),
 		isLastCombi:     false,
+		memo:            newDistanceMemo(s.CompoundDistanceMemo),
+	}
+	cp.ctx = withDistanceMemo(ctx, cp.memo)
+	if s.CompoundWorkers > 1 && len(terms1) > 1 {
+		cp.prefetched = s.prefetchCompound(ctx, terms1, verbositypkg.Top, maxEditDistance)
 	}
 	for i := range terms1 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		cp.tokenIndex = i
 		cp.terms1 = terms1[i]
+		if item, ok := s.verbatimItem(phrase[spans[i].start:spans[i].end]); ok {
+			cp.suggestions = []items.SuggestItem{item}
+			cp.appendPart(item)
+			cp.isLastCombi = false
+			continue
+		}
+		if s.isCJKRun(cp.terms1) {
+			cp.suggestions = []items.SuggestItem{s.correctCJKRun(cp.terms1, maxEditDistance)}
+			cp.appendPart(cp.suggestions[0])
+			cp.isLastCombi = false
+			continue
+		}
 		if i != len(terms1)-1 || runeLen(cp.terms1) > s.MinimumCharToChange {
 			s.replaceExactMatch(&cp)
 		}
 		s.getSuggestion(&cp, maxEditDistance)
 		// Combine adjacent terms
-		if i > 0 && !cp.isLastCombi {
+		if i > 0 && !cp.isLastCombi && !s.isVerbatim(terms1[i-1]) && !s.isCJKRun(terms1[i-1]) {
 			cp.terms2 = terms1[i-1]
-			suggestionsCombi, _ := s.Lookup(fmt.Sprintf("%s %s", cp.terms2, cp.terms1), verbositypkg.Top, maxEditDistance)
+			suggestionsCombi := cp.lookup(s, fmt.Sprintf("%s %s", cp.terms2, cp.terms1), maxEditDistance)
 			if len(suggestionsCombi) > 0 {
 				best1 := cp.suggestionParts[len(cp.suggestionParts)-1]
 				best2 := s.getBestSuggestion2(cp, maxEditDistance)
@@ -65,7 +155,7 @@ func (s *SymSpell) LookupCompound(phrase string, maxEditDistance int) *items.Sug
 
 		// Handle terms with no perfect suggestion
 		if len(cp.suggestions) > 0 && (cp.suggestions[0].Distance == 0 || runeLen(cp.terms1) == 1) {
-			cp.suggestionParts = append(cp.suggestionParts, cp.suggestions[0])
+			cp.appendPart(cp.suggestions[0])
 		} else {
 			var suggestionSplitBest *items.SuggestItem
 			if len(cp.suggestions) > 0 {
@@ -77,13 +167,17 @@ func (s *SymSpell) LookupCompound(phrase string, maxEditDistance int) *items.Sug
 			}
 			if runeLen(cp.terms1) > 1 && shouldSplit {
 				runes := []rune(cp.terms1)
+				var splits map[string][]items.SuggestItem
+				if s.CompoundWorkers > 1 {
+					splits = s.prefetchSplits(cp.ctx, runes, maxEditDistance)
+				}
 				for j := 1; j < len(runes); j++ {
-					suggestions1, suggestions2, isValid := s.getSuggestions(runes, j, maxEditDistance)
+					suggestions1, suggestions2, isValid := s.getSuggestions(cp.ctx, splits, runes, j, maxEditDistance)
 					if !isValid {
 						continue
 					}
 					cp.suggestion1, cp.suggestion2 = *suggestions1, *suggestions2
-					tmpDistance := s.distanceCompare(cp.terms1, cp.tempTerm(), maxEditDistance)
+					tmpDistance := cp.memo.compare(s, cp.terms1, cp.tempTerm(), maxEditDistance)
 					if tmpDistance < 0 {
 						tmpDistance = maxEditDistance + 1
 					}
@@ -101,7 +195,7 @@ func (s *SymSpell) LookupCompound(phrase string, maxEditDistance int) *items.Sug
 					tmpCount := s.checkForBigram(&cp)
 
 					splitSuggestion := items.SuggestItem{Term: cp.tempTerm(), Distance: tmpDistance, Count: tmpCount}
-					if suggestionSplitBest == nil || splitSuggestion.Count > suggestionSplitBest.Count {
+					if suggestionSplitBest == nil || s.compoundRanksBefore(&cp, splitSuggestion, *suggestionSplitBest) {
 						suggestionSplitBest = &splitSuggestion
 					}
 				}
@@ -115,12 +209,44 @@ func (s *SymSpell) LookupCompound(phrase string, maxEditDistance int) *items.Sug
 		}
 	}
 
-	return s.finalizeAnswer(phrase, cp.suggestionParts)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result := &items.CompoundResult{
+		Suggestion: *s.finalizeAnswer(phrase, cp.suggestionParts),
+		Tokens:     s.tokenCorrections(phrase, spans, &cp),
+	}
+	offsets.tokens(input, result.Tokens)
+	return result, nil
+}
+
+// tokenCorrections maps every part of the answer back to the span of input
+// words it was built from.
+func (s *SymSpell) tokenCorrections(phrase string, spans []wordSpan, cp *compoundProcessor) []items.TokenCorrection {
+	corrections := make([]items.TokenCorrection, len(cp.suggestionParts))
+	for i, part := range cp.suggestionParts {
+		first, last := spans[cp.partTokens[i][0]], spans[cp.partTokens[i][1]]
+		original := phrase[first.start:last.end]
+		replacement := part.Term
+		if s.PreserveCase {
+			replacement = s.caseMapping().transferCasing(original, replacement)
+		}
+		corrections[i] = items.TokenCorrection{
+			Original:    original,
+			Replacement: replacement,
+			Start:       first.start,
+			End:         last.end,
+			Distance:    part.Distance,
+		}
+	}
+	return corrections
 }
 
 func (s *SymSpell) getSuggestion(cp *compoundProcessor, maxEditDistance int) {
-	if runeLen(cp.terms1) > s.MinimumCharToChange {
-		cp.suggestions, _ = s.Lookup(cp.terms1, verbositypkg.Top, maxEditDistance)
+	if runeLen(cp.terms1) > s.MinimumCharToChange && s.languageModel != nil {
+		cp.suggestions = s.closestInContext(cp, cp.terms1, maxEditDistance)
+	} else if runeLen(cp.terms1) > s.MinimumCharToChange {
+		cp.suggestions = cp.lookup(s, cp.terms1, maxEditDistance)
 	} else {
 		cp.suggestions = []items.SuggestItem{{
 			Term:     cp.terms1,
@@ -145,9 +271,10 @@ func (s *SymSpell) validateCombinationDistance(best1 items.SuggestItem, best2 it
 
 	if distance1 >= 0 && suggestionsCombine.Distance+1 < distance1 ||
 		(suggestionsCombine.Distance+1 == distance1 &&
-			float64(suggestionsCombine.Count) > (float64(best1.Count)/s.N)*float64(best2.Count)) {
+			s.combinationMorePlausible(cp, best1, best2, suggestionsCombine)) {
 		suggestionsCombine.Distance++
 		cp.suggestionParts[len(cp.suggestionParts)-1] = suggestionsCombine
+		cp.partTokens[len(cp.partTokens)-1][1] = cp.tokenIndex
 		cp.replacedWords[cp.terms2] = suggestionsCombine
 		cp.isLastCombi = true
 		return true
@@ -155,11 +282,16 @@ func (s *SymSpell) validateCombinationDistance(best1 items.SuggestItem, best2 it
 	return false
 }
 
-func (s *SymSpell) getSuggestions(runes []rune, split int, maxEditDistance int) (*items.SuggestItem, *items.SuggestItem, bool) {
-	part1 := string(runes[:split])
-	part2 := string(runes[split:])
-	suggestions1, _ := s.Lookup(part1, verbositypkg.Top, maxEditDistance)
-	suggestions2, _ := s.Lookup(part2, verbositypkg.Top, maxEditDistance)
+func (s *SymSpell) getSuggestions(ctx context.Context, prefetched map[string][]items.SuggestItem, runes []rune, split int, maxEditDistance int) (*items.SuggestItem, *items.SuggestItem, bool) {
+	lookup := func(part string) []items.SuggestItem {
+		if suggestions, ok := prefetched[part]; ok {
+			return suggestions
+		}
+		suggestions, _ := s.lookupCached(ctx, part, verbositypkg.Top, maxEditDistance)
+		return suggestions
+	}
+	suggestions1 := lookup(string(runes[:split]))
+	suggestions2 := lookup(string(runes[split:]))
 	if len(suggestions1) == 0 || len(suggestions2) == 0 {
 		return nil, nil, false
 	}
@@ -167,37 +299,38 @@ func (s *SymSpell) getSuggestions(runes []rune, split int, maxEditDistance int)
 }
 
 func (s *SymSpell) checkForBigram(cp *compoundProcessor) int {
-	var tmpCount uint32
+	// counted in float64, since word counts plus one may overflow int
+	var tmpCount float64
 	if count, exists := s.Bigrams[cp.tempTerm()]; exists {
-		tmpCount = count
+		tmpCount = float64(count)
 
 		if len(cp.suggestions) > 0 {
 			bestSI := cp.suggestions[0]
 			if cp.suggestion1.Term+cp.suggestion2.Term == cp.terms1 {
-				tmpCount = uint32(math.Max(float64(tmpCount), float64(bestSI.Count+2)))
+				tmpCount = math.Max(tmpCount, float64(bestSI.Count)+2)
 			} else if bestSI.Term == cp.suggestion1.Term || bestSI.Term == cp.suggestion2.Term {
-				tmpCount = uint32(math.Max(float64(tmpCount), float64(bestSI.Count+1)))
+				tmpCount = math.Max(tmpCount, float64(bestSI.Count)+1)
 			}
 		} else if cp.suggestion1.Term+cp.suggestion2.Term == cp.terms1 {
-			tmpCount = uint32(math.Max(
-				float64(tmpCount),
+			tmpCount = math.Max(
+				tmpCount,
 				math.Max(
 					float64(cp.suggestion1.Count),
 					float64(cp.suggestion2.Count),
 				)+2,
-			))
+			)
 		}
 	} else {
-		tmpCount = uint32(math.Min(
+		tmpCount = math.Min(
 			float64(s.BigramCountMin),
 			float64(cp.suggestion1.Count)/s.N*float64(cp.suggestion2.Count),
-		))
+		)
 	}
-	return int(tmpCount)
+	return itemCount(floatCount(tmpCount))
 }
 
 func (c *compoundProcessor) updateReplaceWord(terms1 string, item items.SuggestItem) {
-	c.suggestionParts = append(c.suggestionParts, item)
+	c.appendPart(item)
 	c.replacedWords[terms1] = item
 }
 
@@ -209,11 +342,17 @@ func (s *SymSpell) finalizeAnswer(phrase string, suggestionParts []items.Suggest
 		joinedCount *= float64(item.Count) / s.N
 	}
 	joinedTerm = strings.TrimSpace(joinedTerm)
+	if s.languageModel != nil {
+		joinedCount = s.N * math.Pow(10, s.sequenceScore(suggestionParts))
+	}
+	if s.PreserveCase {
+		joinedTerm = s.caseMapping().transferCasing(phrase, joinedTerm)
+	}
 
 	return &items.SuggestItem{
 		Term:     joinedTerm,
 		Distance: s.distanceCompare(phrase, joinedTerm, math.MaxInt32),
-		Count:    int(joinedCount),
+		Count:    itemCount(floatCount(joinedCount)),
 	}
 }
 
@@ -235,26 +374,28 @@ func createWithProbability(term string, distance int) items.SuggestItem {
 }
 
 // Helper function to safely parse integers
-func tryParseUint32(value string) (uint32, bool) {
+func (s *SymSpell) tryParseUint32(value string) (uint32, bool) {
 	parsed, err := strconv.ParseUint(value, 10, 32)
 	if err != nil {
-		fmt.Println("[ERROR] parsing integer: ", err)
+		s.logger.Warn("invalid count", "value", value, "error", err)
 		return 0, false
 	}
 	return uint32(parsed), true
 }
 
-// Load bigram dictionary from a stream
-func (s *SymSpell) LoadBigramDictionaryStream(corpusStream *os.File, termIndex, countIndex int, separator string) bool {
+// LoadBigramDictionaryStream loads bigram counts from a reader. With an empty
+// separator the two words of the bigram are the fields at termIndex and
+// termIndex+1; otherwise the field at termIndex holds the whole bigram.
+func (s *SymSpell) LoadBigramDictionaryStream(corpusStream io.Reader, termIndex, countIndex int, separator string) (bool, error) {
 	if s.Bigrams == nil {
 		s.Bigrams = make(map[string]uint32)
 	}
-	scanner := bufio.NewScanner(corpusStream)
+	scanner := s.newLineScanner(corpusStream)
 
 	// Define minimum parts depending on the separator
-	minParts := 3
+	minParts := max(3, termIndex+2, countIndex+1)
 	if separator != "" {
-		minParts = 2
+		minParts = max(2, termIndex+1, countIndex+1)
 	}
 
 	for scanner.Scan() {
@@ -276,7 +417,7 @@ func (s *SymSpell) LoadBigramDictionaryStream(corpusStream *os.File, termIndex,
 		}
 
 		// Parse count
-		count, ok := tryParseUint32(parts[countIndex])
+		count, ok := s.tryParseUint32(parts[countIndex])
 		if !ok {
 			continue
 		}
@@ -288,17 +429,25 @@ func (s *SymSpell) LoadBigramDictionaryStream(corpusStream *os.File, termIndex,
 		} else {
 			key = parts[termIndex]
 		}
+		key, err := s.checkUTF8(key)
+		if err != nil {
+			return false, err
+		}
 		// Add to bigram dictionary
-		s.Bigrams[key] = count
+		s.Bigrams[s.dictionaryKey(key)] = count
 
 		if count < s.BigramCountMin {
 			s.BigramCountMin = count
 		}
 	}
+	if err := scanner.Err(); err != nil {
+		return false, err
+	}
 
-	return true
+	return true, nil
 }
 
+// LoadBigramDictionary loads bigram counts from a file, see LoadBigramDictionaryStream.
 func (s *SymSpell) LoadBigramDictionary(
 	corpusPath string,
 	termIndex, countIndex int,
@@ -307,15 +456,14 @@ func (s *SymSpell) LoadBigramDictionary(
 	if corpusPath == "" {
 		return false, fmt.Errorf("corpus path cannot be empty")
 	}
-	// Check if the file exists
-	file, err := os.Open(corpusPath)
+	file, err := openDictionary(corpusPath)
 	if err != nil {
 		return false, err
 	}
 	defer file.Close()
 
 	// Use the stream-based loading function
-	return s.LoadBigramDictionaryStream(file, termIndex, countIndex, separator), nil
+	return s.LoadBigramDictionaryStream(file, termIndex, countIndex, separator)
 }
 
 type compoundProcessor struct {
@@ -327,22 +475,30 @@ type compoundProcessor struct {
 	suggestion1     items.SuggestItem
 	suggestion2     items.SuggestItem
 	isLastCombi     bool
+	prefetched      map[string][]items.SuggestItem
+	tokenIndex      int
+	partTokens      [][2]int // first and last input word of every suggestion part
+	memo            *distanceMemo
+	ctx             context.Context // carries memo into the lookups
 }
 
-func (c *compoundProcessor) tempTerm() string {
-	return fmt.Sprintf("%s %s", c.suggestion1.Term, c.suggestion2.Term)
+func (c *compoundProcessor) appendPart(item items.SuggestItem) {
+	c.suggestionParts = append(c.suggestionParts, item)
+	c.partTokens = append(c.partTokens, [2]int{c.tokenIndex, c.tokenIndex})
 }
 
-func separateNumbers(inputs []string) []string {
-	var results []string
-	for _, input := range inputs {
-		if len(input) == 0 {
-			continue
-		}
-		results = append(results, splitWordAndNumber(input)...)
+// lookup returns the Top suggestions for term, served from the prefetched
+// results when LookupCompound runs with several workers.
+func (c *compoundProcessor) lookup(s *SymSpell, term string, maxEditDistance int) []items.SuggestItem {
+	if suggestions, ok := c.prefetched[term]; ok {
+		return append([]items.SuggestItem(nil), suggestions...)
 	}
+	suggestions, _ := s.lookupCached(c.ctx, term, verbositypkg.Top, maxEditDistance)
+	return suggestions
+}
 
-	return results
+func (c *compoundProcessor) tempTerm() string {
+	return fmt.Sprintf("%s %s", c.suggestion1.Term, c.suggestion2.Term)
 }
 
 func splitWordAndNumber(input string) []string {
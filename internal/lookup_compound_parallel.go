@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"context"
+	"sync"
+
+	"symspell/pkg/items"
+	verbositypkg "symspell/pkg/verbosity"
+)
+
+// prefetchCompound looks up every token of a compound phrase with the given
+// verbosity and every adjacent token pair with Top on CompoundWorkers
+// goroutines. The segmentation and merge decisions that depend on neighbors
+// stay sequential and only read these results.
+func (s *SymSpell) prefetchCompound(ctx context.Context, terms []string, verbosity verbositypkg.Verbosity, maxEditDistance int) map[string][]items.SuggestItem {
+	queries := make([]compoundQuery, 0, 2*len(terms))
+	seen := make(map[string]struct{}, 2*len(terms))
+	enqueue := func(term string, verbosity verbositypkg.Verbosity) {
+		if _, ok := seen[term]; !ok {
+			seen[term] = struct{}{}
+			queries = append(queries, compoundQuery{term, verbosity})
+		}
+	}
+	for i, term := range terms {
+		if i != len(terms)-1 || runeLen(term) > s.MinimumCharToChange {
+			if result, found := s.ExactTransform[term]; found {
+				term = result
+			}
+		}
+		if runeLen(term) > s.MinimumCharToChange {
+			enqueue(term, verbosity)
+		}
+		if i > 0 {
+			enqueue(terms[i-1]+" "+term, verbositypkg.Top)
+		}
+	}
+
+	return s.lookupConcurrently(ctx, queries, maxEditDistance)
+}
+
+// prefetchSplits looks up both parts of every split of runes into two words
+// with Top on CompoundWorkers goroutines, for the split loop of
+// LookupCompound to compare them sequentially.
+func (s *SymSpell) prefetchSplits(ctx context.Context, runes []rune, maxEditDistance int) map[string][]items.SuggestItem {
+	queries := make([]compoundQuery, 0, 2*len(runes))
+	seen := make(map[string]struct{}, 2*len(runes))
+	for j := 1; j < len(runes); j++ {
+		for _, part := range [2]string{string(runes[:j]), string(runes[j:])} {
+			if _, ok := seen[part]; !ok {
+				seen[part] = struct{}{}
+				queries = append(queries, compoundQuery{part, verbositypkg.Top})
+			}
+		}
+	}
+	return s.lookupConcurrently(ctx, queries, maxEditDistance)
+}
+
+type compoundQuery struct {
+	term      string
+	verbosity verbositypkg.Verbosity
+}
+
+// lookupConcurrently runs queries on CompoundWorkers goroutines and returns
+// the suggestions by term.
+func (s *SymSpell) lookupConcurrently(ctx context.Context, queries []compoundQuery, maxEditDistance int) map[string][]items.SuggestItem {
+	results := make([][]items.SuggestItem, len(queries))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(s.CompoundWorkers, len(queries)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], _ = s.lookupCached(ctx, queries[i].term, queries[i].verbosity, maxEditDistance)
+			}
+		}()
+	}
+	for i := range queries {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	prefetched := make(map[string][]items.SuggestItem, len(queries))
+	for i, query := range queries {
+		prefetched[query.term] = results[i]
+	}
+	return prefetched
+}
@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"symspell/pkg/items"
+	verbositypkg "symspell/pkg/verbosity"
+)
+
+// contextDistancePenalty is the log10 penalty applied per edit when ranking
+// suggestions in context.
+const contextDistancePenalty = 1.0
+
+// LookupInContext corrects tokens[index] using its left and right neighbors for
+// disambiguation, see LookupWithNeighbors.
+func (s *SymSpell) LookupInContext(tokens []string, index int, maxEditDistance int) ([]items.SuggestItem, error) {
+	if index < 0 || index >= len(tokens) {
+		return nil, errors.New("index out of range")
+	}
+	var left, right string
+	if index > 0 {
+		left = tokens[index-1]
+	}
+	if index < len(tokens)-1 {
+		right = tokens[index+1]
+	}
+	return s.LookupWithNeighbors(left, tokens[index], right, maxEditDistance)
+}
+
+// LookupWithNeighbors corrects word given the words before and after it; an
+// empty neighbor marks the edge of a sentence. Candidates are ranked by bigram
+// probability with the neighbors (falling back to the unigram estimate used by
+// LookupCompound) and the optional context scorer, so "there" and "their" are
+// told apart by context rather than by raw frequency. LookupInContext takes
+// a token slice and an index, so this form of it has its own name.
+func (s *SymSpell) LookupWithNeighbors(prev, word, next string, maxEditDistance int) ([]items.SuggestItem, error) {
+	suggestions, err := s.Lookup(word, verbositypkg.All, maxEditDistance)
+	if err != nil || len(suggestions) == 0 {
+		return suggestions, err
+	}
+	prev, next = s.dictionaryKey(prev), s.dictionaryKey(next)
+
+	scores := make(map[string]float64, len(suggestions))
+	for _, suggestion := range suggestions {
+		scores[suggestion.Term] = s.contextScore(prev, suggestion, next)
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return scores[suggestions[i].Term] > scores[suggestions[j].Term]
+	})
+	return suggestions, nil
+}
+
+func (s *SymSpell) contextScore(left string, suggestion items.SuggestItem, right string) float64 {
+	score := -float64(suggestion.Distance) * contextDistancePenalty
+	if left == "" && right == "" {
+		score += math.Log10(float64(suggestion.Count) / s.N)
+	}
+	if left != "" {
+		score += math.Log10(s.bigramCount(left, suggestion.Term) / s.N)
+	}
+	if right != "" {
+		score += math.Log10(s.bigramCount(suggestion.Term, right) / s.N)
+	}
+	if s.ContextScorer != nil {
+		score += s.ContextScorer(left, suggestion.Term, right)
+	}
+	return score
+}
+
+// bigramCount returns the bigram count of "first second", estimating it from
+// unigram counts when the pair is not in the bigram dictionary.
+func (s *SymSpell) bigramCount(first, second string) float64 {
+	if count, exists := s.Bigrams[first+" "+second]; exists {
+		return float64(count)
+	}
+	estimate := float64(s.wordCount(first)) / s.N * float64(s.wordCount(second))
+	estimate = math.Min(float64(s.BigramCountMin), estimate)
+	// keep unseen pairs comparable instead of collapsing to log(0)
+	return math.Max(estimate, 1/s.N)
+}
+
+func (s *SymSpell) wordCount(term string) uint64 {
+	count, _ := s.dictionaryCount(term)
+	return count
+}
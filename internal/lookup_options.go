@@ -0,0 +1,25 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+)
+
+// LookupWithOptions works like Lookup with per-call overrides of verbosity,
+// edit distance, result size and the frequency gate.
+func (s *SymSpell) LookupWithOptions(phrase string, opts options.LookupOptions) ([]items.SuggestItem, error) {
+	if opts.MaxSuggestions < 0 {
+		return nil, fmt.Errorf("%w: maxSuggestions cannot be negative", ErrInvalidOptions)
+	}
+	result, err := s.lookupTerm(context.Background(), phrase, opts.Verbosity, opts.MaxEditDistance, !opts.DisableFrequencyGate)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxSuggestions > 0 && len(result) > opts.MaxSuggestions {
+		result = result[:opts.MaxSuggestions]
+	}
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+
+	verbositypkg "symspell/pkg/verbosity"
+)
+
+// useParallelLookup reports whether the candidates of a lookup are probed on
+// LookupWorkers goroutines. Early termination and streaming depend on the
+// order in which suggestions are found, so they keep the sequential search.
+func (s *SymSpell) useParallelLookup(maxEditDistance int, cp *candidateProcessor) bool {
+	return s.LookupWorkers > 1 && maxEditDistance >= 2 && cp.phraseLen >= s.ParallelLookupMinLength &&
+		cp.sink == nil && s.EarlyStopCandidates == 0 && s.EarlyStopCount == 0
+}
+
+// processCandidateParallel generates every delete candidate of the phrase up
+// front, splits them into buckets by hash and probes the buckets
+// concurrently. Each worker collects all suggestions within maxEditDistance;
+// the merged suggestions are then reduced to the verbosity of the lookup.
+func (s *SymSpell) processCandidateParallel(maxEditDistance int, cp *candidateProcessor) {
+	s.expandCandidates(maxEditDistance, cp)
+
+	buckets := make([][]string, s.LookupWorkers)
+	for _, candidate := range cp.candidates {
+		b := xxhash.Sum64String(candidate) % uint64(len(buckets))
+		buckets[b] = append(buckets[b], candidate)
+	}
+	workers := make([]*candidateProcessor, 0, len(buckets))
+	var wg sync.WaitGroup
+	for _, bucket := range buckets {
+		if len(bucket) == 0 {
+			continue
+		}
+		wcp := acquireCandidateProcessor(maxEditDistance, verbositypkg.All, cp.phrase)
+		wcp.done = cp.done
+		wcp.frequencyGate = cp.frequencyGate
+		wcp.pregenerated = true
+		wcp.candidates = append(wcp.candidates, bucket...)
+		workers = append(workers, wcp)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.processCandidate(maxEditDistance, wcp)
+		}()
+	}
+	wg.Wait()
+
+	// a word reached through several buckets keeps its smallest distance
+	found := make(map[string]int, len(cp.suggestions))
+	for i, suggestion := range cp.suggestions {
+		found[suggestion.Term] = i
+	}
+	for _, wcp := range workers {
+		for _, suggestion := range wcp.suggestions {
+			i, ok := found[suggestion.Term]
+			switch {
+			case !ok:
+				found[suggestion.Term] = len(cp.suggestions)
+				cp.suggestions = append(cp.suggestions, suggestion)
+			case suggestion.Distance < cp.suggestions[i].Distance:
+				cp.suggestions[i] = suggestion
+			}
+		}
+		for reason, n := range wcp.skips {
+			cp.skips[reason] += n
+		}
+		cp.stopped = cp.stopped || wcp.stopped
+		releaseCandidateProcessor(wcp)
+	}
+	s.reduceSuggestions(cp)
+}
+
+// expandCandidates appends to cp.candidates every delete the sequential
+// search could reach.
+func (s *SymSpell) expandCandidates(maxEditDistance int, cp *candidateProcessor) {
+	for cp.candidatePointer < len(cp.candidates) {
+		candidate := s.preProcessCandidate(cp)
+		if cp.lenDiff <= maxEditDistance && cp.candidateLen <= s.PrefixLength {
+			s.addEditDistance(candidate, cp)
+		}
+	}
+}
+
+// reduceSuggestions keeps the suggestions the sequential search would keep
+// for Closest and Top.
+func (s *SymSpell) reduceSuggestions(cp *candidateProcessor) {
+	if len(cp.suggestions) < 2 {
+		return
+	}
+	switch cp.verbosity {
+	case verbositypkg.Top:
+		best := cp.suggestions[0]
+		for _, suggestion := range cp.suggestions[1:] {
+			// outranksTop compares with cp.suggestions[0]
+			cp.suggestions[0] = best
+			if suggestion.Distance < best.Distance || suggestion.Distance == best.Distance && s.outranksTop(cp, suggestion) {
+				best = suggestion
+			}
+		}
+		cp.suggestions = append(cp.suggestions[:0], best)
+	case verbositypkg.Closest:
+		closest := cp.suggestions[0].Distance
+		for _, suggestion := range cp.suggestions[1:] {
+			closest = min(closest, suggestion.Distance)
+		}
+		kept := cp.suggestions[:0]
+		for _, suggestion := range cp.suggestions {
+			if suggestion.Distance == closest {
+				kept = append(kept, suggestion)
+			}
+		}
+		cp.suggestions = kept
+	}
+}
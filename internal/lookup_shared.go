@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+
+	"symspell/pkg/items"
+	verbositypkg "symspell/pkg/verbosity"
+)
+
+// lookupShared runs lookupSuggestions, sharing one computation between
+// concurrent calls with the same arguments when Singleflight is enabled.
+// Every caller appends its own copy of the result to dst. A caller whose own context is
+// still live computes the result itself if the shared computation was
+// cancelled by the context of the caller that started it. Streaming lookups
+// are never shared.
+func (s *SymSpell) lookupShared(ctx context.Context, dst []items.SuggestItem, phrase string, verbosity verbositypkg.Verbosity, maxEditDistance int, frequencyGate bool) []items.SuggestItem {
+	if s.lookupGroup == nil || suggestionSinkFrom(ctx) != nil {
+		return s.lookupSuggestions(ctx, dst, phrase, verbosity, maxEditDistance, frequencyGate)
+	}
+	key := strconv.Itoa(int(verbosity)) + "/" + strconv.Itoa(maxEditDistance) + "/" + strconv.FormatBool(frequencyGate) + "/" + phrase
+	result, err := s.lookupGroup.do(key, func() ([]items.SuggestItem, error) {
+		result := s.lookupSuggestions(ctx, nil, phrase, verbosity, maxEditDistance, frequencyGate)
+		return result, ctx.Err()
+	})
+	if err != nil && ctx.Err() == nil {
+		return s.lookupSuggestions(ctx, dst, phrase, verbosity, maxEditDistance, frequencyGate)
+	}
+	return append(dst, result...)
+}
+
+// errLookupAborted is what waiters get when the shared lookup panicked.
+var errLookupAborted = errors.New("shared lookup aborted")
+
+// lookupGroup runs at most one lookup per key at a time; callers arriving
+// while it runs wait for it and get its result.
+type lookupGroup struct {
+	mu    sync.Mutex
+	calls map[string]*lookupCall
+}
+
+type lookupCall struct {
+	done   sync.WaitGroup
+	result []items.SuggestItem
+	err    error
+}
+
+func (g *lookupGroup) do(key string, fn func() ([]items.SuggestItem, error)) ([]items.SuggestItem, error) {
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		c.done.Wait()
+		return c.result, c.err
+	}
+	if g.calls == nil {
+		g.calls = make(map[string]*lookupCall)
+	}
+	c := &lookupCall{err: errLookupAborted}
+	c.done.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		c.done.Done()
+	}()
+	c.result, c.err = fn()
+	return c.result, c.err
+}
@@ -2,43 +2,84 @@ package internal
 
 import (
 	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+
 	"symspell/pkg/items"
+	verbositypkg "symspell/pkg/verbosity"
 )
 
 type topCache struct {
+	mu       sync.Mutex
 	capacity int
+	ttl      time.Duration // zero keeps entries until they are evicted
 	ll       *list.List
-	cache    map[string]*list.Element
+	cache    map[cacheKey]*list.Element
+	hits     atomic.Uint64
+	misses   atomic.Uint64
+}
+
+// cacheKey identifies a cached lookup; results for the same phrase differ by
+// verbosity and maximum edit distance.
+type cacheKey struct {
+	phrase          string
+	verbosity       verbositypkg.Verbosity
+	maxEditDistance int
 }
 
 type cacheEntry struct {
-	key string
-	val items.SuggestItem
+	key     cacheKey
+	val     items.SuggestItem
+	expires time.Time
 }
 
-func newTopCache(capacity int) *topCache {
+func newTopCache(capacity int, ttl time.Duration) *topCache {
 	return &topCache{
 		capacity: capacity,
+		ttl:      ttl,
 		ll:       list.New(),
-		cache:    make(map[string]*list.Element),
+		cache:    make(map[cacheKey]*list.Element),
 	}
 }
 
-func (c *topCache) Get(key string) (items.SuggestItem, bool) {
+func (c *topCache) Get(key cacheKey) (items.SuggestItem, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	if ele, ok := c.cache[key]; ok {
-		c.ll.MoveToFront(ele)
-		return ele.Value.(cacheEntry).val, true
+		entry := ele.Value.(cacheEntry)
+		if c.ttl == 0 || time.Now().Before(entry.expires) {
+			c.ll.MoveToFront(ele)
+			c.hits.Add(1)
+			return entry.val, true
+		}
+		c.ll.Remove(ele)
+		delete(c.cache, key)
 	}
+	c.misses.Add(1)
 	return items.SuggestItem{}, false
 }
 
-func (c *topCache) Add(key string, val items.SuggestItem) {
+func (c *topCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ll.Init()
+	clear(c.cache)
+}
+
+func (c *topCache) Add(key cacheKey, val items.SuggestItem) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := cacheEntry{key: key, val: val}
+	if c.ttl > 0 {
+		entry.expires = time.Now().Add(c.ttl)
+	}
 	if ele, ok := c.cache[key]; ok {
 		c.ll.MoveToFront(ele)
-		ele.Value = cacheEntry{key: key, val: val}
+		ele.Value = entry
 		return
 	}
-	ele := c.ll.PushFront(cacheEntry{key: key, val: val})
+	ele := c.ll.PushFront(entry)
 	c.cache[key] = ele
 	if c.ll.Len() > c.capacity {
 		if last := c.ll.Back(); last != nil {
@@ -48,3 +89,15 @@ func (c *topCache) Add(key string, val items.SuggestItem) {
 		}
 	}
 }
+
+// enabled reports whether the cache holds any results; a zero capacity
+// disables it.
+func (c *topCache) enabled() bool {
+	return c.capacity > 0
+}
+
+func (c *topCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
@@ -0,0 +1,345 @@
+package internal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"unsafe"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+var mappedIndexMagic = [4]byte{'S', 'Y', 'M', 'M'}
+
+const (
+	mappedIndexVersion    = 2 // version 1 stored uint32 counts
+	mappedIndexHeaderSize = 48
+	mappedSlotSize        = 16
+)
+
+// mappedTable is the on-disk open-addressing hash table of a mapped index.
+// Slots hold the xxhash of a delete key and its packed postings; a slot with
+// no postings is empty. Keys are not stored: a hash collision only adds
+// candidates that the distance check then rejects.
+type mappedTable struct {
+	slots []byte
+	mask  uint64
+	keys  int
+}
+
+// entries returns the filled slots as a hashed deletes index.
+func (t *mappedTable) entries() map[uint64]uint64 {
+	entries := make(map[uint64]uint64, t.keys)
+	for i := 0; i < len(t.slots); i += mappedSlotSize {
+		if v := binary.LittleEndian.Uint64(t.slots[i+8:]); v != 0 {
+			entries[binary.LittleEndian.Uint64(t.slots[i:])] = v
+		}
+	}
+	return entries
+}
+
+func (t *mappedTable) lookup(h uint64) (uint64, bool) {
+	for i := h & t.mask; ; i = (i + 1) & t.mask {
+		slot := t.slots[i*mappedSlotSize:]
+		v := binary.LittleEndian.Uint64(slot[8:])
+		if v == 0 {
+			return 0, false
+		}
+		if binary.LittleEndian.Uint64(slot) == h {
+			return v, true
+		}
+	}
+}
+
+// postings returns the main-index postings of a delete key. Keys rejected by
+// the delete filter are not probed.
+func (s *SymSpell) postings(key string) ([]uint32, bool) {
+	var h uint64
+	if s.deleteFilter != nil || s.hashedKeys() {
+		h = xxhash.Sum64String(key)
+		if s.deleteFilter != nil && !s.deleteFilter.mayContain(h) {
+			return nil, false
+		}
+	}
+	var v uint64
+	var found bool
+	switch {
+	case s.disk != nil:
+		return s.disk.postings(key)
+	case s.mappedDeletes != nil:
+		v, found = s.mappedDeletes.lookup(h)
+	case s.trieDeletes != nil:
+		return s.trieDeletes.postings(key, s.DeletesData)
+	case s.hashedDeletes != nil:
+		v, found = s.hashedDeletes[h]
+	default:
+		v, found = s.DeletesIdx[key]
+	}
+	if !found {
+		return nil, false
+	}
+	offset := uint32(v >> 32)
+	length := uint32(v)
+	return s.DeletesData[offset : offset+length], true
+}
+
+// eachDelete calls fn for every delete key of a string keyed index with the
+// position of its postings in DeletesData.
+func (s *SymSpell) eachDelete(fn func(del string, offset, length uint32)) {
+	if s.trieDeletes != nil {
+		t := s.trieDeletes
+		t.each(func(del string, rank uint32) {
+			fn(del, t.offsets[rank], t.offsets[rank+1]-t.offsets[rank])
+		})
+		return
+	}
+	for del, v := range s.DeletesIdx {
+		fn(del, uint32(v>>32), uint32(v))
+	}
+}
+
+// unmapDeletes replaces the deletes table of a mapped index with an
+// in-memory hashed one, for the operations that rewrite the index. The
+// postings are not copied.
+func (s *SymSpell) unmapDeletes() {
+	if s.mappedDeletes != nil {
+		s.hashedDeletes = s.mappedDeletes.entries()
+		s.mappedDeletes = nil
+	}
+}
+
+// SaveMappedIndex writes the index in a fixed-layout format that
+// LoadMappedIndex maps into memory instead of decoding it: the words as
+// offsets into one byte blob, the counts and postings as flat little-endian
+// uint64 and uint32 arrays and the delete keys as a hash table.
+func (s *SymSpell) SaveMappedIndex(w io.Writer) error {
+	if s.disk != nil {
+		return s.Clone().SaveMappedIndex(w)
+	}
+	if s.deletedCount > 0 {
+		s.Compact()
+	}
+	s.unmapDeletes()
+	s.mergeDelta()
+
+	deletes, data := s.hashedDeletes, s.DeletesData
+	if deletes == nil {
+		deletes, data = s.hashPostings()
+	}
+	slots := uint64(1) << bits.Len64(uint64(max(len(deletes), 1))*2-1)
+	table := make([]byte, slots*mappedSlotSize)
+	for h, v := range deletes {
+		i := h & (slots - 1)
+		for binary.LittleEndian.Uint64(table[i*mappedSlotSize+8:]) != 0 {
+			i = (i + 1) & (slots - 1)
+		}
+		binary.LittleEndian.PutUint64(table[i*mappedSlotSize:], h)
+		binary.LittleEndian.PutUint64(table[i*mappedSlotSize+8:], v)
+	}
+	blobLength := 0
+	for i := range s.wordSlots() {
+		blobLength += len(s.word(uint32(i)))
+	}
+	if blobLength > int(maxUint32) {
+		return errors.New("writing index: words exceed 4 GiB")
+	}
+
+	bw := bufio.NewWriter(w)
+	le := binary.LittleEndian
+	header := make([]byte, mappedIndexHeaderSize)
+	copy(header, mappedIndexMagic[:])
+	le.PutUint32(header[4:], mappedIndexVersion)
+	le.PutUint32(header[8:], uint32(s.MaxDictionaryEditDistance))
+	le.PutUint32(header[12:], uint32(s.PrefixLength))
+	le.PutUint32(header[16:], uint32(s.maxLength))
+	le.PutUint32(header[20:], uint32(s.wordSlots()))
+	le.PutUint32(header[24:], uint32(len(deletes)))
+	le.PutUint32(header[28:], uint32(blobLength))
+	le.PutUint64(header[32:], uint64(len(data)))
+	le.PutUint64(header[40:], slots)
+	bw.Write(header)
+
+	buf := make([]byte, 8)
+	writeUint32 := func(v uint32) {
+		le.PutUint32(buf, v)
+		bw.Write(buf[:4])
+	}
+	offset := uint32(0)
+	for i := range s.wordSlots() {
+		writeUint32(offset)
+		offset += uint32(len(s.word(uint32(i))))
+	}
+	writeUint32(offset)
+	bw.Write(make([]byte, mappedPadding(s.wordSlots()+1)))
+	for _, count := range s.counts {
+		le.PutUint64(buf, count)
+		bw.Write(buf)
+	}
+	for _, idx := range data {
+		writeUint32(idx)
+	}
+	bw.Write(make([]byte, mappedPadding(len(data))))
+	bw.Write(table)
+	for i := range s.wordSlots() {
+		bw.WriteString(s.word(uint32(i)))
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("writing index: %w", err)
+	}
+	return nil
+}
+
+// mappedPadding returns the bytes that align n uint32 values to 8 bytes.
+func mappedPadding(n int) int {
+	return n % 2 * 4
+}
+
+// LoadMappedIndex replaces the dictionary with an index written by
+// SaveMappedIndex, mapped read-only into memory where the platform supports
+// it, so that processes loading the same file share one copy. The postings
+// and the delete keys are used in place; the words, counts and the word
+// lookup map are built in memory. Runtime changes are supported, but
+// compacting or saving copies the deletes index into memory first.
+//
+// The returned closer unmaps the file. The instance must not be used after
+// it is called.
+func (s *SymSpell) LoadMappedIndex(path string) (io.Closer, error) {
+	if s.disk != nil {
+		return nil, errors.New("disk storage keeps its own index, use LoadIndex")
+	}
+	data, unmap, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.useMappedIndex(data); err != nil {
+		unmap()
+		return nil, err
+	}
+	return closerFunc(unmap), nil
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+func (s *SymSpell) useMappedIndex(data []byte) error {
+	index, err := s.parseMappedIndex(data)
+	if err != nil {
+		return err
+	}
+	s.replaceIndex(index.words, index.counts, index.postings, make(map[string]uint64), index.maxLength)
+	s.mappedDeletes = index.table
+	s.buildDeleteFilter()
+	return nil
+}
+
+// mappedIndex is a mapped index file viewed in place.
+type mappedIndex struct {
+	words     []string
+	counts    []uint64
+	postings  []uint32
+	table     *mappedTable
+	maxLength int
+}
+
+func (s *SymSpell) parseMappedIndex(data []byte) (mappedIndex, error) {
+	le := binary.LittleEndian
+	if len(data) < mappedIndexHeaderSize || [4]byte(data[:4]) != mappedIndexMagic {
+		return mappedIndex{}, errors.New("not a symspell mapped index")
+	}
+	version := le.Uint32(data[4:])
+	if version != 1 && version != mappedIndexVersion {
+		return mappedIndex{}, fmt.Errorf("unsupported mapped index version %d", version)
+	}
+	maxEditDistance, prefixLength := int(le.Uint32(data[8:])), int(le.Uint32(data[12:]))
+	if maxEditDistance != s.MaxDictionaryEditDistance || prefixLength != s.PrefixLength {
+		return mappedIndex{}, fmt.Errorf("index built with maxDictionaryEditDistance=%d prefixLength=%d, instance uses %d and %d",
+			maxEditDistance, prefixLength, s.MaxDictionaryEditDistance, s.PrefixLength)
+	}
+	maxLength := int(le.Uint32(data[16:]))
+	wordCount := uint64(le.Uint32(data[20:]))
+	keyCount := int(le.Uint32(data[24:]))
+	blobLength := uint64(le.Uint32(data[28:]))
+	dataLength := le.Uint64(data[32:])
+	slots := le.Uint64(data[40:])
+
+	offsetsStart := uint64(mappedIndexHeaderSize)
+	countsStart := offsetsStart + (wordCount+1)*4
+	var postingsStart uint64
+	if version == 1 {
+		postingsStart = countsStart + wordCount*4 + uint64(mappedPadding(int(2*wordCount+1)))
+	} else {
+		countsStart += uint64(mappedPadding(int(wordCount + 1)))
+		postingsStart = countsStart + wordCount*8
+	}
+	if slots == 0 || slots&(slots-1) != 0 || slots > uint64(len(data))/mappedSlotSize || dataLength > uint64(maxUint32) {
+		return mappedIndex{}, errors.New("corrupt mapped index: size mismatch")
+	}
+	tableStart := postingsStart + dataLength*4 + uint64(mappedPadding(int(dataLength)))
+	blobStart := tableStart + slots*mappedSlotSize
+	if blobStart+blobLength != uint64(len(data)) {
+		return mappedIndex{}, errors.New("corrupt mapped index: size mismatch")
+	}
+	table := data[tableStart:blobStart]
+	empty := false
+	for i := uint64(0); i < slots; i++ {
+		v := le.Uint64(table[i*mappedSlotSize+8:])
+		if v == 0 {
+			empty = true
+		} else if v>>32+v&0xffffffff > dataLength {
+			return mappedIndex{}, errors.New("corrupt mapped index: postings out of range")
+		}
+	}
+	if !empty {
+		return mappedIndex{}, errors.New("corrupt mapped index: no empty slot")
+	}
+
+	// Words are copied out of the mapping, as they outlive it in suggestions.
+	blob := string(data[blobStart:])
+	words := make([]string, wordCount)
+	counts := make([]uint64, wordCount)
+	for i := range words {
+		start, end := le.Uint32(data[offsetsStart+uint64(i)*4:]), le.Uint32(data[offsetsStart+uint64(i+1)*4:])
+		if start > end || uint64(end) > blobLength {
+			return mappedIndex{}, errors.New("corrupt mapped index: word out of range")
+		}
+		words[i] = blob[start:end]
+		if version == 1 {
+			counts[i] = uint64(le.Uint32(data[countsStart+uint64(i)*4:]))
+		} else {
+			counts[i] = le.Uint64(data[countsStart+uint64(i)*8:])
+		}
+	}
+	postings := mappedUint32s(data[postingsStart:tableStart], int(dataLength))
+	for _, idx := range postings {
+		if uint64(idx) >= wordCount {
+			return mappedIndex{}, errors.New("corrupt mapped index: posting out of range")
+		}
+	}
+	return mappedIndex{
+		words:     words,
+		counts:    counts,
+		postings:  postings,
+		table:     &mappedTable{slots: table, mask: slots - 1, keys: keyCount},
+		maxLength: maxLength,
+	}, nil
+}
+
+// mappedUint32s views little-endian uint32 values in place on little-endian
+// hosts and decodes a copy elsewhere.
+func mappedUint32s(b []byte, n int) []uint32 {
+	if n == 0 {
+		return nil
+	}
+	if binary.NativeEndian.Uint16([]byte{1, 0}) == 1 {
+		return unsafe.Slice((*uint32)(unsafe.Pointer(&b[0])), n)
+	}
+	values := make([]uint32, n)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint32(b[i*4:])
+	}
+	return values
+}
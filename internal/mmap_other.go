@@ -0,0 +1,21 @@
+//go:build !unix
+
+package internal
+
+import (
+	"io"
+)
+
+// mapFile reads the file at path into memory on platforms without mmap.
+func mapFile(path string) ([]byte, func() error, error) {
+	file, err := openDictionary(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return nil }, nil
+}
@@ -0,0 +1,30 @@
+//go:build unix
+
+package internal
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// mapFile maps the file at path read-only into memory.
+func mapFile(path string) ([]byte, func() error, error) {
+	file, err := openDictionary(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.Size() == 0 {
+		return nil, func() error { return nil }, nil
+	}
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, fmt.Errorf("mapping %s: %w", path, os.NewSyscallError("mmap", err))
+	}
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
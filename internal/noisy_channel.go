@@ -0,0 +1,32 @@
+package internal
+
+import (
+	"math"
+	"sort"
+
+	"symspell/pkg/items"
+)
+
+// sortByChannel orders suggestions for phrase by their noisy-channel score,
+// log10 P(term) + log10 P(phrase | term) with P(term) = count/N, see
+// options.WithNoisyChannel. N is left out since it shifts every score alike.
+// The channel model is called once per suggestion.
+func (s *SymSpell) sortByChannel(phrase string, suggestions []items.SuggestItem) {
+	if len(suggestions) < 2 {
+		return
+	}
+	type scored struct {
+		item  items.SuggestItem
+		score float64
+	}
+	ranked := make([]scored, len(suggestions))
+	for i, item := range suggestions {
+		ranked[i] = scored{item, math.Log10(float64(item.Count)) + s.channelModel.LogProb(phrase, item.Term)}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].score > ranked[j].score
+	})
+	for i := range ranked {
+		suggestions[i] = ranked[i].item
+	}
+}
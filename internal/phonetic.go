@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"math"
+	"sort"
+
+	"symspell/pkg/items"
+	verbositypkg "symspell/pkg/verbosity"
+)
+
+func (s *SymSpell) buildPhoneticIndex() {
+	if s.phoneticEncoder == nil {
+		return
+	}
+	s.phoneticIdx = make(map[string][]uint32)
+	for idx := range s.wordSlots() {
+		s.addPhoneticForIndex(s.word(uint32(idx)), uint32(idx))
+	}
+}
+
+func (s *SymSpell) addPhoneticForIndex(key string, index uint32) {
+	if s.phoneticEncoder == nil {
+		return
+	}
+	if s.phoneticIdx == nil {
+		s.phoneticIdx = make(map[string][]uint32)
+	}
+	for _, code := range s.phoneticEncoder.Encode(key) {
+		s.phoneticIdx[code] = append(s.phoneticIdx[code], index)
+	}
+}
+
+// mergePhoneticCandidates adds words sharing a phonetic code with the phrase to
+// the suggestions. Phonetic matches are ranked PhoneticWeight edits closer than
+// their real distance, so they may be admitted beyond maxEditDistance.
+func (s *SymSpell) mergePhoneticCandidates(maxEditDistance int, cp *candidateProcessor) {
+	codes := s.phoneticEncoder.Encode(cp.phrase)
+	if len(codes) == 0 {
+		return
+	}
+	found := make(map[string]struct{}, len(cp.suggestions))
+	for _, suggestion := range cp.suggestions {
+		found[suggestion.Term] = struct{}{}
+	}
+	limit := maxEditDistance + int(math.Ceil(s.phoneticWeight))
+	for _, code := range codes {
+		for _, idx := range s.phoneticIdx[code] {
+			if !s.isLiveIndex(idx) {
+				continue
+			}
+			word := s.word(idx)
+			cp.phoneticMatches[word] = struct{}{}
+			if _, ok := found[word]; ok {
+				continue
+			}
+			found[word] = struct{}{}
+			if _, ok := s.blacklist[word]; ok {
+				continue
+			}
+			distance := s.distanceComparer.DistanceMax(cp.phrase, word, limit)
+			if distance > limit {
+				continue
+			}
+			item := items.SuggestItem{Term: word, Distance: distance, Count: itemCount(s.counts[idx])}
+			if s.SuggestionFilter != nil && !s.SuggestionFilter(item) {
+				continue
+			}
+			if s.phoneticRank(cp, item) > float64(maxEditDistance) {
+				continue
+			}
+			cp.suggestions = append(cp.suggestions, item)
+		}
+	}
+}
+
+func (s *SymSpell) phoneticRank(cp *candidateProcessor, item items.SuggestItem) float64 {
+	if _, ok := cp.phoneticMatches[item.Term]; ok {
+		return math.Max(0, float64(item.Distance)-s.phoneticWeight)
+	}
+	return float64(item.Distance)
+}
+
+// sortPhonetic orders suggestions by phonetic rank and count; cutPhonetic
+// trims them according to verbosity.
+func (s *SymSpell) sortPhonetic(cp *candidateProcessor) {
+	if len(cp.phoneticMatches) == 0 || len(cp.suggestions) < 2 {
+		return
+	}
+	sort.SliceStable(cp.suggestions, func(i, j int) bool {
+		ri, rj := s.phoneticRank(cp, cp.suggestions[i]), s.phoneticRank(cp, cp.suggestions[j])
+		if ri == rj {
+			return cp.suggestions[i].Count > cp.suggestions[j].Count
+		}
+		return ri < rj
+	})
+}
+
+// cutPhonetic applies the verbosity cut to suggestions that phonetic
+// matching extended beyond the closest distance, once they are in their final
+// order.
+func (s *SymSpell) cutPhonetic(cp *candidateProcessor) {
+	if len(cp.phoneticMatches) == 0 || len(cp.suggestions) < 2 {
+		return
+	}
+	switch cp.verbosity {
+	case verbositypkg.Top:
+		cp.suggestions = cp.suggestions[:1]
+	case verbositypkg.Closest:
+		best := s.phoneticRank(cp, cp.suggestions[0])
+		n := 1
+		for n < len(cp.suggestions) && s.phoneticRank(cp, cp.suggestions[n]) == best {
+			n++
+		}
+		cp.suggestions = cp.suggestions[:n]
+	}
+}
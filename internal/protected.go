@@ -0,0 +1,27 @@
+package internal
+
+import (
+	"io"
+	"strings"
+)
+
+// LoadProtectedWords reads one protected word per line. Empty lines and lines
+// starting with # are skipped.
+func (s *SymSpell) LoadProtectedWords(r io.Reader) error {
+	scanner := s.newLineScanner(r)
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		s.protected[strings.ToLower(word)] = struct{}{}
+	}
+	s.topCache.Clear()
+	return scanner.Err()
+}
+
+// IsProtected reports whether word is never corrected.
+func (s *SymSpell) IsProtected(word string) bool {
+	_, ok := s.protected[strings.ToLower(word)]
+	return ok
+}
@@ -0,0 +1,36 @@
+package internal
+
+import (
+	"symspell/pkg/stats"
+)
+
+// PruneDictionary removes every word whose count is below minCount, except
+// user words, and compacts the index. Pending below-threshold counts under
+// the floor are dropped as well.
+func (s *SymSpell) PruneDictionary(minCount uint64) stats.CompactStats {
+	for term, count := range s.BelowThresholdWords {
+		if count < minCount {
+			delete(s.BelowThresholdWords, term)
+		}
+	}
+	if len(s.deleted) < s.wordSlots() {
+		s.deleted = append(s.deleted, make([]bool, s.wordSlots()-len(s.deleted))...)
+	}
+	maxLength := 0
+	for i := range s.wordSlots() {
+		if s.deleted[i] {
+			continue
+		}
+		term := s.word(uint32(i))
+		if _, user := s.userWords[term]; s.counts[i] >= minCount || user {
+			maxLength = max(maxLength, len(term))
+			continue
+		}
+		s.unindexWord(term)
+		s.deleted[i] = true
+		s.deletedCount++
+	}
+	s.maxLength = maxLength
+	s.topCache.Clear()
+	return s.Compact()
+}
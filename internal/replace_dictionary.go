@@ -0,0 +1,94 @@
+package internal
+
+import "errors"
+
+// Replacement is a dictionary ready to be swapped into an instance by
+// CommitReplacement, with the indexes derived from it already built.
+type Replacement struct {
+	next        *SymSpell
+	userWords   map[string]userWord
+	userIdx     *SymSpell
+	userVersion uint64 // of the user dictionary the split was made for
+}
+
+// ReplaceDictionary replaces the dictionary and deletes index of s with those
+// of next, typically a new version of the dictionary file loaded off to the
+// side. Everything else stays with s: the settings, the user dictionary, the
+// blacklist, protected words, boost lists and bigrams. next must be built
+// with the same edit distance, prefix length and index layout as s, and must
+// not be used afterwards.
+//
+// ReplaceDictionary is PrepareReplacement followed by CommitReplacement. The
+// thread-safe instance runs the first under its read lock, so that lookups go
+// on while the indexes are built, and only the second under its write lock.
+func (s *SymSpell) ReplaceDictionary(next *SymSpell) error {
+	r, err := s.PrepareReplacement(next)
+	if err != nil {
+		return err
+	}
+	s.CommitReplacement(r)
+	return nil
+}
+
+// PrepareReplacement builds on next, with the settings of s, everything
+// ReplaceDictionary derives from the dictionary: the delete filter, the
+// phonetic and diacritic indexes and the split of the user words of s
+// between userIdx and the base dictionary. It only reads s.
+func (s *SymSpell) PrepareReplacement(next *SymSpell) (*Replacement, error) {
+	switch {
+	case s.disk != nil || next.disk != nil:
+		return nil, errors.New("cannot replace the dictionary of a disk-backed instance")
+	case next.MaxDictionaryEditDistance != s.MaxDictionaryEditDistance || next.PrefixLength != s.PrefixLength:
+		return nil, errors.New("replacement dictionary has a different edit distance or prefix length")
+	case next.IndexBackend != s.IndexBackend || next.hashDeletes != s.hashDeletes:
+		return nil, errors.New("replacement dictionary has a different index layout")
+	}
+	next.distanceComparer, next.customDistance, next.weightedComparer = s.distanceComparer, s.customDistance, s.weightedComparer
+	next.deleteFilterBits = s.deleteFilterBits
+	next.buildDeleteFilter()
+	next.phoneticEncoder, next.phoneticIdx = s.phoneticEncoder, nil
+	next.buildPhoneticIndex()
+	next.ignoreDiacritics, next.accentFree, next.accentOriginals = s.ignoreDiacritics, nil, nil
+	next.buildDiacriticIndex()
+	userWords, userIdx := s.splitUserWords(next)
+	return &Replacement{next: next, userWords: userWords, userIdx: userIdx, userVersion: s.userVersion}, nil
+}
+
+// CommitReplacement swaps the dictionary prepared by PrepareReplacement into
+// s. Only the user words, if they changed since, are split anew.
+func (s *SymSpell) CommitReplacement(r *Replacement) {
+	next := r.next
+	s.takeIndex(next)
+	s.BelowThresholdWords = next.BelowThresholdWords
+	s.byFrequency = nil
+	s.deleteFilter = next.deleteFilter
+	s.phoneticIdx = next.phoneticIdx
+	s.accentFree, s.accentOriginals = next.accentFree, next.accentOriginals
+	if r.userVersion != s.userVersion {
+		r.userWords, r.userIdx = s.splitUserWords(s)
+	}
+	s.userWords, s.userIdx = r.userWords, r.userIdx
+	s.topCache.Clear()
+}
+
+// splitUserWords returns the user words of s split between a new userIdx and
+// the base dictionary of dictionary.
+func (s *SymSpell) splitUserWords(dictionary *SymSpell) (map[string]userWord, *SymSpell) {
+	if len(s.userWords) == 0 {
+		return s.userWords, nil
+	}
+	userWords := make(map[string]userWord, len(s.userWords))
+	var userIdx *SymSpell
+	for term, word := range s.userWords {
+		_, inBase := dictionary.wordIndex(term)
+		word.added = !inBase
+		if word.added {
+			if userIdx == nil {
+				userIdx = s.newUserIndex()
+			}
+			userIdx.CreateDictionaryEntry(term, word.count)
+		}
+		userWords[term] = word
+	}
+	return userWords, userIdx
+}
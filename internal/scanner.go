@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// lineScanner wraps bufio.Scanner with the configured line length limit and
+// reports over-long lines as errors instead of silently stopping. gzip and
+// zstd input is decompressed transparently.
+type lineScanner struct {
+	*bufio.Scanner
+	line          int
+	maxLineLength int
+	err           error  // failure to set up decompression
+	release       func() // releases the decompressor once scanning stops
+}
+
+func (s *SymSpell) newLineScanner(r io.Reader) *lineScanner {
+	r, release, err := decompress(r)
+	if err != nil {
+		r, release = strings.NewReader(""), func() {}
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, min(s.MaxLineLength, bufio.MaxScanTokenSize)), s.MaxLineLength)
+	return &lineScanner{Scanner: scanner, maxLineLength: s.MaxLineLength, err: err, release: release}
+}
+
+func (l *lineScanner) Scan() bool {
+	if l.Scanner.Scan() {
+		l.line++
+		return true
+	}
+	l.release()
+	return false
+}
+
+func (l *lineScanner) Err() error {
+	if l.err != nil {
+		return l.err
+	}
+	err := l.Scanner.Err()
+	if errors.Is(err, bufio.ErrTooLong) {
+		return fmt.Errorf("line %d exceeds the maximum line length of %d bytes: %w", l.line+1, l.maxLineLength, err)
+	}
+	if err != nil {
+		return fmt.Errorf("reading line %d: %w", l.line+1, err)
+	}
+	return nil
+}
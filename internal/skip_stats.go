@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"sync/atomic"
+
+	"symspell/pkg/stats"
+)
+
+// skipReason classifies why a candidate suggestion was discarded.
+type skipReason int
+
+const (
+	skipLengthDiff skipReason = iota
+	skipAlreadyConsidered
+	skipDistanceCutoff
+	skipPrefixMismatch
+	skipReasonCount
+)
+
+// skipCounters aggregates skip reasons over all lookups of an instance.
+type skipCounters struct {
+	lookups atomic.Uint64
+	reasons [skipReasonCount]atomic.Uint64
+}
+
+func (s *SymSpell) recordSkips(cp *candidateProcessor) {
+	s.countLookup()
+	for reason, n := range cp.skips {
+		if n > 0 {
+			s.skipStats.reasons[reason].Add(n)
+		}
+	}
+}
+
+// countLookup counts a lookup, including one answered without a candidate
+// search, which skips no candidates.
+func (s *SymSpell) countLookup() {
+	s.skipStats.lookups.Add(1)
+}
+
+// SkipStats returns the candidate skip counters aggregated since creation or
+// the last ResetSkipStats call.
+func (s *SymSpell) SkipStats() stats.SkipStats {
+	return stats.SkipStats{
+		Lookups:           s.skipStats.lookups.Load(),
+		LengthDiff:        s.skipStats.reasons[skipLengthDiff].Load(),
+		AlreadyConsidered: s.skipStats.reasons[skipAlreadyConsidered].Load(),
+		DistanceCutoff:    s.skipStats.reasons[skipDistanceCutoff].Load(),
+		PrefixMismatch:    s.skipStats.reasons[skipPrefixMismatch].Load(),
+	}
+}
+
+// ResetSkipStats zeroes the candidate skip counters.
+func (s *SymSpell) ResetSkipStats() {
+	s.skipStats.lookups.Store(0)
+	for i := range s.skipStats.reasons {
+		s.skipStats.reasons[i].Store(0)
+	}
+}
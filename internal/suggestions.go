@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"context"
+	"iter"
+
+	"symspell/pkg/items"
+	verbositypkg "symspell/pkg/verbosity"
+)
+
+// Suggestions yields the suggestions for phrase. With verbosity All they are
+// yielded as the candidate search discovers them, unsorted and without
+// collecting them first, and breaking out of the loop stops the search. The
+// exact match is yielded first even if a more frequent alternative would drop
+// it from the Lookup result. Other verbosities, and configurations that
+// rerank the whole result (case preservation, diacritics, transliteration,
+// phonetic matching, escalation, a Ranker or noisy-channel ranking), yield the Lookup result in order. Invalid
+// input yields nothing.
+func (s *SymSpell) Suggestions(
+	phrase string,
+	verbosity verbositypkg.Verbosity,
+	maxEditDistance int,
+) iter.Seq[items.SuggestItem] {
+	return func(yield func(items.SuggestItem) bool) {
+		if verbosity != verbositypkg.All || !s.canStream() {
+			result, err := s.Lookup(phrase, verbosity, maxEditDistance)
+			if err != nil {
+				return
+			}
+			for _, item := range result {
+				if !yield(item) {
+					return
+				}
+			}
+			return
+		}
+		sink := &suggestionSink{yield: yield}
+		ctx := withSuggestionSink(context.Background(), sink)
+		// Words answered without a candidate search come back in result.
+		result, err := s.lookupTerm(ctx, phrase, verbosity, maxEditDistance, true)
+		if err != nil {
+			return
+		}
+		for _, item := range result {
+			if !sink.emit(item) {
+				return
+			}
+		}
+	}
+}
+
+// canStream reports whether the candidate search alone decides the All
+// result, so that suggestions can be passed on as they are found.
+func (s *SymSpell) canStream() bool {
+	return !s.PreserveCase && !s.ignoreDiacritics && len(s.transliterators) == 0 &&
+		s.phoneticEncoder == nil && s.EscalationPolicy == nil &&
+		s.Ranker == nil && s.channelModel == nil
+}
+
+// suggestionSink receives the suggestions of a streaming lookup.
+type suggestionSink struct {
+	yield   func(items.SuggestItem) bool
+	n       int
+	stopped bool
+}
+
+// emit passes item on and reports whether the consumer wants more.
+func (k *suggestionSink) emit(item items.SuggestItem) bool {
+	if k.stopped {
+		return false
+	}
+	k.n++
+	if !k.yield(item) {
+		k.stopped = true
+	}
+	return !k.stopped
+}
+
+// empty reports whether nothing has been streamed. A nil sink is empty.
+func (k *suggestionSink) empty() bool {
+	return k == nil || k.n == 0
+}
+
+type suggestionSinkKey struct{}
+
+// withSuggestionSink streams the suggestions of the lookups run with the
+// returned context to sink instead of collecting them.
+func withSuggestionSink(ctx context.Context, sink *suggestionSink) context.Context {
+	return context.WithValue(ctx, suggestionSinkKey{}, sink)
+}
+
+func suggestionSinkFrom(ctx context.Context) *suggestionSink {
+	sink, _ := ctx.Value(suggestionSinkKey{}).(*suggestionSink)
+	return sink
+}
@@ -1,21 +1,31 @@
 package internal
 
 import (
-	"bufio"
+	"context"
 	"errors"
 	"fmt"
-	"log"
-	"os"
+	"io"
+	"log/slog"
+	"math"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"unicode/utf8"
 
+	"golang.org/x/text/language"
+
+	"symspell/pkg/channel"
 	"symspell/pkg/editdistance"
 	"symspell/pkg/options"
+	"symspell/pkg/phonetic"
+	"symspell/pkg/translit"
 )
 
-const maxUint32 = ^uint32(0)
+const (
+	maxUint32 = ^uint32(0)
+	maxCount  = ^uint64(0)
+)
 
 // SymSpell represents the Symmetric Delete spelling correction algorithm.
 type SymSpell struct {
@@ -24,25 +34,102 @@ type SymSpell struct {
 	CountThreshold            int
 	SplitThreshold            int
 	PreserveCase              bool
+	CaseFolding               bool
+	CaseLocale                language.Tag
 	SplitWordBySpace          bool
 	SplitWordAndNumber        bool
 	MinimumCharToChange       int
 	FrequencyThreshold        int // Новое поле: минимальная частота для точных совпадений
 	FrequencyMultiplier       int // Новое поле: множитель для сравнения частот
+	ContextScorer             options.ContextScorer
+	InvalidUTF8Policy         options.InvalidUTF8Policy
+	UnicodeNormalization      options.NormalizationForm
+	CompoundWorkers           int
+	LookupWorkers             int
+	ParallelLookupMinLength   int
+	CompoundDistanceMemo      int
+	CompoundBeamWidth         int
+	LoadWorkers               int
+	CompactStorage            bool
+	IndexBackend              options.IndexBackend
+	StorageBackend            options.StorageBackend
+	StoragePath               string
+	MaxLineLength             int
+	EscalationPolicy          options.EscalationPolicy
+	CoarseEditDistance        int
+	EarlyStopCandidates       int
+	EarlyStopCount            int
+	ThreadSafe                bool
+	IncludeUnknown            bool
+	ShortWordLength           int
+	SuggestionFilter          options.SuggestionFilter
+	Ranker                    options.Ranker
+	logProbRanking            bool // Ranker is logProbRanker, see options.WithLogProbRanking
+	channelModel              channel.Model
+	languageModel             options.LanguageModel
+	cjkSegmentLength          int // 0 unless options.WithCJKSegmentation
+	compoundMinPartLength     int // 0 unless options.WithCompoundSplitting
+	linkingMorphemes          []string
 	Words                     map[string]uint32
-	BelowThresholdWords       map[string]uint32
+	BelowThresholdWords       map[string]uint64
 	DeletesIdx                map[string]uint64
 	DeletesData               []uint32
 	ExactTransform            map[string]string
 	words                     []string
-	counts                    []uint32
+	arena                     *wordArena // holds the words instead of words and Words, see CompactStorage
+	counts                    []uint64
 	maxLength                 int
 	distanceComparer          editdistance.IEditDistance
+	customDistance            bool // unit-cost shortcuts in lookup do not apply
+	weightedComparer          editdistance.IWeightedEditDistance
 	// lookup compound
 	N              float64
 	Bigrams        map[string]uint32
 	BigramCountMin uint32
 	topCache       *topCache
+	boostLists     map[string]*boostList
+	byFrequency    []uint32
+	byTerm         []uint32
+	// phonetic index
+	phoneticEncoder phonetic.Encoder
+	phoneticWeight  float64
+	phoneticIdx     map[string][]uint32
+	skipStats       *skipCounters
+	blacklist       map[string]struct{}
+	protected       map[string]struct{} // lowercased
+	ignoreTokens    []options.TokenClassifier
+	userWords       map[string]userWord
+	userVersion     uint64    // bumped on every change of userWords
+	userIdx         *SymSpell // user words missing from the base dictionary
+	logger          *slog.Logger
+	casing          caseMapping
+	layouts         []map[rune]rune
+	transliterators []translit.Transliterator
+	// accent-free forms of words with diacritics, see WithIgnoreDiacritics
+	ignoreDiacritics bool
+	accentFree       *SymSpell
+	accentOriginals  map[string][]uint32
+	// tombstones of deleted words, compacted by Compact
+	deleted      []bool
+	deletedCount int
+	// postings of words added at runtime, merged into DeletesData by mergeDelta
+	deltaIdx      map[string][]uint32
+	deltaPostings int
+	// deletes table of an index loaded by LoadMappedIndex, used instead of
+	// DeletesIdx until the index is rebuilt
+	mappedDeletes *mappedTable
+	// DeletesIdx keyed by xxhash of the delete keys, see WithHashedDeleteKeys
+	hashDeletes   bool
+	hashedDeletes map[uint64]uint64
+	// deletes index of IndexBackendTrie, used instead of DeletesIdx
+	trieDeletes *deleteTrie
+	// Bloom filter of the main delete keys, see options.WithDeleteKeyFilter
+	deleteFilterBits int
+	deleteFilter     *deleteFilter
+	// store of StorageDisk, holding the deletes index instead of DeletesIdx
+	disk *diskStore
+	// shares concurrent identical lookups, see options.WithSingleflight
+	lookupGroup *lookupGroup
 }
 
 // NewSymSpell is the constructor for the SymSpell struct.
@@ -52,125 +139,278 @@ func NewSymSpell(opt ...options.Options) (*SymSpell, error) {
 		config.Apply(&opts)
 	}
 	if opts.MaxDictionaryEditDistance < 0 {
-		return nil, errors.New("maxDictionaryEditDistance cannot be negative")
+		return nil, fmt.Errorf("%w: maxDictionaryEditDistance cannot be negative", ErrInvalidOptions)
 	}
 	if opts.PrefixLength < 1 {
-		return nil, errors.New("prefixLength cannot be less than 1")
+		return nil, fmt.Errorf("%w: prefixLength cannot be less than 1", ErrInvalidOptions)
 	}
 	if opts.PrefixLength <= opts.MaxDictionaryEditDistance {
-		return nil, errors.New("prefixLength must be greater than maxDictionaryEditDistance")
+		return nil, fmt.Errorf("%w: prefixLength must be greater than maxDictionaryEditDistance", ErrInvalidOptions)
 	}
 	if opts.CountThreshold < 0 {
-		return nil, errors.New("countThreshold cannot be negative")
+		return nil, fmt.Errorf("%w: countThreshold cannot be negative", ErrInvalidOptions)
 	}
 	if opts.FrequencyThreshold < 0 {
-		return nil, errors.New("frequencyThreshold cannot be negative")
+		return nil, fmt.Errorf("%w: frequencyThreshold cannot be negative", ErrInvalidOptions)
 	}
 	if opts.FrequencyMultiplier <= 1 {
-		return nil, errors.New("frequencyMultiplier must be greater than 1")
+		return nil, fmt.Errorf("%w: frequencyMultiplier must be greater than 1", ErrInvalidOptions)
+	}
+	if opts.EarlyStopCandidates < 0 || opts.EarlyStopCount < 0 {
+		return nil, fmt.Errorf("%w: early termination limits cannot be negative", ErrInvalidOptions)
+	}
+	if opts.CoarseEditDistance < 0 {
+		return nil, fmt.Errorf("%w: coarseEditDistance cannot be negative", ErrInvalidOptions)
+	}
+	if opts.MaxLineLength < 1 {
+		return nil, fmt.Errorf("%w: maxLineLength must be positive", ErrInvalidOptions)
+	}
+	if opts.ShortWordLength < 0 {
+		return nil, fmt.Errorf("%w: shortWordLength cannot be negative", ErrInvalidOptions)
+	}
+	if opts.CompoundWorkers < 0 {
+		return nil, fmt.Errorf("%w: compoundWorkers cannot be negative", ErrInvalidOptions)
+	}
+	if opts.LookupWorkers < 0 || opts.ParallelLookupMinLength < 0 {
+		return nil, fmt.Errorf("%w: parallel lookup settings cannot be negative", ErrInvalidOptions)
+	}
+	if opts.CompoundDistanceMemo < 0 {
+		return nil, fmt.Errorf("%w: compoundDistanceMemo cannot be negative", ErrInvalidOptions)
+	}
+	if slices.Contains(opts.LinkingMorphemes, "") {
+		return nil, fmt.Errorf("%w: linking morphemes cannot be empty", ErrInvalidOptions)
+	}
+	if opts.CompoundBeamWidth < 0 {
+		return nil, fmt.Errorf("%w: compoundBeamWidth cannot be negative", ErrInvalidOptions)
+	}
+	if opts.LookupCacheSize < 0 {
+		return nil, fmt.Errorf("%w: lookupCacheSize cannot be negative", ErrInvalidOptions)
+	}
+	if opts.LookupCacheTTL < 0 {
+		return nil, fmt.Errorf("%w: lookupCacheTTL cannot be negative", ErrInvalidOptions)
+	}
+	if opts.LoadWorkers < 0 {
+		return nil, fmt.Errorf("%w: loadWorkers cannot be negative", ErrInvalidOptions)
+	}
+	if opts.DeleteKeyFilterBits < 0 {
+		return nil, fmt.Errorf("%w: deleteKeyFilterBits cannot be negative", ErrInvalidOptions)
+	}
+	if opts.IndexBackend != options.IndexBackendMap && opts.IndexBackend != options.IndexBackendTrie {
+		return nil, fmt.Errorf("%w: unknown index backend %v", ErrInvalidOptions, opts.IndexBackend)
+	}
+	if opts.HashedDeleteKeys && opts.IndexBackend != options.IndexBackendMap {
+		return nil, fmt.Errorf("%w: hashed delete keys require the map index backend", ErrInvalidOptions)
+	}
+	if opts.StorageBackend != options.StorageMemory && opts.StorageBackend != options.StorageDisk {
+		return nil, fmt.Errorf("%w: unknown storage backend %v", ErrInvalidOptions, opts.StorageBackend)
+	}
+	if opts.StorageBackend == options.StorageDisk && opts.StoragePath == "" {
+		return nil, fmt.Errorf("%w: disk storage requires a path", ErrInvalidOptions)
+	}
+	if opts.StorageBackend == options.StorageDisk && opts.IndexBackend != options.IndexBackendMap {
+		return nil, fmt.Errorf("%w: disk storage requires the map index backend", ErrInvalidOptions)
+	}
+	if opts.LogProbRanking && opts.Ranker != nil {
+		return nil, fmt.Errorf("%w: log-probability ranking cannot be combined with a custom ranker", ErrInvalidOptions)
+	}
+	if opts.LogProbRanking && !(opts.EditPenalty >= 0) {
+		return nil, fmt.Errorf("%w: editPenalty cannot be negative", ErrInvalidOptions)
+	}
+	if opts.ChannelModel != nil && (opts.Ranker != nil || opts.LogProbRanking) {
+		return nil, fmt.Errorf("%w: noisy-channel ranking cannot be combined with another ranking", ErrInvalidOptions)
+	}
+	if opts.PhoneticWeight < 0 {
+		return nil, fmt.Errorf("%w: phoneticWeight cannot be negative", ErrInvalidOptions)
+	}
+	if opts.DistanceComparer == nil && !editdistance.IsSupported(opts.EditDistanceAlgorithm) {
+		return nil, fmt.Errorf("%w: unsupported edit distance algorithm %q", ErrInvalidOptions, opts.EditDistanceAlgorithm)
+	}
+
+	var distanceComparer editdistance.IEditDistance = editdistance.NewEditDistance(opts.EditDistanceAlgorithm)
+	if opts.DistanceComparer != nil {
+		distanceComparer = opts.DistanceComparer
+	}
+	weightedComparer, _ := distanceComparer.(editdistance.IWeightedEditDistance)
+
+	blacklist := make(map[string]struct{}, len(opts.SuggestionBlacklist))
+	for _, word := range opts.SuggestionBlacklist {
+		blacklist[word] = struct{}{}
+	}
+	layouts, err := newLayoutMaps(opts.LayoutPairs)
+	if err != nil {
+		return nil, err
+	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	protected := make(map[string]struct{}, len(opts.ProtectedWords))
+	for _, word := range opts.ProtectedWords {
+		protected[strings.ToLower(word)] = struct{}{}
 	}
 
-	return &SymSpell{
+	s := &SymSpell{
 		MaxDictionaryEditDistance: opts.MaxDictionaryEditDistance,
 		PrefixLength:              opts.PrefixLength,
 		CountThreshold:            opts.CountThreshold,
 		SplitThreshold:            opts.SplitItemThreshold,
 		PreserveCase:              opts.PreserveCase,
+		CaseFolding:               opts.CaseFolding,
+		CaseLocale:                opts.CaseLocale,
 		SplitWordBySpace:          opts.SplitWordBySpace,
 		SplitWordAndNumber:        opts.SplitWordAndNumber,
 		MinimumCharToChange:       opts.MinimumCharacterToChange,
 		FrequencyThreshold:        opts.FrequencyThreshold,
 		FrequencyMultiplier:       opts.FrequencyMultiplier,
+		ContextScorer:             opts.ContextScorer,
+		InvalidUTF8Policy:         opts.InvalidUTF8Policy,
+		UnicodeNormalization:      opts.UnicodeNormalization,
+		CompoundWorkers:           opts.CompoundWorkers,
+		LookupWorkers:             opts.LookupWorkers,
+		ParallelLookupMinLength:   opts.ParallelLookupMinLength,
+		CompoundDistanceMemo:      opts.CompoundDistanceMemo,
+		CompoundBeamWidth:         opts.CompoundBeamWidth,
+		LoadWorkers:               opts.LoadWorkers,
+		hashDeletes:               opts.HashedDeleteKeys,
+		deleteFilterBits:          opts.DeleteKeyFilterBits,
+		CompactStorage:            opts.CompactStorage,
+		IndexBackend:              opts.IndexBackend,
+		StorageBackend:            opts.StorageBackend,
+		StoragePath:               opts.StoragePath,
+		MaxLineLength:             opts.MaxLineLength,
+		EscalationPolicy:          opts.EscalationPolicy,
+		CoarseEditDistance:        opts.CoarseEditDistance,
+		EarlyStopCandidates:       opts.EarlyStopCandidates,
+		EarlyStopCount:            opts.EarlyStopCount,
+		ThreadSafe:                opts.ThreadSafe,
+		IncludeUnknown:            opts.IncludeUnknown,
+		ShortWordLength:           opts.ShortWordLength,
+		SuggestionFilter:          opts.SuggestionFilter,
+		Ranker:                    opts.Ranker,
 		Words:                     make(map[string]uint32),
-		BelowThresholdWords:       make(map[string]uint32),
+		BelowThresholdWords:       make(map[string]uint64),
 		DeletesIdx:                make(map[string]uint64),
 		DeletesData:               make([]uint32, 0),
 		ExactTransform:            nil,
 		words:                     make([]string, 0),
-		counts:                    make([]uint32, 0),
-		distanceComparer:          editdistance.NewEditDistance(editdistance.DamerauLevenshtein),
+		counts:                    make([]uint64, 0),
+		distanceComparer:          distanceComparer,
+		customDistance:            opts.DistanceComparer != nil,
+		weightedComparer:          weightedComparer,
 		maxLength:                 0,
 		Bigrams:                   nil,
 		N:                         1024908267229,
 		BigramCountMin:            maxUint32,
-		topCache:                  newTopCache(128),
-	}, nil
+		topCache:                  newTopCache(opts.LookupCacheSize, opts.LookupCacheTTL),
+		skipStats:                 new(skipCounters),
+		boostLists:                make(map[string]*boostList),
+		phoneticEncoder:           opts.PhoneticEncoder,
+		phoneticWeight:            opts.PhoneticWeight,
+		blacklist:                 blacklist,
+		protected:                 protected,
+		ignoreTokens:              opts.IgnoreTokens,
+		logger:                    logger,
+		layouts:                   layouts,
+		transliterators:           opts.Transliterators,
+		ignoreDiacritics:          opts.IgnoreDiacritics,
+		channelModel:              opts.ChannelModel,
+		languageModel:             opts.LanguageModel,
+		casing:                    newCaseMapping(opts.CaseFolding, opts.CaseLocale),
+	}
+	if opts.Singleflight {
+		s.lookupGroup = new(lookupGroup)
+	}
+	if opts.LogProbRanking {
+		s.Ranker = logProbRanker(opts.EditPenalty)
+		s.logProbRanking = true
+	}
+	if opts.CompoundSplitting {
+		s.compoundMinPartLength = opts.CompoundMinPartLength
+		if s.compoundMinPartLength <= 0 {
+			s.compoundMinPartLength = defaultCompoundMinPartLength
+		}
+		s.linkingMorphemes = slices.Clone(opts.LinkingMorphemes)
+	}
+	if opts.CJKSegmentation {
+		s.cjkSegmentLength = opts.CJKSegmentLength
+		if s.cjkSegmentLength <= 0 {
+			s.cjkSegmentLength = defaultCJKSegmentLength
+		}
+	}
+	if s.StorageBackend == options.StorageDisk {
+		loaded, err := s.openDiskStorage()
+		if err != nil {
+			return nil, fmt.Errorf("opening disk storage: %w", err)
+		}
+		if loaded {
+			// the store already holds the words of the dictionary files
+			opts.Dictionaries = nil
+		}
+	}
+	if err := s.loadDictionaryFiles(opts); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
 }
 
 // createDictionaryEntry creates or updates an entry in the dictionary.
-func (s *SymSpell) addWordEntry(key string, count uint32) bool {
+func (s *SymSpell) addWordEntry(key string, count uint64) bool {
 	if count == 0 {
 		if s.CountThreshold > 0 {
 			return false
 		}
 	}
 
-	if s.CountThreshold > 1 {
-		if countPrev, found := s.BelowThresholdWords[key]; found {
-			count = incrementCount(count, countPrev)
-			if int(count) < s.CountThreshold {
-				s.BelowThresholdWords[key] = count
-				return false
-			}
-			delete(s.BelowThresholdWords, key)
+	s.byFrequency = nil
+	if countPrev, found := s.BelowThresholdWords[key]; found && s.CountThreshold > 1 {
+		count = incrementCount(count, countPrev)
+		if count < uint64(s.CountThreshold) {
+			s.BelowThresholdWords[key] = count
+			return false
 		}
-	} else if idx, found := s.Words[key]; found {
+		delete(s.BelowThresholdWords, key)
+	} else if idx, found := s.wordIndex(key); found {
 		s.counts[idx] = incrementCount(count, s.counts[idx])
 		return false
 	}
-	if int(count) < s.CountThreshold {
+	if count < uint64(s.CountThreshold) {
 		s.BelowThresholdWords[key] = count
 		return false
 	}
 
-	index := uint32(len(s.words))
-	s.words = append(s.words, key)
+	s.appendWord(key, count)
+	return true
+}
+
+// appendWord stores a new word and returns its index. The deletes index is
+// not updated.
+func (s *SymSpell) appendWord(key string, count uint64) uint32 {
+	s.addBaseUserWord(key)
+	index := s.storeWord(key)
 	s.counts = append(s.counts, count)
-	s.Words[key] = index
 
 	if len(key) > s.maxLength {
 		s.maxLength = len(key)
 	}
-
-	return true
+	return index
 }
 
-func (s *SymSpell) addDeletesForIndex(key string, index uint32) {
-	edits := s.editsPrefix(key)
-	for deleteWord := range edits {
-		if v, found := s.DeletesIdx[deleteWord]; found {
-			offset := uint32(v >> 32)
-			length := uint32(v)
-			insertPos := offset + length
-			s.DeletesData = append(s.DeletesData, 0)
-			copy(s.DeletesData[insertPos+1:], s.DeletesData[insertPos:])
-			s.DeletesData[insertPos] = index
-			for k, val := range s.DeletesIdx {
-				if k == deleteWord {
-					continue
-				}
-				o := uint32(val >> 32)
-				l := uint32(val)
-				if o >= insertPos {
-					s.DeletesIdx[k] = uint64(o+1)<<32 | uint64(l)
-				}
-			}
-			s.DeletesIdx[deleteWord] = uint64(offset)<<32 | uint64(length+1)
-		} else {
-			offset := uint32(len(s.DeletesData))
-			s.DeletesData = append(s.DeletesData, index)
-			s.DeletesIdx[deleteWord] = uint64(offset)<<32 | 1
-		}
-	}
-}
-
-// createDictionaryEntry creates or updates an entry in the dictionary.
-func (s *SymSpell) createDictionaryEntry(key string, count uint32) bool {
+// CreateDictionaryEntry creates or updates an entry in the dictionary. It
+// returns true if a new word was added.
+func (s *SymSpell) CreateDictionaryEntry(key string, count uint64) bool {
+	s.topCache.Clear()
+	key = s.dictionaryKey(s.normalize(key))
 	if !s.addWordEntry(key, count) {
+		if idx, found := s.wordIndex(key); found {
+			s.persistCount(idx)
+		}
 		return false
 	}
-	index := uint32(len(s.words) - 1)
+	index := uint32(s.wordSlots() - 1)
 	s.addDeletesForIndex(key, index)
+	s.addPhoneticForIndex(key, index)
+	s.addDiacriticForIndex(key, index)
 	return true
 }
 
@@ -209,69 +449,147 @@ func (s *SymSpell) editsPrefix(key string) map[string]bool {
 	return hashSet
 }
 
-// LoadDictionary loads dictionary entries from a file.
-func (s *SymSpell) LoadDictionary(corpusPath string, termIndex int, countIndex int, separator string) (bool, error) {
+// LoadDictionary loads dictionary entries from a file. It may be called
+// several times: counts of words present in more than one source are summed,
+// after scaling by the source weight.
+func (s *SymSpell) LoadDictionary(corpusPath string, termIndex int, countIndex int, separator string, opts ...options.LoadOption) (bool, error) {
+	return s.LoadDictionaryContext(context.Background(), corpusPath, termIndex, countIndex, separator, nil, opts...)
+}
+
+// LoadDictionaryContext works like LoadDictionary, calls progress, if not
+// nil, every few thousand lines and once at the end, and stops reading once
+// ctx is done. The words read before cancellation stay in the dictionary and
+// are indexed, and ctx.Err() is returned.
+func (s *SymSpell) LoadDictionaryContext(ctx context.Context, corpusPath string, termIndex int, countIndex int, separator string, progress func(linesRead, wordsAdded int), opts ...options.LoadOption) (bool, error) {
 	if corpusPath == "" {
 		return false, errors.New("corpus path cannot be empty")
 	}
-
-	// Check if the file exists
-	if _, err := os.Stat(corpusPath); os.IsNotExist(err) {
-		log.Printf("Dictionary file not found at %s.\n", corpusPath)
-		return false, nil
-	}
-
-	// Open the file
-	file, err := os.Open(corpusPath)
+	file, err := openDictionary(corpusPath)
 	if err != nil {
 		return false, err
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	return s.loadDictionaryStream(ctx, file, termIndex, countIndex, separator, progress, opts)
+}
+
+// LoadDictionaryStream works like LoadDictionary but reads the entries from a reader.
+func (s *SymSpell) LoadDictionaryStream(corpusStream io.Reader, termIndex int, countIndex int, separator string, opts ...options.LoadOption) (bool, error) {
+	return s.loadDictionaryStream(context.Background(), corpusStream, termIndex, countIndex, separator, nil, opts)
+}
+
+func (s *SymSpell) loadDictionaryStream(ctx context.Context, corpusStream io.Reader, termIndex int, countIndex int, separator string, progress func(linesRead, wordsAdded int), opts []options.LoadOption) (bool, error) {
+	loadOptions := options.LoadOptions{SourceWeight: 1}
+	for _, opt := range opts {
+		opt(&loadOptions)
+	}
+	if !(loadOptions.SourceWeight > 0) {
+		return false, fmt.Errorf("%w: source weight must be positive", ErrInvalidOptions)
+	}
+
+	switch loadOptions.Format {
+	case options.FrequencyGoogleBooks, options.FrequencyOpenSubtitles:
+		return s.loadSummedFrequencies(ctx, corpusStream, termIndex, countIndex, separator, progress, loadOptions)
+	}
+
+	tracker := loadTracker{ctx: ctx, progress: progress}
+	parse := func(line string) (dictionaryLine, error) {
+		return s.parseDictionaryLine(line, termIndex, countIndex, separator, loadOptions)
+	}
+	scanner := s.newLineScanner(corpusStream)
+	if s.LoadWorkers > 1 {
+		return s.loadDictionaryParallel(scanner, parse, tracker)
+	}
+	wordsAdded := 0
 	for scanner.Scan() {
-		line := scanner.Text()
-		var term string
-		var c64 uint64
-		var err error
-		if separator == "" || separator == " " {
-			fields := strings.Fields(line)
-			if len(fields) <= max(termIndex, countIndex) {
-				continue
-			}
-			term = fields[termIndex]
-			c64, err = strconv.ParseUint(fields[countIndex], 10, 32)
-			if err != nil {
-				continue
-			}
-		} else if termIndex == 0 && countIndex == 1 {
-			idx := strings.LastIndex(line, separator)
-			if idx < 0 {
-				continue
-			}
-			term = line[:idx]
-			c64, err = strconv.ParseUint(line[idx+len(separator):], 10, 32)
-			if err != nil {
-				continue
-			}
-		} else {
-			fields := strings.Split(line, separator)
-			if len(fields) <= max(termIndex, countIndex) {
-				continue
-			}
-			term = fields[termIndex]
-			c64, err = strconv.ParseUint(fields[countIndex], 10, 32)
-			if err != nil {
-				continue
-			}
+		if err := tracker.check(scanner.line, wordsAdded); err != nil {
+			s.buildIndex()
+			return false, err
+		}
+		entry, err := parse(scanner.Text())
+		if err != nil {
+			return false, fmt.Errorf("line %d: %w", scanner.line, err)
+		}
+		if entry.ok && s.addWordEntry(entry.term, entry.count) {
+			wordsAdded++
 		}
-		s.addWordEntry(term, uint32(c64))
 	}
 
-	if err = scanner.Err(); err != nil {
+	if err := scanner.Err(); err != nil {
 		return false, err
 	}
 
+	s.buildIndex()
+	tracker.done(scanner.line, wordsAdded)
+	return true, nil
+}
+
+// dictionaryLine is a parsed dictionary line; ok is false for lines that are
+// skipped, such as lines without a valid count.
+type dictionaryLine struct {
+	term  string
+	count uint64
+	ok    bool
+}
+
+// parseDictionaryLine parses a line of a "term count" or Zipf list into a
+// dictionary key and its weighted count. It is safe for concurrent use.
+func (s *SymSpell) parseDictionaryLine(line string, termIndex, countIndex int, separator string, loadOptions options.LoadOptions) (dictionaryLine, error) {
+	term, value, ok := splitDictionaryLine(line, termIndex, countIndex, separator)
+	if !ok {
+		return dictionaryLine{}, nil
+	}
+	var c64 uint64
+	var err error
+	if loadOptions.Format == options.FrequencyZipf {
+		c64, err = zipfCount(value)
+	} else {
+		c64, err = strconv.ParseUint(value, 10, 64)
+	}
+	if err != nil {
+		return dictionaryLine{}, nil
+	}
+	term, err = s.checkUTF8(term)
+	if err != nil {
+		return dictionaryLine{}, err
+	}
+	return dictionaryLine{term: s.dictionaryKey(term), count: weightCount(c64, loadOptions.SourceWeight), ok: true}, nil
+}
+
+// splitDictionaryLine returns the term and count fields of a dictionary line.
+// With the default "term count" layout and a separator other than a space,
+// the count is split off at the last separator so terms may contain it.
+func splitDictionaryLine(line string, termIndex, countIndex int, separator string) (string, string, bool) {
+	var fields []string
+	switch {
+	case separator == "" || separator == " ":
+		fields = strings.Fields(line)
+	case termIndex == 0 && countIndex == 1:
+		idx := strings.LastIndex(line, separator)
+		if idx < 0 {
+			return "", "", false
+		}
+		return line[:idx], line[idx+len(separator):], true
+	default:
+		fields = strings.Split(line, separator)
+	}
+	if len(fields) <= max(termIndex, countIndex) {
+		return "", "", false
+	}
+	return fields[termIndex], fields[countIndex], true
+}
+
+// buildIndex rebuilds the deletes and phonetic indexes from all live words
+// after a bulk load.
+func (s *SymSpell) buildIndex() {
+	s.topCache.Clear()
+	s.packWords()
+	if s.disk != nil && s.rebuildDiskStorage() {
+		s.buildDeleteFilter()
+		s.buildPhoneticIndex()
+		s.buildDiacriticIndex()
+		return
+	}
 	shardCount := 16
 	type shardMap map[string][]uint32
 	shards := make([]shardMap, shardCount)
@@ -284,8 +602,11 @@ func (s *SymSpell) LoadDictionary(corpusPath string, termIndex int, countIndex i
 		wg.Add(1)
 		go func(offset int, shard shardMap) {
 			defer wg.Done()
-			for idx := offset; idx < len(s.words); idx += shardCount {
-				word := s.words[idx]
+			for idx := offset; idx < s.wordSlots(); idx += shardCount {
+				if !s.isLiveIndex(uint32(idx)) {
+					continue
+				}
+				word := s.word(uint32(idx))
 				edits := s.editsPrefix(word)
 				for del := range edits {
 					shard[del] = append(shard[del], uint32(idx))
@@ -302,22 +623,56 @@ func (s *SymSpell) LoadDictionary(corpusPath string, termIndex int, countIndex i
 		}
 	}
 
-	for del, slice := range combined {
-		offset := uint32(len(s.DeletesData))
-		s.DeletesData = append(s.DeletesData, slice...)
-		s.DeletesIdx[del] = uint64(offset)<<32 | uint64(len(slice))
+	if s.mappedDeletes != nil {
+		// DeletesData is read-only mapped memory
+		s.DeletesData, s.mappedDeletes = nil, nil
+	}
+	s.clearDelta()
+	// the old postings are not reused, they may be shared with a clone
+	switch {
+	case s.IndexBackend == options.IndexBackendTrie:
+		s.DeletesIdx = make(map[string]uint64)
+		s.trieDeletes, s.DeletesData = newDeleteTrie(combined, nil)
+	case s.hashDeletes:
+		s.DeletesIdx = make(map[string]uint64)
+		s.hashedDeletes, s.DeletesData = packPostings(hashDeleteKeys(combined), nil)
+	default:
+		s.DeletesIdx, s.DeletesData = packPostings(combined, nil)
 	}
 
-	s.BelowThresholdWords = nil
+	s.buildDeleteFilter()
+	s.buildPhoneticIndex()
+	s.buildDiacriticIndex()
+}
 
-	return true, nil
+// weightCount scales a count from a weighted source, saturating at maxCount.
+func weightCount(count uint64, weight float64) uint64 {
+	if weight == 1 {
+		return count
+	}
+	return floatCount(math.Round(float64(count) * weight))
 }
 
-func incrementCount(count, countPrevious uint32) uint32 {
-	if maxUint32-countPrevious > count {
+// floatCount converts a non-negative float to a count, saturating at
+// maxCount; converting larger floats to uint64 directly is undefined.
+func floatCount(f float64) uint64 {
+	if f >= 1<<64 {
+		return maxCount
+	}
+	return uint64(f)
+}
+
+// itemCount converts a count to the int of SuggestItem.Count, saturating at
+// math.MaxInt.
+func itemCount(count uint64) int {
+	return int(min(count, math.MaxInt))
+}
+
+func incrementCount(count, countPrevious uint64) uint64 {
+	if maxCount-countPrevious > count {
 		return countPrevious + count
 	}
-	return maxUint32
+	return maxCount
 }
 
 func (s *SymSpell) LoadExactDictionary(
@@ -327,22 +682,21 @@ func (s *SymSpell) LoadExactDictionary(
 	if corpusPath == "" {
 		return false, fmt.Errorf("corpus path cannot be empty")
 	}
-	// Check if the file exists
-	file, err := os.Open(corpusPath)
+	file, err := openDictionary(corpusPath)
 	if err != nil {
 		return false, err
 	}
 	defer file.Close()
 
 	// Use the stream-based loading function
-	return s.LoadExactDictionaryStream(file, separator), nil
+	return s.LoadExactDictionaryStream(file, separator)
 }
 
-func (s *SymSpell) LoadExactDictionaryStream(corpusStream *os.File, separator string) bool {
+func (s *SymSpell) LoadExactDictionaryStream(corpusStream io.Reader, separator string) (bool, error) {
 	if s.ExactTransform == nil {
 		s.ExactTransform = make(map[string]string)
 	}
-	scanner := bufio.NewScanner(corpusStream)
+	scanner := s.newLineScanner(corpusStream)
 	// Define minimum parts depending on the separator
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -360,13 +714,22 @@ func (s *SymSpell) LoadExactDictionaryStream(corpusStream *os.File, separator st
 			continue
 		}
 		// Parse count
-		exactMatch := parts[1]
+		exactMatch, err := s.checkUTF8(parts[1])
+		if err != nil {
+			return false, err
+		}
 		// Create the key
-		key := parts[0]
+		key, err := s.checkUTF8(parts[0])
+		if err != nil {
+			return false, err
+		}
 		// Add to Exact Transform dictionary
-		s.ExactTransform[key] = exactMatch
+		s.ExactTransform[s.dictionaryKey(key)] = exactMatch
 	}
-	return true
+	if err := scanner.Err(); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // ClearTransformData releases memory used by optional bigram and transform maps.
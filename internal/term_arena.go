@@ -0,0 +1,208 @@
+package internal
+
+import (
+	"hash/maphash"
+	"unsafe"
+)
+
+// wordArena holds the dictionary words of CompactStorage: their bytes in one
+// arena, an (offset, length) reference per word index and an open-addressing
+// table from the hash of a word to its index. None of the three holds a
+// pointer per word, so the garbage collector neither scans nor allocates a
+// string header for every word, as it does for words and the keys of Words.
+//
+// Words are only ever appended to the arena. Bytes of deleted words stay in
+// it until Compact repacks the live words.
+type wordArena struct {
+	bytes []byte
+	refs  []wordRef
+	slots []uint32 // word index plus one, 0 when empty, see arenaTombstone
+	used  int      // slots that are not empty, tombstones included
+	live  int      // words in slots
+	seed  maphash.Seed
+}
+
+// wordRef locates a word in the bytes of a wordArena.
+type wordRef struct {
+	offset uint32
+	length uint32
+}
+
+// arenaTombstone marks the slot of a removed word, so that probes for the
+// words after it go on.
+const arenaTombstone = ^uint32(0)
+
+// packWords moves the dictionary words into a wordArena and drops words and
+// Words. It runs when the index is built or loaded; once packed, words added
+// later are appended to the arena.
+func (s *SymSpell) packWords() {
+	if !s.CompactStorage || s.arena != nil {
+		return
+	}
+	size := 0
+	for _, word := range s.words {
+		size += len(word)
+	}
+	a := newWordArena(size, len(s.words), len(s.Words))
+	for i, word := range s.words {
+		a.append(word)
+		// a deleted word is not indexed, but keeps its index
+		if idx, found := s.Words[word]; found && idx == uint32(i) {
+			a.insert(word, idx)
+		}
+	}
+	s.arena, s.words, s.Words = a, nil, nil
+}
+
+// newWordArena returns an empty arena sized for n words of size bytes in
+// total, live of which are indexed.
+func newWordArena(size, n, live int) *wordArena {
+	a := &wordArena{
+		bytes: make([]byte, 0, size),
+		refs:  make([]wordRef, 0, n),
+		seed:  maphash.MakeSeed(),
+	}
+	a.rehash(live)
+	return a
+}
+
+// word returns the word at idx. It points into the arena, no bytes are
+// copied.
+func (a *wordArena) word(idx uint32) string {
+	ref := a.refs[idx]
+	if ref.length == 0 {
+		return ""
+	}
+	return unsafe.String(&a.bytes[ref.offset], ref.length)
+}
+
+// append stores word at the next index.
+func (a *wordArena) append(word string) uint32 {
+	a.refs = append(a.refs, wordRef{offset: uint32(len(a.bytes)), length: uint32(len(word))})
+	a.bytes = append(a.bytes, word...)
+	return uint32(len(a.refs) - 1)
+}
+
+// find returns the slot holding word, or the empty slot ending its probe
+// sequence.
+func (a *wordArena) find(word string) (int, bool) {
+	mask := uint64(len(a.slots) - 1)
+	for i := maphash.String(a.seed, word) & mask; ; i = (i + 1) & mask {
+		switch slot := a.slots[i]; {
+		case slot == 0:
+			return int(i), false
+		case slot != arenaTombstone && a.word(slot-1) == word:
+			return int(i), true
+		}
+	}
+}
+
+func (a *wordArena) index(word string) (uint32, bool) {
+	i, found := a.find(word)
+	if !found {
+		return 0, false
+	}
+	return a.slots[i] - 1, true
+}
+
+// insert indexes word, which must not be indexed yet, at idx.
+func (a *wordArena) insert(word string, idx uint32) {
+	if (a.used+1)*4 > len(a.slots)*3 {
+		a.rehash(a.live + 1)
+	}
+	i, _ := a.find(word)
+	a.slots[i] = idx + 1
+	a.used++
+	a.live++
+}
+
+// remove drops word from the table; its bytes stay in the arena.
+func (a *wordArena) remove(word string) {
+	if i, found := a.find(word); found {
+		a.slots[i] = arenaTombstone
+		a.live--
+	}
+}
+
+// rehash sizes the table for n words, at most half full, and drops the
+// tombstones.
+func (a *wordArena) rehash(n int) {
+	size := 8
+	for size < 2*n {
+		size *= 2
+	}
+	old := a.slots
+	a.slots, a.used = make([]uint32, size), 0
+	for _, slot := range old {
+		if slot != 0 && slot != arenaTombstone {
+			i, _ := a.find(a.word(slot - 1))
+			a.slots[i] = slot
+			a.used++
+		}
+	}
+}
+
+// clone returns a copy of a whose appends do not write into a.
+func (a *wordArena) clone() *wordArena {
+	c := *a
+	c.bytes = a.bytes[:len(a.bytes):len(a.bytes)]
+	c.refs = a.refs[:len(a.refs):len(a.refs)]
+	c.slots = append([]uint32(nil), a.slots...)
+	return &c
+}
+
+// word returns the dictionary word at idx, which may be deleted.
+func (s *SymSpell) word(idx uint32) string {
+	if s.arena != nil {
+		return s.arena.word(idx)
+	}
+	return s.words[idx]
+}
+
+// wordIndex returns the index of a dictionary word.
+func (s *SymSpell) wordIndex(term string) (uint32, bool) {
+	if s.arena != nil {
+		return s.arena.index(term)
+	}
+	idx, found := s.Words[term]
+	return idx, found
+}
+
+// wordSlots returns the number of word indexes, deleted words included.
+func (s *SymSpell) wordSlots() int {
+	if s.arena != nil {
+		return len(s.arena.refs)
+	}
+	return len(s.words)
+}
+
+// liveWords returns the number of dictionary words.
+func (s *SymSpell) liveWords() int {
+	if s.arena != nil {
+		return s.arena.live
+	}
+	return len(s.Words)
+}
+
+// storeWord appends a new word and returns its index.
+func (s *SymSpell) storeWord(term string) uint32 {
+	s.byTerm = nil
+	if s.arena != nil {
+		idx := s.arena.append(term)
+		s.arena.insert(term, idx)
+		return idx
+	}
+	idx := uint32(len(s.words))
+	s.words = append(s.words, term)
+	s.Words[term] = idx
+	return idx
+}
+
+// unindexWord removes term from the word index; its index stays taken.
+func (s *SymSpell) unindexWord(term string) {
+	if s.arena != nil {
+		s.arena.remove(term)
+		return
+	}
+	delete(s.Words, term)
+}
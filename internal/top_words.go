@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"sort"
+	"strings"
+
+	"symspell/pkg/items"
+)
+
+// TopWords returns the n most frequent dictionary words.
+func (s *SymSpell) TopWords(n int) []items.SuggestItem {
+	return s.TopWordsWithPrefix("", n)
+}
+
+// TopWordsWithPrefix returns up to n of the most frequent dictionary words that
+// start with prefix. Words are served from a frequency-sorted index and, for a
+// non-empty prefix, from a term-sorted one that narrows the candidates to the
+// words with the prefix; both are built on first use after the dictionary
+// changes.
+func (s *SymSpell) TopWordsWithPrefix(prefix string, n int) []items.SuggestItem {
+	if n <= 0 {
+		return nil
+	}
+	if prefix == "" {
+		return s.topWords(n)
+	}
+	if s.byTerm == nil {
+		s.buildTermIndex()
+	}
+	lo := sort.Search(len(s.byTerm), func(i int) bool {
+		return s.word(s.byTerm[i]) >= prefix
+	})
+	hi := lo + sort.Search(len(s.byTerm)-lo, func(i int) bool {
+		return !strings.HasPrefix(s.word(s.byTerm[lo+i]), prefix)
+	})
+	// keep the n most frequent matches, ordered as in byFrequency
+	top := make([]uint32, 0, min(n, hi-lo))
+	for _, idx := range s.byTerm[lo:hi] {
+		if !s.isLiveIndex(idx) {
+			continue
+		}
+		pos := sort.Search(len(top), func(i int) bool {
+			return s.moreFrequent(idx, top[i])
+		})
+		if pos == n {
+			continue
+		}
+		if len(top) < n {
+			top = append(top, 0)
+		}
+		copy(top[pos+1:], top[pos:])
+		top[pos] = idx
+	}
+	result := make([]items.SuggestItem, len(top))
+	for i, idx := range top {
+		result[i] = items.SuggestItem{Term: s.word(idx), Distance: 0, Count: itemCount(s.counts[idx])}
+	}
+	return result
+}
+
+// topWords returns the n most frequent dictionary words.
+func (s *SymSpell) topWords(n int) []items.SuggestItem {
+	if s.byFrequency == nil {
+		s.buildFrequencyIndex()
+	}
+	result := make([]items.SuggestItem, 0, min(n, len(s.byFrequency)))
+	for _, idx := range s.byFrequency {
+		if !s.isLiveIndex(idx) {
+			continue
+		}
+		result = append(result, items.SuggestItem{Term: s.word(idx), Distance: 0, Count: itemCount(s.counts[idx])})
+		if len(result) == n {
+			break
+		}
+	}
+	return result
+}
+
+// moreFrequent reports whether word i comes before word j in byFrequency.
+func (s *SymSpell) moreFrequent(i, j uint32) bool {
+	if s.counts[i] != s.counts[j] {
+		return s.counts[i] > s.counts[j]
+	}
+	return i < j
+}
+
+func (s *SymSpell) buildFrequencyIndex() {
+	order := make([]uint32, s.wordSlots())
+	for i := range order {
+		order[i] = uint32(i)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return s.counts[order[i]] > s.counts[order[j]]
+	})
+	s.byFrequency = order
+}
+
+// buildTermIndex sorts the word indexes by term. Deleted words stay in it
+// and are skipped by TopWordsWithPrefix, as in byFrequency.
+func (s *SymSpell) buildTermIndex() {
+	order := make([]uint32, s.wordSlots())
+	for i := range order {
+		order[i] = uint32(i)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return s.word(order[i]) < s.word(order[j])
+	})
+	s.byTerm = order
+}
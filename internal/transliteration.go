@@ -0,0 +1,29 @@
+package internal
+
+import (
+	"context"
+	"strings"
+
+	"symspell/pkg/items"
+	verbositypkg "symspell/pkg/verbosity"
+)
+
+// mergeTransliterations looks up the transliterations of phrase and merges
+// their suggestions into result.
+func (s *SymSpell) mergeTransliterations(ctx context.Context, phrase string, verbosity verbositypkg.Verbosity, maxEditDistance int, frequencyGate bool, result []items.SuggestItem) []items.SuggestItem {
+	lower := strings.ToLower(phrase)
+	merged := false
+	for _, transliterator := range s.transliterators {
+		converted := transliterator.Transliterate(lower)
+		if converted == lower || converted == "" {
+			continue
+		}
+		var changed bool
+		result, changed = mergeSuggestions(result, s.lookupStaged(ctx, converted, verbosity, maxEditDistance, frequencyGate))
+		merged = merged || changed
+	}
+	if !merged {
+		return result
+	}
+	return s.rankMerged(result, verbosity)
+}
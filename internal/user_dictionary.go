@@ -0,0 +1,236 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"symspell/pkg/options"
+)
+
+// userWord is a word of the user dictionary layered over the base dictionary.
+type userWord struct {
+	count uint64
+	added bool // the word is in userIdx, not in the base dictionary
+}
+
+// AddUserWord adds term to the user dictionary. User words are found by
+// lookups like base words, are ranked before base words at the same distance
+// and are never replaced by the frequency check. Words the base dictionary
+// lacks are kept in an index of their own, userIdx, and the counts of base
+// words are left alone: lookups add the user count to them. Neither is
+// written by SaveIndex or to the disk store; use SaveUserDictionary.
+func (s *SymSpell) AddUserWord(term string, count uint64) (bool, error) {
+	term, err := s.checkUTF8(term)
+	if err != nil {
+		return false, err
+	}
+	term = s.dictionaryKey(term)
+	if s.userWords == nil {
+		s.userWords = make(map[string]userWord)
+	}
+	s.topCache.Clear()
+	s.byFrequency = nil
+	s.userVersion++
+	word := s.userWords[term]
+	word.count = incrementCount(count, word.count)
+	defer func() { s.userWords[term] = word }()
+	if _, found := s.wordIndex(term); found {
+		return false, nil
+	}
+	if word.added {
+		s.userIdx.UpdateWordFrequency(term, word.count)
+		return false, nil
+	}
+	// user words are added regardless of CountThreshold
+	if s.userIdx == nil {
+		s.userIdx = s.newUserIndex()
+	}
+	s.userIdx.CreateDictionaryEntry(term, word.count)
+	word.added = true
+	return true, nil
+}
+
+func (s *SymSpell) newUserIndex() *SymSpell {
+	userIdx, _ := NewSymSpell(
+		options.WithMaxDictionaryEditDistance(s.MaxDictionaryEditDistance),
+		options.WithPrefixLength(s.PrefixLength),
+		options.WithCountThreshold(0),
+	)
+	userIdx.distanceComparer = s.distanceComparer
+	userIdx.customDistance = s.customDistance
+	userIdx.weightedComparer = s.weightedComparer
+	userIdx.logger = s.logger
+	return userIdx
+}
+
+// RemoveUserWord removes term from the user dictionary. A base dictionary
+// word keeps its base count. It returns false if term is not a user word.
+func (s *SymSpell) RemoveUserWord(term string) bool {
+	word, found := s.userWords[term]
+	if !found {
+		return false
+	}
+	delete(s.userWords, term)
+	s.userVersion++
+	if word.added {
+		s.userIdx.DeleteDictionaryEntry(term)
+	}
+	s.topCache.Clear()
+	s.byFrequency = nil
+	return true
+}
+
+// userCount returns the count of a base word with its user count added.
+func (s *SymSpell) userCount(term string, count uint64) uint64 {
+	if len(s.userWords) == 0 {
+		return count
+	}
+	if word, found := s.userWords[term]; found && !word.added {
+		return incrementCount(word.count, count)
+	}
+	return count
+}
+
+// dictionaryCount returns the count of a base or user word.
+func (s *SymSpell) dictionaryCount(term string) (uint64, bool) {
+	if idx, found := s.wordIndex(term); found {
+		return s.userCount(term, s.counts[idx]), true
+	}
+	if s.userIdx != nil {
+		if idx, found := s.userIdx.wordIndex(term); found {
+			return s.userIdx.counts[idx], true
+		}
+	}
+	return 0, false
+}
+
+// maxWordLength returns the length of the longest base or user word.
+func (s *SymSpell) maxWordLength() int {
+	if s.userIdx != nil {
+		return max(s.maxLength, s.userIdx.maxLength)
+	}
+	return s.maxLength
+}
+
+// processUserPostings checks the user words at the postings of candidate.
+func (s *SymSpell) processUserPostings(candidate string, maxEditDistance int, cp *candidateProcessor) {
+	if postings, found := s.userIdx.postings(candidate); found {
+		s.processPostings(s.userIdx, postings, candidate, maxEditDistance, cp)
+	}
+	if postings, found := s.userIdx.deltaIdx[candidate]; found {
+		s.processPostings(s.userIdx, postings, candidate, maxEditDistance, cp)
+	}
+}
+
+// addBaseUserWord moves a user word that is being added to the base
+// dictionary out of userIdx.
+func (s *SymSpell) addBaseUserWord(term string) {
+	if word, found := s.userWords[term]; found && word.added {
+		s.userIdx.DeleteDictionaryEntry(term)
+		word.added = false
+		s.userWords[term] = word
+	}
+}
+
+// syncUserWords moves the user words between userIdx and the base
+// dictionary after the base dictionary was replaced.
+func (s *SymSpell) syncUserWords() {
+	for term, word := range s.userWords {
+		_, inBase := s.wordIndex(term)
+		switch {
+		case word.added && inBase:
+			s.addBaseUserWord(term)
+		case !word.added && !inBase:
+			if s.userIdx == nil {
+				s.userIdx = s.newUserIndex()
+			}
+			s.userIdx.CreateDictionaryEntry(term, word.count)
+			word.added = true
+			s.userWords[term] = word
+		}
+	}
+}
+
+// IsUserWord reports whether term is in the user dictionary.
+func (s *SymSpell) IsUserWord(term string) bool {
+	_, found := s.userWords[term]
+	return found
+}
+
+// ClearUserDictionary removes every user word, leaving the base dictionary
+// as it was before the first AddUserWord.
+func (s *SymSpell) ClearUserDictionary() {
+	for term := range s.userWords {
+		s.RemoveUserWord(term)
+	}
+}
+
+// SaveUserDictionary writes the user words as "term count" lines, sorted by
+// term, in the format read by LoadUserDictionary.
+func (s *SymSpell) SaveUserDictionary(w io.Writer) error {
+	terms := make([]string, 0, len(s.userWords))
+	for term := range s.userWords {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+	bw := bufio.NewWriter(w)
+	for _, term := range terms {
+		if _, err := fmt.Fprintf(bw, "%s %d\n", term, s.userWords[term].count); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// LoadUserDictionary adds the "term count" lines written by
+// SaveUserDictionary to the user dictionary.
+func (s *SymSpell) LoadUserDictionary(r io.Reader) error {
+	scanner := s.newLineScanner(r)
+	for scanner.Scan() {
+		parts := strings.Fields(scanner.Text())
+		if len(parts) == 0 {
+			continue
+		}
+		if len(parts) != 2 {
+			return fmt.Errorf("line %d: expected \"term count\", got %q", scanner.line, scanner.Text())
+		}
+		count, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", scanner.line, err)
+		}
+		if _, err := s.AddUserWord(parts[0], count); err != nil {
+			return fmt.Errorf("line %d: %w", scanner.line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// preferUser orders a user word before a base word; ok is false when both or
+// neither are user words.
+func (s *SymSpell) preferUser(a, b string) (before bool, ok bool) {
+	if len(s.userWords) == 0 {
+		return false, false
+	}
+	_, aUser := s.userWords[a]
+	_, bUser := s.userWords[b]
+	return aUser, aUser != bUser
+}
+
+// sortUserWords moves user words before base words at the same distance.
+func (s *SymSpell) sortUserWords(cp *candidateProcessor) {
+	if len(s.userWords) == 0 || s.Ranker != nil || len(cp.suggestions) < 2 {
+		return
+	}
+	sort.SliceStable(cp.suggestions, func(i, j int) bool {
+		a, b := cp.suggestions[i], cp.suggestions[j]
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		before, ok := s.preferUser(a.Term, b.Term)
+		return ok && before
+	})
+}
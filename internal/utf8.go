@@ -0,0 +1,184 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+)
+
+// InvalidUTF8Error is returned when input contains malformed UTF-8 and the
+// InvalidUTF8Reject policy is active.
+type InvalidUTF8Error struct {
+	Input  string
+	Offset int
+}
+
+func (e *InvalidUTF8Error) Error() string {
+	return fmt.Sprintf("invalid UTF-8 at byte %d in %q", e.Offset, e.Input)
+}
+
+func (e *InvalidUTF8Error) Unwrap() error {
+	return ErrInvalidUTF8
+}
+
+// checkUTF8 applies the configured invalid UTF-8 policy and Unicode
+// normalization to input.
+func (s *SymSpell) checkUTF8(input string) (string, error) {
+	if utf8.ValidString(input) {
+		return s.normalize(input), nil
+	}
+	switch s.InvalidUTF8Policy {
+	case options.InvalidUTF8Reject:
+		return "", &InvalidUTF8Error{Input: input, Offset: invalidUTF8Offset(input)}
+	case options.InvalidUTF8Sanitize:
+		return s.normalize(strings.ToValidUTF8(input, "")), nil
+	}
+	return input, nil
+}
+
+// normalize applies the configured Unicode normalization form to input.
+func (s *SymSpell) normalize(input string) string {
+	if form, ok := s.normForm(); ok {
+		return form.String(input)
+	}
+	return input
+}
+
+func (s *SymSpell) normForm() (norm.Form, bool) {
+	switch s.UnicodeNormalization {
+	case options.NormalizationNFC:
+		return norm.NFC, true
+	case options.NormalizationNFKC:
+		return norm.NFKC, true
+	}
+	return 0, false
+}
+
+// checkUTF8Offsets works like checkUTF8 and also returns the map from byte
+// offsets in the result to byte offsets in input, or nil if input is
+// returned unchanged.
+func (s *SymSpell) checkUTF8Offsets(input string) (string, *offsetMap, error) {
+	output, err := s.checkUTF8(input)
+	if err != nil || output == input {
+		return output, nil, err
+	}
+	valid, offsets := input, (*offsetMap)(nil)
+	if !utf8.ValidString(input) {
+		valid, offsets = dropInvalidUTF8(input)
+	}
+	if form, ok := s.normForm(); ok && valid != output {
+		offsets = normalizeOffsets(form, valid).through(offsets)
+	}
+	return output, offsets, nil
+}
+
+// offsetMap maps byte offsets in a sanitized or normalized string back to
+// the input it was made from. normalized[k] in the output corresponds to
+// original[k] in the input; both are ascending, and an output offset may
+// appear several times when input bytes were dropped.
+type offsetMap struct {
+	normalized, original []int
+}
+
+// start maps an offset at which a span of the output starts.
+func (m *offsetMap) start(i int) int {
+	if m == nil {
+		return i
+	}
+	return m.original[sort.SearchInts(m.normalized, i+1)-1]
+}
+
+// end maps an offset at which a span of the output ends.
+func (m *offsetMap) end(i int) int {
+	if m == nil {
+		return i
+	}
+	return m.original[sort.SearchInts(m.normalized, i)]
+}
+
+// through composes m with inner, the map of the string m was built from.
+func (m *offsetMap) through(inner *offsetMap) *offsetMap {
+	if inner == nil {
+		return m
+	}
+	for k, i := range m.original {
+		m.original[k] = inner.start(i)
+	}
+	return m
+}
+
+// tokens points the spans of tokens back into input.
+func (m *offsetMap) tokens(input string, tokens []items.TokenCorrection) {
+	if m == nil {
+		return
+	}
+	for i := range tokens {
+		tokens[i].Start, tokens[i].End = m.start(tokens[i].Start), m.end(tokens[i].End)
+		tokens[i].Original = input[tokens[i].Start:tokens[i].End]
+	}
+}
+
+func (m *offsetMap) add(normalized, original int) {
+	m.normalized = append(m.normalized, normalized)
+	m.original = append(m.original, original)
+}
+
+// dropInvalidUTF8 removes the invalid bytes of input like
+// strings.ToValidUTF8(input, ""). A span ending before dropped bytes is
+// mapped to end before them, one starting after them to start after them.
+func dropInvalidUTF8(input string) (string, *offsetMap) {
+	var b strings.Builder
+	m := &offsetMap{}
+	valid := true
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		if r == utf8.RuneError && size == 1 {
+			if valid {
+				m.add(b.Len(), i)
+			}
+			valid = false
+		} else {
+			m.add(b.Len(), i)
+			b.WriteString(input[i : i+size])
+			valid = true
+		}
+		i += size
+	}
+	m.add(b.Len(), len(input))
+	return b.String(), m
+}
+
+// normalizeOffsets normalizes input segment by segment, recording where
+// every segment starts in both strings. The segments of a long decomposition
+// start at the same input offset; only the first of them is a boundary.
+func normalizeOffsets(form norm.Form, input string) *offsetMap {
+	m := &offsetMap{}
+	var it norm.Iter
+	it.InitString(form, input)
+	normalized := 0
+	for !it.Done() {
+		if pos := it.Pos(); len(m.original) == 0 || pos > m.original[len(m.original)-1] {
+			m.add(normalized, pos)
+		}
+		normalized += len(it.Next())
+	}
+	m.add(normalized, len(input))
+	return m
+}
+
+func invalidUTF8Offset(input string) int {
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		if r == utf8.RuneError && size == 1 {
+			return i
+		}
+		i += size
+	}
+	return -1
+}
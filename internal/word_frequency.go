@@ -0,0 +1,71 @@
+package internal
+
+import "iter"
+
+// UpdateWordFrequency sets the count of a dictionary word. The index is not
+// touched, so the new count is seen by the next lookup. It returns false if
+// term is not in the dictionary.
+func (s *SymSpell) UpdateWordFrequency(term string, count uint64) bool {
+	idx, found := s.wordIndex(term)
+	if !found {
+		return false
+	}
+	s.setCount(idx, count)
+	return true
+}
+
+// IncrementCount adds delta to the count of a dictionary word, saturating at
+// the maximum uint64, and returns the new count. It returns false if term is
+// not in the dictionary.
+func (s *SymSpell) IncrementCount(term string, delta uint64) (uint64, bool) {
+	idx, found := s.wordIndex(term)
+	if !found {
+		return 0, false
+	}
+	s.setCount(idx, incrementCount(delta, s.counts[idx]))
+	return s.counts[idx], true
+}
+
+func (s *SymSpell) setCount(idx uint32, count uint64) {
+	s.counts[idx] = count
+	s.persistCount(idx)
+	// cached Top results and the frequency order depend on counts
+	s.topCache.Clear()
+	s.byFrequency = nil
+}
+
+// ContainsWord reports whether term is a dictionary or user word. Words
+// still below CountThreshold are not.
+func (s *SymSpell) ContainsWord(term string) bool {
+	_, found := s.dictionaryCount(term)
+	return found
+}
+
+// WordFrequency returns the count of a dictionary or user word.
+func (s *SymSpell) WordFrequency(term string) (uint64, bool) {
+	return s.dictionaryCount(term)
+}
+
+// WordCount returns the number of dictionary and user words.
+func (s *SymSpell) WordCount() int {
+	if s.userIdx != nil {
+		return s.liveWords() + s.userIdx.liveWords()
+	}
+	return s.liveWords()
+}
+
+// Entries iterates over the words of the base dictionary and their counts in
+// insertion order, without the user dictionary. The dictionary must not be
+// modified during the iteration.
+func (s *SymSpell) Entries() iter.Seq2[string, uint64] {
+	return func(yield func(string, uint64) bool) {
+		for i := range s.wordSlots() {
+			if !s.isLiveIndex(uint32(i)) {
+				continue
+			}
+			if !yield(s.word(uint32(i)), s.counts[i]) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package internal
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"symspell/pkg/items"
+	verbositypkg "symspell/pkg/verbosity"
+)
+
+// WordSegmentation divides a string without spaces into words, correcting
+// misspelled parts on the way, e.g. "thequickbrownfox" -> "the quick brown fox".
+// Existing spaces are allowed and count as insertions. A non-positive
+// maxSegmentationWordLength defaults to the longest dictionary word. With
+// bigrams loaded, segmentations are scored by bigram frequencies with
+// backoff to unigrams, see wordLogProb.
+func (s *SymSpell) WordSegmentation(phrase string, maxEditDistance, maxSegmentationWordLength int) (items.Composition, error) {
+	return s.WordSegmentationContext(context.Background(), phrase, maxEditDistance, maxSegmentationWordLength)
+}
+
+// WordSegmentationContext works like WordSegmentation but stops with
+// ctx.Err() once ctx is done.
+func (s *SymSpell) WordSegmentationContext(ctx context.Context, phrase string, maxEditDistance, maxSegmentationWordLength int) (items.Composition, error) {
+	if s.cjkSegmentLength > 0 && strings.ContainsFunc(phrase, isCJK) {
+		// the circular buffer below needs every segment length to be tried
+		compositions, err := s.wordSegmentationNBest(ctx, phrase, maxEditDistance, maxSegmentationWordLength, 1)
+		if len(compositions) == 0 {
+			return items.Composition{}, err
+		}
+		return compositions[0], err
+	}
+	runes, maxSegmentationWordLength, err := s.segmentationInput(phrase, maxEditDistance, maxSegmentationWordLength)
+	if err != nil || len(runes) == 0 {
+		return items.Composition{}, err
+	}
+
+	arraySize := min(maxSegmentationWordLength, len(runes))
+	compositions := make([]segmentation, arraySize)
+	circularIndex := -1
+
+	for j := 0; j < len(runes); j++ {
+		if err := ctx.Err(); err != nil {
+			return items.Composition{}, err
+		}
+		imax := min(len(runes)-j, maxSegmentationWordLength)
+		for i := 1; i <= imax; i++ {
+			seg, ok := s.correctSegment(runes, j, i, maxEditDistance)
+			if !ok {
+				continue
+			}
+
+			dest := (i + circularIndex) % arraySize
+			if j == 0 {
+				compositions[dest] = s.firstSegment(seg)
+				continue
+			}
+			prev := compositions[circularIndex]
+			logProbSum := s.segmentLogProb(&prev, seg)
+			if i == maxSegmentationWordLength ||
+				((prev.DistanceSum+seg.distance == compositions[dest].DistanceSum ||
+					prev.DistanceSum+seg.separatorLength+seg.distance == compositions[dest].DistanceSum) &&
+					compositions[dest].LogProbSum < logProbSum) ||
+				prev.DistanceSum+seg.separatorLength+seg.distance < compositions[dest].DistanceSum {
+				compositions[dest] = s.appendSegment(prev, seg, logProbSum)
+			}
+		}
+		circularIndex++
+		if circularIndex == arraySize {
+			circularIndex = 0
+		}
+	}
+	return compositions[circularIndex].Composition, nil
+}
+
+// WordSegmentationNBest works like WordSegmentation but returns up to n
+// segmentations with distinct corrections, ordered by DistanceSum and then by
+// LogProbSum, the log10 probability of the corrected string.
+func (s *SymSpell) WordSegmentationNBest(phrase string, maxEditDistance, maxSegmentationWordLength, n int) ([]items.Composition, error) {
+	return s.wordSegmentationNBest(context.Background(), phrase, maxEditDistance, maxSegmentationWordLength, n)
+}
+
+func (s *SymSpell) wordSegmentationNBest(ctx context.Context, phrase string, maxEditDistance, maxSegmentationWordLength, n int) ([]items.Composition, error) {
+	runes, maxSegmentationWordLength, err := s.segmentationInput(phrase, maxEditDistance, maxSegmentationWordLength)
+	if err != nil || len(runes) == 0 || n <= 0 {
+		return nil, err
+	}
+
+	// best[j] holds the n best segmentations of runes[:j]
+	best := make([][]segmentation, len(runes)+1)
+	for j := 0; j < len(runes); j++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if j > 0 && len(best[j]) == 0 {
+			continue
+		}
+		imax := min(len(runes)-j, maxSegmentationWordLength)
+		for i := 1; i <= imax; i++ {
+			seg, ok := s.correctSegment(runes, j, i, maxEditDistance)
+			if !ok {
+				continue
+			}
+			if j == 0 {
+				best[i] = insertComposition(best[i], s.firstSegment(seg), n)
+				continue
+			}
+			for _, prev := range best[j] {
+				c := s.appendSegment(prev, seg, s.segmentLogProb(&prev, seg))
+				best[j+i] = insertComposition(best[j+i], c, n)
+			}
+		}
+	}
+	compositions := make([]items.Composition, len(best[len(runes)]))
+	for i, c := range best[len(runes)] {
+		compositions[i] = c.Composition
+	}
+	return compositions, nil
+}
+
+// insertComposition adds c to the ranked list of at most n segmentations,
+// replacing a worse segmentation with the same correction.
+func insertComposition(list []segmentation, c segmentation, n int) []segmentation {
+	before := func(a, b segmentation) bool {
+		if a.DistanceSum != b.DistanceSum {
+			return a.DistanceSum < b.DistanceSum
+		}
+		return a.LogProbSum > b.LogProbSum
+	}
+	for i, other := range list {
+		if other.CorrectedString == c.CorrectedString {
+			if !before(c, other) {
+				return list
+			}
+			list = slices.Delete(list, i, i+1)
+			break
+		}
+	}
+	pos := sort.Search(len(list), func(i int) bool { return before(c, list[i]) })
+	if pos >= n {
+		return list
+	}
+	list = slices.Insert(list, pos, c)
+	return list[:min(len(list), n)]
+}
+
+// segmentationInput validates the arguments of WordSegmentation and returns
+// the runes of the phrase and the effective maximum word length.
+func (s *SymSpell) segmentationInput(phrase string, maxEditDistance, maxSegmentationWordLength int) ([]rune, int, error) {
+	if maxEditDistance > s.MaxDictionaryEditDistance {
+		return nil, 0, ErrDistanceTooLarge
+	}
+	phrase, err := s.checkUTF8(phrase)
+	if err != nil {
+		return nil, 0, err
+	}
+	if maxSegmentationWordLength <= 0 {
+		maxSegmentationWordLength = s.maxLength
+	}
+	if maxSegmentationWordLength <= 0 {
+		return nil, 0, nil
+	}
+	return []rune(strings.TrimSpace(strings.ReplaceAll(phrase, "\n", " "))), maxSegmentationWordLength, nil
+}
+
+// segment is a part of a WordSegmentation input and its correction.
+type segment struct {
+	part, corrected string
+	separatorLength int // 1 if a space has to be inserted before the part
+	distance        int
+	logProb         float64 // unigram estimate of corrected
+}
+
+// correctSegment corrects runes[j:j+i] as one word. It reports false if the
+// part holds nothing but a leading space or cannot be a word.
+func (s *SymSpell) correctSegment(runes []rune, j, i, maxEditDistance int) (segment, bool) {
+	var seg segment
+	partRunes := runes[j : j+i]
+	if unicode.IsSpace(partRunes[0]) {
+		partRunes = partRunes[1:]
+	} else if s.cjkSegmentLength == 0 || j == 0 || !isCJK(runes[j-1]) || !isCJK(runes[j]) {
+		seg.separatorLength = 1
+	}
+	if len(partRunes) == 0 {
+		return segment{}, false
+	}
+	// remove inner spaces, each one counts as an edit
+	seg.part = strings.ReplaceAll(string(partRunes), " ", "")
+	seg.distance = len(partRunes) - runeLen(seg.part)
+	if s.isCJKRun(seg.part) {
+		return seg, s.correctCJKSegment(&seg, maxEditDistance)
+	}
+
+	suggestions, _ := s.lookupCached(context.Background(), seg.part, verbositypkg.Top, maxEditDistance)
+	if len(suggestions) > 0 {
+		seg.corrected = suggestions[0].Term
+		seg.distance += suggestions[0].Distance
+		seg.logProb = math.Log10(float64(suggestions[0].Count) / s.N)
+	} else {
+		seg.corrected = seg.part
+		seg.distance += runeLen(seg.part)
+		// unknown word probability from the Naive Bayes estimate used by LookupCompound
+		seg.logProb = math.Log10(10.0 / s.N / math.Pow(10.0, float64(runeLen(seg.part))))
+	}
+	return seg, true
+}
+
+// firstSegment returns the segmentation that starts with seg.
+func (s *SymSpell) firstSegment(seg segment) segmentation {
+	first := segmentation{Composition: items.Composition{
+		SegmentedString: seg.part,
+		CorrectedString: seg.corrected,
+		DistanceSum:     seg.distance,
+		LogProbSum:      s.segmentLogProb(nil, seg),
+	}}
+	if s.languageModel != nil {
+		first.words = strings.Fields(seg.corrected)
+	}
+	if len(s.Bigrams) > 0 {
+		first.last = lastWord(seg.corrected)
+	}
+	return first
+}
+
+// appendSegment returns prev followed by seg, with logProbSum as the log10
+// probability of the result.
+func (s *SymSpell) appendSegment(prev segmentation, seg segment, logProbSum float64) segmentation {
+	separator := " "
+	if isAttachedToken(seg.corrected) {
+		separator = ""
+	}
+	next := segmentation{Composition: items.Composition{
+		SegmentedString: prev.SegmentedString + separator + seg.part,
+		CorrectedString: prev.CorrectedString + separator + seg.corrected,
+		DistanceSum:     prev.DistanceSum + seg.separatorLength + seg.distance,
+		LogProbSum:      logProbSum,
+	}}
+	if s.languageModel != nil {
+		next.words = append(slices.Clip(prev.words), strings.Fields(seg.corrected)...)
+	}
+	if len(s.Bigrams) > 0 {
+		next.last = lastWord(seg.corrected)
+	}
+	return next
+}
+
+// lastWord returns the last space-separated word of term.
+func lastWord(term string) string {
+	term = strings.TrimRight(term, " ")
+	return term[strings.LastIndexByte(term, ' ')+1:]
+}
+
+// isAttachedToken reports whether a segment is written without a leading space
+// (single punctuation marks and contractions like "'s").
+func isAttachedToken(term string) bool {
+	runes := []rune(term)
+	if len(runes) == 1 && unicode.IsPunct(runes[0]) {
+		return true
+	}
+	return len(runes) == 2 && runes[0] == '\''
+}
@@ -0,0 +1,245 @@
+package symspell_test
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestAddWordAfterLoad(t *testing.T) {
+	s := newGoldenSymSpell(t)
+	for _, word := range []string{"kubernetes", "grafana", "привет"} {
+		if _, err := s.AddWord(word, 500); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.DeleteDictionaryEntry("grafana")
+
+	check := func(s symspell.SymSpell, stage string) {
+		t.Helper()
+		if got, _ := s.Lookup("kubernets", verbosity.Top, 2); len(got) != 1 || got[0].Term != "kubernetes" {
+			t.Errorf("%s: Lookup(kubernets) = %v", stage, got)
+		}
+		if got, _ := s.Lookup("grafna", verbosity.Top, 2); len(got) == 1 && got[0].Term == "grafana" {
+			t.Errorf("%s: deleted word grafana is still suggested", stage)
+		}
+		if got, _ := s.Lookup("hello", verbosity.Top, 0); len(got) != 1 || got[0].Term != "hello" {
+			t.Errorf("%s: Lookup(hello) = %v", stage, got)
+		}
+	}
+	check(s, "delta")
+
+	var buf bytes.Buffer
+	if err := s.SaveIndex(&buf); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := symspell.New(options.WithMaxDictionaryEditDistance(2), options.WithPrefixLength(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := restored.LoadIndex(&buf); err != nil {
+		t.Fatal(err)
+	}
+	check(restored, "restored")
+}
+
+func TestIncrementCountReranks(t *testing.T) {
+	s, err := symspell.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("cat", 100)
+	s.CreateDictionaryEntry("cot", 50)
+	if got, _ := s.Lookup("cxt", verbosity.Top, 1); len(got) != 1 || got[0].Term != "cat" {
+		t.Fatalf("Lookup(cxt) = %v", got)
+	}
+	if count, ok := s.IncrementCount("cot", 100); !ok || count != 150 {
+		t.Fatalf("IncrementCount(cot) = %d, %v", count, ok)
+	}
+	if got, _ := s.Lookup("cxt", verbosity.Top, 1); len(got) != 1 || got[0].Term != "cot" {
+		t.Errorf("Lookup(cxt) after increment = %v", got)
+	}
+	// a frequent enough neighbour overrides the exact match
+	if !s.UpdateWordFrequency("cat", 2000) {
+		t.Fatal("UpdateWordFrequency(cat) = false")
+	}
+	if got, _ := s.Lookup("cot", verbosity.Top, 1); len(got) != 1 || got[0].Term != "cat" {
+		t.Errorf("Lookup(cot) after update = %v", got)
+	}
+	if s.UpdateWordFrequency("dog", 1) {
+		t.Error("UpdateWordFrequency of an unknown word = true")
+	}
+}
+
+func TestWideCounts(t *testing.T) {
+	s, err := symspell.New(options.WithFrequencyThreshold(math.MaxInt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := "the 9000000000000000000\nteh 1000000000000000000\n"
+	if _, err := s.LoadDictionaryStream(strings.NewReader(data), 0, 1, " "); err != nil {
+		t.Fatal(err)
+	}
+	if count, ok := s.WordFrequency("the"); !ok || count != 9e18 {
+		t.Fatalf("WordFrequency(the) = %d, %v, want 9e18", count, ok)
+	}
+	// teh times the frequency multiplier overflows int64
+	if got, _ := s.Lookup("teh", verbosity.All, 2); len(got) != 2 || got[0].Term != "teh" {
+		t.Errorf("Lookup(teh) = %v, want the exact match kept", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "index.mmap")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveMappedIndex(f); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	mapped, err := symspell.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	closer, err := mapped.LoadMappedIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closer.Close()
+	if count, _ := mapped.WordFrequency("teh"); count != 1e18 {
+		t.Errorf("WordFrequency(teh) after LoadMappedIndex = %d, want 1e18", count)
+	}
+
+	if count, _ := s.IncrementCount("the", math.MaxUint64); count != math.MaxUint64 {
+		t.Errorf("IncrementCount(the) = %d, want saturation at MaxUint64", count)
+	}
+	if got, _ := s.Lookup("the", verbosity.Top, 0); len(got) != 1 || got[0].Count != math.MaxInt {
+		t.Errorf("Lookup(the) = %v, want Count saturated at MaxInt", got)
+	}
+}
+
+func TestUserDictionary(t *testing.T) {
+	s := newGoldenSymSpell(t)
+	base, _ := s.Lookup("helo", verbosity.Top, 2)
+	if len(base) != 1 || base[0].Term == "helo" {
+		t.Fatalf("Lookup(helo) = %v", base)
+	}
+	if _, err := s.AddUserWord("hela", 1); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.Lookup("helo", verbosity.Top, 2); len(got) != 1 || got[0].Term != "hela" {
+		t.Errorf("Lookup(helo) with user word = %v", got)
+	}
+
+	var saved bytes.Buffer
+	if err := s.SaveUserDictionary(&saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.String() != "hela 1\n" {
+		t.Errorf("SaveUserDictionary = %q", saved.String())
+	}
+	s.ClearUserDictionary()
+	if got, _ := s.Lookup("helo", verbosity.Top, 2); len(got) != 1 || got[0] != base[0] {
+		t.Errorf("Lookup(helo) after clear = %v, want %v", got, base)
+	}
+	if err := s.LoadUserDictionary(&saved); err != nil {
+		t.Fatal(err)
+	}
+	if !s.IsUserWord("hela") {
+		t.Error("hela is not a user word after LoadUserDictionary")
+	}
+}
+
+func TestUserDictionaryOverlay(t *testing.T) {
+	s := newGoldenSymSpell(t)
+	baseCount, _ := s.WordFrequency("the")
+	s.AddUserWord("hela", 1)
+	s.AddUserWord("the", 5)
+	if count, _ := s.WordFrequency("the"); count != baseCount+5 {
+		t.Errorf("WordFrequency(the) with user count = %d, want %d", count, baseCount+5)
+	}
+
+	var index bytes.Buffer
+	if err := s.SaveIndex(&index); err != nil {
+		t.Fatal(err)
+	}
+	loaded := newGoldenSymSpell(t)
+	if err := loaded.LoadIndex(bytes.NewReader(index.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.ContainsWord("hela") {
+		t.Error("SaveIndex wrote the user word hela")
+	}
+	if count, _ := loaded.WordFrequency("the"); count != baseCount {
+		t.Errorf("saved count of the = %d, want the base count %d", count, baseCount)
+	}
+
+	if err := s.LoadIndex(bytes.NewReader(index.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.Lookup("helo", verbosity.Top, 2); !s.IsUserWord("hela") || len(got) != 1 || got[0].Term != "hela" {
+		t.Errorf("Lookup(helo) after LoadIndex = %v, want the user word hela", got)
+	}
+	s.ClearUserDictionary()
+	if count, _ := s.WordFrequency("the"); count != baseCount {
+		t.Errorf("WordFrequency(the) after clear = %d, want %d", count, baseCount)
+	}
+}
+
+func TestUserWordCounts(t *testing.T) {
+	s, err := symspell.New(options.WithCountThreshold(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("rare", 3)
+	s.AddUserWord("rare", 1)
+	s.RemoveUserWord("rare")
+	if !s.CreateDictionaryEntry("rare", 7) {
+		t.Error("AddUserWord dropped the below-threshold count of rare")
+	}
+
+	const nearMax = ^uint64(0) - 1
+	s.CreateDictionaryEntry("common", nearMax)
+	s.AddUserWord("common", 10)
+	if count, _ := s.WordFrequency("common"); count != ^uint64(0) {
+		t.Errorf("WordFrequency(common) = %d, want it saturated", count)
+	}
+	s.RemoveUserWord("common")
+	if count, _ := s.WordFrequency("common"); count != nearMax {
+		t.Errorf("WordFrequency(common) after RemoveUserWord = %d, want %d", count, nearMax)
+	}
+}
+
+func TestWordIntrospection(t *testing.T) {
+	s, err := symspell.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("cat", 3)
+	s.CreateDictionaryEntry("dog", 5)
+	s.CreateDictionaryEntry("cow", 7)
+	s.DeleteDictionaryEntry("dog")
+
+	if !s.ContainsWord("cat") || s.ContainsWord("dog") || s.WordCount() != 2 {
+		t.Errorf("ContainsWord(cat)=%v ContainsWord(dog)=%v WordCount()=%d",
+			s.ContainsWord("cat"), s.ContainsWord("dog"), s.WordCount())
+	}
+	if count, ok := s.WordFrequency("cow"); !ok || count != 7 {
+		t.Errorf("WordFrequency(cow) = %d, %v", count, ok)
+	}
+	var entries []string
+	for term, count := range s.Entries() {
+		entries = append(entries, fmt.Sprintf("%s:%d", term, count))
+	}
+	if got := strings.Join(entries, " "); got != "cat:3 cow:7" {
+		t.Errorf("Entries() = %s", got)
+	}
+}
@@ -0,0 +1,87 @@
+package symspell_test
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+// stackDistance is Levenshtein over runes on stack buffers, for words of up
+// to 15 runes, so that allocation counts only include the lookup itself.
+type stackDistance struct{}
+
+func (stackDistance) Distance(a, b string) int {
+	var prev, curr [16]int
+	n := utf8.RuneCountInString(b)
+	for j := range n + 1 {
+		prev[j] = j
+	}
+	i := 0
+	for _, ra := range a {
+		i++
+		curr[0] = i
+		j := 0
+		for _, rb := range b {
+			j++
+			cost := 1
+			if ra == rb {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[n]
+}
+
+func (d stackDistance) DistanceMax(a, b string, maxDistance int) int {
+	if distance := d.Distance(a, b); distance <= maxDistance {
+		return distance
+	}
+	return maxDistance + 1
+}
+
+func TestUnicodeLookupAllocs(t *testing.T) {
+	newSymSpell := func(words ...string) symspell.SymSpell {
+		s, err := symspell.New(options.WithLookupCache(0), options.WithDistanceComparer(stackDistance{}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, word := range words {
+			s.CreateDictionaryEntry(word, uint64(100+i))
+		}
+		return s
+	}
+	// the same dictionary in Cyrillic and in Latin letters
+	cyrillic := newSymSpell("привет", "приват", "природа", "пример", "прибор")
+	latin := newSymSpell("privet", "privat", "priroda", "primer", "pribor")
+
+	for _, v := range []verbosity.Verbosity{verbosity.Top, verbosity.Closest, verbosity.All} {
+		want := testing.AllocsPerRun(100, func() { latin.Lookup("privit", v, 2) })
+		got := testing.AllocsPerRun(100, func() { cyrillic.Lookup("привит", v, 2) })
+		if got > want {
+			t.Errorf("Lookup(привит, %v) allocates %v times, the Latin lookup %v", v, got, want)
+		}
+	}
+}
+
+func TestLookupAppendAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector changes allocation counts")
+	}
+	s := newGoldenSymSpell(t, options.WithLookupCache(0))
+	buf := make([]items.SuggestItem, 0, 64)
+	for _, term := range []string{"hello", "helo", "wrlod"} {
+		for _, v := range []verbosity.Verbosity{verbosity.Top, verbosity.Closest, verbosity.All} {
+			lookup := testing.AllocsPerRun(100, func() { s.Lookup(term, v, 2) })
+			got := testing.AllocsPerRun(100, func() { buf, _ = s.LookupAppend(buf[:0], term, v, 2) })
+			if got != lookup-1 {
+				t.Errorf("LookupAppend(%q, %v) allocates %v times, want one less than the %v of Lookup", term, v, got, lookup)
+			}
+		}
+	}
+}
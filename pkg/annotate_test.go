@@ -0,0 +1,107 @@
+package symspell_test
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+)
+
+func TestAnnotate(t *testing.T) {
+	s, err := symspell.New(options.WithPreserveCase())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for word, count := range map[string]uint64{"say": 900, "hello": 1000, "help": 500, "world": 100} {
+		s.CreateDictionaryEntry(word, count)
+	}
+	if _, err := s.LoadExactDictionaryStream(strings.NewReader("thx thanks\n"), " "); err != nil {
+		t.Fatal(err)
+	}
+
+	text := "Say Helo, wrld! thx"
+	got, err := s.Annotate(text, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []items.Annotation{
+		{Start: 4, End: 8, Original: "Helo", Replacement: "Hello", Distance: 1, Confidence: 1000.0 / 1500 * 2 / 3},
+		{Start: 10, End: 14, Original: "wrld", Replacement: "world", Distance: 1, Confidence: 2.0 / 3},
+		{Start: 16, End: 19, Original: "thx", Replacement: "thanks", Distance: 4, Confidence: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Annotate(%q) = %+v, want %+v", text, got, want)
+	}
+	for i := range want {
+		confidence := got[i].Confidence
+		got[i].Confidence = want[i].Confidence
+		if got[i] != want[i] || math.Abs(confidence-want[i].Confidence) > 1e-9 {
+			t.Errorf("annotation %d = %+v with confidence %v, want %+v", i, got[i], confidence, want[i])
+		}
+		if text[got[i].Start:got[i].End] != got[i].Original {
+			t.Errorf("annotation %d spans %q, want %q", i, text[got[i].Start:got[i].End], got[i].Original)
+		}
+	}
+
+	data, err := json.Marshal(got[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"start", "end", "original", "replacement", "distance", "confidence"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("annotation JSON %s misses %q", data, key)
+		}
+	}
+
+	if got, err := s.Annotate("say hello world", 2); err != nil || len(got) != 0 {
+		t.Errorf("Annotate of correct text = %+v, %v, want no annotations", got, err)
+	}
+}
+
+func TestAnnotateUsesCompoundTokens(t *testing.T) {
+	newSymSpell := func(opts ...options.Options) symspell.SymSpell {
+		s, err := symspell.New(opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for word, count := range map[string]uint64{"hello": 1000, "world": 1000, "the": 5000} {
+			s.CreateDictionaryEntry(word, count)
+		}
+		return s
+	}
+	for _, tc := range []struct {
+		name string
+		s    symspell.SymSpell
+		text string
+		want []string
+	}{
+		// the word around an invalid byte is one token, not "hel" and "lo"
+		{"pass-through", newSymSpell(), "hel\xfflo wrld", []string{"hel\xfflo→hello", "wrld→world"}},
+		{"sanitize", newSymSpell(options.WithInvalidUTF8Policy(options.InvalidUTF8Sanitize)), "wr\xffld hello", []string{"wr\xffld→world"}},
+		// a word as long as MinimumCharToChange is corrected, as by Lookup
+		{"minimum length", newSymSpell(options.WithMinimumCharacterToChange(3)), "hte wrld", []string{"hte→the", "wrld→world"}},
+	} {
+		got, err := tc.s.Annotate(tc.text, 2)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		var corrections []string
+		for _, a := range got {
+			if tc.text[a.Start:a.End] != a.Original {
+				t.Errorf("%s: annotation %+v spans %q", tc.name, a, tc.text[a.Start:a.End])
+			}
+			corrections = append(corrections, a.Original+"→"+a.Replacement)
+		}
+		if strings.Join(corrections, " ") != strings.Join(tc.want, " ") {
+			t.Errorf("%s: Annotate(%q) = %q, want %q", tc.name, tc.text, corrections, tc.want)
+		}
+	}
+}
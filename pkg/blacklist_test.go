@@ -0,0 +1,59 @@
+package symspell_test
+
+import (
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestSuggestionBlacklist(t *testing.T) {
+	s, err := symspell.New(options.WithSuggestionBlacklist([]string{"duck"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("duck", 1000)
+	s.CreateDictionaryEntry("dusk", 500)
+	s.CreateDictionaryEntry("dunk", 200)
+
+	terms := func(v verbosity.Verbosity) []string {
+		suggestions, err := s.Lookup("duk", v, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return termsOf(suggestions)
+	}
+	// the blacklisted word cannot take the place of the best remaining one
+	if got := terms(verbosity.Top); len(got) != 1 || got[0] != "dusk" {
+		t.Errorf("Top with duck blacklisted = %v, want [dusk]", got)
+	}
+	if got := terms(verbosity.Closest); len(got) != 2 || got[0] != "dusk" || got[1] != "dunk" {
+		t.Errorf("Closest with duck blacklisted = %v, want [dusk dunk]", got)
+	}
+	if !s.IsBlacklisted("duck") || s.IsBlacklisted("dusk") {
+		t.Error("IsBlacklisted does not match WithSuggestionBlacklist")
+	}
+
+	// runtime changes apply to cached Top results as well
+	s.RemoveFromBlacklist("duck")
+	if got := terms(verbosity.Top); len(got) != 1 || got[0] != "duck" {
+		t.Errorf("Top after RemoveFromBlacklist(duck) = %v, want [duck]", got)
+	}
+	s.AddToBlacklist("duck", "dusk")
+	if got := terms(verbosity.Top); len(got) != 1 || got[0] != "dunk" {
+		t.Errorf("Top after AddToBlacklist(duck, dusk) = %v, want [dunk]", got)
+	}
+	if !s.ContainsWord("duck") {
+		t.Error("blacklisting removed duck from the dictionary")
+	}
+}
+
+func termsOf(suggestions []items.SuggestItem) []string {
+	terms := make([]string, len(suggestions))
+	for i, suggestion := range suggestions {
+		terms[i] = suggestion.Term
+	}
+	return terms
+}
@@ -0,0 +1,54 @@
+package symspell_test
+
+import (
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/verbosity"
+)
+
+func TestLookupWithBoost(t *testing.T) {
+	s, err := symspell.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("hello", 1000)
+	s.CreateDictionaryEntry("help", 500)
+	s.CreateDictionaryEntry("helm", 100)
+	if err := s.RegisterBoostList("support", []string{"help"}, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RegisterBoostList("sailing", []string{"helm"}, 20); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct{ list, want string }{
+		{"", "hello"},
+		{"support", "help"},
+		{"sailing", "helm"},
+	} {
+		got, err := s.LookupWithBoost("helo", verbosity.Top, 2, tt.list)
+		if err != nil || len(got) != 1 || got[0].Term != tt.want {
+			t.Errorf("LookupWithBoost(helo, %q) = %v, %v, want %s", tt.list, got, err, tt.want)
+		}
+	}
+	// boosting reorders, it does not change the counts or the shared index
+	got, err := s.LookupWithBoost("helo", verbosity.Closest, 2, "support")
+	if err != nil || len(got) != 3 || got[0].Term != "help" || got[0].Count != 500 {
+		t.Errorf("LookupWithBoost(helo, Closest, support) = %v, %v", got, err)
+	}
+	if got, _ := s.Lookup("helo", verbosity.Top, 2); len(got) != 1 || got[0].Term != "hello" {
+		t.Errorf("Lookup(helo) after boosted lookups = %v, want hello", got)
+	}
+
+	s.RemoveBoostList("support")
+	if _, err := s.LookupWithBoost("helo", verbosity.Top, 2, "support"); err == nil {
+		t.Error("LookupWithBoost with a removed list: error = nil")
+	}
+	if err := s.RegisterBoostList("", []string{"help"}, 2); err == nil {
+		t.Error("RegisterBoostList without a name: error = nil")
+	}
+	if err := s.RegisterBoostList("none", []string{"help"}, 0); err == nil {
+		t.Error("RegisterBoostList with multiplier 0: error = nil")
+	}
+}
@@ -0,0 +1,85 @@
+package symspell_test
+
+import (
+	"sync"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestPreserveCase(t *testing.T) {
+	s, err := symspell.New(options.WithMaxDictionaryEditDistance(2), options.WithPreserveCase())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.LoadDictionary("testdata/dictionary.txt", 0, 1, " "); err != nil {
+		t.Fatal(err)
+	}
+
+	for input, want := range map[string]string{"Helllo": "Hello", "HELLLO": "HELLO", "helllo": "hello"} {
+		suggestions, err := s.Lookup(input, verbosity.Top, 2)
+		if err != nil || len(suggestions) == 0 || suggestions[0].Term != want {
+			t.Errorf("Lookup(%q) = %v, %v, want %q", input, suggestions, err, want)
+		}
+	}
+	if got := s.LookupCompound("Helllo Wrld", 2); got == nil || got.Term != "Hello World" {
+		t.Errorf("LookupCompound = %v, want %q", got, "Hello World")
+	}
+}
+
+func TestCaseFoldingTurkish(t *testing.T) {
+	s, err := symspell.New(options.WithCaseFolding(language.Turkish))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("İstanbul", 100)
+	s.CreateDictionaryEntry("ışık", 100)
+
+	tests := []struct {
+		input, want string
+		distance    int
+	}{
+		{"istanbul", "istanbul", 0},
+		{"İSTANBUL", "İSTANBUL", 0},
+		{"IŞIK", "IŞIK", 0},
+		{"Işk", "Işık", 1},
+	}
+	for _, tt := range tests {
+		suggestions, err := s.Lookup(tt.input, verbosity.Top, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(suggestions) == 0 || suggestions[0].Term != tt.want || suggestions[0].Distance != tt.distance {
+			t.Errorf("Lookup(%q) = %v, want %q at distance %d", tt.input, suggestions, tt.want, tt.distance)
+		}
+	}
+}
+
+func TestCaseFoldingConcurrentLookups(t *testing.T) {
+	s, err := symspell.New(options.WithCaseFolding(language.Turkish), options.WithThreadSafe())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("İstanbul", 100)
+	s.CreateDictionaryEntry("ışık", 100)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				suggestions, err := s.Lookup("Işk", verbosity.Top, 2)
+				if err != nil || len(suggestions) == 0 || suggestions[0].Term != "Işık" {
+					t.Errorf("Lookup(Işk) = %v, %v", suggestions, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,11 @@
+// Package channel holds error models for noisy-channel ranking, see
+// options.WithNoisyChannel.
+package channel
+
+// Model is the channel model of a noisy-channel speller: the probability of
+// a typist producing observed when they meant candidate. Implementations
+// must be safe for concurrent use.
+type Model interface {
+	// LogProb returns log10 P(observed | candidate), a value <= 0.
+	LogProb(observed, candidate string) float64
+}
@@ -0,0 +1,49 @@
+package channel
+
+import "math"
+
+// EditRates is a Model with one error rate per edit operation. The
+// probability of a typo is the product of the rates of the edits in its most
+// likely alignment with the candidate; correctly typed characters cost
+// nothing. Rates must be in (0, 1].
+type EditRates struct {
+	Insert     float64 // an extra character typed
+	Delete     float64 // a character of the candidate left out
+	Substitute float64 // a character typed in place of another
+	Transpose  float64 // two adjacent characters swapped
+}
+
+var _ Model = EditRates{}
+
+// DefaultEditRates are rough per-operation rates of typing errors.
+var DefaultEditRates = EditRates{Insert: 0.01, Delete: 0.01, Substitute: 0.01, Transpose: 0.005}
+
+func (r EditRates) LogProb(observed, candidate string) float64 {
+	a, b := []rune(candidate), []rune(observed)
+	ins, del := -math.Log10(r.Insert), -math.Log10(r.Delete)
+	sub, trans := -math.Log10(r.Substitute), -math.Log10(r.Transpose)
+
+	// costs of the optimal string alignment of candidate to observed
+	prev2 := make([]float64, len(b)+1)
+	prev := make([]float64, len(b)+1)
+	curr := make([]float64, len(b)+1)
+	for j := range prev {
+		prev[j] = float64(j) * ins
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = float64(i) * del
+		for j := 1; j <= len(b); j++ {
+			cost := prev[j-1]
+			if a[i-1] != b[j-1] {
+				cost += sub
+			}
+			cost = min(cost, prev[j]+del, curr[j-1]+ins)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && a[i-1] != b[j-1] {
+				cost = min(cost, prev2[j-2]+trans)
+			}
+			curr[j] = cost
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return -prev[len(b)]
+}
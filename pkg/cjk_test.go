@@ -0,0 +1,81 @@
+package symspell_test
+
+import (
+	"strings"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/options"
+)
+
+func newCJKSymSpell(t *testing.T) symspell.SymSpell {
+	t.Helper()
+	s, err := symspell.New(options.WithMaxDictionaryEditDistance(2), options.WithCJKSegmentation(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for word, count := range map[string]uint64{
+		"我": 5000, "爱": 3000, "北京": 2000, "天安门": 1000,
+		"北": 400, "天": 600, "安": 300, "门": 500,
+		"東京": 2000, "へ": 4000, "行く": 1500, "hello": 1000,
+	} {
+		s.CreateDictionaryEntry(word, count)
+	}
+	return s
+}
+
+func TestCJKSegmentation(t *testing.T) {
+	s := newCJKSymSpell(t)
+	for _, tt := range []struct{ input, want string }{
+		{"我爱北京天安门", "我 爱 北京 天安门"},
+		{"我爱北景天安门", "我 爱 北京 天安门"},
+		{"東京へ行く", "東京 へ 行く"},
+	} {
+		got, err := s.WordSegmentation(tt.input, 1, 0)
+		if err != nil || got.CorrectedString != tt.want {
+			t.Errorf("WordSegmentation(%q) = %+v, %v, want %q", tt.input, got, err, tt.want)
+		}
+	}
+
+	got := s.LookupCompoundDetailed("helo 我爱北景天安门", 1)
+	if got == nil || got.Suggestion.Term != "hello 我爱北京天安门" || got.Suggestion.Distance != 2 {
+		t.Fatalf("LookupCompoundDetailed = %+v, want hello 我爱北京天安门 at distance 2", got)
+	}
+	if tok := got.Tokens[1]; tok.Original != "我爱北景天安门" || tok.Replacement != "我爱北京天安门" || tok.Distance != 1 {
+		t.Errorf("CJK token = %+v", tok)
+	}
+	if got := s.LookupCompound("helo北京", 1); got == nil || got.Term != "hello 北京" {
+		t.Errorf("LookupCompound(helo北京) = %v, want hello 北京", got)
+	}
+}
+
+func TestCJKDictionaryFormat(t *testing.T) {
+	s, err := symspell.New(options.WithMaxDictionaryEditDistance(2), options.WithCJKSegmentation(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the layout of jieba's dict.txt: word, count and part-of-speech tag
+	dict := "我 5000 r\n爱 3000 v\n北京 2000 ns\n天安门 1000 ns\n"
+	if _, err := s.LoadDictionaryStream(strings.NewReader(dict), 0, 1, " "); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.WordSegmentation("我爱北京天安门", 1, 0); err != nil || got.CorrectedString != "我 爱 北京 天安门" {
+		t.Errorf("WordSegmentation = %+v, %v", got, err)
+	}
+}
+
+func TestCJKSegmentationKeepsLatinSegmentation(t *testing.T) {
+	s := newCJKSymSpell(t)
+	plain, err := symspell.New(options.WithMaxDictionaryEditDistance(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain.CreateDictionaryEntry("hello", 1000)
+	for _, spell := range []symspell.SymSpell{s, plain} {
+		spell.CreateDictionaryEntry("world", 800)
+	}
+	want, _ := plain.WordSegmentation("helloworld", 1, 0)
+	if got, err := s.WordSegmentation("helloworld", 1, 0); err != nil || got != want {
+		t.Errorf("WordSegmentation(helloworld) = %+v, %v, want %+v", got, err, want)
+	}
+}
@@ -0,0 +1,73 @@
+package symspell_test
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestCloneIsIndependent(t *testing.T) {
+	for _, opts := range [][]options.Options{
+		nil,
+		{options.WithCompactStorage()},
+		{options.WithIndexBackend(options.IndexBackendTrie)},
+		{options.WithThreadSafe()},
+	} {
+		s := newGoldenSymSpell(t, opts...)
+		want, _ := s.Lookup("speling", verbosity.All, 2)
+		c, err := symspell.Clone(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.AddWord("spieling", 1000); err != nil {
+			t.Fatal(err)
+		}
+		c.DeleteDictionaryEntry("spelling")
+		c.Compact()
+		if got, _ := s.Lookup("speling", verbosity.All, 2); !reflect.DeepEqual(got, want) {
+			t.Errorf("original after updating the clone = %v, want %v", got, want)
+		}
+		s.DeleteDictionaryEntry("spieling")
+		s.Compact()
+		if !c.ContainsWord("spieling") || c.ContainsWord("spelling") {
+			t.Error("clone changed by updating the original")
+		}
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	s := newGoldenSymSpell(t, options.WithThreadSafe())
+	want, _ := s.Lookup("speling", verbosity.Top, 2)
+	frozen, err := symspell.Freeze(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := frozen.AddWord("spieling", 1); !errors.Is(err, symspell.ErrFrozen) {
+		t.Errorf("AddWord on a frozen instance: err = %v, want ErrFrozen", err)
+	}
+	s.DeleteDictionaryEntry("spelling")
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			if got, _ := frozen.Lookup("speling", verbosity.Top, 2); !reflect.DeepEqual(got, want) {
+				t.Errorf("frozen Lookup = %v, want %v", got, want)
+			}
+			frozen.TopWords(3)
+		})
+	}
+	wg.Wait()
+
+	c, err := symspell.Clone(frozen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := c.AddWord("spieling", 1); err != nil || !ok {
+		t.Errorf("AddWord on a clone of a frozen instance = %v, %v", ok, err)
+	}
+}
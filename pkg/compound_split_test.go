@@ -0,0 +1,69 @@
+package symspell_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestCompoundSplitting(t *testing.T) {
+	newGerman := func(opts ...options.Options) symspell.SymSpell {
+		t.Helper()
+		s, err := symspell.New(append([]options.Options{options.WithMaxDictionaryEditDistance(2)}, opts...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for word, count := range map[string]uint64{
+			"Donau": 500, "Dampf": 800, "Schiff": 2000, "Fahrt": 1500,
+			"Arbeit": 3000, "Zimmer": 2500, "Tür": 1800, "Dampfer": 100,
+		} {
+			s.CreateDictionaryEntry(word, count)
+		}
+		return s
+	}
+
+	s := newGerman(options.WithCompoundSplitting(3, options.GermanLinkingMorphemes...))
+	for _, tt := range []struct {
+		word string
+		want []string
+	}{
+		{"Donaudampfschifffahrt", []string{"Donau", "dampf", "schiff", "fahrt"}},
+		{"Arbeitszimmer", []string{"Arbeit", "zimmer"}},
+		{"Zimmertür", []string{"Zimmer", "tür"}},
+		{"Zimmertüx", nil},
+		{"Schiff", nil},
+	} {
+		if got := s.SplitCompound(tt.word); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitCompound(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+
+	got, err := s.Lookup("Arbeitszimmer", verbosity.Top, 2)
+	if err != nil || len(got) != 1 || got[0].Term != "Arbeitszimmer" || got[0].Distance != 0 {
+		t.Errorf("Lookup(Arbeitszimmer) = %v, %v, want an exact match", got, err)
+	}
+	// a typo of a dictionary compound is corrected although it splits
+	typo := newGerman(options.WithPrefixLength(16), options.WithCompoundSplitting(3, options.GermanLinkingMorphemes...))
+	typo.CreateDictionaryEntry("Arbeitszimmer", 400)
+	if got, _ := typo.Lookup("Arbeitzimmer", verbosity.Top, 2); len(got) != 1 || got[0].Term != "Arbeitszimmer" || got[0].Distance != 1 {
+		t.Errorf("Lookup(Arbeitzimmer) = %v, want Arbeitszimmer at distance 1", got)
+	}
+	if got, _ := newGerman().Lookup("Arbeitszimmer", verbosity.Top, 2); len(got) != 0 {
+		t.Errorf("Lookup(Arbeitszimmer) without splitting = %v, want no suggestions", got)
+	}
+	if got := newGerman(options.WithCompoundSplitting(3)).SplitCompound("Arbeitszimmer"); got != nil {
+		t.Errorf("SplitCompound(Arbeitszimmer) without linking morphemes = %q, want nil", got)
+	}
+
+	if _, err := symspell.New(options.WithCompoundSplitting(3, "")); !errors.Is(err, symspell.ErrInvalidOptions) {
+		t.Errorf("New(empty linking morpheme) error = %v, want ErrInvalidOptions", err)
+	}
+	if _, err := options.FromJSON(strings.NewReader(`{"compound_splitting": {"min_part_length": 4, "linking_morphemes": ["s"]}}`)); err != nil {
+		t.Errorf("FromJSON(compound_splitting) error = %v", err)
+	}
+}
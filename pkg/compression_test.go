@@ -0,0 +1,50 @@
+package symspell_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+
+	symspell "symspell/pkg"
+)
+
+func TestCompressedDictionary(t *testing.T) {
+	const data = "hello 100\nworld 50\n"
+	compressors := map[string]func(io.Writer) io.WriteCloser{
+		"gzip": func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		"zstd": func(w io.Writer) io.WriteCloser {
+			encoder, _ := zstd.NewWriter(w)
+			return encoder
+		},
+	}
+	for name, compress := range compressors {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := compress(&buf)
+			io.WriteString(w, data)
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(t.TempDir(), "dictionary."+name)
+			if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			s, err := symspell.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.LoadDictionary(path, 0, 1, " "); err != nil {
+				t.Fatal(err)
+			}
+			if count, ok := s.WordFrequency("world"); s.WordCount() != 2 || !ok || count != 50 {
+				t.Errorf("WordCount() = %d, WordFrequency(world) = %d, %v", s.WordCount(), count, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,470 @@
+package symspell
+
+import (
+	"context"
+	"io"
+	"iter"
+	"slices"
+	"sync"
+
+	"symspell/internal"
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+	"symspell/pkg/stats"
+	"symspell/pkg/verbosity"
+)
+
+// lockedSymSpell makes a SymSpell safe for concurrent use. Lookups run in
+// parallel under a read lock; methods that mutate the dictionary, the deletes
+// index or the lookup settings take the write lock and wait for in-flight
+// lookups to finish. Every call observes either all or none of a concurrent
+// update.
+type lockedSymSpell struct {
+	mu versionedRWMutex
+	s  SymSpell
+}
+
+// versionedRWMutex counts the write locks taken, so that work done on a
+// snapshot without the lock can tell whether the instance changed meanwhile.
+type versionedRWMutex struct {
+	sync.RWMutex
+	writes uint64
+}
+
+func (m *versionedRWMutex) Lock() {
+	m.RWMutex.Lock()
+	m.writes++
+}
+
+var _ SymSpell = (*lockedSymSpell)(nil)
+
+// NewConcurrent wraps s so that it can be shared between goroutines. Instances
+// created with options.WithThreadSafe are already wrapped, as are wrappers
+// with an Unwrap method around such instances, and are returned as they are.
+func NewConcurrent(s SymSpell) SymSpell {
+	if _, ok := unwrap(s).(*lockedSymSpell); ok {
+		return s
+	}
+	return &lockedSymSpell{s: s}
+}
+
+func (l *lockedSymSpell) Lookup(phrase string, verbosity verbosity.Verbosity, maxEditDistance int) ([]items.SuggestItem, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.Lookup(phrase, verbosity, maxEditDistance)
+}
+
+func (l *lockedSymSpell) LookupAppend(dst []items.SuggestItem, phrase string, verbosity verbosity.Verbosity, maxEditDistance int) ([]items.SuggestItem, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.LookupAppend(dst, phrase, verbosity, maxEditDistance)
+}
+
+// Suggestions collects the suggestions under the read lock and yields them
+// after releasing it, so the loop body may update the dictionary. Stopping
+// early saves no work.
+func (l *lockedSymSpell) Suggestions(phrase string, verbosity verbosity.Verbosity, maxEditDistance int) iter.Seq[items.SuggestItem] {
+	return func(yield func(items.SuggestItem) bool) {
+		l.mu.RLock()
+		suggestions := slices.Collect(l.s.Suggestions(phrase, verbosity, maxEditDistance))
+		l.mu.RUnlock()
+		for _, suggestion := range suggestions {
+			if !yield(suggestion) {
+				return
+			}
+		}
+	}
+}
+
+func (l *lockedSymSpell) LookupContext(ctx context.Context, phrase string, verbosity verbosity.Verbosity, maxEditDistance int) ([]items.SuggestItem, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.LookupContext(ctx, phrase, verbosity, maxEditDistance)
+}
+
+func (l *lockedSymSpell) LookupWithOptions(phrase string, opts options.LookupOptions) ([]items.SuggestItem, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.LookupWithOptions(phrase, opts)
+}
+
+func (l *lockedSymSpell) LookupBatch(terms []string, verbosity verbosity.Verbosity, maxEditDistance int) ([][]items.SuggestItem, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.LookupBatch(terms, verbosity, maxEditDistance)
+}
+
+func (l *lockedSymSpell) LookupCompound(phrase string, maxEditDistance int) *items.SuggestItem {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.LookupCompound(phrase, maxEditDistance)
+}
+
+func (l *lockedSymSpell) LookupCompoundDetailed(phrase string, maxEditDistance int) *items.CompoundResult {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.LookupCompoundDetailed(phrase, maxEditDistance)
+}
+
+func (l *lockedSymSpell) LookupCompoundContext(ctx context.Context, phrase string, maxEditDistance int) (*items.CompoundResult, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.LookupCompoundContext(ctx, phrase, maxEditDistance)
+}
+
+func (l *lockedSymSpell) LookupCompoundNBest(phrase string, maxEditDistance, n int) []items.CompoundResult {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.LookupCompoundNBest(phrase, maxEditDistance, n)
+}
+
+func (l *lockedSymSpell) LookupInContext(tokens []string, index int, maxEditDistance int) ([]items.SuggestItem, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.LookupInContext(tokens, index, maxEditDistance)
+}
+
+func (l *lockedSymSpell) LookupWithNeighbors(prev, word, next string, maxEditDistance int) ([]items.SuggestItem, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.LookupWithNeighbors(prev, word, next, maxEditDistance)
+}
+
+func (l *lockedSymSpell) SplitCompound(word string) []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.SplitCompound(word)
+}
+
+func (l *lockedSymSpell) WordSegmentation(phrase string, maxEditDistance, maxSegmentationWordLength int) (items.Composition, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.WordSegmentation(phrase, maxEditDistance, maxSegmentationWordLength)
+}
+
+func (l *lockedSymSpell) WordSegmentationContext(ctx context.Context, phrase string, maxEditDistance, maxSegmentationWordLength int) (items.Composition, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.WordSegmentationContext(ctx, phrase, maxEditDistance, maxSegmentationWordLength)
+}
+
+func (l *lockedSymSpell) WordSegmentationNBest(phrase string, maxEditDistance, maxSegmentationWordLength, n int) ([]items.Composition, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.WordSegmentationNBest(phrase, maxEditDistance, maxSegmentationWordLength, n)
+}
+
+func (l *lockedSymSpell) LookupWithBoost(phrase string, verbosity verbosity.Verbosity, maxEditDistance int, boostListName string) ([]items.SuggestItem, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.LookupWithBoost(phrase, verbosity, maxEditDistance, boostListName)
+}
+
+func (l *lockedSymSpell) Annotate(text string, maxEditDistance int) ([]items.Annotation, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.Annotate(text, maxEditDistance)
+}
+
+func (l *lockedSymSpell) RegisterBoostList(name string, terms []string, multiplier float64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.RegisterBoostList(name, terms, multiplier)
+}
+
+func (l *lockedSymSpell) RemoveBoostList(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.s.RemoveBoostList(name)
+}
+
+func (l *lockedSymSpell) AddToBlacklist(words ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.s.AddToBlacklist(words...)
+}
+
+func (l *lockedSymSpell) RemoveFromBlacklist(words ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.s.RemoveFromBlacklist(words...)
+}
+
+func (l *lockedSymSpell) IsBlacklisted(word string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.IsBlacklisted(word)
+}
+
+func (l *lockedSymSpell) LoadProtectedWords(r io.Reader) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.LoadProtectedWords(r)
+}
+
+func (l *lockedSymSpell) IsProtected(word string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.IsProtected(word)
+}
+
+func (l *lockedSymSpell) LoadDictionary(corpusPath string, termIndex int, countIndex int, separator string, opts ...options.LoadOption) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.LoadDictionary(corpusPath, termIndex, countIndex, separator, opts...)
+}
+
+func (l *lockedSymSpell) LoadDictionaryContext(ctx context.Context, corpusPath string, termIndex int, countIndex int, separator string, progress func(linesRead, wordsAdded int), opts ...options.LoadOption) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.LoadDictionaryContext(ctx, corpusPath, termIndex, countIndex, separator, progress, opts...)
+}
+
+func (l *lockedSymSpell) LoadDictionaryStream(corpusStream io.Reader, termIndex int, countIndex int, separator string, opts ...options.LoadOption) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.LoadDictionaryStream(corpusStream, termIndex, countIndex, separator, opts...)
+}
+
+func (l *lockedSymSpell) LoadHunspell(dicPath, affPath string, opts ...options.LoadOption) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.LoadHunspell(dicPath, affPath, opts...)
+}
+
+func (l *lockedSymSpell) LoadHunspellStream(dic, aff io.Reader, opts ...options.LoadOption) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.LoadHunspellStream(dic, aff, opts...)
+}
+
+func (l *lockedSymSpell) CreateDictionary(corpus io.Reader) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.CreateDictionary(corpus)
+}
+
+func (l *lockedSymSpell) CreateDictionaryEntry(key string, count uint64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.CreateDictionaryEntry(key, count)
+}
+
+func (l *lockedSymSpell) AddWord(term string, count uint64) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.AddWord(term, count)
+}
+
+func (l *lockedSymSpell) DeleteDictionaryEntry(term string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.DeleteDictionaryEntry(term)
+}
+
+func (l *lockedSymSpell) UpdateWordFrequency(term string, count uint64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.UpdateWordFrequency(term, count)
+}
+
+func (l *lockedSymSpell) IncrementCount(term string, delta uint64) (uint64, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.IncrementCount(term, delta)
+}
+
+func (l *lockedSymSpell) AddUserWord(term string, count uint64) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.AddUserWord(term, count)
+}
+
+func (l *lockedSymSpell) RemoveUserWord(term string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.RemoveUserWord(term)
+}
+
+func (l *lockedSymSpell) IsUserWord(term string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.IsUserWord(term)
+}
+
+func (l *lockedSymSpell) ClearUserDictionary() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.s.ClearUserDictionary()
+}
+
+func (l *lockedSymSpell) SaveUserDictionary(w io.Writer) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.SaveUserDictionary(w)
+}
+
+func (l *lockedSymSpell) LoadUserDictionary(r io.Reader) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.LoadUserDictionary(r)
+}
+
+func (l *lockedSymSpell) LoadBigramDictionary(corpusPath string, termIndex, countIndex int, separator string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.LoadBigramDictionary(corpusPath, termIndex, countIndex, separator)
+}
+
+func (l *lockedSymSpell) LoadBigramDictionaryStream(corpusStream io.Reader, termIndex, countIndex int, separator string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.LoadBigramDictionaryStream(corpusStream, termIndex, countIndex, separator)
+}
+
+func (l *lockedSymSpell) LoadExactDictionary(corpusPath string, separator string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.LoadExactDictionary(corpusPath, separator)
+}
+
+func (l *lockedSymSpell) LoadExactDictionaryStream(corpusStream io.Reader, separator string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.LoadExactDictionaryStream(corpusStream, separator)
+}
+
+// SaveIndex takes the write lock because pending deletions are compacted first.
+func (l *lockedSymSpell) SaveIndex(w io.Writer) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.SaveIndex(w)
+}
+
+func (l *lockedSymSpell) SaveMappedIndex(w io.Writer) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.SaveMappedIndex(w)
+}
+
+func (l *lockedSymSpell) LoadMappedIndex(path string) (io.Closer, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.LoadMappedIndex(path)
+}
+
+func (l *lockedSymSpell) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.Close()
+}
+
+func (l *lockedSymSpell) LoadIndex(r io.Reader) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.LoadIndex(r)
+}
+
+func (l *lockedSymSpell) ClearTransformData() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.s.ClearTransformData()
+}
+
+// Compact compacts a clone of the instance without holding the lock, so that
+// lookups and updates go on meanwhile, and swaps the result in. If the
+// instance was updated in the meantime it is compacted again in place.
+func (l *lockedSymSpell) Compact() stats.CompactStats {
+	s, ok := l.s.(*internal.SymSpell)
+	var c *internal.SymSpell
+	var writes uint64
+	if ok {
+		l.mu.RLock()
+		c, writes = s.CompactClone(), l.mu.writes
+		l.mu.RUnlock()
+	}
+	if c == nil {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return l.s.Compact()
+	}
+	result := c.Compact()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.mu.writes != writes+1 {
+		return s.Compact()
+	}
+	s.CompactSwap(c)
+	return result
+}
+
+func (l *lockedSymSpell) PruneDictionary(minCount uint64) stats.CompactStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.PruneDictionary(minCount)
+}
+
+func (l *lockedSymSpell) ContainsWord(term string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.ContainsWord(term)
+}
+
+func (l *lockedSymSpell) WordFrequency(term string) (uint64, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.WordFrequency(term)
+}
+
+func (l *lockedSymSpell) WordCount() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.WordCount()
+}
+
+// Entries holds the read lock for the whole iteration, so the loop body must
+// not call methods that modify the dictionary.
+func (l *lockedSymSpell) Entries() iter.Seq2[string, uint64] {
+	return func(yield func(string, uint64) bool) {
+		l.mu.RLock()
+		defer l.mu.RUnlock()
+		l.s.Entries()(yield)
+	}
+}
+
+// TopWords takes the write lock because the frequency index is built lazily.
+func (l *lockedSymSpell) TopWords(n int) []items.SuggestItem {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.TopWords(n)
+}
+
+func (l *lockedSymSpell) TopWordsWithPrefix(prefix string, n int) []items.SuggestItem {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.TopWordsWithPrefix(prefix, n)
+}
+
+func (l *lockedSymSpell) Stats() stats.IndexStats {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.Stats()
+}
+
+func (l *lockedSymSpell) CacheStats() stats.CacheStats {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.s.CacheStats()
+}
+
+func (l *lockedSymSpell) InvalidateCache() {
+	l.s.InvalidateCache()
+}
+
+func (l *lockedSymSpell) SkipStats() stats.SkipStats {
+	return l.s.SkipStats()
+}
+
+func (l *lockedSymSpell) ResetSkipStats() {
+	l.s.ResetSkipStats()
+}
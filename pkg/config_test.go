@@ -0,0 +1,57 @@
+package symspell_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestOptionsFromFile(t *testing.T) {
+	dictionary, err := filepath.Abs(filepath.Join("testdata", "dictionary.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := filepath.Join(t.TempDir(), "symspell.yaml")
+	yaml := `max_dictionary_edit_distance: 1
+prefix_length: 5
+ignore_tokens: [numbers, 'v\d+']
+dictionaries:
+  - path: ` + dictionary + `
+    count_index: 1
+`
+	if err := os.WriteFile(config, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	opts, err := options.FromFile(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := symspell.New(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.WordCount() == 0 {
+		t.Fatal("dictionary from config was not loaded")
+	}
+	if _, err := s.Lookup("helo", verbosity.Top, 2); err == nil {
+		t.Error("Lookup with distance 2 succeeded, want max_dictionary_edit_distance 1 to apply")
+	}
+	for _, token := range []string{"2024", "v12"} {
+		got, err := s.Lookup(token, verbosity.Top, 1)
+		if err != nil || len(got) != 1 || got[0].Term != token {
+			t.Errorf("Lookup(%q) = %v, %v; want it returned verbatim", token, got, err)
+		}
+	}
+
+	if _, err := options.FromJSON(strings.NewReader(`{"prefix_lenght": 5}`)); err == nil {
+		t.Error("FromJSON accepted an unknown key")
+	}
+	if _, err := options.FromJSON(strings.NewReader(`{"invalid_utf8_policy": "drop"}`)); err == nil {
+		t.Error("FromJSON accepted an unknown invalid_utf8_policy")
+	}
+}
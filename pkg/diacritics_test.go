@@ -0,0 +1,38 @@
+package symspell_test
+
+import (
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestIgnoreDiacritics(t *testing.T) {
+	s, err := symspell.New(options.WithIgnoreDiacritics())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("résumé", 100)
+	s.CreateDictionaryEntry("über", 100)
+	s.CreateDictionaryEntry("cafe", 100)
+
+	tests := []struct {
+		input, want string
+		distance    int
+	}{
+		{"resume", "résumé", 0},
+		{"uber", "über", 0},
+		{"café", "cafe", 0},
+		{"resme", "résumé", 1},
+	}
+	for _, tt := range tests {
+		suggestions, err := s.Lookup(tt.input, verbosity.Top, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(suggestions) == 0 || suggestions[0].Term != tt.want || suggestions[0].Distance != tt.distance {
+			t.Errorf("Lookup(%q) = %v, want %s at distance %d", tt.input, suggestions, tt.want, tt.distance)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+//go:build symspell_de || symspell_all
+
+package dictionaries
+
+func init() {
+	registerEmbedded("de")
+}
@@ -0,0 +1,66 @@
+// Package dictionaries is a registry of frequency lists compiled into the
+// binary, used by symspell.NewForLanguage.
+//
+// The frequency lists themselves are not part of the source tree. Built-in
+// lists are opt-in: each one is enabled by a build tag and embeds
+// data/<lang>.txt, which go generate downloads and checks against
+// data/SHA256SUMS. A language whose tag is set but whose list has not been
+// generated builds, but Open reports it as not generated:
+//
+//	go generate ./pkg/dictionaries
+//	go test -tags symspell_all ./pkg/dictionaries
+//	go build -tags symspell_ru ./cmd
+//
+// Any other "term count" list can be copied to data/<lang>.txt instead.
+// Tags: symspell_en, symspell_ru, symspell_de, symspell_es, or symspell_all
+// for every language. Applications can add their own lists with Register.
+package dictionaries
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+var (
+	mu       sync.RWMutex
+	registry = make(map[string][]byte)
+	missing  = make(map[string]error) // enabled languages without a list
+)
+
+// Register makes a "term count" frequency list available under lang,
+// replacing any list registered before.
+func Register(lang string, data []byte) {
+	mu.Lock()
+	defer mu.Unlock()
+	registry[lang] = data
+}
+
+// Open returns a reader over the frequency list of lang.
+func Open(lang string) (io.Reader, error) {
+	mu.RLock()
+	data, ok := registry[lang]
+	notGenerated := missing[lang]
+	mu.RUnlock()
+	if !ok {
+		if notGenerated != nil {
+			return nil, notGenerated
+		}
+		return nil, fmt.Errorf("no embedded dictionary for language %q", lang)
+	}
+	return bytes.NewReader(data), nil
+}
+
+// Languages returns the registered languages in sorted order.
+func Languages() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	langs := make([]string, 0, len(registry))
+	for lang := range registry {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
@@ -0,0 +1,32 @@
+//go:build symspell_en || symspell_ru || symspell_de || symspell_es || symspell_all
+
+package dictionaries
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// data holds whatever go generate has written; all: keeps .gitkeep in, so
+// the pattern matches before the lists are generated.
+//
+//go:embed all:data
+var data embed.FS
+
+// registerEmbedded registers data/<lang>.txt, or records that the list of an
+// enabled language has not been generated, so that Open can say so.
+func registerEmbedded(lang string) {
+	list, err := data.ReadFile("data/" + lang + ".txt")
+	if errors.Is(err, fs.ErrNotExist) {
+		mu.Lock()
+		defer mu.Unlock()
+		missing[lang] = fmt.Errorf("dictionary for language %q is enabled but data/%s.txt was not generated; run go generate ./pkg/dictionaries", lang, lang)
+		return
+	}
+	if err != nil {
+		panic(err)
+	}
+	Register(lang, list)
+}
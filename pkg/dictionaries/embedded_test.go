@@ -0,0 +1,62 @@
+package dictionaries_test
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"symspell/pkg/dictionaries"
+)
+
+// TestEmbeddedLists checks every registered list; with a language tag it
+// fails until go generate has written the list of that language.
+func TestEmbeddedLists(t *testing.T) {
+	for _, lang := range embeddedLanguages {
+		r, err := dictionaries.Open(lang)
+		if err != nil {
+			t.Errorf("Open(%s): %v", lang, err)
+			continue
+		}
+		checkList(t, lang, r)
+	}
+	for _, lang := range dictionaries.Languages() {
+		r, _ := dictionaries.Open(lang)
+		checkList(t, lang, r)
+	}
+}
+
+func checkList(t *testing.T, lang string, r io.Reader) {
+	t.Helper()
+	scanner := bufio.NewScanner(r)
+	lines := 0
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			t.Fatalf("%s line %d = %q, want \"term count\"", lang, lines+1, scanner.Text())
+		}
+		if _, err := strconv.ParseUint(fields[1], 10, 64); err != nil {
+			t.Fatalf("%s line %d: %v", lang, lines+1, err)
+		}
+		lines++
+	}
+	if lines == 0 {
+		t.Errorf("%s list is empty", lang)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	dictionaries.Register("xx-test", []byte("hello 10\nworld 5\n"))
+	r, err := dictionaries.Open("xx-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkList(t, "xx-test", r)
+	if !strings.Contains(strings.Join(dictionaries.Languages(), ","), "xx-test") {
+		t.Errorf("Languages() = %v, want xx-test", dictionaries.Languages())
+	}
+	if _, err := dictionaries.Open("xx-missing"); err == nil {
+		t.Error("Open accepted an unregistered language")
+	}
+}
@@ -0,0 +1,7 @@
+//go:build symspell_en || symspell_all
+
+package dictionaries
+
+func init() {
+	registerEmbedded("en")
+}
@@ -0,0 +1,7 @@
+//go:build symspell_es || symspell_all
+
+package dictionaries
+
+func init() {
+	registerEmbedded("es")
+}
@@ -0,0 +1,5 @@
+package dictionaries
+
+// The built-in lists are not committed; generate them before building with a
+// language tag. The fetch verifies every download against data/SHA256SUMS.
+//go:generate go run ./internal/fetch -out data -sums data/SHA256SUMS -n 50000 en ru de es
@@ -0,0 +1,131 @@
+// Command fetch downloads the frequency lists embedded by package
+// dictionaries and writes the n most frequent words of each as
+// data/<lang>.txt.
+//
+// The lists are the 2018 OpenSubtitles counts of the FrequencyWords project
+// (https://github.com/hermitdave/FrequencyWords, CC BY-SA 4.0), already in the
+// "term count" format. Every download is checked against the SHA-256 recorded
+// in the sums file; a language without a recorded sum is rejected until it
+// has been reviewed and recorded with -update.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const sourceURL = "https://raw.githubusercontent.com/hermitdave/FrequencyWords/master/content/2018/%[1]s/%[1]s_50k.txt"
+
+func main() {
+	out := flag.String("out", "data", "output directory")
+	sumsPath := flag.String("sums", "data/SHA256SUMS", "file with the SHA-256 of every source list")
+	n := flag.Int("n", 50000, "number of words to keep per language")
+	update := flag.Bool("update", false, "record the checksums of the downloads instead of verifying them")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		log.Fatal("usage: fetch [flags] lang...")
+	}
+
+	sums, err := readSums(*sumsPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	client := &http.Client{Timeout: 5 * time.Minute}
+	for _, lang := range flag.Args() {
+		data, err := download(client, fmt.Sprintf(sourceURL, lang))
+		if err != nil {
+			log.Fatalf("%s: %v", lang, err)
+		}
+		sum := sha256.Sum256(data)
+		got := hex.EncodeToString(sum[:])
+		switch want, ok := sums[lang]; {
+		case *update:
+			sums[lang] = got
+		case !ok:
+			log.Fatalf("%s: no checksum in %s; review the list and rerun with -update", lang, *sumsPath)
+		case want != got:
+			log.Fatalf("%s: checksum %s, want %s", lang, got, want)
+		}
+		if err := writeList(filepath.Join(*out, lang+".txt"), data, *n); err != nil {
+			log.Fatalf("%s: %v", lang, err)
+		}
+	}
+	if *update {
+		if err := writeSums(*sumsPath, sums); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func download(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// writeList writes the first n "term count" lines of data to path.
+func writeList(path string, data []byte, n int) error {
+	var b bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for i := 0; i < n && scanner.Scan(); i++ {
+		if len(strings.Fields(scanner.Text())) != 2 {
+			return fmt.Errorf("line %d is not a \"term count\" pair", i+1)
+		}
+		b.WriteString(scanner.Text())
+		b.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b.Bytes(), 0o644)
+}
+
+// readSums reads "sha256  lang" lines in the format of sha256sum.
+func readSums(path string) (map[string]string, error) {
+	sums := make(map[string]string)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return sums, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 {
+			sums[fields[1]] = fields[0]
+		}
+	}
+	return sums, nil
+}
+
+func writeSums(path string, sums map[string]string) error {
+	langs := make([]string, 0, len(sums))
+	for lang := range sums {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	var b strings.Builder
+	for _, lang := range langs {
+		fmt.Fprintf(&b, "%s  %s\n", sums[lang], lang)
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
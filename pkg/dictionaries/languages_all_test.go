@@ -0,0 +1,5 @@
+//go:build symspell_all
+
+package dictionaries_test
+
+var embeddedLanguages = []string{"de", "en", "es", "ru"}
@@ -0,0 +1,6 @@
+//go:build !symspell_all
+
+package dictionaries_test
+
+// embeddedLanguages are the languages whose tags are set.
+var embeddedLanguages []string
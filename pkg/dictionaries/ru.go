@@ -0,0 +1,7 @@
+//go:build symspell_ru || symspell_all
+
+package dictionaries
+
+func init() {
+	registerEmbedded("ru")
+}
@@ -0,0 +1,230 @@
+package symspell_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/dictionaries"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestLoadDictionaryMergesWeightedSources(t *testing.T) {
+	s, err := symspell.New(options.WithCountThreshold(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := filepath.Join(t.TempDir(), "base.txt")
+	medical := filepath.Join(t.TempDir(), "medical.txt")
+	if err := os.WriteFile(base, []byte("fever 100\nfemur 4\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(medical, []byte("femur 2\nfever 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.LoadDictionary(base, 0, 1, " "); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.Lookup("femur", verbosity.Top, 2); len(got) != 1 || got[0].Term != "fever" {
+		t.Fatalf("femur is below the count threshold, got %v", got)
+	}
+	if _, err := s.LoadDictionary(medical, 0, 1, " ", options.WithDictionarySourceWeight(5)); err != nil {
+		t.Fatal(err)
+	}
+	for term, want := range map[string]int{"femur": 4 + 2*5, "fever": 100 + 5} {
+		got, _ := s.Lookup(term, verbosity.Top, 0)
+		if len(got) != 1 || got[0].Count != want {
+			t.Errorf("Lookup(%q) = %v, want count %d", term, got, want)
+		}
+	}
+	if _, err := s.LoadDictionary(medical, 0, 1, " ", options.WithDictionarySourceWeight(0)); err == nil {
+		t.Error("LoadDictionary accepted a zero source weight")
+	}
+}
+
+func TestLoggerReportsFrequencyOverride(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	s, err := symspell.New(options.WithLogger(logger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("teh", 5)
+	s.CreateDictionaryEntry("the", 5000)
+	suggestions, err := s.Lookup("teh", verbosity.All, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suggestions) == 0 || suggestions[0].Term != "the" {
+		t.Fatalf("Lookup(teh) = %v, want the first", suggestions)
+	}
+	if !strings.Contains(buf.String(), "term=teh count=5 replacement=the replacement_count=5000") {
+		t.Errorf("missing debug record for frequency override, log:\n%s", buf.String())
+	}
+}
+
+func TestLoggerReportsMissingBigramDictionary(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	missing := filepath.Join(t.TempDir(), "bigrams.txt")
+	symspell.NewSymSpellWithLoadBigramDictionary(filepath.Join("testdata", "dictionary.txt"), missing, "", 0, 1, options.WithLogger(logger))
+	if !strings.Contains(buf.String(), "level=ERROR") || !strings.Contains(buf.String(), missing) {
+		t.Errorf("missing error record for the bigram dictionary, log:\n%s", buf.String())
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	if _, err := symspell.New(options.WithPrefixLength(0)); !errors.Is(err, symspell.ErrInvalidOptions) {
+		t.Errorf("New(prefixLength 0) error = %v, want ErrInvalidOptions", err)
+	}
+	s, err := symspell.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.LoadDictionary(filepath.Join(t.TempDir(), "missing.txt"), 0, 1, " ")
+	if !errors.Is(err, symspell.ErrDictionaryNotFound) || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("LoadDictionary(missing) error = %v, want ErrDictionaryNotFound wrapping fs.ErrNotExist", err)
+	}
+	if _, err := s.Lookup("helo", verbosity.Top, 3); !errors.Is(err, symspell.ErrDistanceTooLarge) {
+		t.Errorf("Lookup(distance 3) error = %v, want ErrDistanceTooLarge", err)
+	}
+}
+
+func TestNewForLanguage(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "dictionary.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dictionaries.Register("test", data)
+	s, err := symspell.NewForLanguage("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	suggestions, err := s.Lookup("wrld", verbosity.Top, 2)
+	if err != nil || len(suggestions) == 0 || suggestions[0].Term != "world" {
+		t.Errorf("Lookup(wrld) = %v, %v; want world", suggestions, err)
+	}
+	if _, err := symspell.NewForLanguage("xx"); !errors.Is(err, symspell.ErrDictionaryNotFound) {
+		t.Errorf("NewForLanguage(xx) error = %v, want ErrDictionaryNotFound", err)
+	}
+}
+
+func TestLoadDictionaryContext(t *testing.T) {
+	s, err := symspell.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines, words int
+	progress := func(linesRead, wordsAdded int) { lines, words = linesRead, wordsAdded }
+	if _, err := s.LoadDictionaryContext(context.Background(), "testdata/dictionary.txt", 0, 1, " ", progress); err != nil {
+		t.Fatal(err)
+	}
+	if lines != 75 || words != s.WordCount() {
+		t.Errorf("progress = %d lines, %d words, want 75 lines, %d words", lines, words, s.WordCount())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s, _ = symspell.New()
+	if _, err := s.LoadDictionaryContext(ctx, "testdata/dictionary.txt", 0, 1, " ", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled load error = %v, want context.Canceled", err)
+	}
+	if s.WordCount() != 0 {
+		t.Errorf("canceled load added %d words", s.WordCount())
+	}
+}
+
+func TestLoadDictionaryParallel(t *testing.T) {
+	var data strings.Builder
+	for i := range 5000 {
+		fmt.Fprintf(&data, "word%d %d\n", i%4000, i+1)
+	}
+	sequential, _ := symspell.New()
+	parallel, _ := symspell.New(options.WithLoadWorkers(4))
+	for _, s := range []symspell.SymSpell{sequential, parallel} {
+		if _, err := s.LoadDictionaryStream(strings.NewReader(data.String()), 0, 1, " "); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if sequential.WordCount() != 4000 || parallel.WordCount() != 4000 {
+		t.Fatalf("WordCount() = %d and %d, want 4000", sequential.WordCount(), parallel.WordCount())
+	}
+	if got, want := parallel.TopWords(10), sequential.TopWords(10); !slices.Equal(got, want) {
+		t.Errorf("TopWords = %v, want %v", got, want)
+	}
+
+	reject, _ := symspell.New(options.WithLoadWorkers(4), options.WithInvalidUTF8Policy(options.InvalidUTF8Reject))
+	_, err := reject.LoadDictionaryStream(strings.NewReader(data.String()+"\xff 1\n"), 0, 1, " ")
+	if err == nil || !strings.HasPrefix(err.Error(), "line 5001:") {
+		t.Errorf("error = %v, want it at line 5001", err)
+	}
+}
+
+func TestMaxLineLength(t *testing.T) {
+	long := strings.Repeat("a", 100*1024)
+	corpus := "hello 100\n" + long + " 5\nworld 50\n"
+
+	s, err := symspell.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.LoadDictionaryStream(strings.NewReader(corpus), 0, 1, " ")
+	if !errors.Is(err, bufio.ErrTooLong) || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("LoadDictionaryStream with a line over the default limit: error = %v, want line 2 too long", err)
+	}
+	if _, err := s.LoadExactDictionaryStream(strings.NewReader("teh the\n"+long+" x\n"), " "); !errors.Is(err, bufio.ErrTooLong) {
+		t.Errorf("LoadExactDictionaryStream with a line over the default limit: error = %v, want bufio.ErrTooLong", err)
+	}
+
+	s, err = symspell.New(options.WithMaxLineLength(1 << 20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.LoadDictionaryStream(strings.NewReader(corpus), 0, 1, " "); err != nil {
+		t.Fatalf("LoadDictionaryStream with a larger limit: %v", err)
+	}
+	for _, word := range []string{"hello", long, "world"} {
+		if !s.ContainsWord(word) {
+			t.Errorf("word of %d bytes missing after loading with a larger limit", len(word))
+		}
+	}
+
+	if _, err := symspell.New(options.WithMaxLineLength(0)); !errors.Is(err, symspell.ErrInvalidOptions) {
+		t.Errorf("New(maxLineLength 0) error = %v, want ErrInvalidOptions", err)
+	}
+}
+
+func TestCreateDictionary(t *testing.T) {
+	s, err := symspell.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	corpus := "The cat sat on the mat.\nThe dog didn't sit; the cat did!\n"
+	if _, err := s.CreateDictionary(strings.NewReader(corpus)); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"the": 4, "cat": 2, "sat": 1, "on": 1, "mat": 1, "dog": 1, "didn't": 1, "sit": 1, "did": 1}
+	if got := s.WordCount(); got != len(want) {
+		t.Errorf("WordCount() = %d, want %d", got, len(want))
+	}
+	for term, count := range want {
+		got, _ := s.Lookup(term, verbosity.All, 0)
+		if len(got) != 1 || got[0].Term != term || got[0].Count != count {
+			t.Errorf("Lookup(%q) = %v, want count %d", term, got, count)
+		}
+	}
+	if got, _ := s.Lookup("dgo", verbosity.Top, 2); len(got) != 1 || got[0].Term != "dog" {
+		t.Errorf("Lookup(dgo) = %v, want dog from the deletes index", got)
+	}
+}
@@ -0,0 +1,64 @@
+package symspell_test
+
+import (
+	"strings"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/editdistance"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+// vowelShyDistance is Levenshtein with an extra edit for suggestions that
+// bring in an "o" missing from the input.
+type vowelShyDistance struct {
+	base  *editdistance.EditDistance
+	calls *int
+}
+
+func (d vowelShyDistance) Distance(a, b string) int {
+	*d.calls++
+	distance := d.base.Distance(a, b)
+	if strings.Contains(b, "o") && !strings.Contains(a, "o") {
+		distance++
+	}
+	return distance
+}
+
+func (d vowelShyDistance) DistanceMax(a, b string, maxDistance int) int {
+	if distance := d.Distance(a, b); distance <= maxDistance {
+		return distance
+	}
+	return maxDistance + 1
+}
+
+func TestDistanceComparer(t *testing.T) {
+	calls := 0
+	newSymSpell := func(opts ...options.Options) symspell.SymSpell {
+		s, err := symspell.New(append(opts, options.WithFrequencyThreshold(1))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.CreateDictionaryEntry("cot", 1000)
+		s.CreateDictionaryEntry("cat", 10)
+		s.CreateDictionaryEntry("sat", 500)
+		return s
+	}
+	plain := newSymSpell()
+	custom := newSymSpell(options.WithDistanceComparer(vowelShyDistance{editdistance.NewEditDistance(editdistance.Levenshtein), &calls}))
+
+	if got, _ := plain.Lookup("cit", verbosity.Top, 2); len(got) != 1 || got[0].Term != "cot" {
+		t.Fatalf("Lookup(cit) = %v, want cot", got)
+	}
+	got, _ := custom.Lookup("cit", verbosity.Closest, 2)
+	if len(got) != 1 || got[0].Term != "cat" || got[0].Distance != 1 {
+		t.Errorf("Lookup(cit) with a custom comparer = %v, want cat at distance 1", got)
+	}
+	if got := custom.LookupCompound("cit sat", 2); got == nil || got.Term != "cat sat" {
+		t.Errorf("LookupCompound(cit sat) with a custom comparer = %v, want cat sat", got)
+	}
+	if calls == 0 {
+		t.Error("the custom comparer was never called")
+	}
+}
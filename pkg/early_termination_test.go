@@ -0,0 +1,74 @@
+package symspell_test
+
+import (
+	"errors"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestTopEarlyTermination(t *testing.T) {
+	// seen counts the suggestions a lookup evaluates
+	seen := 0
+	newSymSpell := func(opts ...options.Options) symspell.SymSpell {
+		s, err := symspell.New(append(opts,
+			options.WithLookupCache(0),
+			options.WithSuggestionFilter(func(items.SuggestItem) bool {
+				seen++
+				return true
+			}))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for word, count := range map[string]uint64{"hello": 1000000, "help": 90, "held": 80, "hell": 70, "heel": 60, "hero": 50, "halo": 40} {
+			s.CreateDictionaryEntry(word, count)
+		}
+		return s
+	}
+	lookup := func(s symspell.SymSpell, v verbosity.Verbosity) ([]items.SuggestItem, int) {
+		seen = 0
+		suggestions, err := s.Lookup("helo", v, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return suggestions, seen
+	}
+
+	full, fullSeen := lookup(newSymSpell(), verbosity.Top)
+	if len(full) != 1 || full[0].Term != "hello" {
+		t.Fatalf("Lookup(helo) = %v, want hello", full)
+	}
+	for _, tt := range []struct {
+		name       string
+		candidates int
+		count      int
+	}{
+		{"frequent word", 0, 1000},
+		{"candidates at best distance", 2, 0},
+	} {
+		got, gotSeen := lookup(newSymSpell(options.WithTopEarlyTermination(tt.candidates, tt.count)), verbosity.Top)
+		if len(got) != 1 || got[0].Distance != 1 {
+			t.Errorf("%s: Lookup(helo) = %v, want one suggestion at distance 1", tt.name, got)
+		}
+		if gotSeen >= fullSeen {
+			t.Errorf("%s: %d suggestions evaluated, want fewer than the %d of a full lookup", tt.name, gotSeen, fullSeen)
+		}
+	}
+	// a frequent word stops the lookup only once it is the best suggestion
+	if got, _ := lookup(newSymSpell(options.WithTopEarlyTermination(0, 1000)), verbosity.Top); got[0].Term != "hello" {
+		t.Errorf("Lookup(helo) with a count limit = %v, want hello", got)
+	}
+
+	// other verbosities are not cut short
+	closest, _ := lookup(newSymSpell(), verbosity.Closest)
+	if got, _ := lookup(newSymSpell(options.WithTopEarlyTermination(1, 1)), verbosity.Closest); len(got) != len(closest) {
+		t.Errorf("Closest with early termination = %v, want %v", got, closest)
+	}
+
+	if _, err := symspell.New(options.WithTopEarlyTermination(-1, 0)); !errors.Is(err, symspell.ErrInvalidOptions) {
+		t.Errorf("New(earlyStopCandidates -1) error = %v, want ErrInvalidOptions", err)
+	}
+}
@@ -0,0 +1,186 @@
+package editdistance
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Confusion is a substitution of From with To, for example "rn" read as "m"
+// by an OCR engine, that costs Cost instead of the usual edits.
+type Confusion struct {
+	From string
+	To   string
+	Cost float64
+}
+
+type confusionRule struct {
+	from, to []rune
+	cost     float64
+}
+
+// ConfusionMatrixDistance is an optimal string alignment distance extended
+// with weighted, possibly multi-character substitutions. Every confusion
+// applies in both directions. Integer distances are the weighted distance
+// rounded up.
+type ConfusionMatrixDistance struct {
+	// rules are indexed by the last rune of their left-hand side.
+	rules      map[rune][]confusionRule
+	maxFromLen int
+}
+
+var _ IWeightedEditDistance = (*ConfusionMatrixDistance)(nil)
+
+func NewConfusionMatrixDistance(confusions []Confusion) (*ConfusionMatrixDistance, error) {
+	d := &ConfusionMatrixDistance{rules: make(map[rune][]confusionRule)}
+	for _, c := range confusions {
+		if c.From == "" && c.To == "" {
+			return nil, fmt.Errorf("confusion %q -> %q is empty", c.From, c.To)
+		}
+		if c.Cost < 0 || math.IsNaN(c.Cost) {
+			return nil, fmt.Errorf("confusion %q -> %q has invalid cost %v", c.From, c.To, c.Cost)
+		}
+		d.addRule([]rune(c.From), []rune(c.To), c.Cost)
+		d.addRule([]rune(c.To), []rune(c.From), c.Cost)
+	}
+	return d, nil
+}
+
+func (d *ConfusionMatrixDistance) addRule(from, to []rune, cost float64) {
+	if len(from) == 0 {
+		// Pure insertions are indexed by a sentinel and tried at every cell.
+		d.rules[-1] = append(d.rules[-1], confusionRule{from: from, to: to, cost: cost})
+		return
+	}
+	last := from[len(from)-1]
+	d.rules[last] = append(d.rules[last], confusionRule{from: from, to: to, cost: cost})
+	d.maxFromLen = max(d.maxFromLen, len(from))
+}
+
+// LoadConfusionMatrix reads one confusion per line as "from to cost",
+// separated by whitespace. Empty lines and lines starting with '#' are
+// skipped.
+func LoadConfusionMatrix(r io.Reader) (*ConfusionMatrixDistance, error) {
+	var confusions []Confusion
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("line %d: expected \"from to cost\", got %q", lineNumber, line)
+		}
+		cost, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		confusions = append(confusions, Confusion{From: parts[0], To: parts[1], Cost: cost})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewConfusionMatrixDistance(confusions)
+}
+
+func (d *ConfusionMatrixDistance) Distance(a, b string) int {
+	return roundUpDistance(d.WeightedDistance(a, b))
+}
+
+func (d *ConfusionMatrixDistance) DistanceMax(a, b string, maxDistance int) int {
+	distance := roundUpDistance(d.weightedDistanceMax([]rune(a), []rune(b), float64(maxDistance)))
+	if distance > maxDistance {
+		return maxDistance + 1
+	}
+	return distance
+}
+
+func (d *ConfusionMatrixDistance) WeightedDistance(a, b string) float64 {
+	return d.weightedDistanceMax([]rune(a), []rune(b), math.Inf(1))
+}
+
+// weightedDistanceMax gives up once maxFromLen consecutive rows exceed limit,
+// since no confusion can reach back further than that.
+func (d *ConfusionMatrixDistance) weightedDistanceMax(a, b []rune, limit float64) float64 {
+	m, n := len(a), len(b)
+	width := n + 1
+	dist := make([]float64, (m+1)*width)
+	for j := 0; j <= n; j++ {
+		dist[j] = float64(j)
+	}
+	insertions := d.rules[-1]
+	rowsOverLimit := 0
+	for i := 0; i <= m; i++ {
+		rowMin := math.Inf(1)
+		for j := 0; j <= n; j++ {
+			if i == 0 && j == 0 {
+				rowMin = 0
+				continue
+			}
+			best := math.Inf(1)
+			if i > 0 {
+				best = dist[(i-1)*width+j] + 1
+			}
+			if j > 0 {
+				best = min(best, dist[i*width+j-1]+1)
+			}
+			if i > 0 && j > 0 {
+				cost := 1.0
+				if a[i-1] == b[j-1] {
+					cost = 0
+				}
+				best = min(best, dist[(i-1)*width+j-1]+cost)
+				if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && a[i-1] != b[j-1] {
+					best = min(best, dist[(i-2)*width+j-2]+1)
+				}
+			}
+			if i > 0 {
+				for _, rule := range d.rules[a[i-1]] {
+					best = min(best, d.applyRule(dist, width, a, b, i, j, rule))
+				}
+			}
+			for _, rule := range insertions {
+				best = min(best, d.applyRule(dist, width, a, b, i, j, rule))
+			}
+			dist[i*width+j] = best
+			rowMin = min(rowMin, best)
+		}
+		if rowMin > limit {
+			rowsOverLimit++
+			if rowsOverLimit >= max(d.maxFromLen, 1) {
+				return rowMin
+			}
+		} else {
+			rowsOverLimit = 0
+		}
+	}
+	return dist[m*width+n]
+}
+
+// applyRule returns the cost of reaching cell (i, j) by rewriting a suffix of
+// a[:i] matching rule.from into a suffix of b[:j] matching rule.to.
+func (d *ConfusionMatrixDistance) applyRule(dist []float64, width int, a, b []rune, i, j int, rule confusionRule) float64 {
+	fi, tj := i-len(rule.from), j-len(rule.to)
+	if fi < 0 || tj < 0 || !runesEqual(a[fi:i], rule.from) || !runesEqual(b[tj:j], rule.to) {
+		return math.Inf(1)
+	}
+	return dist[fi*width+tj] + rule.cost
+}
+
+func runesEqual(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package editdistance
+
+// damerauLevenshteinDistanceRunes computes the unrestricted Damerau-Levenshtein
+// distance (Lowrance-Wagner). Unlike optimal string alignment it keeps, for
+// every character, the last row it occurred in, so a transposition may be
+// combined with edits between the swapped characters.
+func damerauLevenshteinDistanceRunes(a, b []rune) int {
+	m := len(a)
+	n := len(b)
+
+	if m == 0 {
+		return n
+	}
+	if n == 0 {
+		return m
+	}
+
+	width := n + 2
+	d := getIntSlice((m + 2) * width)
+	defer putIntSlice(d)
+
+	inf := m + n
+	d[0] = inf
+	for i := 0; i <= m; i++ {
+		d[(i+1)*width] = inf
+		d[(i+1)*width+1] = i
+	}
+	for j := 0; j <= n; j++ {
+		d[j+1] = inf
+		d[width+j+1] = j
+	}
+
+	lastRow := make(map[rune]int, m)
+	for i := 1; i <= m; i++ {
+		lastCol := 0
+		for j := 1; j <= n; j++ {
+			k := lastRow[b[j-1]]
+			l := lastCol
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+				lastCol = j
+			}
+			d[(i+1)*width+j+1] = min(
+				d[i*width+j]+cost,
+				d[(i+1)*width+j]+1,
+				d[i*width+j+1]+1,
+				d[k*width+l]+(i-k-1)+1+(j-l-1),
+			)
+		}
+		lastRow[a[i-1]] = i
+	}
+
+	return d[(m+1)*width+n+1]
+}
+
+func damerauLevenshteinDistanceMaxRunes(a, b []rune, k int) int {
+	if d := len(a) - len(b); d > k || d < -k {
+		return k + 1
+	}
+	if dist := damerauLevenshteinDistanceRunes(a, b); dist <= k {
+		return dist
+	}
+	return k + 1
+}
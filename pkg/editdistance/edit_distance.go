@@ -10,14 +10,43 @@ type IEditDistance interface {
 	DistanceMax(a, b string, maxDistance int) int
 }
 
+// IWeightedEditDistance is implemented by metrics with fractional edit costs.
+// Lookup uses WeightedDistance to order suggestions that share an integer
+// distance.
+type IWeightedEditDistance interface {
+	IEditDistance
+	WeightedDistance(a, b string) float64
+}
+
 func NewEditDistance(Type string) *EditDistance {
 	return &EditDistance{Type: Type}
 }
 
+// Supported values of EditDistance.Type.
 const (
+	// Levenshtein counts insertions, deletions and substitutions.
+	Levenshtein = "Levenshtein"
+	// OptimalStringAlignment also counts adjacent transpositions, but no
+	// substring may be edited more than once ("ca" -> "abc" costs 3).
+	OptimalStringAlignment = "OptimalStringAlignment"
+	// DamerauLevenshtein is the name this package has always used for
+	// optimal string alignment; both select the same banded algorithm.
 	DamerauLevenshtein = "DamerauLevenshtein"
+	// UnrestrictedDamerauLevenshtein is the true Damerau-Levenshtein distance
+	// (Lowrance-Wagner), which allows further edits around transposed
+	// characters ("ca" -> "abc" costs 2).
+	UnrestrictedDamerauLevenshtein = "UnrestrictedDamerauLevenshtein"
 )
 
+// IsSupported reports whether algorithm is one of the built-in algorithms.
+func IsSupported(algorithm string) bool {
+	switch algorithm {
+	case Levenshtein, OptimalStringAlignment, DamerauLevenshtein, UnrestrictedDamerauLevenshtein:
+		return true
+	}
+	return false
+}
+
 type EditDistance struct {
 	Type string
 }
@@ -42,10 +71,24 @@ func putIntSlice(s []int) {
 
 func (d EditDistance) Distance(a, b string) int {
 	switch d.Type {
-	case DamerauLevenshtein:
+	case Levenshtein:
+		if fitsBitParallel(a, b) {
+			return bitParallelDistanceMax(a, b, len(a)+len(b), false)
+		}
 		if isASCII(a) && isASCII(b) {
-			return damerauLevenshteinDistance(a, b)
+			return levenshteinDistanceMax(a, b, len(a)+len(b))
+		}
+		ra, rb := []rune(a), []rune(b)
+		return levenshteinDistanceMaxRunes(ra, rb, len(ra)+len(rb))
+	case OptimalStringAlignment, DamerauLevenshtein:
+		if fitsBitParallel(a, b) {
+			return bitParallelDistanceMax(a, b, len(a)+len(b), true)
 		}
+		if isASCII(a) && isASCII(b) {
+			return osaDistance(a, b)
+		}
+		return osaDistanceRunes([]rune(a), []rune(b))
+	case UnrestrictedDamerauLevenshtein:
 		return damerauLevenshteinDistanceRunes([]rune(a), []rune(b))
 	}
 	return 0
@@ -53,16 +96,29 @@ func (d EditDistance) Distance(a, b string) int {
 
 func (d EditDistance) DistanceMax(a, b string, maxDistance int) int {
 	switch d.Type {
-	case DamerauLevenshtein:
+	case Levenshtein:
+		if fitsBitParallel(a, b) {
+			return bitParallelDistanceMax(a, b, maxDistance, false)
+		}
+		if isASCII(a) && isASCII(b) {
+			return levenshteinDistanceMax(a, b, maxDistance)
+		}
+		return levenshteinDistanceMaxRunes([]rune(a), []rune(b), maxDistance)
+	case OptimalStringAlignment, DamerauLevenshtein:
+		if fitsBitParallel(a, b) {
+			return bitParallelDistanceMax(a, b, maxDistance, true)
+		}
 		if isASCII(a) && isASCII(b) {
-			return damerauLevenshteinDistanceMax(a, b, maxDistance)
+			return osaDistanceMax(a, b, maxDistance)
 		}
+		return osaDistanceMaxRunes([]rune(a), []rune(b), maxDistance)
+	case UnrestrictedDamerauLevenshtein:
 		return damerauLevenshteinDistanceMaxRunes([]rune(a), []rune(b), maxDistance)
 	}
 	return 0
 }
 
-func damerauLevenshteinDistance(a, b string) int {
+func osaDistance(a, b string) int {
 	m := len(a)
 	n := len(b)
 
@@ -109,7 +165,7 @@ func damerauLevenshteinDistance(a, b string) int {
 	return prev[n]
 }
 
-func damerauLevenshteinDistanceMax(a, b string, k int) int {
+func osaDistanceMax(a, b string, k int) int {
 	m := len(a)
 	n := len(b)
 
@@ -198,7 +254,7 @@ func damerauLevenshteinDistanceMax(a, b string, k int) int {
 	return prev[n]
 }
 
-func damerauLevenshteinDistanceRunes(a, b []rune) int {
+func osaDistanceRunes(a, b []rune) int {
 	m := len(a)
 	n := len(b)
 
@@ -245,7 +301,7 @@ func damerauLevenshteinDistanceRunes(a, b []rune) int {
 	return prev[n]
 }
 
-func damerauLevenshteinDistanceMaxRunes(a, b []rune, k int) int {
+func osaDistanceMaxRunes(a, b []rune, k int) int {
 	m := len(a)
 	n := len(b)
 
@@ -0,0 +1,165 @@
+package editdistance_test
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"symspell/pkg/editdistance"
+)
+
+func TestAlgorithms(t *testing.T) {
+	cases := []struct {
+		a, b                     string
+		levenshtein, osa, damlev int
+	}{
+		{"", "abc", 3, 3, 3},
+		{"kitten", "sitting", 3, 3, 3},
+		{"abcd", "acbd", 2, 1, 1},
+		{"ca", "abc", 3, 3, 2},
+		{"привет", "пирвет", 2, 1, 1},
+		{"жё", "ёкж", 3, 3, 2},
+	}
+	for _, tc := range cases {
+		for alg, want := range map[string]int{
+			editdistance.Levenshtein:                    tc.levenshtein,
+			editdistance.OptimalStringAlignment:         tc.osa,
+			editdistance.DamerauLevenshtein:             tc.osa,
+			editdistance.UnrestrictedDamerauLevenshtein: tc.damlev,
+		} {
+			d := editdistance.NewEditDistance(alg)
+			if got := d.Distance(tc.a, tc.b); got != want {
+				t.Errorf("%s.Distance(%q, %q) = %d, want %d", alg, tc.a, tc.b, got, want)
+			}
+			for k := 0; k <= 4; k++ {
+				wantMax := want
+				if wantMax > k {
+					wantMax = k + 1
+				}
+				if got := d.DistanceMax(tc.a, tc.b, k); got != wantMax {
+					t.Errorf("%s.DistanceMax(%q, %q, %d) = %d, want %d", alg, tc.a, tc.b, k, got, wantMax)
+				}
+			}
+		}
+	}
+}
+
+// referenceDistance is the textbook dynamic program, with transpositions for
+// optimal string alignment.
+func referenceDistance(a, b string, transpositions bool) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if transpositions && i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func TestASCIIDistancesMatchReference(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	word := func() string {
+		b := make([]byte, rng.Intn(70))
+		for i := range b {
+			b[i] = "abcd"[rng.Intn(4)]
+		}
+		return string(b)
+	}
+	for i := 0; i < 2000; i++ {
+		a, b := word(), word()
+		if rng.Intn(2) == 0 && len(a) > 1 {
+			// near misses, where the band matters
+			p := []byte(a)
+			j := rng.Intn(len(p) - 1)
+			p[j], p[j+1] = p[j+1], p[j]
+			b = string(p)
+		}
+		for alg, transpositions := range map[string]bool{editdistance.Levenshtein: false, editdistance.OptimalStringAlignment: true} {
+			d := editdistance.NewEditDistance(alg)
+			want := referenceDistance(a, b, transpositions)
+			if got := d.Distance(a, b); got != want {
+				t.Fatalf("%s.Distance(%q, %q) = %d, want %d", alg, a, b, got, want)
+			}
+			k := rng.Intn(4)
+			if want > k {
+				want = k + 1
+			}
+			if got := d.DistanceMax(a, b, k); got != want {
+				t.Fatalf("%s.DistanceMax(%q, %q, %d) = %d, want %d", alg, a, b, k, got, want)
+			}
+		}
+	}
+}
+
+func TestKeyboardDistance(t *testing.T) {
+	d := editdistance.NewKeyboardDistance(0.5, editdistance.QWERTY, editdistance.JCUKEN)
+	cases := []struct {
+		a, b     string
+		weighted float64
+	}{
+		{"hello", "hello", 0},
+		{"hello", "jello", 0.5},
+		{"hello", "pello", 1},
+		{"hello", "gekko", 1.5},
+		{"привет", "пртвет", 0.5},
+		{"hello", "ehllo", 1},
+	}
+	for _, tc := range cases {
+		if got := d.WeightedDistance(tc.a, tc.b); got != tc.weighted {
+			t.Errorf("WeightedDistance(%q, %q) = %v, want %v", tc.a, tc.b, got, tc.weighted)
+		}
+	}
+	if got := d.DistanceMax("hello", "gekko", 1); got != 2 {
+		t.Errorf("DistanceMax(hello, gekko, 1) = %d, want 2", got)
+	}
+	if got := d.DistanceMax("hello", "jellp", 1); got != 1 {
+		t.Errorf("DistanceMax(hello, jellp, 1) = %d, want 1", got)
+	}
+}
+
+func TestConfusionMatrixDistance(t *testing.T) {
+	d, err := editdistance.LoadConfusionMatrix(strings.NewReader("# OCR\nrn m 0.2\nl 1 0.3\no 0 0.3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		a, b     string
+		weighted float64
+	}{
+		{"modern", "modern", 0},
+		{"rnodern", "modern", 0.2},
+		{"modem", "modern", 0.2},
+		{"he11o", "hello", 0.6},
+		{"rnode1", "model", 0.5},
+		{"hello", "help", 2},
+	}
+	for _, tc := range cases {
+		if got := d.WeightedDistance(tc.a, tc.b); math.Abs(got-tc.weighted) > 1e-9 {
+			t.Errorf("WeightedDistance(%q, %q) = %v, want %v", tc.a, tc.b, got, tc.weighted)
+		}
+	}
+	if got := d.DistanceMax("rnodern", "modern", 1); got != 1 {
+		t.Errorf("DistanceMax(rnodern, modern, 1) = %d, want 1", got)
+	}
+	if got := d.DistanceMax("hello", "help", 1); got != 2 {
+		t.Errorf("DistanceMax(hello, help, 1) = %d, want 2", got)
+	}
+	if _, err := editdistance.LoadConfusionMatrix(strings.NewReader("rn m\n")); err == nil {
+		t.Error("LoadConfusionMatrix accepted a line without cost")
+	}
+}
@@ -0,0 +1,157 @@
+package editdistance
+
+import (
+	"math"
+	"unicode"
+)
+
+// adjacentKeyRadius is the largest distance between key centres, in key
+// widths, at which two keys count as neighbours. It covers keys in the same
+// row and the two touching keys in the rows above and below.
+const adjacentKeyRadius = 1.3
+
+type keyPosition struct {
+	x, y float64
+}
+
+// KeyboardLayout describes where keys are located on a physical keyboard.
+type KeyboardLayout struct {
+	keys map[rune]keyPosition
+}
+
+// NewKeyboardLayout builds a layout from its rows, top to bottom. offsets
+// gives the horizontal shift of every row in key widths.
+func NewKeyboardLayout(rows []string, offsets []float64) *KeyboardLayout {
+	layout := &KeyboardLayout{keys: make(map[rune]keyPosition)}
+	for y, row := range rows {
+		offset := 0.0
+		if y < len(offsets) {
+			offset = offsets[y]
+		}
+		x := 0
+		for _, r := range row {
+			layout.keys[unicode.ToLower(r)] = keyPosition{x: offset + float64(x), y: float64(y)}
+			x++
+		}
+	}
+	return layout
+}
+
+var (
+	// QWERTY is the US English layout.
+	QWERTY = NewKeyboardLayout(
+		[]string{"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./"},
+		[]float64{0, 1.5, 1.75, 2.25},
+	)
+	// JCUKEN is the standard Russian layout.
+	JCUKEN = NewKeyboardLayout(
+		[]string{"ё1234567890-=", "йцукенгшщзхъ\\", "фывапролджэ", "ячсмитьбю."},
+		[]float64{0, 1.5, 1.75, 2.25},
+	)
+)
+
+// KeyboardDistance is an optimal string alignment distance where substituting
+// a character with one typed by a neighbouring key costs AdjacentCost instead
+// of 1. Integer distances are the weighted distance rounded up, so two
+// fat-finger substitutions at AdjacentCost 0.5 still count as one edit.
+type KeyboardDistance struct {
+	AdjacentCost float64
+	adjacent     map[[2]rune]struct{}
+}
+
+var _ IWeightedEditDistance = (*KeyboardDistance)(nil)
+
+// NewKeyboardDistance creates a keyboard-aware metric for the given layouts.
+// Keys adjacent in any of the layouts are treated as neighbours.
+func NewKeyboardDistance(adjacentCost float64, layouts ...*KeyboardLayout) *KeyboardDistance {
+	d := &KeyboardDistance{AdjacentCost: adjacentCost, adjacent: make(map[[2]rune]struct{})}
+	for _, layout := range layouts {
+		for r1, p1 := range layout.keys {
+			for r2, p2 := range layout.keys {
+				if r1 != r2 && math.Hypot(p1.x-p2.x, p1.y-p2.y) <= adjacentKeyRadius {
+					d.adjacent[[2]rune{r1, r2}] = struct{}{}
+				}
+			}
+		}
+	}
+	return d
+}
+
+// IsAdjacent reports whether a and b are typed by neighbouring keys.
+func (d *KeyboardDistance) IsAdjacent(a, b rune) bool {
+	_, ok := d.adjacent[[2]rune{unicode.ToLower(a), unicode.ToLower(b)}]
+	return ok
+}
+
+func (d *KeyboardDistance) Distance(a, b string) int {
+	return roundUpDistance(d.WeightedDistance(a, b))
+}
+
+func (d *KeyboardDistance) DistanceMax(a, b string, maxDistance int) int {
+	ra, rb := []rune(a), []rune(b)
+	if diff := len(ra) - len(rb); diff > maxDistance || diff < -maxDistance {
+		return maxDistance + 1
+	}
+	distance := roundUpDistance(d.weightedDistanceMax(ra, rb, float64(maxDistance)))
+	if distance > maxDistance {
+		return maxDistance + 1
+	}
+	return distance
+}
+
+func (d *KeyboardDistance) WeightedDistance(a, b string) float64 {
+	return d.weightedDistanceMax([]rune(a), []rune(b), math.Inf(1))
+}
+
+func (d *KeyboardDistance) substitutionCost(a, b rune) float64 {
+	if a == b {
+		return 0
+	}
+	if d.IsAdjacent(a, b) {
+		return d.AdjacentCost
+	}
+	return 1
+}
+
+// weightedDistanceMax returns a value greater than limit as soon as every
+// cell of a row exceeds it.
+func (d *KeyboardDistance) weightedDistanceMax(a, b []rune, limit float64) float64 {
+	m, n := len(a), len(b)
+	if m == 0 {
+		return float64(n)
+	}
+	if n == 0 {
+		return float64(m)
+	}
+
+	prev2 := make([]float64, n+1)
+	prev := make([]float64, n+1)
+	curr := make([]float64, n+1)
+	for j := 0; j <= n; j++ {
+		prev[j] = float64(j)
+	}
+
+	for i := 1; i <= m; i++ {
+		curr[0] = float64(i)
+		rowMin := curr[0]
+		for j := 1; j <= n; j++ {
+			cost := d.substitutionCost(a[i-1], b[j-1])
+			dist := min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && a[i-1] != b[j-1] {
+				dist = min(dist, prev2[j-2]+1)
+			}
+			curr[j] = dist
+			rowMin = min(rowMin, dist)
+		}
+		if rowMin > limit {
+			return rowMin
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[n]
+}
+
+// roundUpDistance converts a weighted distance to edits, ignoring float noise.
+func roundUpDistance(weighted float64) int {
+	return int(math.Ceil(weighted - 1e-9))
+}
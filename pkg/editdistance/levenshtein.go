@@ -0,0 +1,143 @@
+package editdistance
+
+func levenshteinDistanceMax(a, b string, k int) int {
+	m := len(a)
+	n := len(b)
+
+	if d := m - n; d > k || d < -k {
+		return k + 1
+	}
+	if m == 0 {
+		return n
+	}
+	if n == 0 {
+		return m
+	}
+
+	prev := getIntSlice(n + 1)
+	curr := getIntSlice(n + 1)
+	defer putIntSlice(prev)
+	defer putIntSlice(curr)
+
+	limit := k + 1
+	for j := 0; j <= n; j++ {
+		if j <= k {
+			prev[j] = j
+		} else {
+			prev[j] = limit
+		}
+	}
+
+	for i := 1; i <= m; i++ {
+		curr[0] = i
+
+		jStart := 1
+		if i > k {
+			jStart = i - k
+		}
+		jEnd := n
+		if jEnd > i+k {
+			jEnd = i + k
+		}
+
+		if jStart > 1 {
+			curr[jStart-1] = limit
+		}
+
+		rowMin := limit
+		for j := jStart; j <= jEnd; j++ {
+			cost := 0
+			if a[i-1] != b[j-1] {
+				cost = 1
+			}
+			dist := min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			curr[j] = dist
+			if dist < rowMin {
+				rowMin = dist
+			}
+		}
+		if rowMin > k {
+			return k + 1
+		}
+		if jEnd < n {
+			curr[jEnd+1] = limit
+		}
+		prev, curr = curr, prev
+	}
+
+	if prev[n] > k {
+		return k + 1
+	}
+	return prev[n]
+}
+
+func levenshteinDistanceMaxRunes(a, b []rune, k int) int {
+	m := len(a)
+	n := len(b)
+
+	if d := m - n; d > k || d < -k {
+		return k + 1
+	}
+	if m == 0 {
+		return n
+	}
+	if n == 0 {
+		return m
+	}
+
+	prev := getIntSlice(n + 1)
+	curr := getIntSlice(n + 1)
+	defer putIntSlice(prev)
+	defer putIntSlice(curr)
+
+	limit := k + 1
+	for j := 0; j <= n; j++ {
+		if j <= k {
+			prev[j] = j
+		} else {
+			prev[j] = limit
+		}
+	}
+
+	for i := 1; i <= m; i++ {
+		curr[0] = i
+
+		jStart := 1
+		if i > k {
+			jStart = i - k
+		}
+		jEnd := n
+		if jEnd > i+k {
+			jEnd = i + k
+		}
+
+		if jStart > 1 {
+			curr[jStart-1] = limit
+		}
+
+		rowMin := limit
+		for j := jStart; j <= jEnd; j++ {
+			cost := 0
+			if a[i-1] != b[j-1] {
+				cost = 1
+			}
+			dist := min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			curr[j] = dist
+			if dist < rowMin {
+				rowMin = dist
+			}
+		}
+		if rowMin > k {
+			return k + 1
+		}
+		if jEnd < n {
+			curr[jEnd+1] = limit
+		}
+		prev, curr = curr, prev
+	}
+
+	if prev[n] > k {
+		return k + 1
+	}
+	return prev[n]
+}
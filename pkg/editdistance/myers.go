@@ -0,0 +1,66 @@
+package editdistance
+
+// maxBitParallelLength is the longest pattern the bit-parallel algorithms
+// handle, one bit per byte of the pattern in a uint64.
+const maxBitParallelLength = 64
+
+// bitParallelDistanceMax computes the Levenshtein distance of two ASCII
+// strings with Myers' bit-vector algorithm, or the optimal string alignment
+// distance with Hyyrö's extension for transpositions when transpositions is
+// set. One of the strings must be at most 64 bytes long. Distances above k
+// are returned as k+1, and the scan stops as soon as the distance can no
+// longer drop to k.
+func bitParallelDistanceMax(a, b string, k int, transpositions bool) int {
+	if len(a) > maxBitParallelLength {
+		a, b = b, a
+	}
+	m, n := len(a), len(b)
+	if d := m - n; d > k || d < -k {
+		return k + 1
+	}
+	if m == 0 {
+		return n
+	}
+
+	var peq [128]uint64
+	for i := 0; i < m; i++ {
+		peq[a[i]] |= 1 << i
+	}
+	last := uint64(1) << (m - 1)
+	vp, vn := ^uint64(0), uint64(0)
+	d0, pmPrev := uint64(0), uint64(0)
+	distance := m
+	for j := 0; j < n; j++ {
+		pm := peq[b[j]]
+		tr := uint64(0)
+		if transpositions {
+			tr = (^d0 & pm) << 1 & pmPrev
+		}
+		d0 = ((pm & vp) + vp) ^ vp | pm | vn | tr
+		hp := vn | ^(d0 | vp)
+		hn := d0 & vp
+		if hp&last != 0 {
+			distance++
+		} else if hn&last != 0 {
+			distance--
+		}
+		// every remaining byte of b lowers the distance by at most one
+		if distance-(n-j-1) > k {
+			return k + 1
+		}
+		hp = hp<<1 | 1
+		hn <<= 1
+		vp = hn | ^(d0 | hp)
+		vn = d0 & hp
+		pmPrev = pm
+	}
+	if distance > k {
+		return k + 1
+	}
+	return distance
+}
+
+// fitsBitParallel reports whether bitParallelDistanceMax can compare a and b.
+func fitsBitParallel(a, b string) bool {
+	return (len(a) <= maxBitParallelLength || len(b) <= maxBitParallelLength) && isASCII(a) && isASCII(b)
+}
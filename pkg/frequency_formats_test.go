@@ -0,0 +1,46 @@
+package symspell_test
+
+import (
+	"strings"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/options"
+)
+
+func TestFrequencyFormats(t *testing.T) {
+	tests := []struct {
+		name   string
+		format options.FrequencyFormat
+		data   string
+		want   map[string]int64
+	}{
+		{"zipf", options.FrequencyZipf, "the 7.73\ncat 4.5\n", map[string]int64{"the": 53703180, "cat": 31623}},
+		{"google books v2", options.FrequencyGoogleBooks,
+			"cat_NOUN\t1999\t10\t3\ncat_NOUN\t2000\t15\t4\ndog\t2000\t7\t1\n_NOUN_\t2000\t99\t9\n",
+			map[string]int64{"cat": 25, "dog": 7}},
+		{"google books v3", options.FrequencyGoogleBooks,
+			"cat\t1999,10,3\t2000,15,4\n", map[string]int64{"cat": 25}},
+		{"opensubtitles", options.FrequencyOpenSubtitles,
+			"you 8589934590\nyou 1\nme 4294967295\n", map[string]int64{"you": 8589934591, "me": 4294967295}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := symspell.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.LoadDictionaryStream(strings.NewReader(tt.data), 0, 1, " ", options.WithFrequencyFormat(tt.format)); err != nil {
+				t.Fatal(err)
+			}
+			if s.WordCount() != len(tt.want) {
+				t.Errorf("WordCount() = %d, want %d", s.WordCount(), len(tt.want))
+			}
+			for word, want := range tt.want {
+				if got, ok := s.WordFrequency(word); !ok || int64(got) != want {
+					t.Errorf("WordFrequency(%q) = %d, %v, want %d", word, got, ok, want)
+				}
+			}
+		})
+	}
+}
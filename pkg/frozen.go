@@ -0,0 +1,177 @@
+package symspell
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"symspell/internal"
+	"symspell/pkg/options"
+	"symspell/pkg/stats"
+)
+
+// frozenSymSpell is a read-only snapshot. Its updating methods return
+// ErrFrozen, or false and zero values where they report no error, and leave
+// the snapshot unchanged.
+type frozenSymSpell struct {
+	*internal.SymSpell
+}
+
+var _ SymSpell = (*frozenSymSpell)(nil)
+
+// Clone returns an independent copy of s that can be updated without
+// affecting s, for example to load a new dictionary off to the side while s
+// keeps serving lookups. Word strings and the deletes index are shared until
+// either side rebuilds them. The clone of a frozen snapshot is mutable, and
+// the clone of a concurrent instance is concurrent as well.
+func Clone(s SymSpell) (SymSpell, error) {
+	switch v := s.(type) {
+	case *internal.SymSpell:
+		return v.Clone(), nil
+	case *frozenSymSpell:
+		return v.SymSpell.Clone(), nil
+	case *lockedSymSpell:
+		v.mu.RLock()
+		defer v.mu.RUnlock()
+		c, err := Clone(v.s)
+		if err != nil {
+			return nil, err
+		}
+		return NewConcurrent(c), nil
+	}
+	return nil, fmt.Errorf("cannot clone %T", s)
+}
+
+// Freeze returns a read-only snapshot of the current state of s. The snapshot
+// never changes, so it is safe for concurrent use without locking, and later
+// updates of s do not affect it. Its updating methods return ErrFrozen; use
+// Clone to get a mutable copy again.
+func Freeze(s SymSpell) (SymSpell, error) {
+	switch v := s.(type) {
+	case *internal.SymSpell:
+		return &frozenSymSpell{v.Freeze()}, nil
+	case *frozenSymSpell:
+		return v, nil
+	case *lockedSymSpell:
+		v.mu.RLock()
+		defer v.mu.RUnlock()
+		return Freeze(v.s)
+	}
+	return nil, fmt.Errorf("cannot freeze %T", s)
+}
+
+func (f *frozenSymSpell) RegisterBoostList(string, []string, float64) error {
+	return ErrFrozen
+}
+
+func (f *frozenSymSpell) RemoveBoostList(string) {}
+
+func (f *frozenSymSpell) AddToBlacklist(...string) {}
+
+func (f *frozenSymSpell) RemoveFromBlacklist(...string) {}
+
+func (f *frozenSymSpell) LoadProtectedWords(io.Reader) error {
+	return ErrFrozen
+}
+
+func (f *frozenSymSpell) LoadDictionary(string, int, int, string, ...options.LoadOption) (bool, error) {
+	return false, ErrFrozen
+}
+
+func (f *frozenSymSpell) LoadDictionaryContext(context.Context, string, int, int, string, func(linesRead, wordsAdded int), ...options.LoadOption) (bool, error) {
+	return false, ErrFrozen
+}
+
+func (f *frozenSymSpell) LoadDictionaryStream(io.Reader, int, int, string, ...options.LoadOption) (bool, error) {
+	return false, ErrFrozen
+}
+
+func (f *frozenSymSpell) LoadHunspell(string, string, ...options.LoadOption) (bool, error) {
+	return false, ErrFrozen
+}
+
+func (f *frozenSymSpell) LoadHunspellStream(io.Reader, io.Reader, ...options.LoadOption) (bool, error) {
+	return false, ErrFrozen
+}
+
+func (f *frozenSymSpell) CreateDictionary(io.Reader) (bool, error) {
+	return false, ErrFrozen
+}
+
+func (f *frozenSymSpell) CreateDictionaryEntry(string, uint64) bool {
+	return false
+}
+
+func (f *frozenSymSpell) AddWord(string, uint64) (bool, error) {
+	return false, ErrFrozen
+}
+
+func (f *frozenSymSpell) DeleteDictionaryEntry(string) bool {
+	return false
+}
+
+func (f *frozenSymSpell) UpdateWordFrequency(string, uint64) bool {
+	return false
+}
+
+func (f *frozenSymSpell) IncrementCount(string, uint64) (uint64, bool) {
+	return 0, false
+}
+
+func (f *frozenSymSpell) AddUserWord(string, uint64) (bool, error) {
+	return false, ErrFrozen
+}
+
+func (f *frozenSymSpell) RemoveUserWord(string) bool {
+	return false
+}
+
+func (f *frozenSymSpell) ClearUserDictionary() {}
+
+func (f *frozenSymSpell) LoadUserDictionary(io.Reader) error {
+	return ErrFrozen
+}
+
+func (f *frozenSymSpell) LoadBigramDictionary(string, int, int, string) (bool, error) {
+	return false, ErrFrozen
+}
+
+func (f *frozenSymSpell) LoadBigramDictionaryStream(io.Reader, int, int, string) (bool, error) {
+	return false, ErrFrozen
+}
+
+func (f *frozenSymSpell) LoadExactDictionary(string, string) (bool, error) {
+	return false, ErrFrozen
+}
+
+func (f *frozenSymSpell) LoadExactDictionaryStream(io.Reader, string) (bool, error) {
+	return false, ErrFrozen
+}
+
+func (f *frozenSymSpell) LoadIndex(io.Reader) error {
+	return ErrFrozen
+}
+
+func (f *frozenSymSpell) LoadMappedIndex(string) (io.Closer, error) {
+	return nil, ErrFrozen
+}
+
+func (f *frozenSymSpell) ClearTransformData() {}
+
+func (f *frozenSymSpell) Compact() stats.CompactStats {
+	return stats.CompactStats{}
+}
+
+func (f *frozenSymSpell) PruneDictionary(uint64) stats.CompactStats {
+	return stats.CompactStats{}
+}
+
+// SaveIndex and SaveMappedIndex write from a throwaway clone, since saving
+// a mapped index decodes it first.
+func (f *frozenSymSpell) SaveIndex(w io.Writer) error {
+	return f.SymSpell.Clone().SaveIndex(w)
+}
+
+func (f *frozenSymSpell) SaveMappedIndex(w io.Writer) error {
+	return f.SymSpell.Clone().SaveMappedIndex(w)
+}
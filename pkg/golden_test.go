@@ -0,0 +1,170 @@
+package symspell_test
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with the current results")
+
+var goldenCases = []struct {
+	name   string
+	inputs []string
+}{
+	{"ascii", []string{"helo", "wrold", "teh", "speling", "corection", "recieve", "seperate", "acomodation", "thier", "quikc", "brwon", "jmups"}},
+	{"cyrillic", []string{"привте", "прывет", "мирр", "словор", "праграма", "ошибко", "исправлене", "провека", "домм", "кт"}},
+	{"mixed_script", []string{"wi-fi", "wifi", "iphnoe", "mp4", "covid19", "e-mial", "hellо", "москвa"}},
+	{"edge_length", []string{"", "a", "x", "ab", "ii", "и", "ыы", "thequickbrownfoxjumpsoverthelazydog", "программированиеее", "spellingspelling"}},
+}
+
+func newGoldenSymSpell(t *testing.T, opts ...options.Options) symspell.SymSpell {
+	t.Helper()
+	spellChecker, err := symspell.New(append([]options.Options{
+		options.WithMaxDictionaryEditDistance(2),
+		options.WithPrefixLength(7),
+	}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := spellChecker.LoadDictionary(filepath.Join("testdata", "dictionary.txt"), 0, 1, " ")
+	if err != nil || !ok {
+		t.Fatalf("loading dictionary: ok=%v err=%v", ok, err)
+	}
+	bigrams, err := os.Open(filepath.Join("testdata", "bigrams.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bigrams.Close()
+	ok, err = spellChecker.LoadBigramDictionaryStream(bigrams, 0, 2, "")
+	if err != nil || !ok {
+		t.Fatalf("loading bigrams: ok=%v err=%v", ok, err)
+	}
+	return spellChecker
+}
+
+func TestGoldenLookup(t *testing.T) {
+	spellChecker := newGoldenSymSpell(t)
+	for _, tc := range goldenCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var b strings.Builder
+			for _, input := range tc.inputs {
+				for _, v := range []verbosity.Verbosity{verbosity.Top, verbosity.Closest, verbosity.All} {
+					suggestions, err := spellChecker.Lookup(input, v, 2)
+					fmt.Fprintf(&b, "%q %s:", input, verbosityName(v))
+					if err != nil {
+						fmt.Fprintf(&b, " error %v\n", err)
+						continue
+					}
+					for _, suggestion := range suggestions {
+						fmt.Fprintf(&b, " %s/%d/%d", suggestion.Term, suggestion.Distance, suggestion.Count)
+					}
+					b.WriteString("\n")
+				}
+			}
+			compareGolden(t, tc.name+".lookup.golden", b.String())
+		})
+	}
+}
+
+var compoundInputs = []string{
+	"helo wrold",
+	"the quikc brwon fox jmups over teh lazy dog",
+	"thequickbrownfox",
+	"привте мирр",
+	"праграма для проверкаа правописания",
+	"helo мир",
+	"thier car is over there",
+	"helloworld",
+}
+
+func TestGoldenLookupCompound(t *testing.T) {
+	spellChecker := newGoldenSymSpell(t)
+	var b strings.Builder
+	for _, input := range compoundInputs {
+		suggestion := spellChecker.LookupCompound(input, 2)
+		if suggestion == nil {
+			fmt.Fprintf(&b, "%q: <nil>\n", input)
+			continue
+		}
+		fmt.Fprintf(&b, "%q: %q/%d\n", input, suggestion.Term, suggestion.Distance)
+	}
+	compareGolden(t, "compound.golden", b.String())
+}
+
+func TestGoldenLookupCompoundDetailed(t *testing.T) {
+	spellChecker := newGoldenSymSpell(t)
+	var b strings.Builder
+	for _, input := range append(compoundInputs, "Helo, wrold!  the  qiuck fox") {
+		result := spellChecker.LookupCompoundDetailed(input, 2)
+		if result == nil {
+			fmt.Fprintf(&b, "%q: <nil>\n", input)
+			continue
+		}
+		fmt.Fprintf(&b, "%q: %q/%d\n", input, result.Suggestion.Term, result.Suggestion.Distance)
+		for _, token := range result.Tokens {
+			if input[token.Start:token.End] != token.Original {
+				t.Errorf("%q: span [%d, %d) is %q, want %q", input, token.Start, token.End, input[token.Start:token.End], token.Original)
+			}
+			fmt.Fprintf(&b, "\t[%d, %d) %q -> %q/%d\n", token.Start, token.End, token.Original, token.Replacement, token.Distance)
+		}
+	}
+	compareGolden(t, "compound_detailed.golden", b.String())
+}
+
+func TestGoldenWordSegmentation(t *testing.T) {
+	spellChecker := newGoldenSymSpell(t)
+	inputs := []string{
+		"thequickbrownfoxjumpsoverthelazydog",
+		"helloworld",
+		"thequikcbrwonfox",
+		"приветмир",
+		"the quick brownfox",
+	}
+	var b strings.Builder
+	for _, input := range inputs {
+		composition, err := spellChecker.WordSegmentation(input, 2, 0)
+		if err != nil {
+			fmt.Fprintf(&b, "%q: error %v\n", input, err)
+			continue
+		}
+		fmt.Fprintf(&b, "%q: %q %q/%d/%.4f\n", input, composition.SegmentedString, composition.CorrectedString,
+			composition.DistanceSum, composition.LogProbSum)
+	}
+	compareGolden(t, "segmentation.golden", b.String())
+}
+
+func compareGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s mismatch (run with -update to accept)\n--- got\n%s\n--- want\n%s", name, got, want)
+	}
+}
+
+func verbosityName(v verbosity.Verbosity) string {
+	switch v {
+	case verbosity.Top:
+		return "top"
+	case verbosity.Closest:
+		return "closest"
+	}
+	return "all"
+}
@@ -0,0 +1,176 @@
+// Package grpcserver serves a SymSpell instance over gRPC, see
+// symspellpb/symspell.proto.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/grpcserver/symspellpb"
+	"symspell/pkg/items"
+	"symspell/pkg/verbosity"
+)
+
+// Server implements symspellpb.SymSpellServiceServer. Register it with
+// symspellpb.RegisterSymSpellServiceServer.
+type Server struct {
+	symspellpb.UnimplementedSymSpellServiceServer
+	dictionaries    *symspell.DictionaryManager
+	maxEditDistance int
+}
+
+// New returns a server for spellChecker, which serves every language.
+// Calls are served concurrently, so spellChecker is wrapped with
+// symspell.NewConcurrent unless it is concurrent already. maxEditDistance is
+// used by requests that do not set one.
+func New(spellChecker symspell.SymSpell, maxEditDistance int) *Server {
+	dictionaries := symspell.NewDictionaryManager("")
+	dictionaries.Register("", spellChecker)
+	return &Server{dictionaries: dictionaries, maxEditDistance: maxEditDistance}
+}
+
+// NewMultilingual returns a server that routes each request to the instance
+// of dictionaries serving the lang of the request, or to the default
+// language if lang is empty. Requests for a language without an instance
+// fail with NotFound. DictionaryManager.Register makes the instances
+// concurrent.
+func NewMultilingual(dictionaries *symspell.DictionaryManager, maxEditDistance int) *Server {
+	return &Server{dictionaries: dictionaries, maxEditDistance: maxEditDistance}
+}
+
+func (s *Server) Lookup(ctx context.Context, req *symspellpb.LookupRequest) (*symspellpb.LookupResponse, error) {
+	spellChecker, err := s.dictionary(req.GetLang())
+	if err != nil {
+		return nil, err
+	}
+	suggestions, err := spellChecker.LookupContext(ctx, req.GetTerm(), toVerbosity(req.GetVerbosity()), s.distance(req.MaxEditDistance))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &symspellpb.LookupResponse{Suggestions: toSuggestions(suggestions)}, nil
+}
+
+func (s *Server) LookupBatch(stream symspellpb.SymSpellService_LookupBatchServer) error {
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		resp, err := s.Lookup(stream.Context(), req)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) LookupCompound(ctx context.Context, req *symspellpb.LookupCompoundRequest) (*symspellpb.LookupCompoundResponse, error) {
+	spellChecker, err := s.dictionary(req.GetLang())
+	if err != nil {
+		return nil, err
+	}
+	result, err := spellChecker.LookupCompoundContext(ctx, req.GetText(), s.distance(req.MaxEditDistance))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	tokens := make([]*symspellpb.TokenCorrection, len(result.Tokens))
+	for i, token := range result.Tokens {
+		tokens[i] = &symspellpb.TokenCorrection{
+			Original:    token.Original,
+			Replacement: token.Replacement,
+			Start:       int32(token.Start),
+			End:         int32(token.End),
+			Distance:    int32(token.Distance),
+		}
+	}
+	return &symspellpb.LookupCompoundResponse{Suggestion: toSuggestion(result.Suggestion), Tokens: tokens}, nil
+}
+
+func (s *Server) WordSegmentation(ctx context.Context, req *symspellpb.WordSegmentationRequest) (*symspellpb.WordSegmentationResponse, error) {
+	spellChecker, err := s.dictionary(req.GetLang())
+	if err != nil {
+		return nil, err
+	}
+	composition, err := spellChecker.WordSegmentationContext(ctx, req.GetText(), s.distance(req.MaxEditDistance), int(req.GetMaxSegmentationWordLength()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &symspellpb.WordSegmentationResponse{
+		Segmented:   composition.SegmentedString,
+		Corrected:   composition.CorrectedString,
+		DistanceSum: int32(composition.DistanceSum),
+		LogProbSum:  composition.LogProbSum,
+	}, nil
+}
+
+func (s *Server) AddWord(ctx context.Context, req *symspellpb.AddWordRequest) (*symspellpb.AddWordResponse, error) {
+	if req.GetTerm() == "" {
+		return nil, status.Error(codes.InvalidArgument, "term cannot be empty")
+	}
+	spellChecker, err := s.dictionary(req.GetLang())
+	if err != nil {
+		return nil, err
+	}
+	added, err := spellChecker.AddWord(req.GetTerm(), uint64(req.GetCount()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &symspellpb.AddWordResponse{Added: added}, nil
+}
+
+// dictionary returns the instance serving lang.
+func (s *Server) dictionary(lang string) (symspell.SymSpell, error) {
+	spellChecker, err := s.dictionaries.Get(lang)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return spellChecker, nil
+}
+
+func (s *Server) distance(requested *int32) int {
+	if requested == nil {
+		return s.maxEditDistance
+	}
+	return int(*requested)
+}
+
+func toVerbosity(v symspellpb.Verbosity) verbosity.Verbosity {
+	switch v {
+	case symspellpb.Verbosity_VERBOSITY_CLOSEST:
+		return verbosity.Closest
+	case symspellpb.Verbosity_VERBOSITY_ALL:
+		return verbosity.All
+	}
+	return verbosity.Top
+}
+
+func toSuggestion(item items.SuggestItem) *symspellpb.Suggestion {
+	return &symspellpb.Suggestion{Term: item.Term, Distance: int32(item.Distance), Count: int64(item.Count)}
+}
+
+func toSuggestions(suggestions []items.SuggestItem) []*symspellpb.Suggestion {
+	result := make([]*symspellpb.Suggestion, len(suggestions))
+	for i, item := range suggestions {
+		result[i] = toSuggestion(item)
+	}
+	return result
+}
+
+// toStatus maps context errors to their gRPC codes and anything else to
+// InvalidArgument, since lookups only fail on bad input.
+func toStatus(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.InvalidArgument, err.Error())
+}
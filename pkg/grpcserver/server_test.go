@@ -0,0 +1,162 @@
+package grpcserver_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/grpcserver"
+	"symspell/pkg/grpcserver/symspellpb"
+	"symspell/pkg/options"
+)
+
+func TestServer(t *testing.T) {
+	spellChecker, err := symspell.New(options.WithThreadSafe())
+	if err != nil {
+		t.Fatal(err)
+	}
+	spellChecker.CreateDictionaryEntry("hello", 100)
+	spellChecker.CreateDictionaryEntry("world", 100)
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	symspellpb.RegisterSymSpellServiceServer(server, grpcserver.New(spellChecker, 2))
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := symspellpb.NewSymSpellServiceClient(conn)
+	ctx := context.Background()
+
+	if _, err := client.AddWord(ctx, &symspellpb.AddWordRequest{Term: "grpc", Count: 50}); err != nil {
+		t.Fatal(err)
+	}
+	stream, err := client.LookupBatch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, term := range []string{"helo", "grcp"} {
+		if err := stream.Send(&symspellpb.LookupRequest{Term: term}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stream.CloseSend()
+	for _, want := range []string{"hello", "grpc"} {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Suggestions) != 1 || resp.Suggestions[0].Term != want {
+			t.Errorf("LookupBatch = %v, want %s", resp.Suggestions, want)
+		}
+	}
+
+	compound, err := client.LookupCompound(ctx, &symspellpb.LookupCompoundRequest{Text: "helo wrld"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if compound.Suggestion.Term != "hello world" || len(compound.Tokens) != 2 {
+		t.Errorf("LookupCompound = %v", compound)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := client.Lookup(canceled, &symspellpb.LookupRequest{Term: "helo"}); err == nil {
+		t.Error("Lookup with a canceled context succeeded")
+	}
+}
+
+func TestServerRoutesLanguages(t *testing.T) {
+	dictionaries := symspell.NewDictionaryManager("en")
+	for lang, word := range map[string]string{"en": "hello", "de": "hallo"} {
+		spellChecker, err := symspell.New(options.WithThreadSafe())
+		if err != nil {
+			t.Fatal(err)
+		}
+		spellChecker.CreateDictionaryEntry(word, 100)
+		dictionaries.Register(lang, spellChecker)
+	}
+	server := grpcserver.NewMultilingual(dictionaries, 2)
+	ctx := context.Background()
+
+	for lang, want := range map[string]string{"": "hello", "en": "hello", "de": "hallo"} {
+		resp, err := server.Lookup(ctx, &symspellpb.LookupRequest{Term: "halo", Lang: lang})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Suggestions) != 1 || resp.Suggestions[0].Term != want {
+			t.Errorf("Lookup(lang=%q) = %v, want %s", lang, resp.Suggestions, want)
+		}
+	}
+	if _, err := server.Lookup(ctx, &symspellpb.LookupRequest{Term: "halo", Lang: "fr"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Lookup(lang=fr) error = %v, want NotFound", err)
+	}
+	if counts := dictionaries.RequestCounts(); counts["en"] != 2 || counts["de"] != 1 {
+		t.Errorf("RequestCounts = %v", counts)
+	}
+}
+
+func TestServerCanceledContext(t *testing.T) {
+	spellChecker, err := symspell.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	spellChecker.CreateDictionaryEntry("hello", 100)
+	server := grpcserver.New(spellChecker, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := server.LookupCompound(ctx, &symspellpb.LookupCompoundRequest{Text: "helo"}); status.Code(err) != codes.Canceled {
+		t.Errorf("LookupCompound error = %v, want Canceled", err)
+	}
+	if _, err := server.WordSegmentation(ctx, &symspellpb.WordSegmentationRequest{Text: "helohelo"}); status.Code(err) != codes.Canceled {
+		t.Errorf("WordSegmentation error = %v, want Canceled", err)
+	}
+}
+
+func TestServerAddWordConcurrently(t *testing.T) {
+	// not created with WithThreadSafe, New makes it concurrent
+	spellChecker, err := symspell.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	spellChecker.CreateDictionaryEntry("hello", 100)
+	server := grpcserver.New(spellChecker, 2)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Go(func() {
+			for j := range 50 {
+				if _, err := server.AddWord(ctx, &symspellpb.AddWordRequest{Term: fmt.Sprintf("word%d_%d", i, j), Count: 1}); err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := server.Lookup(ctx, &symspellpb.LookupRequest{Term: "helo"}); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+	if _, err := server.Lookup(ctx, &symspellpb.LookupRequest{Term: "word3_49"}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,5 @@
+// Package symspellpb holds the generated protobuf and gRPC code of the
+// SymSpell service.
+package symspellpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative symspell.proto
@@ -0,0 +1,822 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: symspell.proto
+
+package symspellpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Verbosity int32
+
+const (
+	Verbosity_VERBOSITY_TOP     Verbosity = 0
+	Verbosity_VERBOSITY_CLOSEST Verbosity = 1
+	Verbosity_VERBOSITY_ALL     Verbosity = 2
+)
+
+// Enum value maps for Verbosity.
+var (
+	Verbosity_name = map[int32]string{
+		0: "VERBOSITY_TOP",
+		1: "VERBOSITY_CLOSEST",
+		2: "VERBOSITY_ALL",
+	}
+	Verbosity_value = map[string]int32{
+		"VERBOSITY_TOP":     0,
+		"VERBOSITY_CLOSEST": 1,
+		"VERBOSITY_ALL":     2,
+	}
+)
+
+func (x Verbosity) Enum() *Verbosity {
+	p := new(Verbosity)
+	*p = x
+	return p
+}
+
+func (x Verbosity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Verbosity) Descriptor() protoreflect.EnumDescriptor {
+	return file_symspell_proto_enumTypes[0].Descriptor()
+}
+
+func (Verbosity) Type() protoreflect.EnumType {
+	return &file_symspell_proto_enumTypes[0]
+}
+
+func (x Verbosity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Verbosity.Descriptor instead.
+func (Verbosity) EnumDescriptor() ([]byte, []int) {
+	return file_symspell_proto_rawDescGZIP(), []int{0}
+}
+
+type Suggestion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Term          string                 `protobuf:"bytes,1,opt,name=term,proto3" json:"term,omitempty"`
+	Distance      int32                  `protobuf:"varint,2,opt,name=distance,proto3" json:"distance,omitempty"`
+	Count         int64                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Suggestion) Reset() {
+	*x = Suggestion{}
+	mi := &file_symspell_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Suggestion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Suggestion) ProtoMessage() {}
+
+func (x *Suggestion) ProtoReflect() protoreflect.Message {
+	mi := &file_symspell_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Suggestion.ProtoReflect.Descriptor instead.
+func (*Suggestion) Descriptor() ([]byte, []int) {
+	return file_symspell_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Suggestion) GetTerm() string {
+	if x != nil {
+		return x.Term
+	}
+	return ""
+}
+
+func (x *Suggestion) GetDistance() int32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *Suggestion) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type LookupRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Term      string                 `protobuf:"bytes,1,opt,name=term,proto3" json:"term,omitempty"`
+	Verbosity Verbosity              `protobuf:"varint,2,opt,name=verbosity,proto3,enum=symspell.v1.Verbosity" json:"verbosity,omitempty"`
+	// Defaults to the maximum edit distance of the server.
+	MaxEditDistance *int32 `protobuf:"varint,3,opt,name=max_edit_distance,json=maxEditDistance,proto3,oneof" json:"max_edit_distance,omitempty"`
+	// Language of the dictionary to use, the default language of the server
+	// if empty.
+	Lang          string `protobuf:"bytes,4,opt,name=lang,proto3" json:"lang,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupRequest) Reset() {
+	*x = LookupRequest{}
+	mi := &file_symspell_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupRequest) ProtoMessage() {}
+
+func (x *LookupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_symspell_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupRequest.ProtoReflect.Descriptor instead.
+func (*LookupRequest) Descriptor() ([]byte, []int) {
+	return file_symspell_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LookupRequest) GetTerm() string {
+	if x != nil {
+		return x.Term
+	}
+	return ""
+}
+
+func (x *LookupRequest) GetVerbosity() Verbosity {
+	if x != nil {
+		return x.Verbosity
+	}
+	return Verbosity_VERBOSITY_TOP
+}
+
+func (x *LookupRequest) GetMaxEditDistance() int32 {
+	if x != nil && x.MaxEditDistance != nil {
+		return *x.MaxEditDistance
+	}
+	return 0
+}
+
+func (x *LookupRequest) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+type LookupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Suggestions   []*Suggestion          `protobuf:"bytes,1,rep,name=suggestions,proto3" json:"suggestions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupResponse) Reset() {
+	*x = LookupResponse{}
+	mi := &file_symspell_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupResponse) ProtoMessage() {}
+
+func (x *LookupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_symspell_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupResponse.ProtoReflect.Descriptor instead.
+func (*LookupResponse) Descriptor() ([]byte, []int) {
+	return file_symspell_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LookupResponse) GetSuggestions() []*Suggestion {
+	if x != nil {
+		return x.Suggestions
+	}
+	return nil
+}
+
+type LookupCompoundRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Text            string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	MaxEditDistance *int32                 `protobuf:"varint,2,opt,name=max_edit_distance,json=maxEditDistance,proto3,oneof" json:"max_edit_distance,omitempty"`
+	// Language of the dictionary to use, the default language of the server
+	// if empty.
+	Lang          string `protobuf:"bytes,3,opt,name=lang,proto3" json:"lang,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupCompoundRequest) Reset() {
+	*x = LookupCompoundRequest{}
+	mi := &file_symspell_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupCompoundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupCompoundRequest) ProtoMessage() {}
+
+func (x *LookupCompoundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_symspell_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupCompoundRequest.ProtoReflect.Descriptor instead.
+func (*LookupCompoundRequest) Descriptor() ([]byte, []int) {
+	return file_symspell_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LookupCompoundRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *LookupCompoundRequest) GetMaxEditDistance() int32 {
+	if x != nil && x.MaxEditDistance != nil {
+		return *x.MaxEditDistance
+	}
+	return 0
+}
+
+func (x *LookupCompoundRequest) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+type TokenCorrection struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Original    string                 `protobuf:"bytes,1,opt,name=original,proto3" json:"original,omitempty"`
+	Replacement string                 `protobuf:"bytes,2,opt,name=replacement,proto3" json:"replacement,omitempty"`
+	// Byte offsets of the corrected span in the request text.
+	Start         int32 `protobuf:"varint,3,opt,name=start,proto3" json:"start,omitempty"`
+	End           int32 `protobuf:"varint,4,opt,name=end,proto3" json:"end,omitempty"`
+	Distance      int32 `protobuf:"varint,5,opt,name=distance,proto3" json:"distance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TokenCorrection) Reset() {
+	*x = TokenCorrection{}
+	mi := &file_symspell_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenCorrection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenCorrection) ProtoMessage() {}
+
+func (x *TokenCorrection) ProtoReflect() protoreflect.Message {
+	mi := &file_symspell_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenCorrection.ProtoReflect.Descriptor instead.
+func (*TokenCorrection) Descriptor() ([]byte, []int) {
+	return file_symspell_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TokenCorrection) GetOriginal() string {
+	if x != nil {
+		return x.Original
+	}
+	return ""
+}
+
+func (x *TokenCorrection) GetReplacement() string {
+	if x != nil {
+		return x.Replacement
+	}
+	return ""
+}
+
+func (x *TokenCorrection) GetStart() int32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *TokenCorrection) GetEnd() int32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *TokenCorrection) GetDistance() int32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+type LookupCompoundResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Suggestion    *Suggestion            `protobuf:"bytes,1,opt,name=suggestion,proto3" json:"suggestion,omitempty"`
+	Tokens        []*TokenCorrection     `protobuf:"bytes,2,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupCompoundResponse) Reset() {
+	*x = LookupCompoundResponse{}
+	mi := &file_symspell_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupCompoundResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupCompoundResponse) ProtoMessage() {}
+
+func (x *LookupCompoundResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_symspell_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupCompoundResponse.ProtoReflect.Descriptor instead.
+func (*LookupCompoundResponse) Descriptor() ([]byte, []int) {
+	return file_symspell_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LookupCompoundResponse) GetSuggestion() *Suggestion {
+	if x != nil {
+		return x.Suggestion
+	}
+	return nil
+}
+
+func (x *LookupCompoundResponse) GetTokens() []*TokenCorrection {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+type WordSegmentationRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Text            string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	MaxEditDistance *int32                 `protobuf:"varint,2,opt,name=max_edit_distance,json=maxEditDistance,proto3,oneof" json:"max_edit_distance,omitempty"`
+	// 0 uses the longest dictionary word.
+	MaxSegmentationWordLength int32 `protobuf:"varint,3,opt,name=max_segmentation_word_length,json=maxSegmentationWordLength,proto3" json:"max_segmentation_word_length,omitempty"`
+	// Language of the dictionary to use, the default language of the server
+	// if empty.
+	Lang          string `protobuf:"bytes,4,opt,name=lang,proto3" json:"lang,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WordSegmentationRequest) Reset() {
+	*x = WordSegmentationRequest{}
+	mi := &file_symspell_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WordSegmentationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WordSegmentationRequest) ProtoMessage() {}
+
+func (x *WordSegmentationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_symspell_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WordSegmentationRequest.ProtoReflect.Descriptor instead.
+func (*WordSegmentationRequest) Descriptor() ([]byte, []int) {
+	return file_symspell_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *WordSegmentationRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *WordSegmentationRequest) GetMaxEditDistance() int32 {
+	if x != nil && x.MaxEditDistance != nil {
+		return *x.MaxEditDistance
+	}
+	return 0
+}
+
+func (x *WordSegmentationRequest) GetMaxSegmentationWordLength() int32 {
+	if x != nil {
+		return x.MaxSegmentationWordLength
+	}
+	return 0
+}
+
+func (x *WordSegmentationRequest) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+type WordSegmentationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Segmented     string                 `protobuf:"bytes,1,opt,name=segmented,proto3" json:"segmented,omitempty"`
+	Corrected     string                 `protobuf:"bytes,2,opt,name=corrected,proto3" json:"corrected,omitempty"`
+	DistanceSum   int32                  `protobuf:"varint,3,opt,name=distance_sum,json=distanceSum,proto3" json:"distance_sum,omitempty"`
+	LogProbSum    float64                `protobuf:"fixed64,4,opt,name=log_prob_sum,json=logProbSum,proto3" json:"log_prob_sum,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WordSegmentationResponse) Reset() {
+	*x = WordSegmentationResponse{}
+	mi := &file_symspell_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WordSegmentationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WordSegmentationResponse) ProtoMessage() {}
+
+func (x *WordSegmentationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_symspell_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WordSegmentationResponse.ProtoReflect.Descriptor instead.
+func (*WordSegmentationResponse) Descriptor() ([]byte, []int) {
+	return file_symspell_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *WordSegmentationResponse) GetSegmented() string {
+	if x != nil {
+		return x.Segmented
+	}
+	return ""
+}
+
+func (x *WordSegmentationResponse) GetCorrected() string {
+	if x != nil {
+		return x.Corrected
+	}
+	return ""
+}
+
+func (x *WordSegmentationResponse) GetDistanceSum() int32 {
+	if x != nil {
+		return x.DistanceSum
+	}
+	return 0
+}
+
+func (x *WordSegmentationResponse) GetLogProbSum() float64 {
+	if x != nil {
+		return x.LogProbSum
+	}
+	return 0
+}
+
+type AddWordRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Term  string                 `protobuf:"bytes,1,opt,name=term,proto3" json:"term,omitempty"`
+	Count uint32                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	// Language of the dictionary to use, the default language of the server
+	// if empty.
+	Lang          string `protobuf:"bytes,3,opt,name=lang,proto3" json:"lang,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddWordRequest) Reset() {
+	*x = AddWordRequest{}
+	mi := &file_symspell_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddWordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddWordRequest) ProtoMessage() {}
+
+func (x *AddWordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_symspell_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddWordRequest.ProtoReflect.Descriptor instead.
+func (*AddWordRequest) Descriptor() ([]byte, []int) {
+	return file_symspell_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AddWordRequest) GetTerm() string {
+	if x != nil {
+		return x.Term
+	}
+	return ""
+}
+
+func (x *AddWordRequest) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *AddWordRequest) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+type AddWordResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Added         bool                   `protobuf:"varint,1,opt,name=added,proto3" json:"added,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddWordResponse) Reset() {
+	*x = AddWordResponse{}
+	mi := &file_symspell_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddWordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddWordResponse) ProtoMessage() {}
+
+func (x *AddWordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_symspell_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddWordResponse.ProtoReflect.Descriptor instead.
+func (*AddWordResponse) Descriptor() ([]byte, []int) {
+	return file_symspell_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AddWordResponse) GetAdded() bool {
+	if x != nil {
+		return x.Added
+	}
+	return false
+}
+
+var File_symspell_proto protoreflect.FileDescriptor
+
+const file_symspell_proto_rawDesc = "" +
+	"\n" +
+	"\x0esymspell.proto\x12\vsymspell.v1\"R\n" +
+	"\n" +
+	"Suggestion\x12\x12\n" +
+	"\x04term\x18\x01 \x01(\tR\x04term\x12\x1a\n" +
+	"\bdistance\x18\x02 \x01(\x05R\bdistance\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x03R\x05count\"\xb4\x01\n" +
+	"\rLookupRequest\x12\x12\n" +
+	"\x04term\x18\x01 \x01(\tR\x04term\x124\n" +
+	"\tverbosity\x18\x02 \x01(\x0e2\x16.symspell.v1.VerbosityR\tverbosity\x12/\n" +
+	"\x11max_edit_distance\x18\x03 \x01(\x05H\x00R\x0fmaxEditDistance\x88\x01\x01\x12\x12\n" +
+	"\x04lang\x18\x04 \x01(\tR\x04langB\x14\n" +
+	"\x12_max_edit_distance\"K\n" +
+	"\x0eLookupResponse\x129\n" +
+	"\vsuggestions\x18\x01 \x03(\v2\x17.symspell.v1.SuggestionR\vsuggestions\"\x86\x01\n" +
+	"\x15LookupCompoundRequest\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12/\n" +
+	"\x11max_edit_distance\x18\x02 \x01(\x05H\x00R\x0fmaxEditDistance\x88\x01\x01\x12\x12\n" +
+	"\x04lang\x18\x03 \x01(\tR\x04langB\x14\n" +
+	"\x12_max_edit_distance\"\x93\x01\n" +
+	"\x0fTokenCorrection\x12\x1a\n" +
+	"\boriginal\x18\x01 \x01(\tR\boriginal\x12 \n" +
+	"\vreplacement\x18\x02 \x01(\tR\vreplacement\x12\x14\n" +
+	"\x05start\x18\x03 \x01(\x05R\x05start\x12\x10\n" +
+	"\x03end\x18\x04 \x01(\x05R\x03end\x12\x1a\n" +
+	"\bdistance\x18\x05 \x01(\x05R\bdistance\"\x87\x01\n" +
+	"\x16LookupCompoundResponse\x127\n" +
+	"\n" +
+	"suggestion\x18\x01 \x01(\v2\x17.symspell.v1.SuggestionR\n" +
+	"suggestion\x124\n" +
+	"\x06tokens\x18\x02 \x03(\v2\x1c.symspell.v1.TokenCorrectionR\x06tokens\"\xc9\x01\n" +
+	"\x17WordSegmentationRequest\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12/\n" +
+	"\x11max_edit_distance\x18\x02 \x01(\x05H\x00R\x0fmaxEditDistance\x88\x01\x01\x12?\n" +
+	"\x1cmax_segmentation_word_length\x18\x03 \x01(\x05R\x19maxSegmentationWordLength\x12\x12\n" +
+	"\x04lang\x18\x04 \x01(\tR\x04langB\x14\n" +
+	"\x12_max_edit_distance\"\x9b\x01\n" +
+	"\x18WordSegmentationResponse\x12\x1c\n" +
+	"\tsegmented\x18\x01 \x01(\tR\tsegmented\x12\x1c\n" +
+	"\tcorrected\x18\x02 \x01(\tR\tcorrected\x12!\n" +
+	"\fdistance_sum\x18\x03 \x01(\x05R\vdistanceSum\x12 \n" +
+	"\flog_prob_sum\x18\x04 \x01(\x01R\n" +
+	"logProbSum\"N\n" +
+	"\x0eAddWordRequest\x12\x12\n" +
+	"\x04term\x18\x01 \x01(\tR\x04term\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\rR\x05count\x12\x12\n" +
+	"\x04lang\x18\x03 \x01(\tR\x04lang\"'\n" +
+	"\x0fAddWordResponse\x12\x14\n" +
+	"\x05added\x18\x01 \x01(\bR\x05added*H\n" +
+	"\tVerbosity\x12\x11\n" +
+	"\rVERBOSITY_TOP\x10\x00\x12\x15\n" +
+	"\x11VERBOSITY_CLOSEST\x10\x01\x12\x11\n" +
+	"\rVERBOSITY_ALL\x10\x022\xa2\x03\n" +
+	"\x0fSymSpellService\x12A\n" +
+	"\x06Lookup\x12\x1a.symspell.v1.LookupRequest\x1a\x1b.symspell.v1.LookupResponse\x12J\n" +
+	"\vLookupBatch\x12\x1a.symspell.v1.LookupRequest\x1a\x1b.symspell.v1.LookupResponse(\x010\x01\x12Y\n" +
+	"\x0eLookupCompound\x12\".symspell.v1.LookupCompoundRequest\x1a#.symspell.v1.LookupCompoundResponse\x12_\n" +
+	"\x10WordSegmentation\x12$.symspell.v1.WordSegmentationRequest\x1a%.symspell.v1.WordSegmentationResponse\x12D\n" +
+	"\aAddWord\x12\x1b.symspell.v1.AddWordRequest\x1a\x1c.symspell.v1.AddWordResponseB$Z\"symspell/pkg/grpcserver/symspellpbb\x06proto3"
+
+var (
+	file_symspell_proto_rawDescOnce sync.Once
+	file_symspell_proto_rawDescData []byte
+)
+
+func file_symspell_proto_rawDescGZIP() []byte {
+	file_symspell_proto_rawDescOnce.Do(func() {
+		file_symspell_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_symspell_proto_rawDesc), len(file_symspell_proto_rawDesc)))
+	})
+	return file_symspell_proto_rawDescData
+}
+
+var file_symspell_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_symspell_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_symspell_proto_goTypes = []any{
+	(Verbosity)(0),                   // 0: symspell.v1.Verbosity
+	(*Suggestion)(nil),               // 1: symspell.v1.Suggestion
+	(*LookupRequest)(nil),            // 2: symspell.v1.LookupRequest
+	(*LookupResponse)(nil),           // 3: symspell.v1.LookupResponse
+	(*LookupCompoundRequest)(nil),    // 4: symspell.v1.LookupCompoundRequest
+	(*TokenCorrection)(nil),          // 5: symspell.v1.TokenCorrection
+	(*LookupCompoundResponse)(nil),   // 6: symspell.v1.LookupCompoundResponse
+	(*WordSegmentationRequest)(nil),  // 7: symspell.v1.WordSegmentationRequest
+	(*WordSegmentationResponse)(nil), // 8: symspell.v1.WordSegmentationResponse
+	(*AddWordRequest)(nil),           // 9: symspell.v1.AddWordRequest
+	(*AddWordResponse)(nil),          // 10: symspell.v1.AddWordResponse
+}
+var file_symspell_proto_depIdxs = []int32{
+	0,  // 0: symspell.v1.LookupRequest.verbosity:type_name -> symspell.v1.Verbosity
+	1,  // 1: symspell.v1.LookupResponse.suggestions:type_name -> symspell.v1.Suggestion
+	1,  // 2: symspell.v1.LookupCompoundResponse.suggestion:type_name -> symspell.v1.Suggestion
+	5,  // 3: symspell.v1.LookupCompoundResponse.tokens:type_name -> symspell.v1.TokenCorrection
+	2,  // 4: symspell.v1.SymSpellService.Lookup:input_type -> symspell.v1.LookupRequest
+	2,  // 5: symspell.v1.SymSpellService.LookupBatch:input_type -> symspell.v1.LookupRequest
+	4,  // 6: symspell.v1.SymSpellService.LookupCompound:input_type -> symspell.v1.LookupCompoundRequest
+	7,  // 7: symspell.v1.SymSpellService.WordSegmentation:input_type -> symspell.v1.WordSegmentationRequest
+	9,  // 8: symspell.v1.SymSpellService.AddWord:input_type -> symspell.v1.AddWordRequest
+	3,  // 9: symspell.v1.SymSpellService.Lookup:output_type -> symspell.v1.LookupResponse
+	3,  // 10: symspell.v1.SymSpellService.LookupBatch:output_type -> symspell.v1.LookupResponse
+	6,  // 11: symspell.v1.SymSpellService.LookupCompound:output_type -> symspell.v1.LookupCompoundResponse
+	8,  // 12: symspell.v1.SymSpellService.WordSegmentation:output_type -> symspell.v1.WordSegmentationResponse
+	10, // 13: symspell.v1.SymSpellService.AddWord:output_type -> symspell.v1.AddWordResponse
+	9,  // [9:14] is the sub-list for method output_type
+	4,  // [4:9] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_symspell_proto_init() }
+func file_symspell_proto_init() {
+	if File_symspell_proto != nil {
+		return
+	}
+	file_symspell_proto_msgTypes[1].OneofWrappers = []any{}
+	file_symspell_proto_msgTypes[3].OneofWrappers = []any{}
+	file_symspell_proto_msgTypes[6].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_symspell_proto_rawDesc), len(file_symspell_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_symspell_proto_goTypes,
+		DependencyIndexes: file_symspell_proto_depIdxs,
+		EnumInfos:         file_symspell_proto_enumTypes,
+		MessageInfos:      file_symspell_proto_msgTypes,
+	}.Build()
+	File_symspell_proto = out.File
+	file_symspell_proto_goTypes = nil
+	file_symspell_proto_depIdxs = nil
+}
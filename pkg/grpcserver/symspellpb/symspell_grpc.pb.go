@@ -0,0 +1,276 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: symspell.proto
+
+package symspellpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SymSpellService_Lookup_FullMethodName           = "/symspell.v1.SymSpellService/Lookup"
+	SymSpellService_LookupBatch_FullMethodName      = "/symspell.v1.SymSpellService/LookupBatch"
+	SymSpellService_LookupCompound_FullMethodName   = "/symspell.v1.SymSpellService/LookupCompound"
+	SymSpellService_WordSegmentation_FullMethodName = "/symspell.v1.SymSpellService/WordSegmentation"
+	SymSpellService_AddWord_FullMethodName          = "/symspell.v1.SymSpellService/AddWord"
+)
+
+// SymSpellServiceClient is the client API for SymSpellService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SymSpellService exposes spelling correction over gRPC. Deadlines of the
+// calls are propagated to lookups.
+type SymSpellServiceClient interface {
+	Lookup(ctx context.Context, in *LookupRequest, opts ...grpc.CallOption) (*LookupResponse, error)
+	// LookupBatch answers every request of the stream in order.
+	LookupBatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[LookupRequest, LookupResponse], error)
+	LookupCompound(ctx context.Context, in *LookupCompoundRequest, opts ...grpc.CallOption) (*LookupCompoundResponse, error)
+	WordSegmentation(ctx context.Context, in *WordSegmentationRequest, opts ...grpc.CallOption) (*WordSegmentationResponse, error)
+	AddWord(ctx context.Context, in *AddWordRequest, opts ...grpc.CallOption) (*AddWordResponse, error)
+}
+
+type symSpellServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSymSpellServiceClient(cc grpc.ClientConnInterface) SymSpellServiceClient {
+	return &symSpellServiceClient{cc}
+}
+
+func (c *symSpellServiceClient) Lookup(ctx context.Context, in *LookupRequest, opts ...grpc.CallOption) (*LookupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LookupResponse)
+	err := c.cc.Invoke(ctx, SymSpellService_Lookup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *symSpellServiceClient) LookupBatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[LookupRequest, LookupResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SymSpellService_ServiceDesc.Streams[0], SymSpellService_LookupBatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[LookupRequest, LookupResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SymSpellService_LookupBatchClient = grpc.BidiStreamingClient[LookupRequest, LookupResponse]
+
+func (c *symSpellServiceClient) LookupCompound(ctx context.Context, in *LookupCompoundRequest, opts ...grpc.CallOption) (*LookupCompoundResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LookupCompoundResponse)
+	err := c.cc.Invoke(ctx, SymSpellService_LookupCompound_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *symSpellServiceClient) WordSegmentation(ctx context.Context, in *WordSegmentationRequest, opts ...grpc.CallOption) (*WordSegmentationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WordSegmentationResponse)
+	err := c.cc.Invoke(ctx, SymSpellService_WordSegmentation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *symSpellServiceClient) AddWord(ctx context.Context, in *AddWordRequest, opts ...grpc.CallOption) (*AddWordResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddWordResponse)
+	err := c.cc.Invoke(ctx, SymSpellService_AddWord_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SymSpellServiceServer is the server API for SymSpellService service.
+// All implementations must embed UnimplementedSymSpellServiceServer
+// for forward compatibility.
+//
+// SymSpellService exposes spelling correction over gRPC. Deadlines of the
+// calls are propagated to lookups.
+type SymSpellServiceServer interface {
+	Lookup(context.Context, *LookupRequest) (*LookupResponse, error)
+	// LookupBatch answers every request of the stream in order.
+	LookupBatch(grpc.BidiStreamingServer[LookupRequest, LookupResponse]) error
+	LookupCompound(context.Context, *LookupCompoundRequest) (*LookupCompoundResponse, error)
+	WordSegmentation(context.Context, *WordSegmentationRequest) (*WordSegmentationResponse, error)
+	AddWord(context.Context, *AddWordRequest) (*AddWordResponse, error)
+	mustEmbedUnimplementedSymSpellServiceServer()
+}
+
+// UnimplementedSymSpellServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSymSpellServiceServer struct{}
+
+func (UnimplementedSymSpellServiceServer) Lookup(context.Context, *LookupRequest) (*LookupResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Lookup not implemented")
+}
+func (UnimplementedSymSpellServiceServer) LookupBatch(grpc.BidiStreamingServer[LookupRequest, LookupResponse]) error {
+	return status.Error(codes.Unimplemented, "method LookupBatch not implemented")
+}
+func (UnimplementedSymSpellServiceServer) LookupCompound(context.Context, *LookupCompoundRequest) (*LookupCompoundResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method LookupCompound not implemented")
+}
+func (UnimplementedSymSpellServiceServer) WordSegmentation(context.Context, *WordSegmentationRequest) (*WordSegmentationResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method WordSegmentation not implemented")
+}
+func (UnimplementedSymSpellServiceServer) AddWord(context.Context, *AddWordRequest) (*AddWordResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddWord not implemented")
+}
+func (UnimplementedSymSpellServiceServer) mustEmbedUnimplementedSymSpellServiceServer() {}
+func (UnimplementedSymSpellServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeSymSpellServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SymSpellServiceServer will
+// result in compilation errors.
+type UnsafeSymSpellServiceServer interface {
+	mustEmbedUnimplementedSymSpellServiceServer()
+}
+
+func RegisterSymSpellServiceServer(s grpc.ServiceRegistrar, srv SymSpellServiceServer) {
+	// If the following call panics, it indicates UnimplementedSymSpellServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SymSpellService_ServiceDesc, srv)
+}
+
+func _SymSpellService_Lookup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SymSpellServiceServer).Lookup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SymSpellService_Lookup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SymSpellServiceServer).Lookup(ctx, req.(*LookupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SymSpellService_LookupBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SymSpellServiceServer).LookupBatch(&grpc.GenericServerStream[LookupRequest, LookupResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SymSpellService_LookupBatchServer = grpc.BidiStreamingServer[LookupRequest, LookupResponse]
+
+func _SymSpellService_LookupCompound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupCompoundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SymSpellServiceServer).LookupCompound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SymSpellService_LookupCompound_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SymSpellServiceServer).LookupCompound(ctx, req.(*LookupCompoundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SymSpellService_WordSegmentation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WordSegmentationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SymSpellServiceServer).WordSegmentation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SymSpellService_WordSegmentation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SymSpellServiceServer).WordSegmentation(ctx, req.(*WordSegmentationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SymSpellService_AddWord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddWordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SymSpellServiceServer).AddWord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SymSpellService_AddWord_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SymSpellServiceServer).AddWord(ctx, req.(*AddWordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SymSpellService_ServiceDesc is the grpc.ServiceDesc for SymSpellService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SymSpellService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "symspell.v1.SymSpellService",
+	HandlerType: (*SymSpellServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Lookup",
+			Handler:    _SymSpellService_Lookup_Handler,
+		},
+		{
+			MethodName: "LookupCompound",
+			Handler:    _SymSpellService_LookupCompound_Handler,
+		},
+		{
+			MethodName: "WordSegmentation",
+			Handler:    _SymSpellService_WordSegmentation_Handler,
+		},
+		{
+			MethodName: "AddWord",
+			Handler:    _SymSpellService_AddWord_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "LookupBatch",
+			Handler:       _SymSpellService_LookupBatch_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "symspell.proto",
+}
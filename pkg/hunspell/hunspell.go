@@ -0,0 +1,432 @@
+// Package hunspell reads Hunspell dictionaries (.dic word list and .aff affix
+// file) and expands their affix rules into surface forms.
+//
+// Supported: SET (UTF-8 and the single-byte charsets of golang.org/x/text),
+// FLAG (char, long, num, UTF-8), AF flag aliases, PFX and SFX with strip,
+// append, condition and cross products, suffix continuation classes,
+// FORBIDDENWORD, NEEDAFFIX and ONLYINCOMPOUND. Compound rules, REP, MAP and
+// morphology are ignored.
+package hunspell
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+)
+
+// Dictionary is a parsed Hunspell dictionary.
+type Dictionary struct {
+	flagMode       string
+	aliases        [][]string
+	prefixes       map[string]*affixClass
+	suffixes       map[string]*affixClass
+	forbidden      string
+	needAffix      string
+	onlyInCompound string
+	entries        []entry
+}
+
+type entry struct {
+	word  string
+	flags []string
+}
+
+type affixClass struct {
+	cross bool
+	rules []affixRule
+}
+
+type affixRule struct {
+	strip     string
+	add       string
+	flags     []string // continuation classes
+	condition []charClass
+}
+
+// charClass is one position of an affix condition: ".", "x", "[xyz]" or
+// "[^xyz]".
+type charClass struct {
+	any    bool
+	negate bool
+	chars  string
+}
+
+// Parse reads a dictionary from its .dic and .aff files.
+func Parse(dic, aff io.Reader) (*Dictionary, error) {
+	d := &Dictionary{prefixes: make(map[string]*affixClass), suffixes: make(map[string]*affixClass)}
+	affData, err := io.ReadAll(aff)
+	if err != nil {
+		return nil, fmt.Errorf("reading aff: %w", err)
+	}
+	enc, err := affEncoding(affData)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.parseAff(decoded(bytes.NewReader(affData), enc)); err != nil {
+		return nil, err
+	}
+	if err := d.parseDic(decoded(dic, enc)); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Expand calls fn for every surface form of every dictionary word, except
+// the words marked FORBIDDENWORD. root is true for the words as listed in the
+// .dic file. Forms may repeat.
+func (d *Dictionary) Expand(fn func(form string, root bool)) {
+	forbidden := make(map[string]struct{})
+	for _, e := range d.entries {
+		if d.has(e.flags, d.forbidden) {
+			forbidden[e.word] = struct{}{}
+		}
+	}
+	if len(forbidden) > 0 {
+		emit := fn
+		fn = func(form string, root bool) {
+			if _, ok := forbidden[form]; !ok {
+				emit(form, root)
+			}
+		}
+	}
+	for _, e := range d.entries {
+		if d.has(e.flags, d.forbidden) || d.has(e.flags, d.onlyInCompound) {
+			continue
+		}
+		if !d.has(e.flags, d.needAffix) {
+			fn(e.word, true)
+		}
+		for _, flag := range e.flags {
+			if class, ok := d.suffixes[flag]; ok {
+				for _, rule := range class.rules {
+					form, ok := rule.applySuffix(e.word)
+					if !ok {
+						continue
+					}
+					d.emit(form, rule.flags, fn)
+					for _, cont := range rule.flags {
+						if contClass, ok := d.suffixes[cont]; ok {
+							for _, contRule := range contClass.rules {
+								if twofold, ok := contRule.applySuffix(form); ok {
+									fn(twofold, false)
+								}
+							}
+						}
+					}
+					if class.cross {
+						d.expandPrefixes(form, e.flags, true, fn)
+					}
+				}
+			}
+		}
+		d.expandPrefixes(e.word, e.flags, false, fn)
+	}
+}
+
+// expandPrefixes applies the prefix classes among flags to word; crossOnly
+// limits them to classes that combine with suffixes.
+func (d *Dictionary) expandPrefixes(word string, flags []string, crossOnly bool, fn func(string, bool)) {
+	for _, flag := range flags {
+		class, ok := d.prefixes[flag]
+		if !ok || crossOnly && !class.cross {
+			continue
+		}
+		for _, rule := range class.rules {
+			if form, ok := rule.applyPrefix(word); ok {
+				d.emit(form, rule.flags, fn)
+			}
+		}
+	}
+}
+
+func (d *Dictionary) emit(form string, flags []string, fn func(string, bool)) {
+	if d.has(flags, d.forbidden) || d.has(flags, d.needAffix) || d.has(flags, d.onlyInCompound) {
+		return
+	}
+	fn(form, false)
+}
+
+func (d *Dictionary) has(flags []string, flag string) bool {
+	if flag == "" {
+		return false
+	}
+	for _, f := range flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+func (r affixRule) applySuffix(word string) (string, bool) {
+	if len(word) <= len(r.strip) || !strings.HasSuffix(word, r.strip) {
+		return "", false
+	}
+	runes := []rune(word)
+	if len(r.condition) > len(runes) || !matchCondition(r.condition, runes[len(runes)-len(r.condition):]) {
+		return "", false
+	}
+	return word[:len(word)-len(r.strip)] + r.add, true
+}
+
+func (r affixRule) applyPrefix(word string) (string, bool) {
+	if len(word) <= len(r.strip) || !strings.HasPrefix(word, r.strip) {
+		return "", false
+	}
+	runes := []rune(word)
+	if len(r.condition) > len(runes) || !matchCondition(r.condition, runes[:len(r.condition)]) {
+		return "", false
+	}
+	return r.add + word[len(r.strip):], true
+}
+
+func matchCondition(condition []charClass, runes []rune) bool {
+	for i, class := range condition {
+		if class.any {
+			continue
+		}
+		if strings.ContainsRune(class.chars, runes[i]) == class.negate {
+			return false
+		}
+	}
+	return true
+}
+
+func parseCondition(s string) ([]charClass, error) {
+	if s == "." {
+		return nil, nil
+	}
+	var condition []charClass
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '.':
+			condition = append(condition, charClass{any: true})
+			i++
+		case '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated condition %q", s)
+			}
+			class := charClass{chars: s[i+1 : i+end]}
+			if strings.HasPrefix(class.chars, "^") {
+				class.negate, class.chars = true, class.chars[1:]
+			}
+			condition = append(condition, class)
+			i += end + 1
+		default:
+			_, size := utf8.DecodeRuneInString(s[i:])
+			condition = append(condition, charClass{chars: s[i : i+size]})
+			i += size
+		}
+	}
+	return condition, nil
+}
+
+func (d *Dictionary) parseAff(r io.Reader) error {
+	scanner := newScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		var err error
+		switch fields[0] {
+		case "FLAG":
+			if len(fields) > 1 {
+				d.flagMode = fields[1]
+			}
+		case "AF":
+			if len(fields) > 1 {
+				if _, numErr := strconv.Atoi(fields[1]); numErr == nil && d.aliases == nil {
+					d.aliases = [][]string{}
+				} else {
+					d.aliases = append(d.aliases, d.splitFlags(fields[1]))
+				}
+			}
+		case "FORBIDDENWORD":
+			d.forbidden, err = d.singleFlag(fields)
+		case "NEEDAFFIX", "PSEUDOROOT":
+			d.needAffix, err = d.singleFlag(fields)
+		case "ONLYINCOMPOUND":
+			d.onlyInCompound, err = d.singleFlag(fields)
+		case "PFX":
+			err = d.parseAffix(d.prefixes, fields)
+		case "SFX":
+			err = d.parseAffix(d.suffixes, fields)
+		}
+		if err != nil {
+			return fmt.Errorf("aff line %d: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading aff: %w", err)
+	}
+	return nil
+}
+
+func (d *Dictionary) singleFlag(fields []string) (string, error) {
+	if len(fields) < 2 {
+		return "", fmt.Errorf("%s without a flag", fields[0])
+	}
+	return fields[1], nil
+}
+
+// parseAffix reads a class header "SFX A Y 2" or one of its rules
+// "SFX A strip add condition".
+func (d *Dictionary) parseAffix(classes map[string]*affixClass, fields []string) error {
+	if len(fields) < 4 {
+		return fmt.Errorf("short %s line", fields[0])
+	}
+	class, ok := classes[fields[1]]
+	if !ok {
+		classes[fields[1]] = &affixClass{cross: fields[2] == "Y"}
+		return nil
+	}
+	rule := affixRule{strip: fields[2], add: fields[3]}
+	if rule.strip == "0" {
+		rule.strip = ""
+	}
+	if add, flags, found := strings.Cut(rule.add, "/"); found {
+		rule.add, rule.flags = add, d.resolveFlags(flags)
+	}
+	if rule.add == "0" {
+		rule.add = ""
+	}
+	if len(fields) > 4 {
+		condition, err := parseCondition(fields[4])
+		if err != nil {
+			return err
+		}
+		rule.condition = condition
+	}
+	class.rules = append(class.rules, rule)
+	return nil
+}
+
+func (d *Dictionary) parseDic(r io.Reader) error {
+	scanner := newScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if line == 1 {
+			if _, err := strconv.Atoi(text); err == nil {
+				continue
+			}
+		}
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if i := strings.IndexAny(text, " \t"); i >= 0 {
+			text = text[:i]
+		}
+		word, flags := splitEntry(text)
+		if word == "" {
+			continue
+		}
+		d.entries = append(d.entries, entry{word: word, flags: d.resolveFlags(flags)})
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading dic: %w", err)
+	}
+	return nil
+}
+
+// splitEntry splits "word/flags" at the first unescaped slash.
+func splitEntry(text string) (string, string) {
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '/':
+			if i == 0 {
+				continue
+			}
+			return strings.ReplaceAll(text[:i], `\/`, "/"), text[i+1:]
+		}
+	}
+	return strings.ReplaceAll(text, `\/`, "/"), ""
+}
+
+// resolveFlags decodes a flag field, looking it up in the AF aliases when
+// the dictionary defines them.
+func (d *Dictionary) resolveFlags(s string) []string {
+	if s == "" {
+		return nil
+	}
+	if len(d.aliases) > 0 {
+		if n, err := strconv.Atoi(s); err == nil && n >= 1 && n <= len(d.aliases) {
+			return d.aliases[n-1]
+		}
+	}
+	return d.splitFlags(s)
+}
+
+func (d *Dictionary) splitFlags(s string) []string {
+	var flags []string
+	switch d.flagMode {
+	case "long":
+		for i := 0; i+1 < len(s); i += 2 {
+			flags = append(flags, s[i:i+2])
+		}
+	case "num":
+		flags = strings.Split(s, ",")
+	case "UTF-8":
+		for _, r := range s {
+			flags = append(flags, string(r))
+		}
+	default:
+		for i := 0; i < len(s); i++ {
+			flags = append(flags, s[i:i+1])
+		}
+	}
+	return flags
+}
+
+func newScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	return scanner
+}
+
+// affEncoding returns the encoding named by the SET directive, nil for
+// UTF-8.
+func affEncoding(aff []byte) (encoding.Encoding, error) {
+	for _, line := range bytes.Split(aff, []byte("\n")) {
+		fields := strings.Fields(string(line))
+		if len(fields) < 2 || fields[0] != "SET" {
+			continue
+		}
+		name := fields[1]
+		switch {
+		case strings.EqualFold(name, "UTF-8"):
+			return nil, nil
+		case strings.HasPrefix(name, "microsoft-cp"):
+			name = "windows-" + strings.TrimPrefix(name, "microsoft-cp")
+		case strings.HasPrefix(name, "ISO8859-"):
+			name = "ISO-8859-" + strings.TrimPrefix(name, "ISO8859-")
+		}
+		enc, err := ianaindex.IANA.Encoding(name)
+		if err != nil || enc == nil {
+			return nil, fmt.Errorf("unsupported encoding %q", fields[1])
+		}
+		return enc, nil
+	}
+	return nil, nil
+}
+
+func decoded(r io.Reader, enc encoding.Encoding) io.Reader {
+	if enc == nil {
+		return r
+	}
+	return enc.NewDecoder().Reader(r)
+}
@@ -0,0 +1,67 @@
+package symspell_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/hunspell"
+	"symspell/pkg/verbosity"
+)
+
+const testAff = `SET UTF-8
+
+PFX U Y 1
+PFX U 0 un .
+
+SFX S Y 2
+SFX S y ies [^aeiou]y
+SFX S 0 s [^y]
+
+SFX D N 1
+SFX D 0 ed .
+
+FORBIDDENWORD X
+`
+
+const testDic = `4
+carry/SU
+hope/D
+tie/US
+hopeed/X
+`
+
+func TestHunspellExpand(t *testing.T) {
+	d, err := hunspell.Parse(strings.NewReader(testDic), strings.NewReader(testAff))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var forms []string
+	d.Expand(func(form string, root bool) { forms = append(forms, form) })
+	slices.Sort(forms)
+	want := []string{"carries", "carry", "hope", "tie", "ties", "uncarries", "uncarry", "untie", "unties"}
+	if !slices.Equal(forms, want) {
+		t.Errorf("forms = %v, want %v", forms, want)
+	}
+}
+
+func TestLoadHunspell(t *testing.T) {
+	s, err := symspell.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.LoadHunspellStream(strings.NewReader(testDic), strings.NewReader(testAff)); err != nil {
+		t.Fatal(err)
+	}
+	if count, ok := s.WordFrequency("carry"); !ok || count != 2 {
+		t.Errorf("WordFrequency(carry) = %d, %v, want 2", count, ok)
+	}
+	suggestions, err := s.Lookup("carryes", verbosity.Top, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suggestions) == 0 || suggestions[0].Term != "carries" {
+		t.Errorf("Lookup(carryes) = %v, want carries", suggestions)
+	}
+}
@@ -0,0 +1,49 @@
+package symspell_test
+
+import (
+	"reflect"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestIncludeUnknown(t *testing.T) {
+	newSymSpell := func(opts ...options.Options) symspell.SymSpell {
+		s, err := symspell.New(opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.CreateDictionaryEntry("hello", 1000)
+		s.CreateDictionaryEntry("world", 1000)
+		return s
+	}
+	plain := newSymSpell()
+	unknown := newSymSpell(options.WithIncludeUnknown())
+
+	for _, v := range []verbosity.Verbosity{verbosity.Top, verbosity.Closest, verbosity.All} {
+		if got, err := plain.Lookup("xyzzy", v, 2); err != nil || len(got) != 0 {
+			t.Errorf("Lookup(xyzzy, %v) = %v, %v, want nothing", v, got, err)
+		}
+		want := []items.SuggestItem{{Term: "xyzzy", Distance: 3, Count: 0}}
+		if got, err := unknown.Lookup("xyzzy", v, 2); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("Lookup(xyzzy, %v) with unknown words = %v, %v, want %v", v, got, err, want)
+		}
+		if got, _ := unknown.Lookup("helo", v, 2); len(got) == 0 || got[0].Term != "hello" {
+			t.Errorf("Lookup(helo, %v) with unknown words = %v, want hello", v, got)
+		}
+	}
+	// compound lookups and segmentation see unknown words as unknown
+	for _, input := range []string{"xyzzy helo", "helo wrld"} {
+		if got, want := unknown.LookupCompound(input, 2), plain.LookupCompound(input, 2); !reflect.DeepEqual(got, want) {
+			t.Errorf("LookupCompound(%q) with unknown words = %v, want %v", input, got, want)
+		}
+	}
+	got, _ := unknown.WordSegmentation("xyzzyhelloworld", 2, 10)
+	want, _ := plain.WordSegmentation("xyzzyhelloworld", 2, 10)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WordSegmentation with unknown words = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,596 @@
+package symspell_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"runtime/metrics"
+	"slices"
+	"strings"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestSaveLoadIndexRoundTrip(t *testing.T) {
+	original := newGoldenSymSpell(t)
+	var buf bytes.Buffer
+	if err := original.SaveIndex(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := symspell.New(options.WithMaxDictionaryEditDistance(2), options.WithPrefixLength(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := restored.LoadIndex(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range goldenCases {
+		for _, input := range tc.inputs {
+			want, _ := original.Lookup(input, verbosity.All, 2)
+			got, _ := restored.Lookup(input, verbosity.All, 2)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Lookup(%q) after LoadIndex = %v, want %v", input, got, want)
+			}
+		}
+	}
+
+	mismatched, err := symspell.New(options.WithMaxDictionaryEditDistance(1), options.WithPrefixLength(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mismatched.LoadIndex(bytes.NewReader(buf.Bytes())); err == nil {
+		t.Error("LoadIndex accepted an index built with a different maxDictionaryEditDistance")
+	}
+}
+
+func TestLoadIndexRejectsOversizedLengths(t *testing.T) {
+	header := []byte{'S', 'Y', 'M', 'I', 1, 2, 7, 10}
+	huge := binary.AppendUvarint(nil, 1<<32-1)
+	inputs := map[string][]byte{
+		"word count":    append(append(slices.Clone(header), huge...), 0),
+		"word length":   append(append(append(slices.Clone(header), 1), huge...), 'a'),
+		"posting count": append(append(append(slices.Clone(header), 1, 1, 'a', 5), huge...), 0),
+	}
+	for name, input := range inputs {
+		s, err := symspell.New(options.WithMaxDictionaryEditDistance(2), options.WithPrefixLength(7))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.LoadIndex(bytes.NewReader(input)); err == nil {
+			t.Errorf("%s: LoadIndex accepted a length beyond the input", name)
+		}
+		// a reader without a known size must fail without allocating the length
+		if err := s.LoadIndex(io.MultiReader(bytes.NewReader(input))); err == nil {
+			t.Errorf("%s: LoadIndex of an unsized reader accepted a length beyond the input", name)
+		}
+	}
+}
+
+func TestMappedIndexRoundTrip(t *testing.T) {
+	original := newGoldenSymSpell(t)
+	path := filepath.Join(t.TempDir(), "index.symm")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := original.SaveMappedIndex(file); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	mapped, err := symspell.New(options.WithMaxDictionaryEditDistance(2), options.WithPrefixLength(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	closer, err := mapped.LoadMappedIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closer.Close()
+	if got, want := mapped.Stats().DeleteKeys, original.Stats().DeleteKeys; got != want {
+		t.Errorf("DeleteKeys = %d, want %d", got, want)
+	}
+	for _, tc := range goldenCases {
+		for _, input := range tc.inputs {
+			want, _ := original.Lookup(input, verbosity.All, 2)
+			got, _ := mapped.Lookup(input, verbosity.All, 2)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Lookup(%q) after LoadMappedIndex = %v, want %v", input, got, want)
+			}
+		}
+	}
+
+	// runtime changes go to the delta index and survive a rebuild
+	mapped.CreateDictionaryEntry("zyxwv", 5)
+	mapped.Compact()
+	if got, _ := mapped.Lookup("zyxw", verbosity.Top, 2); len(got) == 0 || got[0].Term != "zyxwv" {
+		t.Errorf("Lookup(zyxw) after Compact = %v, want zyxwv", got)
+	}
+}
+
+func TestMappedIndexTermsOutliveClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.symm")
+	saveMappedIndex(t, newGoldenSymSpell(t), path)
+	mapped, err := symspell.New(options.WithMaxDictionaryEditDistance(2), options.WithPrefixLength(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	closer, err := mapped.LoadMappedIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := mapped.Lookup("wrld", verbosity.Top, 2)
+	if err := closer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Term != "world" {
+		t.Errorf("Lookup(wrld) read after Close = %v, want world", got)
+	}
+}
+
+func TestMappedIndexRejectsCorruptSlots(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.symm")
+	saveMappedIndex(t, newGoldenSymSpell(t), path)
+	valid, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	le := binary.LittleEndian
+	slots := int(le.Uint64(valid[40:]))
+	tableStart := len(valid) - int(le.Uint32(valid[28:])) - slots*16
+
+	corruptions := map[string]func(table []byte){
+		"postings out of range": func(table []byte) {
+			for i := 0; i < slots; i++ {
+				if le.Uint64(table[i*16+8:]) != 0 {
+					le.PutUint64(table[i*16+8:], 1<<63|1)
+					return
+				}
+			}
+		},
+		"no empty slot": func(table []byte) {
+			for i := 0; i < slots; i++ {
+				if le.Uint64(table[i*16+8:]) == 0 {
+					le.PutUint64(table[i*16+8:], 1<<32)
+				}
+			}
+		},
+	}
+	for name, corrupt := range corruptions {
+		data := slices.Clone(valid)
+		corrupt(data[tableStart : tableStart+slots*16])
+		corruptPath := filepath.Join(t.TempDir(), "corrupt.symm")
+		if err := os.WriteFile(corruptPath, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		s, err := symspell.New(options.WithMaxDictionaryEditDistance(2), options.WithPrefixLength(7))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if closer, err := s.LoadMappedIndex(corruptPath); err == nil {
+			closer.Close()
+			t.Errorf("%s: LoadMappedIndex accepted a corrupt table", name)
+		}
+	}
+}
+
+func saveMappedIndex(t *testing.T, s symspell.SymSpell, path string) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := s.SaveMappedIndex(file); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHashedDeleteKeys(t *testing.T) {
+	plain := newGoldenSymSpell(t)
+	hashed, err := symspell.New(
+		options.WithMaxDictionaryEditDistance(2),
+		options.WithPrefixLength(7),
+		options.WithHashedDeleteKeys(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := hashed.LoadDictionary(filepath.Join("testdata", "dictionary.txt"), 0, 1, " ")
+	if err != nil || !ok {
+		t.Fatalf("loading dictionary: ok=%v err=%v", ok, err)
+	}
+	if got, want := hashed.Stats().EstimatedBytes, plain.Stats().EstimatedBytes; got >= want {
+		t.Errorf("EstimatedBytes = %d, want less than %d", got, want)
+	}
+	for _, tc := range goldenCases {
+		for _, input := range tc.inputs {
+			want, _ := plain.Lookup(input, verbosity.All, 2)
+			got, _ := hashed.Lookup(input, verbosity.All, 2)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Lookup(%q) with hashed keys = %v, want %v", input, got, want)
+			}
+		}
+	}
+	if err := hashed.SaveIndex(io.Discard); err == nil {
+		t.Error("SaveIndex with hashed keys succeeded, want error")
+	}
+}
+
+func TestDeleteKeyFilter(t *testing.T) {
+	plain := newGoldenSymSpell(t)
+	backends := map[string][]options.Options{
+		"map":    nil,
+		"hashed": {options.WithHashedDeleteKeys()},
+		"trie":   {options.WithIndexBackend(options.IndexBackendTrie)},
+	}
+	for name, opts := range backends {
+		filtered := newGoldenSymSpell(t, append(opts, options.WithDeleteKeyFilter(10))...)
+		if got, want := filtered.Stats().EstimatedBytes, plain.Stats().EstimatedBytes; name == "map" && got <= want {
+			t.Errorf("%s: EstimatedBytes = %d, want more than %d", name, got, want)
+		}
+		for _, tc := range goldenCases {
+			for _, input := range tc.inputs {
+				want, _ := plain.Lookup(input, verbosity.All, 2)
+				got, _ := filtered.Lookup(input, verbosity.All, 2)
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("%s: Lookup(%q) with delete filter = %v, want %v", name, input, got, want)
+				}
+			}
+		}
+
+		// words added at runtime are found before and after the merge
+		filtered.CreateDictionaryEntry("zyxwv", 5)
+		for range 2 {
+			if got, _ := filtered.Lookup("zyxw", verbosity.Top, 2); len(got) == 0 || got[0].Term != "zyxwv" {
+				t.Errorf("%s: Lookup(zyxw) = %v, want zyxwv", name, got)
+			}
+			filtered.Compact()
+		}
+	}
+
+	if _, err := symspell.New(options.WithDeleteKeyFilter(-1)); !errors.Is(err, symspell.ErrInvalidOptions) {
+		t.Errorf("New with negative filter bits: err = %v, want ErrInvalidOptions", err)
+	}
+}
+
+func TestTrieIndexBackend(t *testing.T) {
+	plain := newGoldenSymSpell(t)
+	trie, err := symspell.New(
+		options.WithMaxDictionaryEditDistance(2),
+		options.WithPrefixLength(7),
+		options.WithIndexBackend(options.IndexBackendTrie),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := trie.LoadDictionary(filepath.Join("testdata", "dictionary.txt"), 0, 1, " ")
+	if err != nil || !ok {
+		t.Fatalf("loading dictionary: ok=%v err=%v", ok, err)
+	}
+	if got, want := trie.Stats().DeleteKeys, plain.Stats().DeleteKeys; got != want {
+		t.Errorf("DeleteKeys = %d, want %d", got, want)
+	}
+	if got, want := trie.Stats().EstimatedBytes, plain.Stats().EstimatedBytes; got >= want {
+		t.Errorf("EstimatedBytes = %d, want less than %d", got, want)
+	}
+	check := func(name string) {
+		for _, tc := range goldenCases {
+			for _, input := range tc.inputs {
+				want, _ := plain.Lookup(input, verbosity.All, 2)
+				got, _ := trie.Lookup(input, verbosity.All, 2)
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("Lookup(%q) %s = %v, want %v", input, name, got, want)
+				}
+			}
+		}
+	}
+	check("with the trie backend")
+
+	var buf bytes.Buffer
+	if err := trie.SaveIndex(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := trie.LoadIndex(&buf); err != nil {
+		t.Fatal(err)
+	}
+	check("after LoadIndex")
+
+	trie.CreateDictionaryEntry("zyxwv", 5)
+	trie.Compact()
+	if got, _ := trie.Lookup("zyxw", verbosity.Top, 2); len(got) == 0 || got[0].Term != "zyxwv" {
+		t.Errorf("Lookup(zyxw) after Compact = %v, want zyxwv", got)
+	}
+}
+
+func TestDiskStorageBackend(t *testing.T) {
+	plain := newGoldenSymSpell(t)
+	path := filepath.Join(t.TempDir(), "index.db")
+	openDisk := func() symspell.SymSpell {
+		t.Helper()
+		s, err := symspell.New(
+			options.WithMaxDictionaryEditDistance(2),
+			options.WithPrefixLength(7),
+			options.WithStorageBackend(options.StorageDisk, path),
+			options.WithDictionaryFile(options.DictionaryFile{Path: filepath.Join("testdata", "dictionary.txt"), CountIndex: 1, Separator: " "}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	check := func(name string, s symspell.SymSpell) {
+		t.Helper()
+		for _, tc := range goldenCases {
+			for _, input := range tc.inputs {
+				want, _ := plain.Lookup(input, verbosity.All, 2)
+				got, _ := s.Lookup(input, verbosity.All, 2)
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("Lookup(%q) %s = %v, want %v", input, name, got, want)
+				}
+			}
+		}
+	}
+	disk := openDisk()
+	if stats := disk.Stats(); stats.Postings != 0 || stats.DeleteKeys == 0 {
+		t.Errorf("Stats() = %+v, want the delete keys on disk and no postings in memory", stats)
+	}
+	check("on disk", disk)
+	if err := disk.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the dictionary file is not added again on restart
+	disk = openDisk()
+	check("after reopening", disk)
+	want, _ := plain.WordFrequency("hello")
+	if got, _ := disk.WordFrequency("hello"); got != want {
+		t.Errorf("WordFrequency(hello) after reopening = %d, want %d", got, want)
+	}
+
+	for _, s := range []symspell.SymSpell{plain, disk} {
+		if _, err := s.AddWord("spieling", 1000); err != nil {
+			t.Fatal(err)
+		}
+		s.UpdateWordFrequency("world", 7)
+		s.DeleteDictionaryEntry("spelling")
+	}
+	check("after runtime changes", disk)
+	disk.Close()
+	disk = openDisk()
+	check("after reopening with runtime changes", disk)
+	if got, _ := disk.WordFrequency("world"); got != 7 {
+		t.Errorf("WordFrequency(world) after reopening = %d, want 7", got)
+	}
+	for _, s := range []symspell.SymSpell{plain, disk} {
+		s.Compact()
+	}
+	check("after Compact", disk)
+	disk.Close()
+	disk = openDisk()
+	defer disk.Close()
+	check("after reopening a compacted store", disk)
+
+	// a clone does not share the store
+	clone, err := symspell.Clone(disk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("clone", clone)
+	clone.AddWord("spelling", 1000)
+	if disk.ContainsWord("spelling") {
+		t.Error("AddWord on a clone changed the disk store")
+	}
+}
+
+func TestCompactStorage(t *testing.T) {
+	plain := newGoldenSymSpell(t)
+	packed, err := symspell.New(
+		options.WithMaxDictionaryEditDistance(2),
+		options.WithPrefixLength(7),
+		options.WithCompactStorage(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := packed.LoadDictionary(filepath.Join("testdata", "dictionary.txt"), 0, 1, " ")
+	if err != nil || !ok {
+		t.Fatalf("loading dictionary: ok=%v err=%v", ok, err)
+	}
+	for _, tc := range goldenCases {
+		for _, input := range tc.inputs {
+			want, _ := plain.Lookup(input, verbosity.All, 2)
+			got, _ := packed.Lookup(input, verbosity.All, 2)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Lookup(%q) with compact storage = %v, want %v", input, got, want)
+			}
+		}
+	}
+
+	packed.CreateDictionaryEntry("zyxwv", 5)
+	packed.DeleteDictionaryEntry("hello")
+	packed.Compact()
+	if got, _ := packed.Lookup("zyxw", verbosity.Top, 2); len(got) == 0 || got[0].Term != "zyxwv" {
+		t.Errorf("Lookup(zyxw) after Compact = %v, want zyxwv", got)
+	}
+	if packed.ContainsWord("hello") {
+		t.Error("hello still in the dictionary after DeleteDictionaryEntry")
+	}
+	packed.CreateDictionaryEntry("hello", 7)
+	if count, found := packed.WordFrequency("hello"); !found || count != 7 {
+		t.Errorf("WordFrequency(hello) after adding it back = %d, %v, want 7, true", count, found)
+	}
+	if got, want := packed.WordCount(), plain.WordCount()+1; got != want {
+		t.Errorf("WordCount() = %d, want %d", got, want)
+	}
+}
+
+func TestCompactStorageHeap(t *testing.T) {
+	const n = 50000
+	var corpus strings.Builder
+	for i := range n {
+		fmt.Fprintf(&corpus, "word%06d %d\n", i, n-i)
+	}
+	// the live heap objects and scannable heap bytes of a loaded dictionary;
+	// hashed delete keys leave the words as the only per-word strings
+	samples := []metrics.Sample{{Name: "/gc/heap/objects:objects"}, {Name: "/gc/scan/heap:bytes"}}
+	heap := func(opts ...options.Options) (objects, scan uint64) {
+		runtime.GC()
+		metrics.Read(samples)
+		objects, scan = samples[0].Value.Uint64(), samples[1].Value.Uint64()
+		s, err := symspell.New(append(opts, options.WithMaxDictionaryEditDistance(1), options.WithHashedDeleteKeys())...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := s.LoadDictionaryStream(strings.NewReader(corpus.String()), 0, 1, " "); err != nil || !ok {
+			t.Fatalf("loading dictionary: ok=%v err=%v", ok, err)
+		}
+		runtime.GC()
+		metrics.Read(samples)
+		if !s.ContainsWord("word000042") {
+			t.Fatal("word000042 missing from the dictionary")
+		}
+		runtime.KeepAlive(s)
+		return samples[0].Value.Uint64() - min(objects, samples[0].Value.Uint64()),
+			samples[1].Value.Uint64() - min(scan, samples[1].Value.Uint64())
+	}
+	plainObjects, plainScan := heap()
+	packedObjects, packedScan := heap(options.WithCompactStorage())
+	t.Logf("%d words: %d heap objects, %d scannable bytes plain; %d and %d with compact storage",
+		n, plainObjects, plainScan, packedObjects, packedScan)
+	if packedObjects+n/2 > plainObjects {
+		t.Errorf("compact storage keeps %d heap objects, want at least %d fewer than the %d of plain storage", packedObjects, n/2, plainObjects)
+	}
+	// a string header per word is 16 scannable bytes
+	if packedScan+8*n > plainScan {
+		t.Errorf("compact storage keeps %d scannable heap bytes, want at least %d fewer than the %d of plain storage", packedScan, 8*n, plainScan)
+	}
+}
+
+func TestPruneDictionaryMatchesCountThreshold(t *testing.T) {
+	const floor = 50000000
+	pruned := newGoldenSymSpell(t)
+	before := pruned.Stats()
+	result := pruned.PruneDictionary(floor)
+	if result.WordsRemoved == 0 || result.PostingsAfter >= result.PostingsBefore {
+		t.Fatalf("PruneDictionary = %+v", result)
+	}
+	after := pruned.Stats()
+	if after.Words != before.Words-result.WordsRemoved || after.Postings != result.PostingsAfter ||
+		after.EstimatedBytes >= before.EstimatedBytes {
+		t.Errorf("Stats after PruneDictionary = %+v, before = %+v", after, before)
+	}
+
+	filtered, err := symspell.New(
+		options.WithMaxDictionaryEditDistance(2),
+		options.WithPrefixLength(7),
+		options.WithCountThreshold(floor),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := filtered.LoadDictionary(filepath.Join("testdata", "dictionary.txt"), 0, 1, " "); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range goldenCases {
+		for _, input := range tc.inputs {
+			want, _ := filtered.Lookup(input, verbosity.All, 2)
+			got, _ := pruned.Lookup(input, verbosity.All, 2)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Lookup(%q) after PruneDictionary = %v, want %v", input, got, want)
+			}
+		}
+	}
+}
+
+func TestCompactRebuildsPostings(t *testing.T) {
+	for _, threadSafe := range []bool{false, true} {
+		var opts []options.Options
+		if threadSafe {
+			opts = append(opts, options.WithThreadSafe())
+		}
+		s := newGoldenSymSpell(t, opts...)
+		for _, word := range []string{"spieling", "spellling", "worldly"} {
+			if _, err := s.AddWord(word, 1000); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// below the quarter of deleted words that compacts automatically
+		deleted, limit := []string{"spieling", "spelling"}, s.WordCount()/5
+		for term := range s.Entries() {
+			if len(deleted) >= limit {
+				break
+			}
+			if term != "spellling" && term != "worldly" {
+				deleted = append(deleted, term)
+			}
+		}
+		for _, term := range deleted {
+			s.DeleteDictionaryEntry(term)
+		}
+
+		result := s.Compact()
+		var live bytes.Buffer
+		for term, count := range s.Entries() {
+			fmt.Fprintf(&live, "%s %d\n", term, count)
+		}
+		fresh, err := symspell.New(options.WithMaxDictionaryEditDistance(2), options.WithPrefixLength(7))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fresh.LoadDictionaryStream(&live, 0, 1, " "); err != nil {
+			t.Fatal(err)
+		}
+		got, want := s.Stats(), fresh.Stats()
+		if result.WordsRemoved != len(deleted) || result.ReclaimedBytes <= 0 || result.PostingsAfter != want.Postings {
+			t.Errorf("thread-safe %v: Compact = %+v, want %d words removed and %d postings", threadSafe, result, len(deleted), want.Postings)
+		}
+		if got.Postings != want.Postings || got.DeltaPostings != 0 || got.DeleteKeys != want.DeleteKeys {
+			t.Errorf("thread-safe %v: Stats after Compact = %+v, want the index of the live words %+v", threadSafe, got, want)
+		}
+		for _, term := range deleted {
+			suggestions, _ := s.Lookup(term, verbosity.All, 2)
+			for _, suggestion := range suggestions {
+				if slices.Contains(deleted, suggestion.Term) {
+					t.Errorf("thread-safe %v: Lookup(%q) after Compact suggests the deleted word %q", threadSafe, term, suggestion.Term)
+				}
+			}
+		}
+	}
+}
+
+func TestConcurrentCompactKeepsUpdates(t *testing.T) {
+	s := newGoldenSymSpell(t, options.WithThreadSafe())
+	s.DeleteDictionaryEntry("spelling")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 200 {
+			s.AddWord(fmt.Sprintf("word%d", i), 10)
+			s.Lookup("helo", verbosity.Top, 2)
+		}
+	}()
+	for range 5 {
+		s.Compact()
+	}
+	<-done
+	s.Compact()
+	for i := range 200 {
+		if term := fmt.Sprintf("word%d", i); !s.ContainsWord(term) {
+			t.Fatalf("%s added during Compact is missing", term)
+		}
+	}
+	if got, _ := s.Lookup("word199x", verbosity.Top, 2); len(got) != 1 || got[0].Term != "word199" {
+		t.Errorf("Lookup(word199x) = %v, want word199", got)
+	}
+}
@@ -6,3 +6,49 @@ type SuggestItem struct {
 	Distance int
 	Count    int
 }
+
+// Annotation is a standoff correction over an immutable source text.
+//
+// JSON schema:
+//
+//	{
+//	  "start":       integer, byte offset of the token in the source (inclusive)
+//	  "end":         integer, byte offset of the token end (exclusive)
+//	  "original":    string,  source text of the token
+//	  "replacement": string,  suggested correction
+//	  "distance":    integer, edit distance between original and replacement
+//	  "confidence":  number,  in [0, 1]
+//	}
+type Annotation struct {
+	Start       int     `json:"start"`
+	End         int     `json:"end"`
+	Original    string  `json:"original"`
+	Replacement string  `json:"replacement"`
+	Distance    int     `json:"distance"`
+	Confidence  float64 `json:"confidence"`
+}
+
+// Composition is the result of word segmentation.
+type Composition struct {
+	SegmentedString string
+	CorrectedString string
+	DistanceSum     int
+	LogProbSum      float64
+}
+
+// TokenCorrection describes how a span of a LookupCompound input was
+// corrected. A span covers one word, or two words that were merged.
+type TokenCorrection struct {
+	Original    string
+	Replacement string
+	Start       int // byte offset of the span in the input (inclusive)
+	End         int // byte offset of the span end (exclusive)
+	Distance    int
+}
+
+// CompoundResult is the detailed result of LookupCompound.
+type CompoundResult struct {
+	Suggestion SuggestItem
+	Tokens     []TokenCorrection
+	LogProb    float64 // log10 probability of Suggestion, set by the beam search only
+}
@@ -0,0 +1,86 @@
+package symspell_test
+
+import (
+	"strings"
+	"testing"
+
+	"symspell/pkg/options"
+)
+
+// languageModelFunc adapts a function to options.LanguageModel.
+type languageModelFunc func(tokens []string) float64
+
+func (f languageModelFunc) ScoreSequence(tokens []string) float64 { return f(tokens) }
+
+func TestLanguageModel(t *testing.T) {
+	// prefers "to" after "help" and charges one log10 unit per word otherwise
+	model := languageModelFunc(func(tokens []string) float64 {
+		score := -float64(len(tokens))
+		for i := 1; i < len(tokens); i++ {
+			if tokens[i-1] == "help" && tokens[i] == "to" {
+				score += 10
+			}
+		}
+		return score
+	})
+
+	if got := newGoldenSymSpell(t).LookupCompound("help te house", 2); got == nil || got.Term != "help the house" {
+		t.Fatalf("LookupCompound without model = %v", got)
+	}
+	s := newGoldenSymSpell(t, options.WithLanguageModel(model))
+	if got := s.LookupCompound("help te house", 2); got == nil || got.Term != "help to house" {
+		t.Errorf("LookupCompound with model = %v, want help to house", got)
+	}
+
+	got, err := s.WordSegmentation("thequickbrownfox", 2, 0)
+	if err != nil || got.CorrectedString != "the quick brown fox" {
+		t.Fatalf("WordSegmentation = %v, %v", got, err)
+	}
+	if want := model(strings.Fields(got.CorrectedString)); got.LogProbSum != want {
+		t.Errorf("LogProbSum = %v, want the model score %v", got.LogProbSum, want)
+	}
+}
+
+// bigramModel is an incremental model that rewards "help to" and charges one
+// log10 unit per word otherwise.
+type bigramModel struct{ sequences int }
+
+func (m *bigramModel) ScoreSequence(tokens []string) float64 {
+	m.sequences++
+	score := 0.0
+	for i, token := range tokens {
+		score += m.ScoreNext(tokens[:i], token)
+	}
+	return score
+}
+
+func (m *bigramModel) ScoreNext(prefix []string, token string) float64 {
+	if len(prefix) > 0 && prefix[len(prefix)-1] == "help" && token == "to" {
+		return 9
+	}
+	return -1
+}
+
+func TestIncrementalLanguageModel(t *testing.T) {
+	model := &bigramModel{}
+	s := newGoldenSymSpell(t, options.WithLanguageModel(model))
+	got, err := s.WordSegmentation("thequickbrownfox", 2, 0)
+	if err != nil || got.CorrectedString != "the quick brown fox" {
+		t.Fatalf("WordSegmentation = %v, %v", got, err)
+	}
+	if want := (&bigramModel{}).ScoreSequence(strings.Fields(got.CorrectedString)); got.LogProbSum != want {
+		t.Errorf("LogProbSum = %v, want the model score %v", got.LogProbSum, want)
+	}
+	nbest, err := s.WordSegmentationNBest("helpto", 2, 0, 1)
+	if err != nil || len(nbest) != 1 || nbest[0].CorrectedString != "help to" || nbest[0].LogProbSum != 8 {
+		t.Errorf("WordSegmentationNBest(helpto) = %+v, %v, want help to scored 8", nbest, err)
+	}
+
+	beam := newGoldenSymSpell(t, options.WithLanguageModel(model), options.WithCompoundBeamWidth(4))
+	if got := beam.LookupCompound("help te house", 2); got == nil || got.Term != "help to house" {
+		t.Errorf("beam LookupCompound with model = %v, want help to house", got)
+	}
+	if model.sequences != 0 {
+		t.Errorf("ScoreSequence called %d times, want the sentences scored incrementally", model.sequences)
+	}
+}
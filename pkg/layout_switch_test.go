@@ -0,0 +1,36 @@
+package symspell_test
+
+import (
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestLayoutSwitch(t *testing.T) {
+	s, err := symspell.New(options.WithLayoutSwitch(options.LayoutQwertyToJcuken, options.LayoutQwertyToJcuken.Reverse()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, word := range []string{"привет", "мир", "hello", "ghost"} {
+		s.CreateDictionaryEntry(word, 1000)
+	}
+
+	tests := []struct{ input, want string }{
+		{"ghbdtn", "привет"},
+		{"Ghbdtn", "привет"},
+		{"руддщ", "hello"},
+		{"ghost", "ghost"}, // known words are never converted
+		{"helo", "hello"},  // no conversion is a word, edit distance applies
+	}
+	for _, tt := range tests {
+		got, err := s.Lookup(tt.input, verbosity.Top, 2)
+		if err != nil || len(got) == 0 || got[0].Term != tt.want {
+			t.Errorf("Lookup(%q) = %v, %v; want %q", tt.input, got, err, tt.want)
+		}
+	}
+	if got := s.LookupCompound("ghbdtn vbh", 2); got == nil || got.Term != "привет мир" {
+		t.Errorf("LookupCompound(ghbdtn vbh) = %v, want привет мир", got)
+	}
+}
@@ -0,0 +1,98 @@
+package symspell_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestLogProbRanking(t *testing.T) {
+	newRanked := func(opts ...options.Options) symspell.SymSpell {
+		t.Helper()
+		s, err := symspell.New(opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.CreateDictionaryEntry("word", 10)
+		s.CreateDictionaryEntry("world", 1000000)
+		return s
+	}
+	top := func(s symspell.SymSpell) string {
+		t.Helper()
+		got, err := s.Lookup("wrd", verbosity.Top, 2)
+		if err != nil || len(got) != 1 {
+			t.Fatalf("Lookup(wrd) = %v, %v", got, err)
+		}
+		return got[0].Term
+	}
+
+	if got := top(newRanked()); got != "word" {
+		t.Errorf("default Top = %q, want the closer word", got)
+	}
+	// world is 10^5 times as frequent, which outweighs one more edit at 2
+	// but not at 6
+	ranked := newRanked(options.WithLogProbRanking(2))
+	if got := top(ranked); got != "world" {
+		t.Errorf("Top with penalty 2 = %q, want world", got)
+	}
+	if got := top(newRanked(options.WithLogProbRanking(6))); got != "word" {
+		t.Errorf("Top with penalty 6 = %q, want word", got)
+	}
+	all, _ := ranked.Lookup("wrd", verbosity.All, 2)
+	if want := []items.SuggestItem{{Term: "world", Distance: 2, Count: 1000000}, {Term: "word", Distance: 1, Count: 10}}; !reflect.DeepEqual(all, want) {
+		t.Errorf("All with penalty 2 = %v, want %v", all, want)
+	}
+
+	// dictionary words are looked up as without ranking
+	exact := newRanked(options.WithLogProbRanking(2), options.WithFrequencyThreshold(1))
+	if got, _ := exact.Lookup("word", verbosity.Top, 2); len(got) != 1 || got[0].Term != "word" {
+		t.Errorf("Top(word) = %v, want the exact match", got)
+	}
+
+	for _, opts := range [][]options.Options{
+		{options.WithLogProbRanking(-1)},
+		{options.WithLogProbRanking(1), options.WithRanker(func(a, b items.SuggestItem) bool { return a.Count > b.Count })},
+	} {
+		if _, err := symspell.New(opts...); !errors.Is(err, symspell.ErrInvalidOptions) {
+			t.Errorf("New(%d options) error = %v, want ErrInvalidOptions", len(opts), err)
+		}
+	}
+}
+
+func TestLogProbRankingEscalation(t *testing.T) {
+	for _, escalate := range []bool{false, true} {
+		var coarse []items.SuggestItem
+		s, err := symspell.New(
+			options.WithLogProbRanking(2),
+			options.WithTwoStageLookup(1, func(result []items.SuggestItem) bool {
+				coarse = result
+				return escalate
+			}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.CreateDictionaryEntry("word", 10)
+		s.CreateDictionaryEntry("world", 1000000)
+
+		got, err := s.Lookup("wrd", verbosity.Top, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(coarse) != 1 || coarse[0].Term != "word" {
+			t.Errorf("escalate=%v: coarse pass = %v, want word", escalate, coarse)
+		}
+		want := "word"
+		if escalate {
+			want = "world"
+		}
+		if len(got) != 1 || got[0].Term != want {
+			t.Errorf("escalate=%v: Top = %v, want %s", escalate, got, want)
+		}
+	}
+}
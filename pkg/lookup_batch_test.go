@@ -0,0 +1,46 @@
+package symspell_test
+
+import (
+	"reflect"
+	"testing"
+
+	"symspell/pkg/items"
+	"symspell/pkg/verbosity"
+)
+
+func TestLookupBatchMatchesLookup(t *testing.T) {
+	s := newGoldenSymSpell(t)
+	var terms []string
+	for _, tc := range goldenCases {
+		terms = append(terms, tc.inputs...)
+	}
+	got, err := s.LookupBatch(terms, verbosity.Closest, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, term := range terms {
+		want, _ := s.Lookup(term, verbosity.Closest, 2)
+		if !reflect.DeepEqual(got[i], want) {
+			t.Errorf("LookupBatch[%d] (%q) = %v, want %v", i, term, got[i], want)
+		}
+	}
+}
+
+func TestLookupAppendMatchesLookup(t *testing.T) {
+	s := newGoldenSymSpell(t)
+	buf := make([]items.SuggestItem, 0, 64)
+	for _, tc := range goldenCases {
+		for _, term := range tc.inputs {
+			want, _ := s.Lookup(term, verbosity.All, 2)
+			marker := items.SuggestItem{Term: "marker"}
+			got, err := s.LookupAppend(append(buf[:0], marker), term, verbosity.All, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got[0] != marker || !reflect.DeepEqual(got[1:], append([]items.SuggestItem{}, want...)) {
+				t.Errorf("LookupAppend(%q) = %v, want marker followed by %v", term, got, want)
+			}
+			buf = got
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package symspell_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestLookupCacheKeysByDistance(t *testing.T) {
+	s, err := symspell.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("hello", 10)
+	if got, _ := s.Lookup("hxllx", verbosity.Top, 2); len(got) != 1 || got[0].Term != "hello" {
+		t.Fatalf("Lookup(hxllx, 2) = %v, want hello", got)
+	}
+	if got, _ := s.Lookup("hxllx", verbosity.Top, 1); len(got) != 0 {
+		t.Errorf("Lookup(hxllx, 1) = %v, want no suggestions", got)
+	}
+	s.Lookup("hxllx", verbosity.Top, 2)
+	if got := s.CacheStats(); got.Hits != 1 || got.Misses != 2 {
+		t.Errorf("CacheStats() = %+v, want 1 hit and 2 misses", got)
+	}
+}
+
+func TestWithoutLookupCache(t *testing.T) {
+	s, err := symspell.New(options.WithoutLookupCache())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("hello", 10)
+	for i := 0; i < 2; i++ {
+		if got, _ := s.Lookup("helo", verbosity.Top, 2); len(got) != 1 || got[0].Term != "hello" {
+			t.Fatalf("Lookup(helo) = %v, want hello", got)
+		}
+	}
+	if got := s.CacheStats(); got.Hits != 0 || got.Size != 0 {
+		t.Errorf("CacheStats() = %+v, want an unused cache", got)
+	}
+}
+
+func TestLookupCacheTTLAndInvalidate(t *testing.T) {
+	s, err := symspell.New(options.WithLookupCacheTTL(time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("hello", 10)
+	s.Lookup("helo", verbosity.Top, 2)
+	time.Sleep(5 * time.Millisecond)
+	s.Lookup("helo", verbosity.Top, 2)
+	if got := s.CacheStats(); got.Hits != 0 {
+		t.Errorf("CacheStats() = %+v, want the entry expired", got)
+	}
+
+	s.InvalidateCache()
+	if got := s.CacheStats(); got.Size != 0 {
+		t.Errorf("CacheStats().Size after InvalidateCache = %d, want 0", got.Size)
+	}
+}
+
+func TestSingleflightLookups(t *testing.T) {
+	s := newGoldenSymSpell(t, options.WithSingleflight(), options.WithoutLookupCache())
+	want, _ := s.Lookup("speling", verbosity.All, 2)
+	if len(want) == 0 {
+		t.Fatal("Lookup(speling) found nothing")
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := s.Lookup("speling", verbosity.All, 2)
+			if err != nil || !reflect.DeepEqual(got, want) {
+				t.Errorf("Lookup(speling) = %v, %v, want %v", got, err, want)
+				return
+			}
+			// results must not be shared between callers
+			got[0].Term = ""
+		}()
+	}
+	wg.Wait()
+}
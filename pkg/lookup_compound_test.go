@@ -0,0 +1,148 @@
+package symspell_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+)
+
+func TestCompoundDistanceMemo(t *testing.T) {
+	plain := newGoldenSymSpell(t)
+	for _, limit := range []int{4, 1 << 16} {
+		memo := newGoldenSymSpell(t, options.WithCompoundDistanceMemo(limit))
+		for _, input := range compoundInputs {
+			want := plain.LookupCompoundDetailed(input, 2)
+			got := memo.LookupCompoundDetailed(input, 2)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("LookupCompoundDetailed(%q) with a memo of %d = %+v, want %+v", input, limit, got, want)
+			}
+		}
+	}
+	if _, err := symspell.New(options.WithCompoundDistanceMemo(-1)); !errors.Is(err, symspell.ErrInvalidOptions) {
+		t.Errorf("New(compoundDistanceMemo -1) error = %v, want ErrInvalidOptions", err)
+	}
+}
+
+func TestCompoundBeamWidth(t *testing.T) {
+	greedy := newGoldenSymSpell(t)
+	beam := newGoldenSymSpell(t, options.WithCompoundBeamWidth(8))
+	for _, tt := range []struct{ input, greedy, beam string }{
+		{"thei rhouse", "the house", "their house"},
+		{"helo wrold", "help world", "hello world"},
+		{"the quikc brwon fox jmups over teh lazy dog", "the quick brown fox jumps over the lazy dog", "the quick brown fox jumps over the lazy dog"},
+	} {
+		if got := greedy.LookupCompound(tt.input, 2); got == nil || got.Term != tt.greedy {
+			t.Errorf("greedy LookupCompound(%q) = %v, want %q", tt.input, got, tt.greedy)
+		}
+		if got := beam.LookupCompound(tt.input, 2); got == nil || got.Term != tt.beam {
+			t.Errorf("beam LookupCompound(%q) = %v, want %q", tt.input, got, tt.beam)
+		}
+	}
+
+	got := beam.LookupCompoundDetailed("thei rhouse", 2)
+	want := []items.TokenCorrection{
+		{Original: "thei", Replacement: "their", Start: 0, End: 4, Distance: 1},
+		{Original: "rhouse", Replacement: "house", Start: 5, End: 11, Distance: 1},
+	}
+	if got == nil {
+		t.Fatal("LookupCompoundDetailed(thei rhouse) = nil")
+	}
+	if !reflect.DeepEqual(got.Tokens, want) {
+		t.Errorf("LookupCompoundDetailed(thei rhouse).Tokens = %+v, want %+v", got.Tokens, want)
+	}
+	if _, err := symspell.New(options.WithCompoundBeamWidth(-1)); !errors.Is(err, symspell.ErrInvalidOptions) {
+		t.Errorf("New(compoundBeamWidth -1) error = %v, want ErrInvalidOptions", err)
+	}
+}
+
+func TestCompoundWorkers(t *testing.T) {
+	sequential := newGoldenSymSpell(t)
+	parallel := newGoldenSymSpell(t, options.WithCompoundWorkers(4))
+	inputs := append([]string{"thequick brownfox", "whereis th elove", "helo wrold"}, compoundInputs...)
+	for _, input := range inputs {
+		want := sequential.LookupCompoundDetailed(input, 2)
+		if got := parallel.LookupCompoundDetailed(input, 2); !reflect.DeepEqual(got, want) {
+			t.Errorf("LookupCompoundDetailed(%q) with workers = %+v, want %+v", input, got, want)
+		}
+	}
+}
+
+func TestCompoundBeamWorkers(t *testing.T) {
+	sequential := newGoldenSymSpell(t, options.WithCompoundBeamWidth(8))
+	parallel := newGoldenSymSpell(t, options.WithCompoundBeamWidth(8), options.WithCompoundWorkers(4))
+	for _, input := range []string{"thei rhouse", "helo wrold", "the quikc brwon fox jmups over teh lazy dog", "the quick brownfox"} {
+		want := sequential.LookupCompoundNBest(input, 2, 8)
+		if got := parallel.LookupCompoundNBest(input, 2, 8); !reflect.DeepEqual(got, want) {
+			t.Errorf("LookupCompoundNBest(%q) with workers = %+v, want %+v", input, got, want)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := parallel.LookupCompoundContext(ctx, "helo wrold", 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("LookupCompoundContext(canceled) error = %v, want context.Canceled", err)
+	}
+}
+
+func TestLookupCompoundNBest(t *testing.T) {
+	s := newGoldenSymSpell(t)
+	results := s.LookupCompoundNBest("helo wrold", 2, 4)
+	var terms []string
+	for _, r := range results {
+		terms = append(terms, r.Suggestion.Term)
+	}
+	if want := []string{"hello world", "help world"}; !reflect.DeepEqual(terms, want) {
+		t.Fatalf("LookupCompoundNBest(helo wrold) = %v, want %v", terms, want)
+	}
+	if results[0].Suggestion.Distance != 2 || results[0].LogProb <= results[1].LogProb {
+		t.Errorf("LookupCompoundNBest(helo wrold) = %+v, want equal distances by falling probability", results)
+	}
+
+	beam := newGoldenSymSpell(t, options.WithCompoundBeamWidth(4)).LookupCompoundDetailed("helo wrold", 2)
+	if !reflect.DeepEqual(*beam, results[0]) {
+		t.Errorf("LookupCompoundDetailed with a beam = %+v, want %+v", *beam, results[0])
+	}
+	// a correction reached in several ways fills one place
+	// of the beam, so a beam of n yields n corrections
+	terms = nil
+	for _, r := range s.LookupCompoundNBest("the quick brownfox", 2, 3) {
+		terms = append(terms, r.Suggestion.Term)
+	}
+	if want := []string{"the quick brown fox", "the quick brown on", "the quick brown i"}; !reflect.DeepEqual(terms, want) {
+		t.Errorf("LookupCompoundNBest(the quick brownfox) = %v, want %v", terms, want)
+	}
+	if got := s.LookupCompoundNBest("helo wrold", 2, 0); got != nil {
+		t.Errorf("LookupCompoundNBest(n=0) = %v, want nil", got)
+	}
+}
+
+func TestLookupCompoundContext(t *testing.T) {
+	reject := newGoldenSymSpell(t, options.WithInvalidUTF8Policy(options.InvalidUTF8Reject))
+	for _, width := range []int{1, 4} {
+		s := newGoldenSymSpell(t, options.WithInvalidUTF8Policy(options.InvalidUTF8Reject), options.WithCompoundBeamWidth(width))
+		result, err := s.LookupCompoundContext(context.Background(), "quik brwn fox", 2)
+		if err != nil || result == nil || result.Suggestion.Term != "quick brown fox" {
+			t.Errorf("beam %d: LookupCompoundContext = %+v, %v", width, result, err)
+		}
+		_, err = s.LookupCompoundContext(context.Background(), "helo \xffwrld", 2)
+		var utf8Err *symspell.InvalidUTF8Error
+		if !errors.Is(err, symspell.ErrInvalidUTF8) || !errors.As(err, &utf8Err) || utf8Err.Offset != 5 {
+			t.Errorf("beam %d: invalid UTF-8 error = %v", width, err)
+		}
+		if _, err := s.LookupCompoundContext(context.Background(), "helo", 3); !errors.Is(err, symspell.ErrDistanceTooLarge) {
+			t.Errorf("beam %d: error = %v, want ErrDistanceTooLarge", width, err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if result, err := s.LookupCompoundContext(ctx, "quik brwn fox", 2); result != nil || !errors.Is(err, context.Canceled) {
+			t.Errorf("beam %d: canceled lookup = %+v, %v", width, result, err)
+		}
+	}
+	if got := reject.LookupCompoundDetailed("helo \xffwrld", 2); got != nil {
+		t.Errorf("LookupCompoundDetailed = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,25 @@
+package symspell_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"symspell/pkg/verbosity"
+)
+
+func TestLookupContext(t *testing.T) {
+	s := newGoldenSymSpell(t)
+	want, _ := s.Lookup("helo", verbosity.All, 2)
+	got, err := s.LookupContext(context.Background(), "helo", verbosity.All, 2)
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("LookupContext = %v, %v, want %v", got, err, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got, err := s.LookupContext(ctx, "helo", verbosity.All, 2); !errors.Is(err, context.Canceled) || got != nil {
+		t.Errorf("LookupContext with canceled context = %v, %v", got, err)
+	}
+}
@@ -0,0 +1,69 @@
+package symspell_test
+
+import (
+	"strings"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/options"
+)
+
+func newContextSymSpell(t *testing.T, opts ...options.Options) symspell.SymSpell {
+	t.Helper()
+	s, err := symspell.New(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("there", 5000)
+	s.CreateDictionaryEntry("their", 3000)
+	s.CreateDictionaryEntry("is", 9000)
+	s.CreateDictionaryEntry("house", 800)
+	bigrams := "there is 400\ntheir house 90\n"
+	if _, err := s.LoadBigramDictionaryStream(strings.NewReader(bigrams), 0, 2, ""); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestLookupInContext(t *testing.T) {
+	s := newContextSymSpell(t)
+	for _, tt := range []struct {
+		tokens []string
+		index  int
+		want   string
+	}{
+		{[]string{"ther"}, 0, "there"},
+		{[]string{"ther", "is"}, 0, "there"},
+		{[]string{"ther", "house"}, 0, "their"},
+		{[]string{"in", "ther", "house"}, 1, "their"},
+		{[]string{"look", "ther", "is", "a", "house"}, 1, "there"},
+	} {
+		got, err := s.LookupInContext(tt.tokens, tt.index, 2)
+		if err != nil || len(got) == 0 || got[0].Term != tt.want {
+			t.Errorf("LookupInContext(%q, %d) = %v, %v, want %s first", tt.tokens, tt.index, got, err, tt.want)
+		}
+	}
+	for _, index := range []int{-1, 2} {
+		if _, err := s.LookupInContext([]string{"ther", "is"}, index, 2); err == nil {
+			t.Errorf("LookupInContext(index %d) error = nil, want out of range", index)
+		}
+	}
+}
+
+func TestLookupInContextScorer(t *testing.T) {
+	var calls [][3]string
+	s := newContextSymSpell(t, options.WithContextScorer(func(left, term, right string) float64 {
+		calls = append(calls, [3]string{left, term, right})
+		if term == "their" {
+			return 10
+		}
+		return 0
+	}))
+	got, err := s.LookupInContext([]string{"ther", "is"}, 0, 2)
+	if err != nil || len(got) == 0 || got[0].Term != "their" {
+		t.Errorf("LookupInContext with a scorer = %v, %v, want their first", got, err)
+	}
+	if len(calls) == 0 || calls[0][0] != "" || calls[0][2] != "is" {
+		t.Errorf("ContextScorer calls = %q, want neighbors \"\" and \"is\"", calls)
+	}
+}
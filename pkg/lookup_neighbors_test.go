@@ -0,0 +1,35 @@
+package symspell_test
+
+import (
+	"strings"
+	"testing"
+
+	symspell "symspell/pkg"
+)
+
+func TestLookupWithNeighbors(t *testing.T) {
+	s, err := symspell.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("there", 5000)
+	s.CreateDictionaryEntry("their", 3000)
+	s.CreateDictionaryEntry("is", 9000)
+	s.CreateDictionaryEntry("house", 800)
+	bigrams := "there is 400\ntheir house 90\n"
+	if _, err := s.LoadBigramDictionaryStream(strings.NewReader(bigrams), 0, 2, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct{ prev, next, want string }{
+		{"", "", "there"},
+		{"", "is", "there"},
+		{"", "house", "their"},
+		{"in", "house", "their"},
+	} {
+		got, err := s.LookupWithNeighbors(tt.prev, "ther", tt.next, 2)
+		if err != nil || len(got) == 0 || got[0].Term != tt.want {
+			t.Errorf("LookupWithNeighbors(%q, ther, %q) = %v, %v, want %s first", tt.prev, tt.next, got, err, tt.want)
+		}
+	}
+}
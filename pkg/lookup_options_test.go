@@ -0,0 +1,48 @@
+package symspell_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestLookupWithOptions(t *testing.T) {
+	s, err := symspell.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for word, count := range map[string]uint64{"file": 5, "fine": 100000, "fire": 5000, "five": 3000} {
+		s.CreateDictionaryEntry(word, count)
+	}
+
+	// the frequency gate replaces the rare exact match; Lookup fills the cache
+	if got, _ := s.Lookup("file", verbosity.Top, 2); len(got) != 1 || got[0].Term != "fine" {
+		t.Fatalf("Lookup(file) = %v, want fine", got)
+	}
+	for _, tt := range []struct {
+		name string
+		opts options.LookupOptions
+		want []string
+	}{
+		{"gate", options.LookupOptions{Verbosity: verbosity.Top, MaxEditDistance: 2}, []string{"fine"}},
+		{"no gate", options.LookupOptions{Verbosity: verbosity.Top, MaxEditDistance: 2, DisableFrequencyGate: true}, []string{"file"}},
+		{"limit", options.LookupOptions{Verbosity: verbosity.Closest, MaxEditDistance: 2, MaxSuggestions: 2}, []string{"fine", "fire"}},
+		{"distance 0", options.LookupOptions{Verbosity: verbosity.All, MaxEditDistance: 0, DisableFrequencyGate: true}, []string{"file"}},
+	} {
+		got, err := s.LookupWithOptions("file", tt.opts)
+		if err != nil || !reflect.DeepEqual(termsOf(got), tt.want) {
+			t.Errorf("%s: LookupWithOptions(file, %+v) = %v, %v, want %v", tt.name, tt.opts, got, err, tt.want)
+		}
+	}
+
+	if _, err := s.LookupWithOptions("file", options.LookupOptions{MaxEditDistance: 2, MaxSuggestions: -1}); !errors.Is(err, symspell.ErrInvalidOptions) {
+		t.Errorf("LookupWithOptions(maxSuggestions -1) error = %v, want ErrInvalidOptions", err)
+	}
+	if _, err := s.LookupWithOptions("file", options.LookupOptions{MaxEditDistance: 3}); !errors.Is(err, symspell.ErrDistanceTooLarge) {
+		t.Errorf("LookupWithOptions(maxEditDistance 3) error = %v, want ErrDistanceTooLarge", err)
+	}
+}
@@ -0,0 +1,34 @@
+package symspell_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestParallelLookupMatchesSequential(t *testing.T) {
+	sequential := newGoldenSymSpell(t, options.WithPrefixLength(20))
+	parallel := newGoldenSymSpell(t, options.WithPrefixLength(20), options.WithParallelLookup(4, 3))
+	sorted := func(result []items.SuggestItem) []items.SuggestItem {
+		return slices.SortedFunc(slices.Values(result), func(a, b items.SuggestItem) int {
+			return strings.Compare(a.Term, b.Term)
+		})
+	}
+	inputs := []string{"acomodation", "acommodatoin", "программированиеее", "праграмирование"}
+	for _, tc := range goldenCases {
+		inputs = append(inputs, tc.inputs...)
+	}
+	for _, input := range inputs {
+		for _, v := range []verbosity.Verbosity{verbosity.Top, verbosity.Closest, verbosity.All} {
+			want, _ := sequential.Lookup(input, v, 2)
+			got, _ := parallel.Lookup(input, v, 2)
+			if !slices.Equal(sorted(got), sorted(want)) {
+				t.Errorf("Lookup(%q, %v) = %v, want %v", input, v, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package symspell
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// DictionaryManager routes requests to one SymSpell instance per language and
+// counts requests per language. It is safe for concurrent use, and so are the
+// instances it returns.
+type DictionaryManager struct {
+	mu          sync.RWMutex
+	defaultLang string
+	instances   map[string]*managedDictionary
+}
+
+type managedDictionary struct {
+	symspell SymSpell
+	requests atomic.Uint64
+}
+
+func NewDictionaryManager(defaultLang string) *DictionaryManager {
+	return &DictionaryManager{
+		defaultLang: defaultLang,
+		instances:   make(map[string]*managedDictionary),
+	}
+}
+
+// Register adds or replaces the instance serving lang. Since the requests of
+// all goroutines share it, symspell is wrapped with NewConcurrent unless it
+// is concurrent already.
+func (m *DictionaryManager) Register(lang string, symspell SymSpell) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.instances[lang] = &managedDictionary{symspell: NewConcurrent(symspell)}
+}
+
+// Unregister removes the instance serving lang.
+func (m *DictionaryManager) Unregister(lang string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.instances, lang)
+}
+
+// Get returns the instance for lang, falling back to the default language when
+// lang is empty, and counts the request against the resolved language.
+func (m *DictionaryManager) Get(lang string) (SymSpell, error) {
+	if lang == "" {
+		lang = m.defaultLang
+	}
+	m.mu.RLock()
+	dictionary, ok := m.instances[lang]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: no dictionary loaded for language %q", ErrDictionaryNotFound, lang)
+	}
+	dictionary.requests.Add(1)
+	return dictionary.symspell, nil
+}
+
+// Languages returns the registered languages in sorted order.
+func (m *DictionaryManager) Languages() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	langs := make([]string, 0, len(m.instances))
+	for lang := range m.instances {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// RequestCounts returns the number of requests routed to each language.
+func (m *DictionaryManager) RequestCounts() map[string]uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	counts := make(map[string]uint64, len(m.instances))
+	for lang, dictionary := range m.instances {
+		counts[lang] = dictionary.requests.Load()
+	}
+	return counts
+}
@@ -0,0 +1,250 @@
+// Package metrics exports Prometheus metrics for a SymSpell instance.
+//
+// Instrument wraps a SymSpell so that lookups are counted and timed:
+//
+//	spellChecker = metrics.Instrument(spellChecker, prometheus.DefaultRegisterer)
+//	http.Handle("/metrics", promhttp.Handler())
+package metrics
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+const namespace = "symspell"
+
+type instrumented struct {
+	symspell.SymSpell
+	lookups  *prometheus.CounterVec
+	distance prometheus.Histogram
+	duration *prometheus.HistogramVec
+}
+
+// Instrument registers the SymSpell metrics with reg and returns s wrapped so
+// that every lookup updates them. Single-word lookups, including those of
+// LookupBatch, LookupWithBoost and the context lookups, count towards
+// lookups_total; compound lookups, segmentations and Annotate are only timed. Registration panics on duplicate metrics,
+// so instrument a given registry only once.
+//
+// Exported metrics:
+//
+//	symspell_lookups_total{verbosity}        single-word lookups
+//	symspell_suggestion_distance             edit distance of the best suggestion
+//	symspell_lookup_duration_seconds{operation}
+//	symspell_cache_hits_total                hits of the Top lookup cache
+//	symspell_dictionary_words                words in the dictionary
+func Instrument(s symspell.SymSpell, reg prometheus.Registerer) symspell.SymSpell {
+	return instrument(s, reg, nil)
+}
+
+// InstrumentLanguage is Instrument for the instance serving lang, for
+// servers with one instance per language. Its metrics carry the label
+// lang="<lang>", so the instances of all languages can be instrumented with
+// the same registry, but not together with an instance wrapped by
+// Instrument.
+func InstrumentLanguage(s symspell.SymSpell, lang string, reg prometheus.Registerer) symspell.SymSpell {
+	return instrument(s, reg, prometheus.Labels{"lang": lang})
+}
+
+func instrument(s symspell.SymSpell, reg prometheus.Registerer, labels prometheus.Labels) symspell.SymSpell {
+	m := &instrumented{
+		SymSpell: s,
+		lookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "lookups_total",
+			Help:        "Number of single-word lookups by verbosity.",
+			ConstLabels: labels,
+		}, []string{"verbosity"}),
+		distance: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "suggestion_distance",
+			Help:        "Edit distance of the best suggestion returned by a lookup.",
+			ConstLabels: labels,
+			Buckets:     prometheus.LinearBuckets(0, 1, 5),
+		}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "lookup_duration_seconds",
+			Help:        "Lookup latency by operation.",
+			ConstLabels: labels,
+			Buckets:     prometheus.ExponentialBuckets(1e-6, 4, 10),
+		}, []string{"operation"}),
+	}
+	reg.MustRegister(
+		m.lookups,
+		m.distance,
+		m.duration,
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "cache_hits_total",
+			Help:        "Number of Top lookups answered from the cache.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(s.CacheStats().Hits) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "dictionary_words",
+			Help:        "Number of words in the dictionary.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(s.WordCount()) }),
+	)
+	return m
+}
+
+// Unwrap returns the instrumented instance.
+func (m *instrumented) Unwrap() symspell.SymSpell {
+	return m.SymSpell
+}
+
+func (m *instrumented) Lookup(phrase string, v verbosity.Verbosity, maxEditDistance int) ([]items.SuggestItem, error) {
+	start := time.Now()
+	suggestions, err := m.SymSpell.Lookup(phrase, v, maxEditDistance)
+	m.observeLookup(start, v, suggestions)
+	return suggestions, err
+}
+
+func (m *instrumented) LookupContext(ctx context.Context, phrase string, v verbosity.Verbosity, maxEditDistance int) ([]items.SuggestItem, error) {
+	start := time.Now()
+	suggestions, err := m.SymSpell.LookupContext(ctx, phrase, v, maxEditDistance)
+	m.observeLookup(start, v, suggestions)
+	return suggestions, err
+}
+
+func (m *instrumented) LookupWithOptions(phrase string, opts options.LookupOptions) ([]items.SuggestItem, error) {
+	start := time.Now()
+	suggestions, err := m.SymSpell.LookupWithOptions(phrase, opts)
+	m.observeLookup(start, opts.Verbosity, suggestions)
+	return suggestions, err
+}
+
+func (m *instrumented) LookupAppend(dst []items.SuggestItem, phrase string, v verbosity.Verbosity, maxEditDistance int) ([]items.SuggestItem, error) {
+	start := time.Now()
+	n := len(dst)
+	dst, err := m.SymSpell.LookupAppend(dst, phrase, v, maxEditDistance)
+	m.observeLookup(start, v, dst[min(n, len(dst)):])
+	return dst, err
+}
+
+// Suggestions times the lookup until the caller stops iterating, so the
+// duration includes the time spent by the loop body.
+func (m *instrumented) Suggestions(phrase string, v verbosity.Verbosity, maxEditDistance int) iter.Seq[items.SuggestItem] {
+	suggestions := m.SymSpell.Suggestions(phrase, v, maxEditDistance)
+	return func(yield func(items.SuggestItem) bool) {
+		start := time.Now()
+		var best []items.SuggestItem
+		defer func() { m.observeLookup(start, v, best) }()
+		for suggestion := range suggestions {
+			if best == nil {
+				best = []items.SuggestItem{suggestion}
+			}
+			if !yield(suggestion) {
+				return
+			}
+		}
+	}
+}
+
+func (m *instrumented) LookupBatch(terms []string, v verbosity.Verbosity, maxEditDistance int) ([][]items.SuggestItem, error) {
+	defer m.observeDuration("batch", time.Now())
+	results, err := m.SymSpell.LookupBatch(terms, v, maxEditDistance)
+	for _, suggestions := range results {
+		m.countLookup(v, suggestions)
+	}
+	return results, err
+}
+
+func (m *instrumented) LookupWithBoost(phrase string, v verbosity.Verbosity, maxEditDistance int, boostListName string) ([]items.SuggestItem, error) {
+	start := time.Now()
+	suggestions, err := m.SymSpell.LookupWithBoost(phrase, v, maxEditDistance, boostListName)
+	m.observeLookup(start, v, suggestions)
+	return suggestions, err
+}
+
+func (m *instrumented) LookupInContext(tokens []string, index int, maxEditDistance int) ([]items.SuggestItem, error) {
+	start := time.Now()
+	suggestions, err := m.SymSpell.LookupInContext(tokens, index, maxEditDistance)
+	m.observeLookup(start, verbosity.All, suggestions)
+	return suggestions, err
+}
+
+func (m *instrumented) LookupWithNeighbors(prev, word, next string, maxEditDistance int) ([]items.SuggestItem, error) {
+	start := time.Now()
+	suggestions, err := m.SymSpell.LookupWithNeighbors(prev, word, next, maxEditDistance)
+	m.observeLookup(start, verbosity.All, suggestions)
+	return suggestions, err
+}
+
+func (m *instrumented) LookupCompound(phrase string, maxEditDistance int) *items.SuggestItem {
+	defer m.observeDuration("compound", time.Now())
+	return m.SymSpell.LookupCompound(phrase, maxEditDistance)
+}
+
+func (m *instrumented) LookupCompoundDetailed(phrase string, maxEditDistance int) *items.CompoundResult {
+	defer m.observeDuration("compound", time.Now())
+	return m.SymSpell.LookupCompoundDetailed(phrase, maxEditDistance)
+}
+
+func (m *instrumented) LookupCompoundContext(ctx context.Context, phrase string, maxEditDistance int) (*items.CompoundResult, error) {
+	defer m.observeDuration("compound", time.Now())
+	return m.SymSpell.LookupCompoundContext(ctx, phrase, maxEditDistance)
+}
+
+func (m *instrumented) LookupCompoundNBest(phrase string, maxEditDistance, n int) []items.CompoundResult {
+	defer m.observeDuration("compound", time.Now())
+	return m.SymSpell.LookupCompoundNBest(phrase, maxEditDistance, n)
+}
+
+func (m *instrumented) WordSegmentation(phrase string, maxEditDistance, maxSegmentationWordLength int) (items.Composition, error) {
+	defer m.observeDuration("segmentation", time.Now())
+	return m.SymSpell.WordSegmentation(phrase, maxEditDistance, maxSegmentationWordLength)
+}
+
+func (m *instrumented) WordSegmentationContext(ctx context.Context, phrase string, maxEditDistance, maxSegmentationWordLength int) (items.Composition, error) {
+	defer m.observeDuration("segmentation", time.Now())
+	return m.SymSpell.WordSegmentationContext(ctx, phrase, maxEditDistance, maxSegmentationWordLength)
+}
+
+func (m *instrumented) WordSegmentationNBest(phrase string, maxEditDistance, maxSegmentationWordLength, n int) ([]items.Composition, error) {
+	defer m.observeDuration("segmentation", time.Now())
+	return m.SymSpell.WordSegmentationNBest(phrase, maxEditDistance, maxSegmentationWordLength, n)
+}
+
+func (m *instrumented) Annotate(text string, maxEditDistance int) ([]items.Annotation, error) {
+	defer m.observeDuration("annotate", time.Now())
+	return m.SymSpell.Annotate(text, maxEditDistance)
+}
+
+func (m *instrumented) observeLookup(start time.Time, v verbosity.Verbosity, suggestions []items.SuggestItem) {
+	m.observeDuration("lookup", start)
+	m.countLookup(v, suggestions)
+}
+
+func (m *instrumented) countLookup(v verbosity.Verbosity, suggestions []items.SuggestItem) {
+	m.lookups.WithLabelValues(verbosityLabel(v)).Inc()
+	if len(suggestions) > 0 {
+		m.distance.Observe(float64(suggestions[0].Distance))
+	}
+}
+
+func (m *instrumented) observeDuration(operation string, start time.Time) {
+	m.duration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+func verbosityLabel(v verbosity.Verbosity) string {
+	switch v {
+	case verbosity.Top:
+		return "top"
+	case verbosity.Closest:
+		return "closest"
+	case verbosity.All:
+		return "all"
+	}
+	return "unknown"
+}
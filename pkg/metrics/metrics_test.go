@@ -0,0 +1,119 @@
+package metrics_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/metrics"
+	"symspell/pkg/verbosity"
+)
+
+func TestInstrument(t *testing.T) {
+	s, err := symspell.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("hello", 100)
+	s.CreateDictionaryEntry("world", 100)
+
+	reg := prometheus.NewPedanticRegistry()
+	s = metrics.Instrument(s, reg)
+	for range 3 {
+		if _, err := s.Lookup("helo", verbosity.Top, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Lookup("wrld", verbosity.Closest, 2)
+	s.LookupCompound("helo wrld", 2)
+
+	expected := `
+# HELP symspell_cache_hits_total Number of Top lookups answered from the cache.
+# TYPE symspell_cache_hits_total counter
+symspell_cache_hits_total 3
+# HELP symspell_dictionary_words Number of words in the dictionary.
+# TYPE symspell_dictionary_words gauge
+symspell_dictionary_words 2
+# HELP symspell_lookups_total Number of single-word lookups by verbosity.
+# TYPE symspell_lookups_total counter
+symspell_lookups_total{verbosity="closest"} 1
+symspell_lookups_total{verbosity="top"} 3
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"symspell_cache_hits_total", "symspell_dictionary_words", "symspell_lookups_total"); err != nil {
+		t.Error(err)
+	}
+	if n := testutil.CollectAndCount(reg, "symspell_lookup_duration_seconds"); n != 2 {
+		t.Errorf("lookup_duration_seconds series = %d, want 2", n)
+	}
+}
+
+func TestInstrumentLanguage(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	instances := make(map[string]symspell.SymSpell)
+	for lang, word := range map[string]string{"en": "hello", "de": "hallo"} {
+		s, err := symspell.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.CreateDictionaryEntry(word, 100)
+		instances[lang] = metrics.InstrumentLanguage(s, lang, reg)
+	}
+	instances["en"].Lookup("helo", verbosity.Top, 2)
+	instances["de"].Lookup("halo", verbosity.Top, 2)
+	instances["de"].Lookup("hall", verbosity.Top, 2)
+
+	expected := `
+# HELP symspell_lookups_total Number of single-word lookups by verbosity.
+# TYPE symspell_lookups_total counter
+symspell_lookups_total{lang="de",verbosity="top"} 2
+symspell_lookups_total{lang="en",verbosity="top"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "symspell_lookups_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestInstrumentWrapsEveryLookup(t *testing.T) {
+	s, err := symspell.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("hello", 100)
+	s.CreateDictionaryEntry("world", 100)
+	if err := s.RegisterBoostList("greetings", []string{"hello"}, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	reg := prometheus.NewPedanticRegistry()
+	s = metrics.Instrument(s, reg)
+	s.LookupAppend(nil, "helo", verbosity.Top, 2)
+	for range s.Suggestions("helo", verbosity.Top, 2) {
+	}
+	s.LookupBatch([]string{"helo", "wrld"}, verbosity.Top, 2)
+	s.LookupWithBoost("helo", verbosity.Top, 2, "greetings")
+	s.LookupInContext([]string{"helo", "world"}, 0, 2)
+	s.LookupWithNeighbors("hello", "wrld", "", 2)
+	s.LookupCompoundContext(context.Background(), "helo wrld", 2)
+	s.LookupCompoundNBest("helo wrld", 2, 2)
+	s.WordSegmentationNBest("helloworld", 2, 10, 2)
+	s.Annotate("helo wrld", 2)
+
+	expected := `
+# HELP symspell_lookups_total Number of single-word lookups by verbosity.
+# TYPE symspell_lookups_total counter
+symspell_lookups_total{verbosity="all"} 2
+symspell_lookups_total{verbosity="top"} 5
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "symspell_lookups_total"); err != nil {
+		t.Error(err)
+	}
+	// lookup, batch, compound, segmentation and annotate
+	if n := testutil.CollectAndCount(reg, "symspell_lookup_duration_seconds"); n != 5 {
+		t.Errorf("lookup_duration_seconds series = %d, want 5", n)
+	}
+}
@@ -0,0 +1,177 @@
+package symspell
+
+import (
+	"strings"
+	"unicode"
+
+	"symspell/pkg/items"
+	"symspell/pkg/verbosity"
+)
+
+// LanguageDetector returns the language of a single token, or "" when the
+// token carries no hint, such as a number or punctuation.
+type LanguageDetector func(token string) string
+
+// Script maps a Unicode script to a language for ScriptDetector.
+type Script struct {
+	Table *unicode.RangeTable
+	Lang  string
+}
+
+// ScriptDetector detects the language of a token by its script: the token
+// gets the language of the script most of its letters belong to. A letter
+// counts for the first script that contains it, and of languages with as many
+// letters the one whose letter comes first in the token wins.
+//
+//	ScriptDetector(Script{unicode.Latin, "en"}, Script{unicode.Cyrillic, "ru"})
+func ScriptDetector(scripts ...Script) LanguageDetector {
+	return func(token string) string {
+		counts := make(map[string]int, len(scripts))
+		best, bestCount := "", 0
+		for _, r := range token {
+			if !unicode.IsLetter(r) {
+				continue
+			}
+			for _, script := range scripts {
+				if unicode.Is(script.Table, r) {
+					counts[script.Lang]++
+					if counts[script.Lang] > bestCount {
+						best, bestCount = script.Lang, counts[script.Lang]
+					}
+					break
+				}
+			}
+		}
+		return best
+	}
+}
+
+// MultiSymSpell corrects mixed-language text by routing every word to the
+// dictionary of its language. Languages are registered as with
+// DictionaryManager; words whose language is unknown or not registered go to
+// the default language.
+type MultiSymSpell struct {
+	*DictionaryManager
+	detect LanguageDetector
+}
+
+// NewMultiSymSpell returns a MultiSymSpell without registered languages that
+// routes words with detect and falls back to defaultLang.
+func NewMultiSymSpell(defaultLang string, detect LanguageDetector) *MultiSymSpell {
+	return &MultiSymSpell{DictionaryManager: NewDictionaryManager(defaultLang), detect: detect}
+}
+
+// Lookup looks up a single word in the dictionary of its language.
+func (m *MultiSymSpell) Lookup(phrase string, v verbosity.Verbosity, maxEditDistance int) ([]items.SuggestItem, error) {
+	s, err := m.Get(m.language(m.detect(phrase)))
+	if err != nil {
+		return nil, err
+	}
+	return s.Lookup(phrase, v, maxEditDistance)
+}
+
+// LookupCompound works like SymSpell.LookupCompound on mixed-language text.
+func (m *MultiSymSpell) LookupCompound(phrase string, maxEditDistance int) *items.SuggestItem {
+	result := m.LookupCompoundDetailed(phrase, maxEditDistance)
+	if result == nil {
+		return nil
+	}
+	return &result.Suggestion
+}
+
+// LookupCompoundDetailed splits phrase into runs of words of the same
+// language and corrects every run with LookupCompoundDetailed of that
+// language, so words are only merged and split within a run. Token offsets
+// refer to phrase. The suggestion joins the corrected runs, sums their
+// distances and takes the smallest count.
+func (m *MultiSymSpell) LookupCompoundDetailed(phrase string, maxEditDistance int) *items.CompoundResult {
+	var result items.CompoundResult
+	var terms []string
+	for _, run := range m.languageRuns(phrase) {
+		s, err := m.Get(run.lang)
+		if err != nil {
+			return nil
+		}
+		runResult := s.LookupCompoundDetailed(phrase[run.start:run.end], maxEditDistance)
+		if runResult == nil {
+			return nil
+		}
+		for _, token := range runResult.Tokens {
+			token.Start += run.start
+			token.End += run.start
+			result.Tokens = append(result.Tokens, token)
+		}
+		if len(terms) == 0 || runResult.Suggestion.Count < result.Suggestion.Count {
+			result.Suggestion.Count = runResult.Suggestion.Count
+		}
+		result.Suggestion.Distance += runResult.Suggestion.Distance
+		terms = append(terms, runResult.Suggestion.Term)
+	}
+	result.Suggestion.Term = strings.Join(terms, " ")
+	return &result
+}
+
+// languageRun is a byte range of the phrase whose words share a language.
+type languageRun struct {
+	lang       string
+	start, end int
+}
+
+// languageRuns groups the whitespace-separated fields of phrase by language.
+// Fields without a language hint join the run before them, or the first run
+// when they lead the phrase.
+func (m *MultiSymSpell) languageRuns(phrase string) []languageRun {
+	var runs []languageRun
+	pending := -1 // start of leading fields without a language
+	for start := 0; start < len(phrase); {
+		for start < len(phrase) && isSpace(phrase[start]) {
+			start++
+		}
+		end := start
+		for end < len(phrase) && !isSpace(phrase[end]) {
+			end++
+		}
+		if start == end {
+			break
+		}
+		lang := m.detect(phrase[start:end])
+		switch {
+		case lang == "" && len(runs) == 0:
+			if pending < 0 {
+				pending = start
+			}
+		case len(runs) > 0 && (lang == "" || m.language(lang) == runs[len(runs)-1].lang):
+			runs[len(runs)-1].end = end
+		default:
+			run := languageRun{lang: m.language(lang), start: start, end: end}
+			if pending >= 0 {
+				run.start, pending = pending, -1
+			}
+			runs = append(runs, run)
+		}
+		start = end
+	}
+	if pending >= 0 {
+		runs = append(runs, languageRun{lang: m.defaultLang, start: pending, end: len(strings.TrimRight(phrase, " \t\r\n"))})
+	}
+	return runs
+}
+
+// language resolves lang to a registered language, falling back to the
+// default language.
+func (m *MultiSymSpell) language(lang string) string {
+	if lang == "" {
+		return m.defaultLang
+	}
+	m.mu.RLock()
+	_, ok := m.instances[lang]
+	m.mu.RUnlock()
+	if !ok {
+		return m.defaultLang
+	}
+	return lang
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
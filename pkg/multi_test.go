@@ -0,0 +1,60 @@
+package symspell_test
+
+import (
+	"testing"
+	"unicode"
+
+	symspell "symspell/pkg"
+)
+
+func TestMultiSymSpellRoutesTokensByLanguage(t *testing.T) {
+	en, ru := symspell.NewSymSpell(), symspell.NewSymSpell()
+	for _, word := range []string{"hello", "world", "meeting"} {
+		en.CreateDictionaryEntry(word, 1000)
+	}
+	for _, word := range []string{"привет", "встреча", "завтра"} {
+		ru.CreateDictionaryEntry(word, 1000)
+	}
+	multi := symspell.NewMultiSymSpell("en", symspell.ScriptDetector(
+		symspell.Script{Table: unicode.Latin, Lang: "en"},
+		symspell.Script{Table: unicode.Cyrillic, Lang: "ru"},
+	))
+	multi.Register("en", en)
+	multi.Register("ru", ru)
+
+	phrase := "првет helo wrld, встрча завтра 10 meetng"
+	result := multi.LookupCompoundDetailed(phrase, 2)
+	if result == nil {
+		t.Fatal("LookupCompoundDetailed returned nil")
+	}
+	if want := "привет hello world встреча завтра 10 meeting"; result.Suggestion.Term != want {
+		t.Errorf("Suggestion.Term = %q, want %q", result.Suggestion.Term, want)
+	}
+	for _, token := range result.Tokens {
+		if got := phrase[token.Start:token.End]; got != token.Original {
+			t.Errorf("token %q has offsets of %q", token.Original, got)
+		}
+	}
+	if got := multi.RequestCounts(); got["en"] != 2 || got["ru"] != 2 {
+		t.Errorf("RequestCounts = %v, want two runs per language", got)
+	}
+}
+
+func TestScriptDetectorOrder(t *testing.T) {
+	detect := symspell.ScriptDetector(
+		symspell.Script{Table: unicode.Cyrillic, Lang: "ru"},
+		symspell.Script{Table: unicode.Latin, Lang: "en"},
+		symspell.Script{Table: unicode.Latin, Lang: "de"},
+	)
+	for i := 0; i < 20; i++ {
+		if got := detect("ab"); got != "en" {
+			t.Fatalf("detect(ab) = %q, want the first matching script", got)
+		}
+		if got := detect("abвг"); got != "en" {
+			t.Fatalf("detect(abвг) = %q, want the language reached first on a tie", got)
+		}
+		if got := detect("вгab"); got != "ru" {
+			t.Fatalf("detect(вгab) = %q, want the language reached first on a tie", got)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package symspell_test
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/channel"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+// channelFunc adapts a function to channel.Model.
+type channelFunc func(observed, candidate string) float64
+
+func (f channelFunc) LogProb(observed, candidate string) float64 { return f(observed, candidate) }
+
+func TestNoisyChannel(t *testing.T) {
+	newChannel := func(opts ...options.Options) symspell.SymSpell {
+		t.Helper()
+		s, err := symspell.New(opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.CreateDictionaryEntry("hate", 1000)
+		s.CreateDictionaryEntry("the", 500)
+		return s
+	}
+	top := func(s symspell.SymSpell) string {
+		t.Helper()
+		got, err := s.Lookup("hte", verbosity.Top, 1)
+		if err != nil || len(got) != 1 {
+			t.Fatalf("Lookup(hte) = %v, %v", got, err)
+		}
+		return got[0].Term
+	}
+
+	if got := top(newChannel()); got != "hate" {
+		t.Errorf("default Top = %q, want the more frequent hate", got)
+	}
+	// swapped letters are far more likely than a dropped one
+	rates := channel.EditRates{Insert: 0.01, Delete: 0.001, Substitute: 0.01, Transpose: 0.1}
+	if got := top(newChannel(options.WithNoisyChannel(rates))); got != "the" {
+		t.Errorf("Top with edit rates = %q, want the", got)
+	}
+	if got, want := rates.LogProb("hte", "the"), math.Log10(0.1); math.Abs(got-want) > 1e-9 {
+		t.Errorf("LogProb(hte, the) = %v, want %v", got, want)
+	}
+	if got := rates.LogProb("the", "the"); got != 0 {
+		t.Errorf("LogProb(the, the) = %v, want 0", got)
+	}
+
+	// any model plugs in
+	preferHate := channelFunc(func(observed, candidate string) float64 {
+		if candidate == "hate" {
+			return 0
+		}
+		return -10
+	})
+	s := newChannel(options.WithNoisyChannel(preferHate))
+	if got, _ := s.Lookup("hte", verbosity.All, 1); len(got) != 2 || got[0].Term != "hate" {
+		t.Errorf("All with a custom model = %v, want hate first", got)
+	}
+
+	if _, err := symspell.New(options.WithNoisyChannel(rates), options.WithLogProbRanking(1)); !errors.Is(err, symspell.ErrInvalidOptions) {
+		t.Errorf("New with two rankings: err = %v, want ErrInvalidOptions", err)
+	}
+	if _, err := options.FromJSON(strings.NewReader(`{"noisy_channel": {"transpose": 0.1}}`)); err != nil {
+		t.Errorf("FromJSON(noisy_channel) error = %v", err)
+	}
+	if _, err := options.FromJSON(strings.NewReader(`{"noisy_channel": {"insert": 2}}`)); err == nil {
+		t.Error("FromJSON accepted an insert rate of 2")
+	}
+}
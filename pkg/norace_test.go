@@ -0,0 +1,5 @@
+//go:build !race
+
+package symspell_test
+
+const raceEnabled = false
@@ -0,0 +1,80 @@
+package symspell_test
+
+import (
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestUnicodeNormalization(t *testing.T) {
+	s, err := symspell.New(options.WithUnicodeNormalization(options.NormalizationNFKC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("ёлка", 100)  // composed
+	s.CreateDictionaryEntry("ﬁle", 100)   // ligature
+	s.CreateDictionaryEntry("café", 100) // decomposed
+
+	tests := []struct{ input, want string }{
+		{"ёлка", "ёлка"},
+		{"file", "file"},
+		{"café", "café"},
+	}
+	for _, tt := range tests {
+		suggestions, err := s.Lookup(tt.input, verbosity.Top, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(suggestions) == 0 || suggestions[0].Term != tt.want || suggestions[0].Distance != 0 {
+			t.Errorf("Lookup(%q) = %v, want %q at distance 0", tt.input, suggestions, tt.want)
+		}
+	}
+}
+
+func TestNormalizedOffsets(t *testing.T) {
+	input := "ﬁle \xffcupp ﬂow"
+	for _, width := range []int{1, 3} {
+		s, err := symspell.New(
+			options.WithUnicodeNormalization(options.NormalizationNFKC),
+			options.WithInvalidUTF8Policy(options.InvalidUTF8Sanitize),
+			options.WithCompoundBeamWidth(width),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.CreateDictionaryEntry("file", 100)
+		s.CreateDictionaryEntry("cup", 100)
+		s.CreateDictionaryEntry("flow", 100)
+
+		result := s.LookupCompoundDetailed(input, 2)
+		if result == nil {
+			t.Fatalf("beam %d: LookupCompoundDetailed(%q) = nil", width, input)
+		}
+		wantOriginals := []string{"ﬁle", "cupp", "ﬂow"}
+		if len(result.Tokens) != len(wantOriginals) {
+			t.Fatalf("beam %d: LookupCompoundDetailed(%q).Tokens = %+v", width, input, result.Tokens)
+		}
+		for i, token := range result.Tokens {
+			if token.Original != wantOriginals[i] || input[token.Start:token.End] != token.Original {
+				t.Errorf("beam %d: token %d = %+v, want the span of %q in the input", width, i, token, wantOriginals[i])
+			}
+		}
+		for _, nbest := range s.LookupCompoundNBest(input, 2, 2) {
+			for _, token := range nbest.Tokens {
+				if input[token.Start:token.End] != token.Original {
+					t.Errorf("beam %d: LookupCompoundNBest token %+v does not slice the input", width, token)
+				}
+			}
+		}
+
+		annotations, err := s.Annotate(input, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(annotations) != 1 || annotations[0].Original != "cupp" || input[annotations[0].Start:annotations[0].End] != "cupp" {
+			t.Errorf("beam %d: Annotate(%q) = %+v, want one annotation of cupp", width, input, annotations)
+		}
+	}
+}
@@ -0,0 +1,441 @@
+package options
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+
+	"symspell/pkg/channel"
+	"symspell/pkg/phonetic"
+	"symspell/pkg/translit"
+)
+
+// Config is the file form of the options. Only keys present in the file
+// produce options, so everything else keeps its default. Options that take
+// functions, such as WithRanker or WithLogger, have no file form and can be
+// appended to the result of FromFile.
+//
+//	max_dictionary_edit_distance: 2
+//	prefix_length: 7
+//	frequency_threshold: 500
+//	ignore_tokens: [numbers, urls]
+//	dictionaries:
+//	  - path: en_full.txt
+//	    count_index: 1
+type Config struct {
+	MaxDictionaryEditDistance *int             `json:"max_dictionary_edit_distance" yaml:"max_dictionary_edit_distance"`
+	PrefixLength              *int             `json:"prefix_length" yaml:"prefix_length"`
+	CountThreshold            *int             `json:"count_threshold" yaml:"count_threshold"`
+	SplitItemThreshold        *int             `json:"split_item_threshold" yaml:"split_item_threshold"`
+	PreserveCase              *bool            `json:"preserve_case" yaml:"preserve_case"`
+	CaseFolding               *string          `json:"case_folding" yaml:"case_folding"` // тег языка, например tr
+	SplitWordBySpace          *bool            `json:"split_word_by_space" yaml:"split_word_by_space"`
+	SplitWordAndNumber        *bool            `json:"split_word_and_number" yaml:"split_word_and_number"`
+	MinimumCharacterToChange  *int             `json:"minimum_character_to_change" yaml:"minimum_character_to_change"`
+	FrequencyThreshold        *int             `json:"frequency_threshold" yaml:"frequency_threshold"`
+	FrequencyMultiplier       *int             `json:"frequency_multiplier" yaml:"frequency_multiplier"`
+	Phonetic                  *PhoneticConfig  `json:"phonetic" yaml:"phonetic"`
+	NoisyChannel              *ChannelConfig   `json:"noisy_channel" yaml:"noisy_channel"`
+	InvalidUTF8Policy         *string          `json:"invalid_utf8_policy" yaml:"invalid_utf8_policy"`     // pass_through, reject или sanitize
+	UnicodeNormalization      *string          `json:"unicode_normalization" yaml:"unicode_normalization"` // none, nfc или nfkc
+	CompoundWorkers           *int             `json:"compound_workers" yaml:"compound_workers"`
+	LookupWorkers             *int             `json:"lookup_workers" yaml:"lookup_workers"`
+	ParallelLookupMinLength   *int             `json:"parallel_lookup_min_length" yaml:"parallel_lookup_min_length"`
+	CompoundDistanceMemo      *int             `json:"compound_distance_memo" yaml:"compound_distance_memo"`
+	CompoundBeamWidth         *int             `json:"compound_beam_width" yaml:"compound_beam_width"`
+	CJKSegmentLength          *int             `json:"cjk_segment_length" yaml:"cjk_segment_length"` // 0 — длина по умолчанию
+	CompoundSplitting         *SplittingConfig `json:"compound_splitting" yaml:"compound_splitting"`
+	LookupCacheSize           *int             `json:"lookup_cache_size" yaml:"lookup_cache_size"` // 0 отключает кеш
+	Singleflight              *bool            `json:"singleflight" yaml:"singleflight"`
+	LookupCacheTTL            *string          `json:"lookup_cache_ttl" yaml:"lookup_cache_ttl"` // например, "5m"
+	LoadWorkers               *int             `json:"load_workers" yaml:"load_workers"`
+	HashedDeleteKeys          *bool            `json:"hashed_delete_keys" yaml:"hashed_delete_keys"`
+	LogProbEditPenalty        *float64         `json:"log_prob_edit_penalty" yaml:"log_prob_edit_penalty"` // включает WithLogProbRanking
+	DeleteKeyFilterBits       *int             `json:"delete_key_filter_bits" yaml:"delete_key_filter_bits"`
+	CompactStorage            *bool            `json:"compact_storage" yaml:"compact_storage"`
+	IndexBackend              *IndexBackend    `json:"index_backend" yaml:"index_backend"`     // map или trie
+	StorageBackend            *StorageBackend  `json:"storage_backend" yaml:"storage_backend"` // memory или disk
+	StoragePath               *string          `json:"storage_path" yaml:"storage_path"`
+	MaxLineLength             *int             `json:"max_line_length" yaml:"max_line_length"`
+	SuggestionBlacklist       []string         `json:"suggestion_blacklist" yaml:"suggestion_blacklist"`
+	TwoStage                  *TwoStageConfig  `json:"two_stage" yaml:"two_stage"`
+	EarlyStopCandidates       *int             `json:"early_stop_candidates" yaml:"early_stop_candidates"`
+	EarlyStopCount            *int             `json:"early_stop_count" yaml:"early_stop_count"`
+	ThreadSafe                *bool            `json:"thread_safe" yaml:"thread_safe"`
+	EditDistanceAlgorithm     *string          `json:"edit_distance_algorithm" yaml:"edit_distance_algorithm"`
+	IncludeUnknown            *bool            `json:"include_unknown" yaml:"include_unknown"`
+	ShortWordLength           *int             `json:"short_word_length" yaml:"short_word_length"`
+	ProtectedWords            []string         `json:"protected_words" yaml:"protected_words"`
+	IgnoreTokens              []string         `json:"ignore_tokens" yaml:"ignore_tokens"` // numbers, versions, urls, emails или регулярное выражение
+	LayoutSwitch              []string         `json:"layout_switch" yaml:"layout_switch"` // qwerty_to_jcuken или jcuken_to_qwerty
+	IgnoreDiacritics          *bool            `json:"ignore_diacritics" yaml:"ignore_diacritics"`
+	Transliteration           []string         `json:"transliteration" yaml:"transliteration"` // latin_to_cyrillic или cyrillic_to_latin
+	Dictionaries              []DictionaryFile `json:"dictionaries" yaml:"dictionaries"`
+	BigramDictionaries        []DictionaryFile `json:"bigram_dictionaries" yaml:"bigram_dictionaries"`
+	ExactDictionaries         []DictionaryFile `json:"exact_dictionaries" yaml:"exact_dictionaries"`
+}
+
+// PhoneticConfig enables the phonetic index, see WithPhoneticIndex.
+type PhoneticConfig struct {
+	Encoder   string  `json:"encoder" yaml:"encoder"` // double_metaphone (по умолчанию), soundex или russian
+	MaxLength int     `json:"max_length" yaml:"max_length"`
+	Weight    float64 `json:"weight" yaml:"weight"`
+}
+
+func (c PhoneticConfig) encoder() (phonetic.Encoder, error) {
+	switch c.Encoder {
+	case "", "double_metaphone":
+		return phonetic.NewDoubleMetaphone(c.MaxLength), nil
+	case "soundex":
+		return phonetic.NewSoundex(), nil
+	case "russian":
+		return phonetic.NewRussian(), nil
+	}
+	return nil, fmt.Errorf("unknown phonetic encoder %q, expected double_metaphone, soundex or russian", c.Encoder)
+}
+
+// SplittingConfig enables compound splitting, see WithCompoundSplitting.
+type SplittingConfig struct {
+	MinPartLength    int      `json:"min_part_length" yaml:"min_part_length"`
+	LinkingMorphemes []string `json:"linking_morphemes" yaml:"linking_morphemes"`
+}
+
+// ChannelConfig enables noisy-channel ranking with per-operation error rates,
+// see WithNoisyChannel. Rates left out are those of channel.DefaultEditRates.
+type ChannelConfig struct {
+	Insert     float64 `json:"insert" yaml:"insert"`
+	Delete     float64 `json:"delete" yaml:"delete"`
+	Substitute float64 `json:"substitute" yaml:"substitute"`
+	Transpose  float64 `json:"transpose" yaml:"transpose"`
+}
+
+func (c ChannelConfig) model() (channel.Model, error) {
+	rates := channel.DefaultEditRates
+	for _, r := range []struct {
+		name  string
+		value float64
+		rate  *float64
+	}{
+		{"insert", c.Insert, &rates.Insert},
+		{"delete", c.Delete, &rates.Delete},
+		{"substitute", c.Substitute, &rates.Substitute},
+		{"transpose", c.Transpose, &rates.Transpose},
+	} {
+		if r.value == 0 {
+			continue
+		}
+		if !(r.value > 0 && r.value <= 1) {
+			return nil, fmt.Errorf("noisy_channel: %s rate %v is not in (0, 1]", r.name, r.value)
+		}
+		*r.rate = r.value
+	}
+	return rates, nil
+}
+
+// TwoStageConfig enables two-stage lookups, see WithTwoStageLookup. The
+// coarse result is refined when it is empty or, with EscalateBelowCount, when
+// its best suggestion is rarer than that.
+type TwoStageConfig struct {
+	CoarseEditDistance int `json:"coarse_edit_distance" yaml:"coarse_edit_distance"`
+	EscalateBelowCount int `json:"escalate_below_count" yaml:"escalate_below_count"`
+}
+
+// FromFile reads options from a YAML (.yaml, .yml) or JSON (.json) file.
+func FromFile(path string) ([]Options, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var opts []Options
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		opts, err = FromYAML(file)
+	case ".json":
+		opts, err = FromJSON(file)
+	default:
+		return nil, fmt.Errorf("%s: unsupported config format %q", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return opts, nil
+}
+
+// FromJSON reads options from a JSON document. Unknown keys are an error.
+func FromJSON(r io.Reader) ([]Options, error) {
+	var config Config
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, err
+	}
+	return config.Options()
+}
+
+// FromYAML reads options from a YAML document. Unknown keys are an error.
+func FromYAML(r io.Reader) ([]Options, error) {
+	var config Config
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return config.Options()
+}
+
+// Options converts the config to options. Values are validated by
+// symspell.New; only names of enumerations are checked here.
+func (c Config) Options() ([]Options, error) {
+	var opts []Options
+	if c.MaxDictionaryEditDistance != nil {
+		opts = append(opts, WithMaxDictionaryEditDistance(*c.MaxDictionaryEditDistance))
+	}
+	if c.PrefixLength != nil {
+		opts = append(opts, WithPrefixLength(*c.PrefixLength))
+	}
+	if c.CountThreshold != nil {
+		opts = append(opts, WithCountThreshold(*c.CountThreshold))
+	}
+	if c.SplitItemThreshold != nil {
+		opts = append(opts, WithSplitItemThreshold(*c.SplitItemThreshold))
+	}
+	if c.MinimumCharacterToChange != nil {
+		opts = append(opts, WithMinimumCharacterToChange(*c.MinimumCharacterToChange))
+	}
+	if c.FrequencyThreshold != nil {
+		opts = append(opts, WithFrequencyThreshold(*c.FrequencyThreshold))
+	}
+	if c.FrequencyMultiplier != nil {
+		opts = append(opts, WithFrequencyMultiplier(*c.FrequencyMultiplier))
+	}
+	if c.CompoundWorkers != nil {
+		opts = append(opts, WithCompoundWorkers(*c.CompoundWorkers))
+	}
+	if c.LookupWorkers != nil || c.ParallelLookupMinLength != nil {
+		opts = append(opts, WithParallelLookup(deref(c.LookupWorkers), deref(c.ParallelLookupMinLength)))
+	}
+	if c.LookupCacheSize != nil {
+		opts = append(opts, WithLookupCache(*c.LookupCacheSize))
+	}
+	if c.CompoundDistanceMemo != nil {
+		opts = append(opts, WithCompoundDistanceMemo(*c.CompoundDistanceMemo))
+	}
+	if c.CompoundBeamWidth != nil {
+		opts = append(opts, WithCompoundBeamWidth(*c.CompoundBeamWidth))
+	}
+	if c.CJKSegmentLength != nil {
+		opts = append(opts, WithCJKSegmentation(*c.CJKSegmentLength))
+	}
+	if c.CompoundSplitting != nil {
+		opts = append(opts, WithCompoundSplitting(c.CompoundSplitting.MinPartLength, c.CompoundSplitting.LinkingMorphemes...))
+	}
+	if c.LoadWorkers != nil {
+		opts = append(opts, WithLoadWorkers(*c.LoadWorkers))
+	}
+	if c.LogProbEditPenalty != nil {
+		opts = append(opts, WithLogProbRanking(*c.LogProbEditPenalty))
+	}
+	if c.DeleteKeyFilterBits != nil {
+		opts = append(opts, WithDeleteKeyFilter(*c.DeleteKeyFilterBits))
+	}
+	if c.IndexBackend != nil {
+		opts = append(opts, WithIndexBackend(*c.IndexBackend))
+	}
+	if c.StorageBackend != nil {
+		opts = append(opts, WithStorageBackend(*c.StorageBackend, deref(c.StoragePath)))
+	}
+	if c.MaxLineLength != nil {
+		opts = append(opts, WithMaxLineLength(*c.MaxLineLength))
+	}
+	if c.EarlyStopCandidates != nil || c.EarlyStopCount != nil {
+		opts = append(opts, WithTopEarlyTermination(deref(c.EarlyStopCandidates), deref(c.EarlyStopCount)))
+	}
+	if c.ShortWordLength != nil {
+		opts = append(opts, WithShortWordLength(*c.ShortWordLength))
+	}
+	if c.EditDistanceAlgorithm != nil {
+		opts = append(opts, WithEditDistanceAlgorithm(*c.EditDistanceAlgorithm))
+	}
+	opts = appendFlag(opts, c.PreserveCase, WithPreserveCase)
+	opts = appendFlag(opts, c.SplitWordBySpace, WithSplitWordBySpace)
+	opts = appendFlag(opts, c.SplitWordAndNumber, WithSplitWordAndNumbers)
+	opts = appendFlag(opts, c.ThreadSafe, WithThreadSafe)
+	opts = appendFlag(opts, c.IncludeUnknown, WithIncludeUnknown)
+	opts = appendFlag(opts, c.IgnoreDiacritics, WithIgnoreDiacritics)
+	opts = appendFlag(opts, c.HashedDeleteKeys, WithHashedDeleteKeys)
+	opts = appendFlag(opts, c.CompactStorage, WithCompactStorage)
+	opts = appendFlag(opts, c.Singleflight, WithSingleflight)
+
+	if c.Phonetic != nil {
+		encoder, err := c.Phonetic.encoder()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithPhoneticIndex(encoder, c.Phonetic.Weight))
+	}
+	if c.NoisyChannel != nil {
+		model, err := c.NoisyChannel.model()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithNoisyChannel(model))
+	}
+	if c.InvalidUTF8Policy != nil {
+		policy, err := parseInvalidUTF8Policy(*c.InvalidUTF8Policy)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithInvalidUTF8Policy(policy))
+	}
+	if c.LookupCacheTTL != nil {
+		ttl, err := time.ParseDuration(*c.LookupCacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("lookup_cache_ttl: %w", err)
+		}
+		opts = append(opts, WithLookupCacheTTL(ttl))
+	}
+	if c.CaseFolding != nil {
+		locale, err := language.Parse(*c.CaseFolding)
+		if err != nil {
+			return nil, fmt.Errorf("case_folding: %w", err)
+		}
+		opts = append(opts, WithCaseFolding(locale))
+	}
+	if c.UnicodeNormalization != nil {
+		form, err := parseNormalizationForm(*c.UnicodeNormalization)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithUnicodeNormalization(form))
+	}
+	if c.TwoStage != nil {
+		policy := EscalateOnEmpty
+		if c.TwoStage.EscalateBelowCount > 0 {
+			policy = EscalateBelowCount(c.TwoStage.EscalateBelowCount)
+		}
+		opts = append(opts, WithTwoStageLookup(c.TwoStage.CoarseEditDistance, policy))
+	}
+	if len(c.SuggestionBlacklist) > 0 {
+		opts = append(opts, WithSuggestionBlacklist(c.SuggestionBlacklist))
+	}
+	if len(c.ProtectedWords) > 0 {
+		opts = append(opts, WithProtectedWords(c.ProtectedWords))
+	}
+	if len(c.IgnoreTokens) > 0 {
+		classifiers := make([]TokenClassifier, len(c.IgnoreTokens))
+		for i, name := range c.IgnoreTokens {
+			classifier, err := parseTokenClassifier(name)
+			if err != nil {
+				return nil, err
+			}
+			classifiers[i] = classifier
+		}
+		opts = append(opts, WithIgnoreTokens(classifiers...))
+	}
+	if len(c.LayoutSwitch) > 0 {
+		pairs := make([]LayoutPair, len(c.LayoutSwitch))
+		for i, name := range c.LayoutSwitch {
+			switch name {
+			case "qwerty_to_jcuken":
+				pairs[i] = LayoutQwertyToJcuken
+			case "jcuken_to_qwerty":
+				pairs[i] = LayoutQwertyToJcuken.Reverse()
+			default:
+				return nil, fmt.Errorf("unknown layout_switch %q, expected qwerty_to_jcuken or jcuken_to_qwerty", name)
+			}
+		}
+		opts = append(opts, WithLayoutSwitch(pairs...))
+	}
+	if len(c.Transliteration) > 0 {
+		transliterators := make([]translit.Transliterator, len(c.Transliteration))
+		for i, name := range c.Transliteration {
+			switch name {
+			case "latin_to_cyrillic":
+				transliterators[i] = translit.LatinToCyrillic
+			case "cyrillic_to_latin":
+				transliterators[i] = translit.CyrillicToLatin
+			default:
+				return nil, fmt.Errorf("unknown transliteration %q, expected latin_to_cyrillic or cyrillic_to_latin", name)
+			}
+		}
+		opts = append(opts, WithTransliteration(transliterators...))
+	}
+	for _, file := range c.Dictionaries {
+		opts = append(opts, WithDictionaryFile(file))
+	}
+	for _, file := range c.BigramDictionaries {
+		opts = append(opts, WithBigramDictionaryFile(file))
+	}
+	for _, file := range c.ExactDictionaries {
+		opts = append(opts, WithExactDictionaryFile(file))
+	}
+	return opts, nil
+}
+
+func appendFlag(opts []Options, value *bool, option func() Options) []Options {
+	if value != nil && *value {
+		return append(opts, option())
+	}
+	return opts
+}
+
+func deref[T any](value *T) T {
+	var zero T
+	if value == nil {
+		return zero
+	}
+	return *value
+}
+
+func parseInvalidUTF8Policy(name string) (InvalidUTF8Policy, error) {
+	switch name {
+	case "pass_through":
+		return InvalidUTF8PassThrough, nil
+	case "reject":
+		return InvalidUTF8Reject, nil
+	case "sanitize":
+		return InvalidUTF8Sanitize, nil
+	}
+	return 0, fmt.Errorf("unknown invalid_utf8_policy %q, expected pass_through, reject or sanitize", name)
+}
+
+func parseNormalizationForm(name string) (NormalizationForm, error) {
+	switch name {
+	case "none":
+		return NormalizationNone, nil
+	case "nfc":
+		return NormalizationNFC, nil
+	case "nfkc":
+		return NormalizationNFKC, nil
+	}
+	return 0, fmt.Errorf("unknown unicode_normalization %q, expected none, nfc or nfkc", name)
+}
+
+// parseTokenClassifier maps the names of the built-in classifiers; any other
+// value is compiled as a regular expression anchored to the whole token.
+func parseTokenClassifier(name string) (TokenClassifier, error) {
+	switch name {
+	case "numbers":
+		return IgnoreNumbers, nil
+	case "versions":
+		return IgnoreVersions, nil
+	case "urls":
+		return IgnoreURLs, nil
+	case "emails":
+		return IgnoreEmails, nil
+	}
+	re, err := regexp.Compile(`^(?:` + name + `)$`)
+	if err != nil {
+		return nil, fmt.Errorf("ignore_tokens: %w", err)
+	}
+	return IgnorePattern(re), nil
+}
@@ -1,5 +1,21 @@
 package options
 
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"golang.org/x/text/language"
+
+	"symspell/pkg/channel"
+	"symspell/pkg/editdistance"
+	"symspell/pkg/items"
+	"symspell/pkg/phonetic"
+	"symspell/pkg/translit"
+	"symspell/pkg/verbosity"
+)
+
 var DefaultOptions = SymspellOptions{
 	MaxDictionaryEditDistance: 2,
 	PrefixLength:              7,
@@ -11,6 +27,10 @@ var DefaultOptions = SymspellOptions{
 	MinimumCharacterToChange:  1,
 	FrequencyThreshold:        1000, // Новая опция: минимальная частота для точных совпадений
 	FrequencyMultiplier:       10,   // Во сколько раз должна быть больше частота альтернативы
+	MaxLineLength:             64 * 1024,
+	CoarseEditDistance:        1,
+	LookupCacheSize:           128,
+	EditDistanceAlgorithm:     editdistance.OptimalStringAlignment,
 }
 
 type SymspellOptions struct {
@@ -19,13 +39,206 @@ type SymspellOptions struct {
 	CountThreshold            int
 	SplitItemThreshold        int
 	PreserveCase              bool
+	CaseFolding               bool
+	CaseLocale                language.Tag // Правила регистра для CaseFolding
 	SplitWordBySpace          bool
 	SplitWordAndNumber        bool
 	MinimumCharacterToChange  int
 	FrequencyThreshold        int // Минимальная частота для принятия точного совпадения
 	FrequencyMultiplier       int // Во сколько раз альтернатива должна быть частотнее
+	ContextScorer             ContextScorer
+	PhoneticEncoder           phonetic.Encoder
+	PhoneticWeight            float64 // На сколько правок ближе считаются фонетические совпадения
+	InvalidUTF8Policy         InvalidUTF8Policy
+	UnicodeNormalization      NormalizationForm
+	CompoundWorkers           int           // Число горутин для параллельного LookupCompound
+	LookupWorkers             int           // Число горутин для перебора кандидатов одного Lookup
+	ParallelLookupMinLength   int           // Минимальная длина слова для параллельного Lookup
+	CompoundDistanceMemo      int           // Сколько пар слов кешировать расстояния в одном LookupCompound
+	CompoundBeamWidth         int           // Ширина луча LookupCompound, 0 и 1 — жадный разбор
+	CJKSegmentation           bool          // Разбирать китайский и японский текст без пробелов
+	CJKSegmentLength          int           // Максимальная длина слова CJK в символах, по умолчанию 4
+	CompoundSplitting         bool          // Принимать неизвестные слова, составленные из словарных
+	CompoundMinPartLength     int           // Минимальная длина части составного слова, по умолчанию 3
+	LinkingMorphemes          []string      // Соединительные элементы между частями, например "s"
+	LookupCacheSize           int           // Ёмкость LRU-кеша результатов Top, 0 отключает кеш
+	LookupCacheTTL            time.Duration // Время жизни записи кеша, 0 — без ограничения
+	Singleflight              bool          // Объединять одинаковые одновременные запросы Lookup
+	LoadWorkers               int           // Число горутин для разбора строк при загрузке словаря
+	HashedDeleteKeys          bool          // Хранить ключи удалений как 64-битные хеши
+	DeleteKeyFilterBits       int           // Бит фильтра Блума на ключ удаления, 0 отключает фильтр
+	CompactStorage            bool          // Хранить слова словаря в одном байтовом массиве
+	IndexBackend              IndexBackend
+	StorageBackend            StorageBackend
+	StoragePath               string // Файл базы данных для StorageDisk
+	MaxLineLength             int    // Максимальная длина строки при загрузке словарей, в байтах
+	SuggestionBlacklist       []string
+	EscalationPolicy          EscalationPolicy
+	CoarseEditDistance        int // Расстояние первого (грубого) прохода двухэтапного поиска
+	EarlyStopCandidates       int // Top: остановка после N кандидатов на лучшем расстоянии
+	EarlyStopCount            int // Top: остановка, когда частота лучшего варианта на расстоянии 1 достигла порога
+	ThreadSafe                bool
+	EditDistanceAlgorithm     string // Одна из констант editdistance
+	DistanceComparer          editdistance.IEditDistance
+	IncludeUnknown            bool // Возвращать исходное слово, если вариантов нет
+	ShortWordLength           int  // Слова короче этой длины ищутся с расстоянием не больше 1
+	SuggestionFilter          SuggestionFilter
+	Ranker                    Ranker
+	LogProbRanking            bool          // Ранжировать по log10(count/N) со штрафом за правки
+	EditPenalty               float64       // Штраф за одну правку в единицах log10 для LogProbRanking
+	ChannelModel              channel.Model // Модель ошибок для ранжирования noisy channel
+	LanguageModel             LanguageModel // Оценка последовательностей слов для LookupCompound и WordSegmentation
+	ProtectedWords            []string      // Слова, которые никогда не исправляются
+	IgnoreTokens              []TokenClassifier
+	Logger                    *slog.Logger // По умолчанию slog.Default()
+	LayoutPairs               []LayoutPair
+	Transliterators           []translit.Transliterator
+	IgnoreDiacritics          bool
+	Dictionaries              []DictionaryFile // Загружаются в symspell.New
+	BigramDictionaries        []DictionaryFile
+	ExactDictionaries         []DictionaryFile
+}
+
+// LayoutPair maps the characters of one keyboard layout to the characters on
+// the same keys of another: the i-th rune of From and of To share a key.
+type LayoutPair struct {
+	From string
+	To   string
+}
+
+// LayoutQwertyToJcuken converts text typed on the US QWERTY layout while the
+// Russian ЙЦУКЕН layout was meant, so "ghbdtn" becomes "привет".
+var LayoutQwertyToJcuken = LayoutPair{
+	From: "`qwertyuiop[]asdfghjkl;'zxcvbnm,.",
+	To:   "ёйцукенгшщзхъфывапролджэячсмитьбю",
+}
+
+// Reverse returns the pair converting in the opposite direction.
+func (p LayoutPair) Reverse() LayoutPair {
+	return LayoutPair{From: p.To, To: p.From}
+}
+
+// DictionaryFile describes a dictionary file loaded by symspell.New. Bigram
+// dictionaries read the two words at TermIndex and TermIndex+1 when Separator
+// is empty; exact dictionaries use only Path and Separator.
+type DictionaryFile struct {
+	Path       string          `json:"path" yaml:"path"`
+	TermIndex  int             `json:"term_index" yaml:"term_index"`
+	CountIndex int             `json:"count_index" yaml:"count_index"`
+	Separator  string          `json:"separator" yaml:"separator"`
+	Weight     float64         `json:"weight" yaml:"weight"` // Множитель частот, 0 означает 1
+	Format     FrequencyFormat `json:"format" yaml:"format"` // count, zipf, google_books или opensubtitles
+}
+
+// EscalationPolicy decides whether the result of the coarse pass of a
+// two-stage lookup should be refined with the full edit distance.
+type EscalationPolicy func(coarse []items.SuggestItem) bool
+
+// EscalateOnEmpty escalates only when the coarse pass found nothing.
+func EscalateOnEmpty(coarse []items.SuggestItem) bool {
+	return len(coarse) == 0
+}
+
+// EscalateBelowCount escalates when the coarse pass found nothing or its best
+// suggestion is rarer than minCount.
+func EscalateBelowCount(minCount int) EscalationPolicy {
+	return func(coarse []items.SuggestItem) bool {
+		return len(coarse) == 0 || coarse[0].Count < minCount
+	}
+}
+
+// TokenClassifier reports whether a token, such as a number, URL or email
+// address, must be passed through untouched instead of being corrected.
+type TokenClassifier func(token string) bool
+
+var (
+	reNumber  = regexp.MustCompile(`^[+-]?\d+(?:[.,:/-]\d+)*$`)
+	reVersion = regexp.MustCompile(`^[vV]?\d+(?:\.\d+)+(?:[-+][\w.-]+)?$`)
+	reURL     = regexp.MustCompile(`^(?i:[a-z][a-z\d+.-]*://|www\.)\S+$`)
+	reEmail   = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+)
+
+// IgnorePattern classifies tokens matched by re. Anchor re to match whole
+// tokens.
+func IgnorePattern(re *regexp.Regexp) TokenClassifier {
+	return re.MatchString
+}
+
+// IgnoreNumbers matches numbers, dates and times such as "42", "3.14",
+// "2024-01-01" and "12:30".
+func IgnoreNumbers(token string) bool {
+	return reNumber.MatchString(token)
+}
+
+// IgnoreVersions matches version strings such as "v1.2.3" and "2.0.1-rc1".
+func IgnoreVersions(token string) bool {
+	return reVersion.MatchString(token)
+}
+
+// IgnoreURLs matches URLs with a scheme and "www." host names.
+func IgnoreURLs(token string) bool {
+	return reURL.MatchString(token)
+}
+
+// IgnoreEmails matches email addresses.
+func IgnoreEmails(token string) bool {
+	return reEmail.MatchString(token)
+}
+
+// InvalidUTF8Policy controls how malformed UTF-8 input is handled.
+type InvalidUTF8Policy int
+
+const (
+	// InvalidUTF8PassThrough leaves the input untouched.
+	InvalidUTF8PassThrough InvalidUTF8Policy = iota
+	// InvalidUTF8Reject fails with an InvalidUTF8Error.
+	InvalidUTF8Reject
+	// InvalidUTF8Sanitize drops the malformed bytes.
+	InvalidUTF8Sanitize
+)
+
+// NormalizationForm is the Unicode normalization form applied to dictionary
+// words and lookup input.
+type NormalizationForm int
+
+const (
+	// NormalizationNone leaves strings as they are.
+	NormalizationNone NormalizationForm = iota
+	// NormalizationNFC composes characters: "е" + U+0308 becomes "ё".
+	NormalizationNFC
+	// NormalizationNFKC composes characters and also folds compatibility
+	// variants such as ligatures and full-width letters: "ﬁ" becomes "fi".
+	NormalizationNFKC
+)
+
+// ContextScorer is an optional language-model hook used by LookupInContext.
+// It returns a log10 score added to the candidate's bigram score.
+type ContextScorer func(left, term, right string) float64
+
+// LanguageModel scores word sequences for LookupCompound and WordSegmentation,
+// see WithLanguageModel. ScoreSequence returns the log10 probability of the
+// tokens; it is called with growing prefixes of a sentence, so a model backed
+// by n-grams may cache per prefix, or implement IncrementalLanguageModel.
+type LanguageModel interface {
+	ScoreSequence(tokens []string) float64
 }
 
+// IncrementalLanguageModel is a LanguageModel whose score of a sequence is the
+// sum of the scores of its tokens, as with n-gram models. ScoreNext returns
+// the log10 probability of token following prefix. Sentences are then scored
+// one token at a time instead of rescoring every prefix; prefix must not be
+// retained or modified.
+type IncrementalLanguageModel interface {
+	LanguageModel
+	ScoreNext(prefix []string, token string) float64
+}
+
+// SuggestionFilter decides whether a suggestion may be returned by lookups.
+type SuggestionFilter func(item items.SuggestItem) bool
+
+// Ranker reports whether suggestion a should be ranked before b.
+type Ranker func(a, b items.SuggestItem) bool
+
 type Options interface {
 	Apply(options *SymspellOptions)
 }
@@ -66,18 +279,34 @@ func WithSplitItemThreshold(splitThreshold int) Options {
 	})
 }
 
+// WithPreserveCase makes Lookup and LookupCompound look up the lowercased
+// input and transfer its letter case to the suggestions, so "Helllo" is
+// corrected to "Hello".
 func WithPreserveCase() Options {
 	return NewFuncOption(func(options *SymspellOptions) {
 		options.PreserveCase = true
 	})
 }
 
+// WithCaseFolding lowercases dictionary words and lookup input by the case
+// rules of locale, so Turkish "I" folds to "ı" and "İ" to "i", and restores
+// the letter case of the input on suggestions like WithPreserveCase.
+func WithCaseFolding(locale language.Tag) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.CaseFolding = true
+		options.CaseLocale = locale
+		options.PreserveCase = true
+	})
+}
+
 func WithSplitWordBySpace() Options {
 	return NewFuncOption(func(options *SymspellOptions) {
 		options.SplitWordBySpace = true
 	})
 }
 
+// WithMinimumCharacterToChange leaves words shorter than charLength runes
+// uncorrected: Lookup returns them as is with distance 0.
 func WithMinimumCharacterToChange(charLength int) Options {
 	return NewFuncOption(func(options *SymspellOptions) {
 		options.MinimumCharacterToChange = charLength
@@ -122,3 +351,563 @@ func WithLenientFrequencyCorrection() Options {
 		options.FrequencyMultiplier = 20
 	})
 }
+
+func WithContextScorer(scorer ContextScorer) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.ContextScorer = scorer
+	})
+}
+
+func WithPhoneticIndex(encoder phonetic.Encoder, weight float64) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.PhoneticEncoder = encoder
+		options.PhoneticWeight = weight
+	})
+}
+
+func WithInvalidUTF8Policy(policy InvalidUTF8Policy) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.InvalidUTF8Policy = policy
+	})
+}
+
+// WithUnicodeNormalization normalizes dictionary words when they are loaded
+// and lookup input before it is looked up, so that composed and decomposed
+// spellings of the same text are at distance 0.
+func WithUnicodeNormalization(form NormalizationForm) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.UnicodeNormalization = form
+	})
+}
+
+func WithCompoundWorkers(workers int) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.CompoundWorkers = workers
+	})
+}
+
+// WithParallelLookup probes the delete candidates of a single Lookup with
+// workers goroutines, for words of at least minLength runes at an edit
+// distance of 2 or more, where candidate expansion dominates the latency.
+// The candidates are generated up front and split into buckets by hash. It
+// is not used with WithTopEarlyTermination, and SuggestionFilter and Ranker
+// must be safe for concurrent use.
+func WithParallelLookup(workers, minLength int) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.LookupWorkers = workers
+		options.ParallelLookupMinLength = minLength
+	})
+}
+
+// WithLookupCache sets how many Top lookup results are kept in the LRU cache.
+// The default is 128.
+func WithLookupCache(size int) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.LookupCacheSize = size
+	})
+}
+
+// WithLookupCacheTTL expires cached Top lookup results ttl after they were
+// stored. Runtime changes to the dictionary clear the cache regardless.
+func WithLookupCacheTTL(ttl time.Duration) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.LookupCacheTTL = ttl
+	})
+}
+
+// WithSingleflight lets concurrent lookups with the same phrase, verbosity
+// and maximum edit distance share one computation, so a burst of requests for
+// the same misspelling costs a single candidate search.
+func WithSingleflight() Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.Singleflight = true
+	})
+}
+
+// WithoutLookupCache disables the Top lookup cache.
+func WithoutLookupCache() Options {
+	return WithLookupCache(0)
+}
+
+// WithCompoundDistanceMemo caches up to maxEntries edit distances within
+// each LookupCompound call, so the distances between recurring words and
+// suggestions of a long document are computed once. Lookups prefetched by
+// WithCompoundWorkers do not use the cache. Zero disables it.
+func WithCompoundDistanceMemo(maxEntries int) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.CompoundDistanceMemo = maxEntries
+	})
+}
+
+// WithCompoundBeamWidth makes LookupCompound keep the width best partial
+// corrections across word boundaries instead of committing to one per word,
+// and return the best whole sentence: the one with the fewest edits, and of
+// those the most probable by the language model if one is set or by unigram
+// and bigram counts otherwise. Widths 0 and 1 keep the greedy correction.
+func WithCompoundBeamWidth(width int) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.CompoundBeamWidth = width
+	})
+}
+
+// WithCJKSegmentation makes LookupCompound and WordSegmentation handle
+// Chinese and Japanese text, which is written without spaces. Runs of Han and
+// kana characters are segmented into dictionary words of at most
+// maxSegmentLength characters (4 if it is not positive), spaces between them
+// do not count as edits, and a word is corrected only by substituting
+// characters, at most one per two characters. LookupCompound writes the
+// corrected runs back without spaces.
+//
+// The dictionary is an ordinary frequency list with one word and its count
+// per line. Word lists of Chinese segmenters in the "word count tag" layout of
+// jieba's dict.txt load as they are with LoadDictionary(path, 0, 1, " "), as
+// columns after the count are ignored.
+func WithCJKSegmentation(maxSegmentLength int) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.CJKSegmentation = true
+		options.CJKSegmentLength = maxSegmentLength
+	})
+}
+
+// GermanLinkingMorphemes are the common linking elements (Fugenelemente) of
+// German compounds, as the "s" of "Arbeitszimmer".
+var GermanLinkingMorphemes = []string{"s", "es", "n", "en", "er", "e", "ens"}
+
+// NordicLinkingMorphemes are the common linking elements of Swedish, Danish
+// and Norwegian compounds, as the "s" of "arbetsdag".
+var NordicLinkingMorphemes = []string{"s", "e", "a", "u", "o"}
+
+// WithCompoundSplitting makes lookups accept an unknown word that splits into
+// dictionary words of at least minPartLength characters (3 if it is not
+// positive), optionally joined by one of linkingMorphemes, such as
+// "donaudampfschiff" into "donau", "dampf" and "schiff". If the lookup finds
+// no suggestion within edit distance 1, the word is returned as an exact match
+// instead; its count is the geometric mean of the counts of its parts.
+func WithCompoundSplitting(minPartLength int, linkingMorphemes ...string) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.CompoundSplitting = true
+		options.CompoundMinPartLength = minPartLength
+		options.LinkingMorphemes = linkingMorphemes
+	})
+}
+
+// WithLoadWorkers makes LoadDictionary parse lines on workers goroutines
+// while a single goroutine reads the input and another adds the words in
+// input order, so the result is the same as with a sequential load.
+func WithLoadWorkers(workers int) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.LoadWorkers = workers
+	})
+}
+
+// WithHashedDeleteKeys keys the deletes index by the 64-bit xxhash of each
+// delete instead of the delete string, which saves memory on large
+// dictionaries. Hash collisions are resolved by checking every candidate
+// against the suggestion, so results do not change. SaveIndex is not
+// supported with hashed keys; use SaveMappedIndex instead.
+func WithHashedDeleteKeys() Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.HashedDeleteKeys = true
+	})
+}
+
+// WithDeleteKeyFilter checks every delete candidate of a lookup against a
+// Bloom filter of the delete keys before probing the deletes index, using
+// bitsPerKey bits of memory per key; 10 bits reject about 98% of the missing
+// candidates. Most candidates of long words at edit distance 2 miss the
+// index, so this saves most of their probes. Results do not change. Zero
+// disables the filter.
+func WithDeleteKeyFilter(bitsPerKey int) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.DeleteKeyFilterBits = bitsPerKey
+	})
+}
+
+// WithCompactStorage stores the dictionary words in one byte arena instead of
+// one string per word. Words are referenced by offset and length and found
+// through a hash table of word indexes, so no string header is kept and
+// scanned by the garbage collector for every word of a large dictionary. The
+// words are packed when the index is built or loaded; an index loaded with
+// LoadMappedIndex keeps its words in the mapping.
+func WithCompactStorage() Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.CompactStorage = true
+	})
+}
+
+// IndexBackend is the data structure that holds the delete keys.
+type IndexBackend int
+
+const (
+	// IndexBackendMap keeps the delete keys in a Go map, the fastest option.
+	IndexBackendMap IndexBackend = iota
+	// IndexBackendTrie keeps the delete keys in a minimal acyclic automaton
+	// (DAWG) that shares their common prefixes and suffixes. Lookups are
+	// slower, but the index is much smaller when the delete space explodes,
+	// as for long words of agglutinative languages. Runtime additions are
+	// merged by rebuilding the automaton.
+	IndexBackendTrie
+)
+
+var indexBackendNames = []string{"map", "trie"}
+
+func (b IndexBackend) String() string {
+	if int(b) < len(indexBackendNames) {
+		return indexBackendNames[b]
+	}
+	return fmt.Sprintf("IndexBackend(%d)", int(b))
+}
+
+// UnmarshalText parses the backend names used in config files: map and trie.
+func (b *IndexBackend) UnmarshalText(text []byte) error {
+	for i, name := range indexBackendNames {
+		if string(text) == name {
+			*b = IndexBackend(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown index backend %q, expected map or trie", text)
+}
+
+// WithIndexBackend selects the data structure of the deletes index.
+func WithIndexBackend(backend IndexBackend) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.IndexBackend = backend
+	})
+}
+
+// StorageBackend is where the built deletes index is kept.
+type StorageBackend int
+
+const (
+	// StorageMemory keeps the deletes index on the heap.
+	StorageMemory StorageBackend = iota
+	// StorageDisk keeps the dictionary in a bbolt database: the words with
+	// their counts and the postings of every delete key. Lookups read the
+	// postings of their candidates from the database, so the deletes index
+	// never has to fit in memory, at the cost of slower lookups. Words
+	// added, updated or deleted at runtime are written through. An existing
+	// database is loaded at startup instead of the dictionary files; the
+	// words and counts are read into memory. Close the instance to release
+	// the database.
+	StorageDisk
+)
+
+var storageBackendNames = []string{"memory", "disk"}
+
+func (b StorageBackend) String() string {
+	if int(b) < len(storageBackendNames) {
+		return storageBackendNames[b]
+	}
+	return fmt.Sprintf("StorageBackend(%d)", int(b))
+}
+
+// UnmarshalText parses the backend names used in config files: memory and
+// disk.
+func (b *StorageBackend) UnmarshalText(text []byte) error {
+	for i, name := range storageBackendNames {
+		if string(text) == name {
+			*b = StorageBackend(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown storage backend %q, expected memory or disk", text)
+}
+
+// WithStorageBackend selects where the deletes index is kept. StorageDisk
+// needs the path of the database file; it is ignored for StorageMemory.
+func WithStorageBackend(backend StorageBackend, path string) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.StorageBackend = backend
+		options.StoragePath = path
+	})
+}
+
+func WithMaxLineLength(maxLineLength int) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.MaxLineLength = maxLineLength
+	})
+}
+
+func WithSuggestionBlacklist(words []string) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.SuggestionBlacklist = append(options.SuggestionBlacklist, words...)
+	})
+}
+
+// WithProtectedWords adds words that Lookup and LookupCompound always return
+// verbatim, even when a more frequent neighbour would replace them. Matching
+// ignores case.
+func WithProtectedWords(words []string) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.ProtectedWords = append(options.ProtectedWords, words...)
+	})
+}
+
+// WithIgnoreTokens makes Lookup and LookupCompound return tokens accepted by
+// any of the classifiers verbatim. LookupCompound applies them to whole
+// whitespace-separated fields, so "user@example.com" is kept as one token.
+func WithIgnoreTokens(classifiers ...TokenClassifier) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.IgnoreTokens = append(options.IgnoreTokens, classifiers...)
+	})
+}
+
+// WithLogger sets the logger for warnings such as a missing dictionary file
+// and for debug records about candidate processing and frequency overrides.
+func WithLogger(logger *slog.Logger) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.Logger = logger
+	})
+}
+
+// WithLayoutSwitch makes lookups of unknown words first try to convert them
+// through the layout pairs, in order, and return the converted word when it
+// is in the dictionary. Such suggestions have a distance of 0: the word was
+// typed correctly, only on the wrong layout. Edit-distance correction is used
+// when no conversion is a dictionary word.
+func WithLayoutSwitch(pairs ...LayoutPair) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.LayoutPairs = append(options.LayoutPairs, pairs...)
+	})
+}
+
+// WithTransliteration adds the transliterations of every looked up word as
+// an extra source of candidates: each is looked up with the same verbosity
+// and edit distance, and its suggestions are merged with those of the word
+// itself. Distances are measured from the transliterated word.
+func WithTransliteration(transliterators ...translit.Transliterator) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.Transliterators = append(options.Transliterators, transliterators...)
+	})
+}
+
+// WithIgnoreDiacritics makes lookups ignore combining marks, so "resume"
+// finds "résumé" and "uber" finds "über" at distance 0. Suggestions keep the
+// dictionary spelling; distances are measured between the accent-free forms.
+func WithIgnoreDiacritics() Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.IgnoreDiacritics = true
+	})
+}
+
+// WithDictionaryFile makes symspell.New load the dictionary file after the
+// instance is created. Files are loaded in the order they were added.
+func WithDictionaryFile(file DictionaryFile) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.Dictionaries = append(options.Dictionaries, file)
+	})
+}
+
+// WithBigramDictionaryFile makes symspell.New load the bigram dictionary
+// after all word dictionaries.
+func WithBigramDictionaryFile(file DictionaryFile) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.BigramDictionaries = append(options.BigramDictionaries, file)
+	})
+}
+
+// WithExactDictionaryFile makes symspell.New load the exact replacement
+// dictionary after all word dictionaries.
+func WithExactDictionaryFile(file DictionaryFile) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.ExactDictionaries = append(options.ExactDictionaries, file)
+	})
+}
+
+func WithTwoStageLookup(coarseEditDistance int, policy EscalationPolicy) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.CoarseEditDistance = coarseEditDistance
+		options.EscalationPolicy = policy
+	})
+}
+
+// WithTopEarlyTermination stops Top lookups after maxCandidatesAtBest
+// candidates at the current best distance, or once a distance-1 suggestion
+// with at least minCount occurrences is found. Zero disables either rule.
+func WithTopEarlyTermination(maxCandidatesAtBest, minCount int) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.EarlyStopCandidates = maxCandidatesAtBest
+		options.EarlyStopCount = minCount
+	})
+}
+
+// WithThreadSafe makes the instance returned by symspell.New safe for
+// concurrent lookups and dictionary updates.
+func WithThreadSafe() Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.ThreadSafe = true
+	})
+}
+
+// WithEditDistanceAlgorithm selects the metric used to verify candidates:
+// editdistance.Levenshtein, editdistance.OptimalStringAlignment (the default,
+// also named editdistance.DamerauLevenshtein) or
+// editdistance.UnrestrictedDamerauLevenshtein.
+func WithEditDistanceAlgorithm(algorithm string) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.EditDistanceAlgorithm = algorithm
+	})
+}
+
+// WithDistanceComparer replaces the built-in metric with cmp, overriding
+// WithEditDistanceAlgorithm. Candidates are still produced by the deletes
+// index, so cmp can only reorder or reject words reachable within the
+// maximum edit distance. DistanceMax must return a value greater than
+// maxDistance when the limit is exceeded.
+func WithDistanceComparer(cmp editdistance.IEditDistance) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.DistanceComparer = cmp
+	})
+}
+
+// WithIncludeUnknown makes Lookup return the input itself, with a distance of
+// maxEditDistance+1 and a count of 0, when no suggestion is found.
+func WithIncludeUnknown() Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.IncludeUnknown = true
+	})
+}
+
+// WithShortWordLength caps the edit distance of lookups at 1 for words shorter
+// than length runes, where two edits mostly produce unrelated words.
+func WithShortWordLength(length int) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.ShortWordLength = length
+	})
+}
+
+// WithSuggestionFilter drops every suggestion, including exact matches, for
+// which filter returns false. Like the blacklist it is applied while
+// candidates are collected, so Top and Closest fall back to the next best
+// accepted word.
+func WithSuggestionFilter(filter SuggestionFilter) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.SuggestionFilter = filter
+	})
+}
+
+// WithRanker replaces the default distance-then-count ordering of suggestions
+// in Lookup and of split candidates in LookupCompound. Candidates are still
+// collected by distance, so Top and Closest only rank suggestions at the
+// smallest distance found; use verbosity.All to let the ranker trade distance
+// for other signals.
+func WithRanker(ranker Ranker) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.Ranker = ranker
+	})
+}
+
+// WithLogProbRanking ranks suggestions by log10(count/N) minus editPenalty
+// per edit instead of by distance and then count, so that with a penalty of
+// 2 a word one edit farther wins if it is more than 100 times as frequent.
+// This suits dictionaries with very skewed frequencies. Top lookups of words
+// missing from the dictionary consider every suggestion within the maximum
+// edit distance, not only the closest ones; dictionary words are looked up as
+// without ranking. It cannot be combined with WithRanker, and user words get no
+// preference at equal scores.
+func WithLogProbRanking(editPenalty float64) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.LogProbRanking = true
+		options.EditPenalty = editPenalty
+	})
+}
+
+// WithNoisyChannel ranks suggestions by the noisy-channel score
+// log10 P(term) + log10 P(phrase | term): the source model P(term) is the
+// word frequency count/N and the channel model gives the probability of the
+// observed typo, for example channel.DefaultEditRates or a trained error
+// model. As with WithLogProbRanking, Top lookups of words missing from the
+// dictionary consider every suggestion within the maximum edit distance. It cannot be combined with WithRanker or
+// WithLogProbRanking.
+func WithNoisyChannel(model channel.Model) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.ChannelModel = model
+	})
+}
+
+// WithLanguageModel makes LookupCompound and WordSegmentation choose between
+// corrections of equal edit distance by the model's score of the whole
+// sentence, instead of the built-in unigram and bigram counts. LookupCompound
+// reports N·10^score as the count of its answer, and WordSegmentation reports
+// the score as LogProbSum.
+func WithLanguageModel(model LanguageModel) Options {
+	return NewFuncOption(func(options *SymspellOptions) {
+		options.LanguageModel = model
+	})
+}
+
+// LookupOptions overrides lookup behaviour for a single LookupWithOptions call.
+type LookupOptions struct {
+	Verbosity       verbosity.Verbosity
+	MaxEditDistance int
+	// MaxSuggestions limits the number of returned suggestions; 0 means no limit.
+	MaxSuggestions int
+	// DisableFrequencyGate turns off FrequencyThreshold and FrequencyMultiplier:
+	// exact matches are always kept and never replaced by more frequent words.
+	DisableFrequencyGate bool
+}
+
+// LoadOptions configures a single LoadDictionary call.
+type LoadOptions struct {
+	SourceWeight float64 // Множитель частот для этого источника
+	Format       FrequencyFormat
+}
+
+// FrequencyFormat is the layout of a frequency list read by LoadDictionary.
+type FrequencyFormat int
+
+const (
+	// FrequencyCount is the default "term count" list.
+	FrequencyCount FrequencyFormat = iota
+	// FrequencyZipf is a wordfreq list of "term zipf" lines. Zipf values are
+	// log10 of the frequency per billion words and become that frequency.
+	FrequencyZipf
+	// FrequencyGoogleBooks is a Google Books Ngram 1-gram file, in the
+	// per-year "ngram year match_count volume_count" layout or the
+	// "ngram year,match_count,volume_count ..." layout of newer releases.
+	// Counts are summed over the years and part-of-speech tags like "_NOUN"
+	// are stripped; termIndex, countIndex and separator are ignored.
+	FrequencyGoogleBooks
+	// FrequencyOpenSubtitles is an OpenSubtitles "term count" dump. Counts
+	// of terms listed more than once are summed.
+	FrequencyOpenSubtitles
+)
+
+var frequencyFormatNames = []string{"count", "zipf", "google_books", "opensubtitles"}
+
+func (f FrequencyFormat) String() string {
+	if int(f) < len(frequencyFormatNames) {
+		return frequencyFormatNames[f]
+	}
+	return fmt.Sprintf("FrequencyFormat(%d)", int(f))
+}
+
+// UnmarshalText parses the format names used in config files: count, zipf,
+// google_books and opensubtitles.
+func (f *FrequencyFormat) UnmarshalText(text []byte) error {
+	for i, name := range frequencyFormatNames {
+		if string(text) == name {
+			*f = FrequencyFormat(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown frequency format %q, expected count, zipf, google_books or opensubtitles", text)
+}
+
+type LoadOption func(options *LoadOptions)
+
+// WithFrequencyFormat sets the layout of the loaded frequency list.
+func WithFrequencyFormat(format FrequencyFormat) LoadOption {
+	return func(options *LoadOptions) {
+		options.Format = format
+	}
+}
+
+// WithDictionarySourceWeight multiplies every count of the loaded source by
+// weight before it is merged with previously loaded dictionaries.
+func WithDictionarySourceWeight(weight float64) LoadOption {
+	return func(options *LoadOptions) {
+		options.SourceWeight = weight
+	}
+}
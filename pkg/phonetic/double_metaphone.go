@@ -0,0 +1,563 @@
+package phonetic
+
+import "strings"
+
+// DoubleMetaphone implements Lawrence Philips' Double Metaphone algorithm for
+// English. Encode returns the primary code and, when it differs, the alternate.
+type DoubleMetaphone struct {
+	MaxLength int
+}
+
+func NewDoubleMetaphone(maxLength int) *DoubleMetaphone {
+	if maxLength <= 0 {
+		maxLength = 4
+	}
+	return &DoubleMetaphone{MaxLength: maxLength}
+}
+
+func (d DoubleMetaphone) Encode(word string) []string {
+	primary, alternate := d.Codes(word)
+	if primary == "" {
+		return nil
+	}
+	if alternate == "" || alternate == primary {
+		return []string{primary}
+	}
+	return []string{primary, alternate}
+}
+
+// Codes returns the primary and alternate Double Metaphone codes of word.
+func (d DoubleMetaphone) Codes(word string) (string, string) {
+	value := strings.ToUpper(strings.TrimSpace(word))
+	if value == "" {
+		return "", ""
+	}
+	m := &metaphone{value: value, maxLength: d.MaxLength}
+	m.slavoGermanic = strings.ContainsAny(value, "WK") || strings.Contains(value, "CZ") || strings.Contains(value, "WITZ")
+	return m.encode()
+}
+
+type metaphone struct {
+	value         string
+	maxLength     int
+	slavoGermanic bool
+	primary       strings.Builder
+	alternate     strings.Builder
+}
+
+func (m *metaphone) encode() (string, string) {
+	index := 0
+	if m.contains(0, 2, "GN", "KN", "PN", "WR", "PS") {
+		index = 1
+	}
+	if m.charAt(0) == 'X' {
+		m.add("S", "S")
+		index = 1
+	}
+	for !m.complete() && index < len(m.value) {
+		switch c := m.value[index]; c {
+		case 'A', 'E', 'I', 'O', 'U', 'Y':
+			if index == 0 {
+				m.add("A", "A")
+			}
+			index++
+		case 'B':
+			m.add("P", "P")
+			index = m.skipDouble(index, 'B')
+		case 'C':
+			index = m.handleC(index)
+		case 'D':
+			index = m.handleD(index)
+		case 'F':
+			m.add("F", "F")
+			index = m.skipDouble(index, 'F')
+		case 'G':
+			index = m.handleG(index)
+		case 'H':
+			if (index == 0 || m.isVowel(index-1)) && m.isVowel(index+1) {
+				m.add("H", "H")
+				index += 2
+			} else {
+				index++
+			}
+		case 'J':
+			index = m.handleJ(index)
+		case 'K':
+			m.add("K", "K")
+			index = m.skipDouble(index, 'K')
+		case 'L':
+			index = m.handleL(index)
+		case 'M':
+			m.add("M", "M")
+			if m.conditionM0(index) {
+				index += 2
+			} else {
+				index++
+			}
+		case 'N':
+			m.add("N", "N")
+			index = m.skipDouble(index, 'N')
+		case 'P':
+			if m.charAt(index+1) == 'H' {
+				m.add("F", "F")
+				index += 2
+			} else {
+				m.add("P", "P")
+				if m.contains(index+1, 1, "P", "B") {
+					index += 2
+				} else {
+					index++
+				}
+			}
+		case 'Q':
+			m.add("K", "K")
+			index = m.skipDouble(index, 'Q')
+		case 'R':
+			index = m.handleR(index)
+		case 'S':
+			index = m.handleS(index)
+		case 'T':
+			index = m.handleT(index)
+		case 'V':
+			m.add("F", "F")
+			index = m.skipDouble(index, 'V')
+		case 'W':
+			index = m.handleW(index)
+		case 'X':
+			index = m.handleX(index)
+		case 'Z':
+			index = m.handleZ(index)
+		default:
+			index++
+		}
+	}
+	return truncate(m.primary.String(), m.maxLength), truncate(m.alternate.String(), m.maxLength)
+}
+
+func truncate(code string, maxLength int) string {
+	if len(code) > maxLength {
+		return code[:maxLength]
+	}
+	return code
+}
+
+func (m *metaphone) handleC(index int) int {
+	switch {
+	case m.conditionC0(index):
+		m.add("K", "K")
+		return index + 2
+	case index == 0 && m.contains(index, 6, "CAESAR"):
+		m.add("S", "S")
+		return index + 2
+	case m.contains(index, 2, "CH"):
+		return m.handleCH(index)
+	case m.contains(index, 2, "CZ") && !m.contains(index-2, 4, "WICZ"):
+		m.add("S", "X")
+		return index + 2
+	case m.contains(index+1, 3, "CIA"):
+		m.add("X", "X")
+		return index + 3
+	case m.contains(index, 2, "CC") && !(index == 1 && m.charAt(0) == 'M'):
+		return m.handleCC(index)
+	case m.contains(index, 2, "CK", "CG", "CQ"):
+		m.add("K", "K")
+		return index + 2
+	case m.contains(index, 2, "CI", "CE", "CY"):
+		if m.contains(index, 3, "CIO", "CIE", "CIA") {
+			m.add("S", "X")
+		} else {
+			m.add("S", "S")
+		}
+		return index + 2
+	}
+	m.add("K", "K")
+	switch {
+	case m.contains(index+1, 2, " C", " Q", " G"):
+		return index + 3
+	case m.contains(index+1, 1, "C", "K", "Q") && !m.contains(index+1, 2, "CE", "CI"):
+		return index + 2
+	}
+	return index + 1
+}
+
+func (m *metaphone) handleCC(index int) int {
+	if m.contains(index+2, 1, "I", "E", "H") && !m.contains(index+2, 2, "HU") {
+		if (index == 1 && m.charAt(index-1) == 'A') || m.contains(index-1, 5, "UCCEE", "UCCES") {
+			m.add("KS", "KS")
+		} else {
+			m.add("X", "X")
+		}
+		return index + 3
+	}
+	m.add("K", "K")
+	return index + 2
+}
+
+func (m *metaphone) handleCH(index int) int {
+	switch {
+	case index > 0 && m.contains(index, 4, "CHAE"):
+		m.add("K", "X")
+	case m.conditionCH0(index), m.conditionCH1(index):
+		m.add("K", "K")
+	case index > 0:
+		if m.contains(0, 2, "MC") {
+			m.add("K", "K")
+		} else {
+			m.add("X", "K")
+		}
+	default:
+		m.add("X", "X")
+	}
+	return index + 2
+}
+
+func (m *metaphone) handleD(index int) int {
+	switch {
+	case m.contains(index, 2, "DG"):
+		if m.contains(index+2, 1, "I", "E", "Y") {
+			m.add("J", "J")
+			return index + 3
+		}
+		m.add("TK", "TK")
+		return index + 2
+	case m.contains(index, 2, "DT", "DD"):
+		m.add("T", "T")
+		return index + 2
+	}
+	m.add("T", "T")
+	return index + 1
+}
+
+func (m *metaphone) handleG(index int) int {
+	switch {
+	case m.charAt(index+1) == 'H':
+		return m.handleGH(index)
+	case m.charAt(index+1) == 'N':
+		if index == 1 && m.isVowel(0) && !m.slavoGermanic {
+			m.add("KN", "N")
+		} else if !m.contains(index+2, 2, "EY") && m.charAt(index+1) != 'Y' && !m.slavoGermanic {
+			m.add("N", "KN")
+		} else {
+			m.add("KN", "KN")
+		}
+		return index + 2
+	case m.contains(index+1, 2, "LI") && !m.slavoGermanic:
+		m.add("KL", "L")
+		return index + 2
+	case index == 0 && (m.charAt(index+1) == 'Y' ||
+		m.contains(index+1, 2, "ES", "EP", "EB", "EL", "EY", "IB", "IL", "IN", "IE", "EI", "ER")):
+		m.add("K", "J")
+		return index + 2
+	case (m.contains(index+1, 2, "ER") || m.charAt(index+1) == 'Y') &&
+		!m.contains(0, 6, "DANGER", "RANGER", "MANGER") &&
+		!m.contains(index-1, 1, "E", "I") &&
+		!m.contains(index-1, 3, "RGY", "OGY"):
+		m.add("K", "J")
+		return index + 2
+	case m.contains(index+1, 1, "E", "I", "Y") || m.contains(index-1, 4, "AGGI", "OGGI"):
+		if m.contains(0, 4, "VAN ", "VON ") || m.contains(0, 3, "SCH") || m.contains(index+1, 2, "ET") {
+			m.add("K", "K")
+		} else if m.contains(index+1, 3, "IER") {
+			m.add("J", "J")
+		} else {
+			m.add("J", "K")
+		}
+		return index + 2
+	case m.charAt(index+1) == 'G':
+		m.add("K", "K")
+		return index + 2
+	}
+	m.add("K", "K")
+	return index + 1
+}
+
+func (m *metaphone) handleGH(index int) int {
+	switch {
+	case index > 0 && !m.isVowel(index-1):
+		m.add("K", "K")
+	case index == 0:
+		if m.charAt(index+2) == 'I' {
+			m.add("J", "J")
+		} else {
+			m.add("K", "K")
+		}
+	case (index > 1 && m.contains(index-2, 1, "B", "H", "D")) ||
+		(index > 2 && m.contains(index-3, 1, "B", "H", "D")) ||
+		(index > 3 && m.contains(index-4, 1, "B", "H")):
+		// Parker's rule: "hugh"
+	default:
+		if index > 2 && m.charAt(index-1) == 'U' && m.contains(index-3, 1, "C", "G", "L", "R", "T") {
+			// "laugh", "cough", "rough", "tough"
+			m.add("F", "F")
+		} else if index > 0 && m.charAt(index-1) != 'I' {
+			m.add("K", "K")
+		}
+	}
+	return index + 2
+}
+
+func (m *metaphone) handleJ(index int) int {
+	if m.contains(index, 4, "JOSE") || m.contains(0, 4, "SAN ") {
+		if (index == 0 && m.charAt(index+4) == ' ') || len(m.value) == 4 || m.contains(0, 4, "SAN ") {
+			m.add("H", "H")
+		} else {
+			m.add("J", "H")
+		}
+		return index + 1
+	}
+	switch {
+	case index == 0:
+		m.add("J", "A")
+	case m.isVowel(index-1) && !m.slavoGermanic && (m.charAt(index+1) == 'A' || m.charAt(index+1) == 'O'):
+		m.add("J", "H")
+	case index == len(m.value)-1:
+		m.add("J", "")
+	case !m.contains(index+1, 1, "L", "T", "K", "S", "N", "M", "B", "Z") && !m.contains(index-1, 1, "S", "K", "L"):
+		m.add("J", "J")
+	}
+	return m.skipDouble(index, 'J')
+}
+
+func (m *metaphone) handleL(index int) int {
+	if m.charAt(index+1) != 'L' {
+		m.add("L", "L")
+		return index + 1
+	}
+	if m.conditionL0(index) {
+		m.add("L", "")
+	} else {
+		m.add("L", "L")
+	}
+	return index + 2
+}
+
+func (m *metaphone) handleR(index int) int {
+	if index == len(m.value)-1 && !m.slavoGermanic && m.contains(index-2, 2, "IE") && !m.contains(index-4, 2, "ME", "MA") {
+		m.add("", "R")
+	} else {
+		m.add("R", "R")
+	}
+	return m.skipDouble(index, 'R')
+}
+
+func (m *metaphone) handleS(index int) int {
+	switch {
+	case m.contains(index-1, 3, "ISL", "YSL"):
+		// "island", "isle", "carlisle"
+		return index + 1
+	case index == 0 && m.contains(index, 5, "SUGAR"):
+		m.add("X", "S")
+		return index + 1
+	case m.contains(index, 2, "SH"):
+		if m.contains(index+1, 4, "HEIM", "HOEK", "HOLM", "HOLZ") {
+			m.add("S", "S")
+		} else {
+			m.add("X", "X")
+		}
+		return index + 2
+	case m.contains(index, 3, "SIO", "SIA") || m.contains(index, 4, "SIAN"):
+		if m.slavoGermanic {
+			m.add("S", "S")
+		} else {
+			m.add("S", "X")
+		}
+		return index + 3
+	case (index == 0 && m.contains(index+1, 1, "M", "N", "L", "W")) || m.contains(index+1, 1, "Z"):
+		m.add("S", "X")
+		if m.contains(index+1, 1, "Z") {
+			return index + 2
+		}
+		return index + 1
+	case m.contains(index, 2, "SC"):
+		return m.handleSC(index)
+	}
+	if index == len(m.value)-1 && m.contains(index-2, 2, "AI", "OI") {
+		// French: "resnais", "artois"
+		m.add("", "S")
+	} else {
+		m.add("S", "S")
+	}
+	if m.contains(index+1, 1, "S", "Z") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (m *metaphone) handleSC(index int) int {
+	switch {
+	case m.charAt(index+2) == 'H':
+		if m.contains(index+3, 2, "OO", "ER", "EN", "UY", "ED", "EM") {
+			if m.contains(index+3, 2, "ER", "EN") {
+				m.add("X", "SK")
+			} else {
+				m.add("SK", "SK")
+			}
+		} else if index == 0 && !m.isVowel(3) && m.charAt(3) != 'W' {
+			m.add("X", "S")
+		} else {
+			m.add("X", "X")
+		}
+	case m.contains(index+2, 1, "I", "E", "Y"):
+		m.add("S", "S")
+	default:
+		m.add("SK", "SK")
+	}
+	return index + 3
+}
+
+func (m *metaphone) handleT(index int) int {
+	switch {
+	case m.contains(index, 4, "TION"), m.contains(index, 3, "TIA", "TCH"):
+		m.add("X", "X")
+		return index + 3
+	case m.contains(index, 2, "TH") || m.contains(index, 3, "TTH"):
+		if m.contains(index+2, 2, "OM", "AM") || m.contains(0, 4, "VAN ", "VON ") || m.contains(0, 3, "SCH") {
+			m.add("T", "T")
+		} else {
+			m.add("0", "T")
+		}
+		return index + 2
+	}
+	m.add("T", "T")
+	if m.contains(index+1, 1, "T", "D") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (m *metaphone) handleW(index int) int {
+	switch {
+	case m.contains(index, 2, "WR"):
+		m.add("R", "R")
+		return index + 2
+	case index == 0 && (m.isVowel(index+1) || m.contains(index, 2, "WH")):
+		if m.isVowel(index + 1) {
+			m.add("A", "F")
+		} else {
+			m.add("A", "A")
+		}
+	case (index == len(m.value)-1 && m.isVowel(index-1)) ||
+		m.contains(index-1, 5, "EWSKI", "EWSKY", "OWSKI", "OWSKY") ||
+		m.contains(0, 3, "SCH"):
+		m.add("", "F")
+	case m.contains(index, 4, "WICZ", "WITZ"):
+		m.add("TS", "FX")
+		return index + 4
+	}
+	return index + 1
+}
+
+func (m *metaphone) handleX(index int) int {
+	if index == 0 {
+		m.add("S", "S")
+		return index + 1
+	}
+	if !(index == len(m.value)-1 && (m.contains(index-3, 3, "IAU", "EAU") || m.contains(index-2, 2, "AU", "OU"))) {
+		m.add("KS", "KS")
+	}
+	if m.contains(index+1, 1, "C", "X") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (m *metaphone) handleZ(index int) int {
+	if m.charAt(index+1) == 'H' {
+		m.add("J", "J")
+		return index + 2
+	}
+	if m.contains(index+1, 2, "ZO", "ZI", "ZA") || (m.slavoGermanic && index > 0 && m.charAt(index-1) != 'T') {
+		m.add("S", "TS")
+	} else {
+		m.add("S", "S")
+	}
+	return m.skipDouble(index, 'Z')
+}
+
+func (m *metaphone) conditionC0(index int) bool {
+	if m.contains(index, 4, "CHIA") {
+		return true
+	}
+	if index <= 1 || m.isVowel(index-2) || !m.contains(index-1, 3, "ACH") {
+		return false
+	}
+	c := m.charAt(index + 2)
+	return (c != 'I' && c != 'E') || m.contains(index-2, 6, "BACHER", "MACHER")
+}
+
+func (m *metaphone) conditionCH0(index int) bool {
+	if index != 0 {
+		return false
+	}
+	if !m.contains(index+1, 5, "HARAC", "HARIS") && !m.contains(index+1, 3, "HOR", "HYM", "HIA", "HEM") {
+		return false
+	}
+	return !m.contains(0, 5, "CHORE")
+}
+
+func (m *metaphone) conditionCH1(index int) bool {
+	return m.contains(0, 4, "VAN ", "VON ") || m.contains(0, 3, "SCH") ||
+		m.contains(index-2, 6, "ORCHES", "ARCHIT", "ORCHID") ||
+		m.contains(index+2, 1, "T", "S") ||
+		((m.contains(index-1, 1, "A", "O", "U", "E") || index == 0) &&
+			(m.contains(index+2, 1, "L", "R", "N", "M", "B", "H", "F", "V", "W", " ") || index+1 == len(m.value)-1))
+}
+
+func (m *metaphone) conditionL0(index int) bool {
+	if index == len(m.value)-3 && m.contains(index-1, 4, "ILLO", "ILLA", "ALLE") {
+		return true
+	}
+	return (m.contains(len(m.value)-2, 2, "AS", "OS") || m.contains(len(m.value)-1, 1, "A", "O")) &&
+		m.contains(index-1, 4, "ALLE")
+}
+
+func (m *metaphone) conditionM0(index int) bool {
+	if m.charAt(index+1) == 'M' {
+		return true
+	}
+	return m.contains(index-1, 3, "UMB") && (index+1 == len(m.value)-1 || m.contains(index+2, 2, "ER"))
+}
+
+func (m *metaphone) add(primary, alternate string) {
+	m.primary.WriteString(primary)
+	m.alternate.WriteString(alternate)
+}
+
+func (m *metaphone) complete() bool {
+	return m.primary.Len() >= m.maxLength && m.alternate.Len() >= m.maxLength
+}
+
+func (m *metaphone) skipDouble(index int, c byte) int {
+	if m.charAt(index+1) == c {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (m *metaphone) charAt(index int) byte {
+	if index < 0 || index >= len(m.value) {
+		return 0
+	}
+	return m.value[index]
+}
+
+func (m *metaphone) isVowel(index int) bool {
+	switch m.charAt(index) {
+	case 'A', 'E', 'I', 'O', 'U', 'Y':
+		return true
+	}
+	return false
+}
+
+func (m *metaphone) contains(start, length int, criteria ...string) bool {
+	if start < 0 || start+length > len(m.value) {
+		return false
+	}
+	target := m.value[start : start+length]
+	for _, c := range criteria {
+		if target == c {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,7 @@
+package phonetic
+
+// Encoder maps a word to one or more phonetic codes. Words sharing a code are
+// considered to sound alike. An empty result means the word cannot be encoded.
+type Encoder interface {
+	Encode(word string) []string
+}
@@ -0,0 +1,62 @@
+package phonetic
+
+import "strings"
+
+// Russian encodes Russian words by their pronunciation, in the spirit of
+// Petrov's Russian Metaphone: unstressed vowels are reduced (о → а, е → и),
+// "тся"/"ться" become "ца", "жы"/"шы" are read as "жи"/"ши", voiced
+// consonants are devoiced at the end of a word and before voiceless ones,
+// soft and hard signs are dropped and double letters collapse. "малако" and
+// "молоко" both give "малака". Words without Cyrillic letters are not encoded.
+type Russian struct{}
+
+func NewRussian() Russian {
+	return Russian{}
+}
+
+var russianReplacer = strings.NewReplacer(
+	"ться", "ца", "тся", "ца", "тс", "ц", "дс", "ц",
+	"жы", "жи", "шы", "ши", "чя", "ча", "щя", "ща", "чю", "чу", "щю", "щу",
+	"йо", "и", "ио", "и", "йе", "и", "ие", "и",
+	"ь", "", "ъ", "",
+)
+
+var russianVowels = map[rune]rune{
+	'а': 'а', 'о': 'а', 'ы': 'а', 'я': 'а',
+	'е': 'и', 'ё': 'и', 'э': 'и', 'и': 'и',
+	'у': 'у', 'ю': 'у',
+}
+
+var russianDevoiced = map[rune]rune{
+	'б': 'п', 'в': 'ф', 'г': 'к', 'д': 'т', 'ж': 'ш', 'з': 'с',
+}
+
+func (Russian) Encode(word string) []string {
+	letters := make([]rune, 0, len(word))
+	for _, r := range strings.ToLower(word) {
+		if r >= 'а' && r <= 'я' || r == 'ё' {
+			letters = append(letters, r)
+		}
+	}
+	if len(letters) == 0 {
+		return nil
+	}
+	runes := []rune(russianReplacer.Replace(string(letters)))
+	code := make([]rune, 0, len(runes))
+	for i, r := range runes {
+		if v, ok := russianVowels[r]; ok {
+			r = v
+		} else if d, ok := russianDevoiced[r]; ok && (i == len(runes)-1 || isVoicelessRussian(runes[i+1])) {
+			r = d
+		}
+		if len(code) > 0 && code[len(code)-1] == r {
+			continue
+		}
+		code = append(code, r)
+	}
+	return []string{string(code)}
+}
+
+func isVoicelessRussian(r rune) bool {
+	return strings.ContainsRune("пфктшсхцчщ", r)
+}
@@ -0,0 +1,62 @@
+package phonetic
+
+import "strings"
+
+// Soundex implements American Soundex: the first letter of the word followed
+// by three digits for the consonants after it, "Robert" and "Rupert" both
+// giving R163. Only ASCII letters are encoded.
+type Soundex struct{}
+
+func NewSoundex() Soundex {
+	return Soundex{}
+}
+
+func (Soundex) Encode(word string) []string {
+	code := soundex(word)
+	if code == "" {
+		return nil
+	}
+	return []string{code}
+}
+
+var soundexDigits = [26]byte{
+	'0', '1', '2', '3', '0', '1', '2', '0', '0', '2', '2', '4', '5',
+	'5', '0', '1', '2', '6', '2', '3', '0', '1', '0', '2', '0', '2',
+}
+
+func soundex(word string) string {
+	var code [4]byte
+	n := 0
+	var last byte
+	for _, r := range strings.ToUpper(word) {
+		if r < 'A' || r > 'Z' {
+			continue
+		}
+		digit := soundexDigits[r-'A']
+		if n == 0 {
+			code[0] = byte(r)
+			n, last = 1, digit
+			continue
+		}
+		switch {
+		case r == 'H' || r == 'W':
+			// H and W do not separate letters with the same code
+		case digit == '0':
+			last = 0
+		case digit != last:
+			code[n] = digit
+			n++
+			last = digit
+		}
+		if n == len(code) {
+			break
+		}
+	}
+	if n == 0 {
+		return ""
+	}
+	for ; n < len(code); n++ {
+		code[n] = '0'
+	}
+	return string(code[:])
+}
@@ -0,0 +1,67 @@
+package symspell_test
+
+import (
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/options"
+	"symspell/pkg/phonetic"
+	"symspell/pkg/verbosity"
+)
+
+func TestPhoneticIndex(t *testing.T) {
+	s, err := symspell.New(options.WithPhoneticIndex(phonetic.NewDoubleMetaphone(4), 1.5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("phone", 100)
+	s.CreateDictionaryEntry("fore", 500)
+
+	suggestions, err := s.Lookup("fone", verbosity.Top, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suggestions) == 0 || suggestions[0].Term != "phone" {
+		t.Errorf("Lookup(fone) = %v, want phone", suggestions)
+	}
+}
+
+func TestSoundex(t *testing.T) {
+	for word, want := range map[string]string{
+		"Robert": "R163", "Rupert": "R163", "Tymczak": "T522",
+		"Pfister": "P236", "Ashcraft": "A261", "Lee": "L000",
+	} {
+		if got := phonetic.NewSoundex().Encode(word); len(got) != 1 || got[0] != want {
+			t.Errorf("Encode(%q) = %v, want %s", word, got, want)
+		}
+	}
+	if got := phonetic.NewSoundex().Encode("ёж"); got != nil {
+		t.Errorf("Encode(ёж) = %v, want nil", got)
+	}
+}
+
+func TestRussianPhonetic(t *testing.T) {
+	encoder := phonetic.NewRussian()
+	for _, pair := range [][2]string{
+		{"малако", "молоко"}, {"учица", "учиться"}, {"жырафф", "жираф"}, {"зуп", "зуб"}, {"лотка", "лодка"},
+	} {
+		a, b := encoder.Encode(pair[0]), encoder.Encode(pair[1])
+		if len(a) != 1 || len(b) != 1 || a[0] != b[0] {
+			t.Errorf("Encode(%q) = %v, Encode(%q) = %v, want equal codes", pair[0], a, pair[1], b)
+		}
+	}
+
+	s, err := symspell.New(options.WithPhoneticIndex(encoder, 1.5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("молоко", 100)
+	s.CreateDictionaryEntry("малина", 500)
+	suggestions, err := s.Lookup("малако", verbosity.Top, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suggestions) == 0 || suggestions[0].Term != "молоко" {
+		t.Errorf("Lookup(малако) = %v, want молоко", suggestions)
+	}
+}
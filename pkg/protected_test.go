@@ -0,0 +1,54 @@
+package symspell_test
+
+import (
+	"strings"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestProtectedWords(t *testing.T) {
+	s, err := symspell.New(options.WithProtectedWords([]string{"Thw"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("the", 100000)
+	s.CreateDictionaryEntry("thw", 5)
+	s.CreateDictionaryEntry("cat", 5000)
+	s.CreateDictionaryEntry("acme", 10)
+	if err := s.LoadProtectedWords(strings.NewReader("# brands\nAcmeX\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := s.Lookup("thw", verbosity.Top, 2); len(got) != 1 || got[0].Term != "thw" || got[0].Distance != 0 {
+		t.Errorf("Lookup(thw) = %v", got)
+	}
+	if got, _ := s.Lookup("AcmeX", verbosity.Closest, 2); len(got) != 1 || got[0].Term != "AcmeX" {
+		t.Errorf("Lookup(AcmeX) = %v", got)
+	}
+	if got := s.LookupCompound("thw cst acmex", 2); got == nil || got.Term != "thw cat acmex" {
+		t.Errorf("LookupCompound = %v", got)
+	}
+}
+
+func TestIgnoreTokens(t *testing.T) {
+	s, err := symspell.New(options.WithIgnoreTokens(options.IgnoreNumbers, options.IgnoreVersions, options.IgnoreURLs, options.IgnoreEmails))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, word := range []string{"user", "example", "com", "see", "at", "on", "v"} {
+		s.CreateDictionaryEntry(word, 1000)
+	}
+	if got, _ := s.Lookup("v1.2.3", verbosity.Top, 2); len(got) != 1 || got[0].Term != "v1.2.3" {
+		t.Errorf("Lookup(v1.2.3) = %v", got)
+	}
+	got := s.LookupCompoundDetailed("sea usr@exampel.com on 2024-01-01 at https://Example.com/a.", 2)
+	if got == nil || got.Suggestion.Term != "see usr@exampel.com on 2024-01-01 at https://Example.com/a" {
+		t.Fatalf("LookupCompoundDetailed = %v", got)
+	}
+	if token := got.Tokens[1]; token.Original != "usr@exampel.com" || token.Distance != 0 {
+		t.Errorf("email token = %+v", token)
+	}
+}
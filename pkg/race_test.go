@@ -0,0 +1,6 @@
+//go:build race
+
+package symspell_test
+
+// raceEnabled skips allocation counts, which the race detector inflates.
+const raceEnabled = true
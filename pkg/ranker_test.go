@@ -0,0 +1,67 @@
+package symspell_test
+
+import (
+	"slices"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/editdistance"
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+	"symspell/pkg/phonetic"
+	"symspell/pkg/verbosity"
+)
+
+func TestRankerOrdersLast(t *testing.T) {
+	byCount := func(a, b items.SuggestItem) bool { return a.Count > b.Count }
+	s, err := symspell.New(
+		options.WithPhoneticIndex(phonetic.NewDoubleMetaphone(4), 1.5),
+		options.WithRanker(byCount),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("phone", 100)
+	s.CreateDictionaryEntry("fore", 500)
+	s.CreateDictionaryEntry("fine", 50)
+
+	// phonetic matching alone ranks phone first, see TestPhoneticIndex
+	top, err := s.Lookup("fone", verbosity.Top, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != 1 || top[0].Term != "fore" {
+		t.Errorf("Top = %v, want fore", top)
+	}
+	all, err := s.Lookup("fone", verbosity.All, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.IsSortedFunc(all, func(a, b items.SuggestItem) int { return int(b.Count - a.Count) }) || len(all) < 2 {
+		t.Errorf("All = %v, want ordered by the Ranker", all)
+	}
+	if streamed := slices.Collect(s.Suggestions("fone", verbosity.All, 1)); !slices.Equal(streamed, all) {
+		t.Errorf("Suggestions = %v, want the ranked %v", streamed, all)
+	}
+}
+
+func TestRankerOverridesWeightedDistance(t *testing.T) {
+	// y and t are neighbours on the keyboard, so cat is the closer word
+	byTerm := func(a, b items.SuggestItem) bool { return a.Term < b.Term }
+	s, err := symspell.New(
+		options.WithDistanceComparer(editdistance.NewKeyboardDistance(0.5, editdistance.QWERTY)),
+		options.WithRanker(byTerm),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("cat", 10)
+	s.CreateDictionaryEntry("cap", 10)
+	got, err := s.Lookup("cay", verbosity.All, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Term != "cap" {
+		t.Errorf("All = %v, want the Ranker's order", got)
+	}
+}
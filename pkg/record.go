@@ -0,0 +1,107 @@
+package symspell
+
+import (
+	"strings"
+
+	"symspell/internal"
+	"symspell/pkg/verbosity"
+)
+
+// FieldMode selects how a record field is corrected.
+type FieldMode int
+
+const (
+	// FieldModeWord corrects the whole value as a single term (e.g. city names).
+	FieldModeWord FieldMode = iota
+	// FieldModeCompound corrects the value as free text with LookupCompound.
+	FieldModeCompound
+)
+
+// FieldPolicy describes the dictionary and correction settings of one field.
+type FieldPolicy struct {
+	SymSpell        SymSpell
+	Mode            FieldMode
+	MaxEditDistance int
+}
+
+// FieldCorrection reports the change applied to one field.
+type FieldCorrection struct {
+	Original  string
+	Corrected string
+	Distance  int
+}
+
+// RecordCorrector corrects structured records, applying the right dictionary
+// and policy to each field in one call. Fields without a policy are copied as is.
+type RecordCorrector struct {
+	fields map[string]FieldPolicy
+}
+
+func NewRecordCorrector() *RecordCorrector {
+	return &RecordCorrector{fields: make(map[string]FieldPolicy)}
+}
+
+// SetField registers the policy used for field.
+func (r *RecordCorrector) SetField(field string, policy FieldPolicy) {
+	r.fields[field] = policy
+}
+
+// Correct returns a corrected copy of record together with the corrections
+// that changed a value, keyed by field name.
+func (r *RecordCorrector) Correct(record map[string]string) (map[string]string, map[string]FieldCorrection, error) {
+	corrected := make(map[string]string, len(record))
+	changes := make(map[string]FieldCorrection)
+	for field, value := range record {
+		policy, ok := r.fields[field]
+		if !ok || policy.SymSpell == nil || value == "" {
+			corrected[field] = value
+			continue
+		}
+		result, distance, err := policy.correct(value)
+		if err != nil {
+			return nil, nil, err
+		}
+		corrected[field] = result
+		if result != value {
+			changes[field] = FieldCorrection{Original: value, Corrected: result, Distance: distance}
+		}
+	}
+	return corrected, changes, nil
+}
+
+// correct returns the corrected value with the letter case of value. In
+// compound mode MaxEditDistance applies to each corrected word: words
+// without a correction within it keep their original text, the others are
+// corrected, and the returned distance is the sum over the corrected words.
+func (p FieldPolicy) correct(value string) (string, int, error) {
+	switch p.Mode {
+	case FieldModeCompound:
+		result := p.SymSpell.LookupCompoundDetailed(value, p.MaxEditDistance)
+		if result == nil {
+			return value, 0, nil
+		}
+		var corrected strings.Builder
+		last, distance := 0, 0
+		for _, token := range result.Tokens {
+			corrected.WriteString(value[last:token.Start])
+			last = token.End
+			if token.Distance > p.MaxEditDistance {
+				corrected.WriteString(token.Original)
+				continue
+			}
+			corrected.WriteString(internal.TransferCasing(token.Original, token.Replacement))
+			distance += token.Distance
+		}
+		corrected.WriteString(value[last:])
+		return corrected.String(), distance, nil
+	default:
+		suggestions, err := p.SymSpell.Lookup(value, verbosity.Top, p.MaxEditDistance)
+		if err != nil {
+			return value, 0, err
+		}
+		if len(suggestions) == 0 {
+			return value, 0, nil
+		}
+		return internal.TransferCasing(value, suggestions[0].Term), suggestions[0].Distance, nil
+	}
+}
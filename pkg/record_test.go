@@ -0,0 +1,72 @@
+package symspell_test
+
+import (
+	"testing"
+
+	symspell "symspell/pkg"
+)
+
+func newRecordCorrector(t *testing.T) *symspell.RecordCorrector {
+	t.Helper()
+	cities, err := symspell.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cities.CreateDictionaryEntry("berlin", 100)
+	text, err := symspell.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, word := range []string{"hello", "world", "new", "york"} {
+		text.CreateDictionaryEntry(word, 100)
+	}
+	r := symspell.NewRecordCorrector()
+	r.SetField("city", symspell.FieldPolicy{SymSpell: cities, MaxEditDistance: 2})
+	r.SetField("note", symspell.FieldPolicy{SymSpell: text, Mode: symspell.FieldModeCompound, MaxEditDistance: 1})
+	return r
+}
+
+func TestRecordCorrectorKeepsCasing(t *testing.T) {
+	r := newRecordCorrector(t)
+	corrected, changes, err := r.Correct(map[string]string{
+		"city": "Berlni",
+		"note": "Helo WORLD",
+		"id":   "Helo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"city": "Berlin", "note": "Hello WORLD", "id": "Helo"}
+	for field, value := range want {
+		if corrected[field] != value {
+			t.Errorf("%s = %q, want %q", field, corrected[field], value)
+		}
+	}
+	if change := changes["note"]; change.Original != "Helo WORLD" || change.Distance != 1 {
+		t.Errorf("note change = %+v", change)
+	}
+	if _, found := changes["id"]; found {
+		t.Error("field without a policy was changed")
+	}
+}
+
+func TestRecordCorrectorLimitsDistancePerWord(t *testing.T) {
+	r := newRecordCorrector(t)
+	// every word is within the limit, although their sum is not
+	corrected, changes, err := r.Correct(map[string]string{"note": "helo wrld new yrk"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if corrected["note"] != "hello world new york" || changes["note"].Distance != 3 {
+		t.Errorf("note = %q, change %+v", corrected["note"], changes["note"])
+	}
+
+	// a word beyond the limit keeps its text, the others are corrected
+	corrected, _, err = r.Correct(map[string]string{"note": "helo xqzzv, world"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if corrected["note"] != "hello xqzzv, world" {
+		t.Errorf("note = %q, want %q", corrected["note"], "hello xqzzv, world")
+	}
+}
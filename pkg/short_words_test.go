@@ -0,0 +1,66 @@
+package symspell_test
+
+import (
+	"testing"
+
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestMinimumCharacterToChange(t *testing.T) {
+	s := newGoldenSymSpell(t, options.WithMinimumCharacterToChange(4))
+
+	got, err := s.Lookup("dom", verbosity.Top, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Term != "dom" || got[0].Distance != 0 || got[0].Count != 0 {
+		t.Errorf("Lookup(dom) = %v, want the unknown word unchanged", got)
+	}
+	got, err = s.Lookup("dog", verbosity.Top, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Term != "dog" || got[0].Count != 90000000 {
+		t.Errorf("Lookup(dog) = %v, want the dictionary count", got)
+	}
+	got, err = s.Lookup("hwrld", verbosity.Top, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Term != "world" {
+		t.Errorf("Lookup(hwrld) = %v, want world", got)
+	}
+}
+
+func TestLookupEmptyInput(t *testing.T) {
+	for _, opts := range [][]options.Options{nil, {options.WithMinimumCharacterToChange(4)}, {options.WithIncludeUnknown()}} {
+		got, err := newGoldenSymSpell(t, opts...).Lookup("", verbosity.All, 2)
+		if err != nil || len(got) != 0 {
+			t.Errorf("Lookup(\"\") = %v, %v, want no suggestions", got, err)
+		}
+	}
+}
+
+func TestShortWordLength(t *testing.T) {
+	s := newGoldenSymSpell(t, options.WithShortWordLength(5))
+
+	got, err := s.Lookup("hlp", verbosity.All, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, suggestion := range got {
+		if suggestion.Distance > 1 {
+			t.Errorf("Lookup(hlp) suggested %v farther than 1", suggestion)
+		}
+	}
+	if len(got) == 0 || got[0].Term != "help" {
+		t.Errorf("Lookup(hlp) = %v, want help first", got)
+	}
+	if got, _ := s.Lookup("wrlx", verbosity.Top, 2); len(got) != 0 {
+		t.Errorf("Lookup(wrlx) = %v, want no suggestion within distance 1", got)
+	}
+	if got, _ := s.Lookup("wurldd", verbosity.Top, 2); len(got) != 1 || got[0].Term != "world" || got[0].Distance != 2 {
+		t.Errorf("Lookup(wurldd) = %v, want world at distance 2", got)
+	}
+}
@@ -0,0 +1,45 @@
+package symspell_test
+
+import (
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestSkipStatsCountsEveryLookup(t *testing.T) {
+	s, err := symspell.New(options.WithMinimumCharacterToChange(3), options.WithFrequencyThreshold(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("hello", 100)
+	s.CreateDictionaryEntry("help", 50)
+
+	for _, tc := range []struct {
+		phrase          string
+		maxEditDistance int
+	}{
+		{"hi", 2},               // shorter than MinimumCharacterToChange
+		{"hello", 2},            // exact match
+		{"incomprehensibly", 2}, // longer than any dictionary word
+		{"helo", 0},             // no edits allowed
+		{"helo", 2},             // full candidate search
+	} {
+		if _, err := s.Lookup(tc.phrase, verbosity.Closest, tc.maxEditDistance); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := s.SkipStats()
+	if got.Lookups != 5 {
+		t.Errorf("SkipStats().Lookups = %d, want 5", got.Lookups)
+	}
+	if got.LengthDiff+got.AlreadyConsidered+got.DistanceCutoff+got.PrefixMismatch == 0 {
+		t.Errorf("SkipStats() = %+v, want skips of the full search", got)
+	}
+
+	s.ResetSkipStats()
+	if got := s.SkipStats(); got.Lookups != 0 {
+		t.Errorf("SkipStats().Lookups after reset = %d, want 0", got.Lookups)
+	}
+}
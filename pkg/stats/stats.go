@@ -0,0 +1,41 @@
+package stats
+
+// CompactStats reports the outcome of an index compaction.
+type CompactStats struct {
+	WordsRemoved    int
+	PostingsBefore  int
+	PostingsAfter   int
+	DeleteKeysFreed int
+	ReclaimedBytes  int
+}
+
+// SkipStats counts why candidate suggestions were discarded during lookups.
+type SkipStats struct {
+	Lookups           uint64
+	LengthDiff        uint64
+	AlreadyConsidered uint64
+	DistanceCutoff    uint64
+	PrefixMismatch    uint64
+}
+
+// IndexStats describes the size of a dictionary and its deletes index.
+type IndexStats struct {
+	Words            int
+	DeleteKeys       int
+	Postings         int // length of DeletesData
+	DeltaPostings    int // postings of runtime additions not yet merged
+	MaxWordLength    int // in bytes
+	TopCacheSize     int
+	TopCacheCapacity int
+	// EstimatedBytes approximates the heap used by the dictionary and index,
+	// including map overhead; caches and bigrams are not counted.
+	EstimatedBytes int
+}
+
+// CacheStats reports the use of the Top lookup cache since creation.
+type CacheStats struct {
+	Hits     uint64
+	Misses   uint64
+	Size     int
+	Capacity int
+}
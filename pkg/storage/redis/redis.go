@@ -0,0 +1,231 @@
+// Package redis implements storage.Backend on Redis, so that replicas in
+// different processes share one dictionary. The words and counts are kept in
+// the hash <prefix>:words and every update appends the word to the list
+// <prefix>:log. The list keeps the last MaxLogLength updates; the number of
+// updates trimmed off its head is kept in <prefix>:trimmed, so that the
+// version of the dictionary is that number plus the length of the list.
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"symspell/pkg/storage"
+)
+
+// Config configures the connection to Redis.
+type Config struct {
+	Addr     string        // host:port, "localhost:6379" if empty
+	Password string        // sent with AUTH if not empty
+	DB       int           // selected with SELECT if not zero
+	Prefix   string        // key prefix, "symspell" if empty
+	Timeout  time.Duration // per command when ctx has no deadline, 5s if zero
+	// MaxLogLength is the number of updates kept in the change log, 100000
+	// if zero. A replica that falls further behind reloads a snapshot.
+	MaxLogLength int64
+}
+
+// Store is a storage.Backend on a pool of Redis connections. It is safe for
+// concurrent use.
+type Store struct {
+	client  *goredis.Client
+	words   string
+	log     string
+	trimmed string
+	maxLog  int64
+}
+
+var _ storage.Backend = (*Store)(nil)
+
+// updateScript applies one update to the words hash, appends the word to the
+// log and trims the log to ARGV[4] entries, all atomically. It returns the
+// new count as a string, or false if the word was deleted.
+var updateScript = goredis.NewScript(`
+if ARGV[1] == 'add' then
+	redis.call('HINCRBY', KEYS[1], ARGV[2], ARGV[3])
+elseif ARGV[1] == 'set' then
+	redis.call('HSET', KEYS[1], ARGV[2], ARGV[3])
+else
+	redis.call('HDEL', KEYS[1], ARGV[2])
+end
+local excess = redis.call('RPUSH', KEYS[2], ARGV[2]) - tonumber(ARGV[4])
+if excess > 0 then
+	redis.call('LTRIM', KEYS[2], excess, -1)
+	redis.call('INCRBY', KEYS[3], excess)
+end
+return redis.call('HGET', KEYS[1], ARGV[2])
+`)
+
+// changesScript returns the number of trimmed updates and the logged words
+// after version ARGV[1], or no words if they were trimmed.
+var changesScript = goredis.NewScript(`
+local trimmed = tonumber(redis.call('GET', KEYS[2]) or '0')
+local start = tonumber(ARGV[1]) - trimmed
+if start < 0 then
+	return {trimmed, {}}
+end
+return {trimmed, redis.call('LRANGE', KEYS[1], start, -1)}
+`)
+
+// New returns a Store for cfg. It does not connect yet.
+func New(cfg Config) *Store {
+	if cfg.Addr == "" {
+		cfg.Addr = "localhost:6379"
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = "symspell"
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if cfg.MaxLogLength <= 0 {
+		cfg.MaxLogLength = 100000
+	}
+	client := goredis.NewClient(&goredis.Options{
+		Addr:         cfg.Addr,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		DialTimeout:  cfg.Timeout,
+		ReadTimeout:  cfg.Timeout,
+		WriteTimeout: cfg.Timeout,
+	})
+	return &Store{
+		client:  client,
+		words:   cfg.Prefix + ":words",
+		log:     cfg.Prefix + ":log",
+		trimmed: cfg.Prefix + ":trimmed",
+		maxLog:  cfg.MaxLogLength,
+	}
+}
+
+// Close closes the connections.
+func (s *Store) Close() error {
+	return s.client.Close()
+}
+
+func (s *Store) Snapshot(ctx context.Context) (map[string]uint64, uint64, error) {
+	var trimmed *goredis.StringCmd
+	var length *goredis.IntCmd
+	var fields *goredis.MapStringStringCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		trimmed = pipe.Get(ctx, s.trimmed)
+		length = pipe.LLen(ctx, s.log)
+		fields = pipe.HGetAll(ctx, s.words)
+		return nil
+	})
+	if err != nil && !errors.Is(err, goredis.Nil) {
+		return nil, 0, fmt.Errorf("redis: %w", err)
+	}
+	words := make(map[string]uint64, len(fields.Val()))
+	for term, value := range fields.Val() {
+		count, err := parseCount(value)
+		if err != nil {
+			return nil, 0, err
+		}
+		words[term] = count
+	}
+	return words, trimmedCount(trimmed) + uint64(length.Val()), nil
+}
+
+func (s *Store) Get(ctx context.Context, term string) (uint64, bool, error) {
+	value, err := s.client.HGet(ctx, s.words, term).Result()
+	if errors.Is(err, goredis.Nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("redis: %w", err)
+	}
+	count, err := parseCount(value)
+	return count, err == nil, err
+}
+
+func (s *Store) Add(ctx context.Context, term string, delta uint64) (uint64, error) {
+	value, err := s.update(ctx, "add", term, min(delta, math.MaxInt64))
+	if err != nil {
+		return 0, err
+	}
+	return parseCount(value)
+}
+
+func (s *Store) Set(ctx context.Context, term string, count uint64) error {
+	_, err := s.update(ctx, "set", term, min(count, math.MaxInt64))
+	return err
+}
+
+func (s *Store) Delete(ctx context.Context, term string) error {
+	_, err := s.update(ctx, "del", term, 0)
+	return err
+}
+
+func (s *Store) update(ctx context.Context, op, term string, value uint64) (string, error) {
+	keys := []string{s.words, s.log, s.trimmed}
+	result, err := updateScript.Run(ctx, s.client, keys, op, term, value, s.maxLog).Text()
+	if err != nil && !errors.Is(err, goredis.Nil) {
+		return "", fmt.Errorf("redis: %w", err)
+	}
+	return result, nil
+}
+
+func (s *Store) Changes(ctx context.Context, version uint64) ([]storage.Change, uint64, error) {
+	reply, err := changesScript.Run(ctx, s.client, []string{s.log, s.trimmed}, version).Slice()
+	if err != nil {
+		return nil, 0, fmt.Errorf("redis: %w", err)
+	}
+	if len(reply) != 2 {
+		return nil, 0, errors.New("redis: unexpected change log reply")
+	}
+	trimmed, _ := reply[0].(int64)
+	if version < uint64(trimmed) {
+		return nil, 0, storage.ErrLogTrimmed
+	}
+	logged, _ := reply[1].([]any)
+	if len(logged) == 0 {
+		return nil, version, nil
+	}
+	seen := make(map[string]struct{}, len(logged))
+	terms := make([]string, 0, len(logged))
+	for _, v := range logged {
+		term, _ := v.(string)
+		if _, ok := seen[term]; !ok {
+			seen[term] = struct{}{}
+			terms = append(terms, term)
+		}
+	}
+	counts, err := s.client.HMGet(ctx, s.words, terms...).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("redis: %w", err)
+	}
+	changes := make([]storage.Change, len(terms))
+	for i, term := range terms {
+		changes[i].Term = term
+		value, ok := counts[i].(string)
+		if !ok {
+			continue
+		}
+		if changes[i].Count, err = parseCount(value); err != nil {
+			return nil, 0, err
+		}
+	}
+	return changes, version + uint64(len(logged)), nil
+}
+
+func trimmedCount(cmd *goredis.StringCmd) uint64 {
+	n, _ := cmd.Uint64()
+	return n
+}
+
+func parseCount(value string) (uint64, error) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("redis: invalid count %q", value)
+	}
+	// Redis integers are signed, so counts stored in Redis saturate at
+	// math.MaxInt64
+	return uint64(max(n, 0)), nil
+}
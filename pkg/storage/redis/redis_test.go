@@ -0,0 +1,95 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"symspell/pkg/storage"
+	"symspell/pkg/storage/redis"
+	"symspell/pkg/verbosity"
+)
+
+func TestStoreSharesDictionary(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	store := redis.New(redis.Config{Addr: server.Addr()})
+	defer store.Close()
+	if err := store.Set(ctx, "hello", 100); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := storage.NewReplica(ctx, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := redis.New(redis.Config{Addr: server.Addr()})
+	defer other.Close()
+	b, err := storage.NewReplica(ctx, other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count, ok := a.SymSpell.WordFrequency("hello"); !ok || count != 100 {
+		t.Fatalf("snapshot count = %d, %v, want 100", count, ok)
+	}
+
+	if _, err := a.AddWord("wörld", 3); err != nil {
+		t.Fatal(err)
+	}
+	a.IncrementCount("wörld", 2)
+	if err := b.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := b.Lookup("wörlt", verbosity.Top, 2); len(got) != 1 || got[0].Term != "wörld" || got[0].Count != 5 {
+		t.Errorf("Lookup after Sync = %v, want wörld with count 5", got)
+	}
+}
+
+func TestStoreTrimsLog(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	store := redis.New(redis.Config{Addr: server.Addr(), MaxLogLength: 2})
+	defer store.Close()
+	store.Set(ctx, "hello", 100)
+	store.Set(ctx, "world", 100)
+
+	behind, err := storage.NewReplica(ctx, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, term := range []string{"alpha", "beta", "gamma"} {
+		if _, err := store.Add(ctx, term, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store.Delete(ctx, "world")
+	if n, _ := server.List("symspell:log"); len(n) != 2 {
+		t.Errorf("log holds %d updates, want 2", len(n))
+	}
+	if _, _, err := store.Changes(ctx, 2); err != storage.ErrLogTrimmed {
+		t.Fatalf("Changes of a trimmed version: err = %v, want ErrLogTrimmed", err)
+	}
+	_, version, _ := store.Snapshot(ctx)
+	if version != 6 {
+		t.Errorf("Snapshot version = %d, want 6", version)
+	}
+
+	if err := behind.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, term := range []string{"alpha", "beta", "gamma"} {
+		if count, ok := behind.SymSpell.WordFrequency(term); !ok || count != 10 {
+			t.Errorf("after reload %s = %d, %v, want 10", term, count, ok)
+		}
+	}
+	if behind.SymSpell.ContainsWord("world") {
+		t.Error("word deleted before the reload still present")
+	}
+
+	store.Add(ctx, "delta", 1)
+	changes, next, err := store.Changes(ctx, version)
+	if err != nil || len(changes) != 1 || changes[0] != (storage.Change{Term: "delta", Count: 1}) || next != version+1 {
+		t.Errorf("Changes(%d) = %v, %d, %v, want delta at version %d", version, changes, next, err, version+1)
+	}
+}
@@ -0,0 +1,449 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+	"symspell/pkg/stats"
+	"symspell/pkg/verbosity"
+)
+
+// Replica is a SymSpell serving a dictionary shared through a Backend. Word
+// updates, including loading dictionary files, are written to the backend
+// first and then applied to the local index; Sync applies the updates made
+// through other replicas. A lookup of a word the local index does not know
+// yet reads it through from the backend, and words the backend did not have
+// either are remembered until the next Sync. The user dictionary, blacklist,
+// boost lists, protected words and bigram and exact dictionaries stay local
+// to the replica. Replicas are safe for concurrent use.
+type Replica struct {
+	symspell.SymSpell
+	backend Backend
+	mu      sync.Mutex // serializes applying updates
+	version uint64
+
+	missMu sync.Mutex
+	misses map[string]struct{} // words read through but not found
+}
+
+var _ symspell.SymSpell = (*Replica)(nil)
+
+// ErrUnsupported is returned by the methods that would replace the local
+// index of a Replica with one the backend does not hold.
+var ErrUnsupported = errors.New("storage: not supported by a Replica")
+
+// maxMisses bounds the negative cache of read-throughs; it is cleared when
+// full.
+const maxMisses = 1 << 16
+
+// NewReplica creates an instance with opts, loads the current dictionary of
+// backend into it and returns it as a replica.
+func NewReplica(ctx context.Context, backend Backend, opts ...options.Options) (*Replica, error) {
+	s, err := symspell.New(append(slices.Clip(opts), options.WithThreadSafe())...)
+	if err != nil {
+		return nil, err
+	}
+	words, version, err := backend.Snapshot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading shared dictionary: %w", err)
+	}
+	if err := loadWords(s, words); err != nil {
+		return nil, err
+	}
+	return &Replica{SymSpell: s, backend: backend, version: version, misses: make(map[string]struct{})}, nil
+}
+
+// loadWords builds the index of s from words in one pass, adding the words
+// in a fixed order so that word indexes are reproducible.
+func loadWords(s symspell.SymSpell, words map[string]uint64) error {
+	terms := make([]string, 0, len(words))
+	for term := range words {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+	r, w := io.Pipe()
+	go func() {
+		var line []byte
+		for _, term := range terms {
+			line = append(line[:0], term...)
+			line = append(line, '\t')
+			line = strconv.AppendUint(line, uint64(words[term]), 10)
+			line = append(line, '\n')
+			if _, err := w.Write(line); err != nil {
+				return
+			}
+		}
+		w.Close()
+	}()
+	defer r.Close()
+	if _, err := s.LoadDictionaryStream(r, 0, 1, "\t"); err != nil {
+		return fmt.Errorf("loading shared dictionary: %w", err)
+	}
+	return nil
+}
+
+// Sync applies the updates made since the last Sync. If the backend no
+// longer logs them all, the whole dictionary is reloaded from a snapshot.
+func (r *Replica) Sync(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	changes, version, err := r.backend.Changes(ctx, r.version)
+	if errors.Is(err, ErrLogTrimmed) {
+		return r.reload(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("syncing shared dictionary: %w", err)
+	}
+	for _, change := range changes {
+		r.apply(change.Term, change.Count)
+	}
+	r.version = version
+	r.clearMisses()
+	return nil
+}
+
+// reload replaces the local words with a snapshot of the backend.
+func (r *Replica) reload(ctx context.Context) error {
+	words, version, err := r.backend.Snapshot(ctx)
+	if err != nil {
+		return fmt.Errorf("reloading shared dictionary: %w", err)
+	}
+	var stale []string
+	for term := range r.SymSpell.Entries() {
+		if _, ok := words[term]; !ok {
+			stale = append(stale, term)
+		}
+	}
+	for _, term := range stale {
+		r.apply(term, 0)
+	}
+	for term, count := range words {
+		r.apply(term, count)
+	}
+	r.version = version
+	r.clearMisses()
+	return nil
+}
+
+// Run calls Sync every interval until ctx is done or Sync fails.
+func (r *Replica) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if err := r.Sync(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// apply sets the local count of term, deleting the word for count zero, and
+// reports whether the word was new.
+func (r *Replica) apply(term string, count uint64) bool {
+	if count == 0 {
+		r.SymSpell.DeleteDictionaryEntry(term)
+		return false
+	}
+	if r.SymSpell.UpdateWordFrequency(term, count) {
+		return false
+	}
+	added, _ := r.SymSpell.AddWord(term, count)
+	return added
+}
+
+// readThrough adds term from the backend if the local index does not know
+// it. Backend errors leave the lookup to the local index.
+func (r *Replica) readThrough(ctx context.Context, term string) {
+	if r.SymSpell.ContainsWord(term) || r.missed(term) {
+		return
+	}
+	count, found, err := r.backend.Get(ctx, term)
+	if err != nil {
+		return
+	}
+	if found && count > 0 {
+		r.mu.Lock()
+		r.apply(term, count)
+		r.mu.Unlock()
+	}
+	// also words the local index rejects, e.g. below its count threshold
+	if !r.SymSpell.ContainsWord(term) {
+		r.addMiss(term)
+	}
+}
+
+// readThroughText reads the words of a phrase through.
+func (r *Replica) readThroughText(ctx context.Context, text string) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '\''
+	})
+	for _, word := range words {
+		r.readThrough(ctx, word)
+	}
+}
+
+func (r *Replica) missed(term string) bool {
+	r.missMu.Lock()
+	defer r.missMu.Unlock()
+	_, ok := r.misses[term]
+	return ok
+}
+
+func (r *Replica) addMiss(term string) {
+	r.missMu.Lock()
+	defer r.missMu.Unlock()
+	if len(r.misses) >= maxMisses {
+		clear(r.misses)
+	}
+	r.misses[term] = struct{}{}
+}
+
+// clearMisses forgets the words read through before a Sync, since other
+// replicas may have added them since.
+func (r *Replica) clearMisses() {
+	r.missMu.Lock()
+	defer r.missMu.Unlock()
+	clear(r.misses)
+}
+
+func (r *Replica) Lookup(phrase string, verbosity verbosity.Verbosity, maxEditDistance int) ([]items.SuggestItem, error) {
+	r.readThrough(context.Background(), phrase)
+	return r.SymSpell.Lookup(phrase, verbosity, maxEditDistance)
+}
+
+func (r *Replica) LookupContext(ctx context.Context, phrase string, verbosity verbosity.Verbosity, maxEditDistance int) ([]items.SuggestItem, error) {
+	r.readThrough(ctx, phrase)
+	return r.SymSpell.LookupContext(ctx, phrase, verbosity, maxEditDistance)
+}
+
+func (r *Replica) LookupAppend(dst []items.SuggestItem, phrase string, verbosity verbosity.Verbosity, maxEditDistance int) ([]items.SuggestItem, error) {
+	r.readThrough(context.Background(), phrase)
+	return r.SymSpell.LookupAppend(dst, phrase, verbosity, maxEditDistance)
+}
+
+func (r *Replica) Suggestions(phrase string, verbosity verbosity.Verbosity, maxEditDistance int) iter.Seq[items.SuggestItem] {
+	r.readThrough(context.Background(), phrase)
+	return r.SymSpell.Suggestions(phrase, verbosity, maxEditDistance)
+}
+
+func (r *Replica) LookupWithOptions(phrase string, opts options.LookupOptions) ([]items.SuggestItem, error) {
+	r.readThrough(context.Background(), phrase)
+	return r.SymSpell.LookupWithOptions(phrase, opts)
+}
+
+func (r *Replica) LookupWithBoost(phrase string, verbosity verbosity.Verbosity, maxEditDistance int, boostListName string) ([]items.SuggestItem, error) {
+	r.readThrough(context.Background(), phrase)
+	return r.SymSpell.LookupWithBoost(phrase, verbosity, maxEditDistance, boostListName)
+}
+
+func (r *Replica) LookupBatch(terms []string, verbosity verbosity.Verbosity, maxEditDistance int) ([][]items.SuggestItem, error) {
+	for _, term := range terms {
+		r.readThrough(context.Background(), term)
+	}
+	return r.SymSpell.LookupBatch(terms, verbosity, maxEditDistance)
+}
+
+func (r *Replica) LookupCompound(phrase string, maxEditDistance int) *items.SuggestItem {
+	r.readThroughText(context.Background(), phrase)
+	return r.SymSpell.LookupCompound(phrase, maxEditDistance)
+}
+
+func (r *Replica) LookupCompoundDetailed(phrase string, maxEditDistance int) *items.CompoundResult {
+	r.readThroughText(context.Background(), phrase)
+	return r.SymSpell.LookupCompoundDetailed(phrase, maxEditDistance)
+}
+
+func (r *Replica) LookupCompoundContext(ctx context.Context, phrase string, maxEditDistance int) (*items.CompoundResult, error) {
+	r.readThroughText(ctx, phrase)
+	return r.SymSpell.LookupCompoundContext(ctx, phrase, maxEditDistance)
+}
+
+func (r *Replica) LookupCompoundNBest(phrase string, maxEditDistance, n int) []items.CompoundResult {
+	r.readThroughText(context.Background(), phrase)
+	return r.SymSpell.LookupCompoundNBest(phrase, maxEditDistance, n)
+}
+
+func (r *Replica) LookupInContext(tokens []string, index int, maxEditDistance int) ([]items.SuggestItem, error) {
+	for _, token := range tokens {
+		r.readThrough(context.Background(), token)
+	}
+	return r.SymSpell.LookupInContext(tokens, index, maxEditDistance)
+}
+
+func (r *Replica) LookupWithNeighbors(prev, word, next string, maxEditDistance int) ([]items.SuggestItem, error) {
+	r.readThrough(context.Background(), word)
+	return r.SymSpell.LookupWithNeighbors(prev, word, next, maxEditDistance)
+}
+
+func (r *Replica) Annotate(text string, maxEditDistance int) ([]items.Annotation, error) {
+	r.readThroughText(context.Background(), text)
+	return r.SymSpell.Annotate(text, maxEditDistance)
+}
+
+func (r *Replica) ContainsWord(term string) bool {
+	r.readThrough(context.Background(), term)
+	return r.SymSpell.ContainsWord(term)
+}
+
+func (r *Replica) WordFrequency(term string) (uint64, bool) {
+	r.readThrough(context.Background(), term)
+	return r.SymSpell.WordFrequency(term)
+}
+
+// AddWord adds count to term in the backend and applies the new count
+// locally.
+func (r *Replica) AddWord(term string, count uint64) (bool, error) {
+	total, err := r.backend.Add(context.Background(), term, count)
+	if err != nil {
+		return false, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.apply(term, total), nil
+}
+
+// CreateDictionaryEntry works like AddWord and returns false on backend
+// errors.
+func (r *Replica) CreateDictionaryEntry(term string, count uint64) bool {
+	added, _ := r.AddWord(term, count)
+	return added
+}
+
+func (r *Replica) IncrementCount(term string, delta uint64) (uint64, bool) {
+	if !r.ContainsWord(term) {
+		return 0, false
+	}
+	total, err := r.backend.Add(context.Background(), term, delta)
+	if err != nil {
+		return 0, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.apply(term, total)
+	return total, true
+}
+
+func (r *Replica) UpdateWordFrequency(term string, count uint64) bool {
+	if !r.ContainsWord(term) {
+		return false
+	}
+	if err := r.backend.Set(context.Background(), term, count); err != nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.apply(term, count)
+	return true
+}
+
+func (r *Replica) DeleteDictionaryEntry(term string) bool {
+	if !r.ContainsWord(term) {
+		return false
+	}
+	if err := r.backend.Delete(context.Background(), term); err != nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.apply(term, 0)
+	return true
+}
+
+// share reads a dictionary source with load into a scratch instance and
+// adds its words to the shared dictionary.
+func (r *Replica) share(load func(symspell.SymSpell) (bool, error)) (bool, error) {
+	scratch, err := symspell.New(options.WithMaxDictionaryEditDistance(0))
+	if err != nil {
+		return false, err
+	}
+	if ok, err := load(scratch); !ok || err != nil {
+		return ok, err
+	}
+	for term, count := range scratch.Entries() {
+		if _, err := r.AddWord(term, count); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// LoadDictionary adds the words of a dictionary file to the shared
+// dictionary.
+func (r *Replica) LoadDictionary(corpusPath string, termIndex int, countIndex int, separator string, opts ...options.LoadOption) (bool, error) {
+	return r.share(func(s symspell.SymSpell) (bool, error) {
+		return s.LoadDictionary(corpusPath, termIndex, countIndex, separator, opts...)
+	})
+}
+
+func (r *Replica) LoadDictionaryContext(ctx context.Context, corpusPath string, termIndex int, countIndex int, separator string, progress func(linesRead, wordsAdded int), opts ...options.LoadOption) (bool, error) {
+	return r.share(func(s symspell.SymSpell) (bool, error) {
+		return s.LoadDictionaryContext(ctx, corpusPath, termIndex, countIndex, separator, progress, opts...)
+	})
+}
+
+func (r *Replica) LoadDictionaryStream(corpusStream io.Reader, termIndex int, countIndex int, separator string, opts ...options.LoadOption) (bool, error) {
+	return r.share(func(s symspell.SymSpell) (bool, error) {
+		return s.LoadDictionaryStream(corpusStream, termIndex, countIndex, separator, opts...)
+	})
+}
+
+func (r *Replica) LoadHunspell(dicPath, affPath string, opts ...options.LoadOption) (bool, error) {
+	return r.share(func(s symspell.SymSpell) (bool, error) {
+		return s.LoadHunspell(dicPath, affPath, opts...)
+	})
+}
+
+func (r *Replica) LoadHunspellStream(dic, aff io.Reader, opts ...options.LoadOption) (bool, error) {
+	return r.share(func(s symspell.SymSpell) (bool, error) {
+		return s.LoadHunspellStream(dic, aff, opts...)
+	})
+}
+
+func (r *Replica) CreateDictionary(corpus io.Reader) (bool, error) {
+	return r.share(func(s symspell.SymSpell) (bool, error) {
+		return s.CreateDictionary(corpus)
+	})
+}
+
+// PruneDictionary deletes the words rarer than minCount from the shared
+// dictionary and compacts the local index.
+func (r *Replica) PruneDictionary(minCount uint64) stats.CompactStats {
+	var rare []string
+	for term, count := range r.SymSpell.Entries() {
+		if count < minCount {
+			rare = append(rare, term)
+		}
+	}
+	for _, term := range rare {
+		r.DeleteDictionaryEntry(term)
+	}
+	return r.SymSpell.Compact()
+}
+
+// LoadIndex returns ErrUnsupported: a replica serves the backend's words.
+func (r *Replica) LoadIndex(io.Reader) error {
+	return ErrUnsupported
+}
+
+// LoadMappedIndex returns ErrUnsupported: a replica serves the backend's
+// words.
+func (r *Replica) LoadMappedIndex(string) (io.Closer, error) {
+	return nil, ErrUnsupported
+}
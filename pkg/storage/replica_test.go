@@ -0,0 +1,111 @@
+package storage_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"symspell/pkg/storage"
+	"symspell/pkg/verbosity"
+)
+
+func TestReplicasShareUpdates(t *testing.T) {
+	ctx := context.Background()
+	backend := storage.NewMemory()
+	backend.Set(ctx, "hello", 100)
+	backend.Set(ctx, "world", 100)
+
+	a, err := storage.NewReplica(ctx, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := storage.NewReplica(ctx, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := a.Lookup("helo", verbosity.Top, 2); len(got) != 1 || got[0].Term != "hello" {
+		t.Fatalf("Lookup(helo) = %v", got)
+	}
+
+	if _, err := a.AddWord("kubernetes", 50); err != nil {
+		t.Fatal(err)
+	}
+	a.DeleteDictionaryEntry("world")
+	if err := b.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if count, ok := b.SymSpell.WordFrequency("kubernetes"); !ok || count != 50 {
+		t.Errorf("synced count = %d, %v, want 50", count, ok)
+	}
+	if b.SymSpell.ContainsWord("world") {
+		t.Error("deleted word still in the other replica")
+	}
+
+	// read-through before the next Sync
+	backend.Add(ctx, "grafana", 7)
+	if got, _ := b.Lookup("grafana", verbosity.Top, 2); len(got) != 1 || got[0].Term != "grafana" || got[0].Distance != 0 {
+		t.Errorf("Lookup(grafana) = %v, want the shared word", got)
+	}
+}
+
+// countingBackend counts the reads of a Backend.
+type countingBackend struct {
+	storage.Backend
+	gets int
+}
+
+func (b *countingBackend) Get(ctx context.Context, term string) (uint64, bool, error) {
+	b.gets++
+	return b.Backend.Get(ctx, term)
+}
+
+func TestReplicaReadThrough(t *testing.T) {
+	ctx := context.Background()
+	memory := storage.NewMemory()
+	memory.Set(ctx, "hello", 100)
+	backend := &countingBackend{Backend: memory}
+	r, err := storage.NewReplica(ctx, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 3 {
+		r.Lookup("helo", verbosity.Top, 2)
+	}
+	if backend.gets != 1 {
+		t.Errorf("3 lookups of a misspelling read the backend %d times, want 1", backend.gets)
+	}
+
+	memory.Add(ctx, "world", 50)
+	memory.Add(ctx, "wide", 50)
+	memory.Add(ctx, "web", 50)
+	r.LookupCompound("Hello, wide world!", 2)
+	if !r.SymSpell.ContainsWord("wide") || !r.SymSpell.ContainsWord("world") {
+		t.Error("LookupCompound did not read its words through")
+	}
+	if got, _ := r.LookupBatch([]string{"web"}, verbosity.Top, 2); len(got[0]) != 1 || got[0][0].Distance != 0 {
+		t.Errorf("LookupBatch = %v, want web read through", got)
+	}
+}
+
+func TestReplicaSharesLoadedDictionary(t *testing.T) {
+	ctx := context.Background()
+	backend := storage.NewMemory()
+	r, err := storage.NewReplica(ctx, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := r.LoadDictionaryStream(strings.NewReader("hello 100\nworld 50\n"), 0, 1, " "); !ok || err != nil {
+		t.Fatalf("LoadDictionaryStream = %v, %v", ok, err)
+	}
+	if count, found, _ := backend.Get(ctx, "world"); !found || count != 50 {
+		t.Errorf("backend count of world = %d, %v, want 50", count, found)
+	}
+	r.PruneDictionary(60)
+	if _, found, _ := backend.Get(ctx, "world"); found {
+		t.Error("PruneDictionary kept world in the backend")
+	}
+	if err := r.LoadIndex(strings.NewReader("")); err != storage.ErrUnsupported {
+		t.Errorf("LoadIndex err = %v, want ErrUnsupported", err)
+	}
+}
@@ -0,0 +1,130 @@
+// Package storage keeps a dictionary in a store shared by several SymSpell
+// instances, such as stateless API replicas, so that updates made through
+// one of them reach all:
+//
+//	replica, err := storage.NewReplica(ctx, redis.New(redis.Config{Addr: "cache:6379"}))
+//	go replica.Run(ctx, 10*time.Second)
+//
+// Each replica serves lookups from its own in-memory index, which acts as a
+// read-through cache of the Backend.
+package storage
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sync"
+)
+
+// Backend stores the words and counts of a shared dictionary. Every update is
+// appended to a change log whose length is the version of the dictionary, so
+// that replicas can catch up with the updates made by others.
+type Backend interface {
+	// Snapshot returns every word with its count and the version they
+	// reflect.
+	Snapshot(ctx context.Context) (map[string]uint64, uint64, error)
+	// Get returns the count of term.
+	Get(ctx context.Context, term string) (uint64, bool, error)
+	// Add adds delta to the count of term, adding the word if needed, and
+	// returns the new count.
+	Add(ctx context.Context, term string, delta uint64) (uint64, error)
+	// Set sets the count of term, adding the word if needed.
+	Set(ctx context.Context, term string, count uint64) error
+	// Delete removes term.
+	Delete(ctx context.Context, term string) error
+	// Changes returns the words updated after version with their current
+	// counts, zero for deleted words, and the version they lead to. It
+	// returns ErrLogTrimmed if those updates are no longer logged.
+	Changes(ctx context.Context, version uint64) ([]Change, uint64, error)
+}
+
+// ErrLogTrimmed is returned by Backend.Changes when the change log no longer
+// reaches back to the requested version. A replica then reloads a Snapshot.
+var ErrLogTrimmed = errors.New("storage: change log trimmed past version")
+
+// Change is the current state of a word updated since a given version.
+type Change struct {
+	Term  string
+	Count uint64 // 0 if the word was deleted
+}
+
+// Memory is a Backend kept in process memory, for tests and for sharing a
+// dictionary between instances of one process.
+type Memory struct {
+	mu    sync.Mutex
+	words map[string]uint64
+	log   []string
+}
+
+var _ Backend = (*Memory)(nil)
+
+// NewMemory returns an empty Memory backend.
+func NewMemory() *Memory {
+	return &Memory{words: make(map[string]uint64)}
+}
+
+func (m *Memory) Snapshot(context.Context) (map[string]uint64, uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return maps.Clone(m.words), uint64(len(m.log)), nil
+}
+
+func (m *Memory) Get(_ context.Context, term string) (uint64, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	count, found := m.words[term]
+	return count, found, nil
+}
+
+func (m *Memory) Add(_ context.Context, term string, delta uint64) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	count := m.words[term]
+	if count > ^uint64(0)-delta {
+		count = ^uint64(0)
+	} else {
+		count += delta
+	}
+	m.words[term] = count
+	m.log = append(m.log, term)
+	return count, nil
+}
+
+func (m *Memory) Set(_ context.Context, term string, count uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.words[term] = count
+	m.log = append(m.log, term)
+	return nil
+}
+
+func (m *Memory) Delete(_ context.Context, term string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.words, term)
+	m.log = append(m.log, term)
+	return nil
+}
+
+func (m *Memory) Changes(_ context.Context, version uint64) ([]Change, uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if version >= uint64(len(m.log)) {
+		return nil, uint64(len(m.log)), nil
+	}
+	return changesOf(m.log[version:], m.words), uint64(len(m.log)), nil
+}
+
+// changesOf returns the current state of the distinct terms of log.
+func changesOf(log []string, words map[string]uint64) []Change {
+	seen := make(map[string]struct{}, len(log))
+	changes := make([]Change, 0, len(log))
+	for _, term := range log {
+		if _, ok := seen[term]; ok {
+			continue
+		}
+		seen[term] = struct{}{}
+		changes = append(changes, Change{Term: term, Count: words[term]})
+	}
+	return changes
+}
@@ -0,0 +1,41 @@
+package symspell_test
+
+import (
+	"reflect"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestSuggestionFilter(t *testing.T) {
+	s, err := symspell.New(options.WithSuggestionFilter(func(item items.SuggestItem) bool {
+		return len(item.Term) > 1 && item.Term != "at"
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for word, count := range map[string]uint64{"a": 5000, "at": 1000, "an": 400, "as": 300} {
+		s.CreateDictionaryEntry(word, count)
+	}
+
+	for _, tt := range []struct {
+		input string
+		v     verbosity.Verbosity
+		want  []string
+	}{
+		{"ax", verbosity.Closest, []string{"an", "as"}},
+		// the filtered word does not displace the best accepted one
+		{"ax", verbosity.Top, []string{"an"}},
+		// exact matches are filtered as well
+		{"at", verbosity.Top, []string{"an"}},
+		{"a", verbosity.All, []string{"an", "as"}},
+	} {
+		got, err := s.Lookup(tt.input, tt.v, 2)
+		if err != nil || !reflect.DeepEqual(termsOf(got), tt.want) {
+			t.Errorf("Lookup(%q, %v) = %v, %v, want %v", tt.input, tt.v, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package symspell_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestSuggestionsStreamsAllLookup(t *testing.T) {
+	s := newGoldenSymSpell(t)
+	inexact := func(result []items.SuggestItem) []string {
+		var terms []string
+		for _, item := range result {
+			if item.Distance > 0 {
+				terms = append(terms, item.Term)
+			}
+		}
+		slices.Sort(terms)
+		return terms
+	}
+	for _, tc := range goldenCases {
+		for _, term := range tc.inputs {
+			want, err := s.Lookup(term, verbosity.All, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := slices.Collect(s.Suggestions(term, verbosity.All, 2))
+			if !slices.Equal(inexact(got), inexact(want)) {
+				t.Errorf("Suggestions(%q) = %v, want %v", term, got, want)
+			}
+		}
+	}
+}
+
+func TestSuggestionsStopEarly(t *testing.T) {
+	s := newGoldenSymSpell(t)
+	n := 0
+	for range s.Suggestions("speling", verbosity.All, 2) {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Fatalf("got %d suggestions before break, want 1", n)
+	}
+	top, err := s.Lookup("speling", verbosity.Top, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := slices.Collect(s.Suggestions("speling", verbosity.Top, 2))
+	if !slices.Equal(got, top) {
+		t.Errorf("Suggestions(Top) = %v, want %v", got, top)
+	}
+}
+
+func TestSuggestionsThreadSafeLoopMayAddWords(t *testing.T) {
+	s := newGoldenSymSpell(t, options.WithThreadSafe())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for suggestion := range s.Suggestions("wrld", verbosity.All, 2) {
+			s.AddWord(suggestion.Term+"s", 1)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Suggestions deadlocked with AddWord in the loop")
+	}
+	if !s.ContainsWord("worlds") {
+		t.Error("AddWord in the loop had no effect")
+	}
+}
@@ -1,22 +1,62 @@
 package symspell
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"iter"
 	"log"
+	"log/slog"
 
 	"symspell/internal"
+	"symspell/pkg/dictionaries"
 	"symspell/pkg/items"
 	"symspell/pkg/options"
+	"symspell/pkg/stats"
 	"symspell/pkg/verbosity"
 )
 
-func NewSymSpell(opt ...options.Options) SymSpell {
+// New creates a SymSpell instance and reports invalid options as an error.
+// With options.WithThreadSafe the instance is safe for concurrent use.
+func New(opt ...options.Options) (SymSpell, error) {
 	symspell, err := internal.NewSymSpell(opt...)
+	if err != nil {
+		return nil, err
+	}
+	if symspell.ThreadSafe {
+		return NewConcurrent(symspell), nil
+	}
+	return symspell, nil
+}
+
+// NewSymSpell creates a SymSpell instance and exits the process on invalid options.
+func NewSymSpell(opt ...options.Options) SymSpell {
+	symspell, err := New(opt...)
 	if err != nil {
 		log.Fatal("[ERROR] ", err)
 	}
 	return symspell
 }
 
+// NewForLanguage creates a SymSpell instance with the frequency list
+// registered for lang in package dictionaries, so no dictionary file has to
+// ship next to the binary. The built-in lists are generated, not shipped, see
+// package dictionaries.
+func NewForLanguage(lang string, opt ...options.Options) (SymSpell, error) {
+	data, err := dictionaries.Open(lang)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDictionaryNotFound, err)
+	}
+	symspell, err := New(opt...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := symspell.LoadDictionaryStream(data, 0, 1, " "); err != nil {
+		return nil, err
+	}
+	return symspell, nil
+}
+
 // NewSymSpellWithLoadDictionary used when want Lookup only
 func NewSymSpellWithLoadDictionary(dirPath string, termIndex, countIndex int, opt ...options.Options) SymSpell {
 	symspell := NewSymSpell(opt...)
@@ -31,32 +71,234 @@ func NewSymSpellWithLoadDictionary(dirPath string, termIndex, countIndex int, op
 	return symspell
 }
 
+// NewSymSpellWithLoadBigramDictionary loads the vocabulary plus optional bigram
+// and exact-transform dictionaries.
 func NewSymSpellWithLoadBigramDictionary(vocabDirPath, bigramDirPath, exactDirPath string, termIndex, countIndex int, opt ...options.Options) SymSpell {
 	symspell := NewSymSpell(opt...)
 	ok, err := symspell.LoadDictionary(vocabDirPath, termIndex, countIndex, " ")
 	if err != nil || !ok {
 		log.Fatal("[Error] ", err)
 	}
+	logger := optionsLogger(opt)
 	ok, err = symspell.LoadBigramDictionary(bigramDirPath, termIndex, countIndex+1, "")
 	if err != nil || !ok {
 		if bigramDirPath != "" {
-			log.Println("[Error] ", err)
+			logger.Error("loading bigram dictionary failed", "path", bigramDirPath, "err", err)
 		}
 	}
 	if exactDirPath != "" {
 		ok, err = symspell.LoadExactDictionary(exactDirPath, " ")
 		if err != nil || !ok {
-			log.Println("[Error] ", err)
+			logger.Error("loading exact dictionary failed", "path", exactDirPath, "err", err)
 		}
 	}
 	return symspell
 }
 
+// optionsLogger returns the logger configured by options.WithLogger.
+func optionsLogger(opt []options.Options) *slog.Logger {
+	opts := options.DefaultOptions
+	for _, config := range opt {
+		config.Apply(&opts)
+	}
+	if opts.Logger == nil {
+		return slog.Default()
+	}
+	return opts.Logger
+}
+
+// Sentinel errors for branching with errors.Is.
+var (
+	// ErrDistanceTooLarge is returned when a lookup asks for a larger edit
+	// distance than options.WithMaxDictionaryEditDistance.
+	ErrDistanceTooLarge = internal.ErrDistanceTooLarge
+	// ErrDictionaryNotFound is returned when a dictionary file or a language
+	// of DictionaryManager does not exist.
+	ErrDictionaryNotFound = internal.ErrDictionaryNotFound
+	// ErrInvalidOptions wraps every validation error of options.
+	ErrInvalidOptions = internal.ErrInvalidOptions
+	// ErrFrozen is returned by the updating methods of an instance created
+	// with Freeze.
+	ErrFrozen = internal.ErrFrozen
+	// ErrInvalidUTF8 is returned for malformed UTF-8 input under
+	// options.InvalidUTF8Reject; the error is an *InvalidUTF8Error.
+	ErrInvalidUTF8 = internal.ErrInvalidUTF8
+)
+
+// InvalidUTF8Error is returned for malformed UTF-8 input under options.InvalidUTF8Reject.
+type InvalidUTF8Error = internal.InvalidUTF8Error
+
+// SymSpell is the public spelling correction API. Instances are not safe for
+// concurrent use unless created with options.WithThreadSafe or wrapped with
+// NewConcurrent; those allow any number of concurrent lookups alongside
+// dictionary updates, which wait for in-flight lookups.
 type SymSpell interface {
+	// Lookup returns suggestions for a single word within maxEditDistance.
 	Lookup(phrase string, verbosity verbosity.Verbosity, maxEditDistance int) ([]items.SuggestItem, error)
+	// LookupContext works like Lookup but returns ctx.Err() as soon as ctx is
+	// done, so slow lookups can be bound by a request deadline.
+	LookupContext(ctx context.Context, phrase string, verbosity verbosity.Verbosity, maxEditDistance int) ([]items.SuggestItem, error)
+	// LookupAppend works like Lookup but appends the suggestions to dst, so a
+	// loop over many tokens can reuse one buffer.
+	LookupAppend(dst []items.SuggestItem, phrase string, verbosity verbosity.Verbosity, maxEditDistance int) ([]items.SuggestItem, error)
+	// Suggestions iterates over the suggestions for phrase. With verbosity All
+	// they are yielded unsorted as they are found, so a caller can stop at the
+	// first acceptable one without the full search. Invalid input yields
+	// nothing.
+	Suggestions(phrase string, verbosity verbosity.Verbosity, maxEditDistance int) iter.Seq[items.SuggestItem]
+	// LookupWithOptions works like Lookup with per-call overrides.
+	LookupWithOptions(phrase string, opts options.LookupOptions) ([]items.SuggestItem, error)
+	// LookupBatch looks up terms in parallel and returns results in input order.
+	LookupBatch(terms []string, verbosity verbosity.Verbosity, maxEditDistance int) ([][]items.SuggestItem, error)
+	// LookupCompound corrects a multi-word phrase, merging and splitting words
+	// where needed. It returns nil if the phrase is rejected.
 	LookupCompound(phrase string, maxEditDistance int) *items.SuggestItem
+	// LookupCompoundDetailed works like LookupCompound and also returns the
+	// correction and byte offsets of every input word.
+	LookupCompoundDetailed(phrase string, maxEditDistance int) *items.CompoundResult
+	// LookupCompoundContext works like LookupCompoundDetailed but returns
+	// ErrInvalidUTF8 or ErrDistanceTooLarge instead of nil for a rejected
+	// phrase, and ctx.Err() once ctx is done.
+	LookupCompoundContext(ctx context.Context, phrase string, maxEditDistance int) (*items.CompoundResult, error)
+	// LookupCompoundNBest returns up to n alternative corrections of phrase,
+	// best first, found by beam search.
+	LookupCompoundNBest(phrase string, maxEditDistance, n int) []items.CompoundResult
+	// LookupInContext corrects tokens[index] using its neighbors for disambiguation.
+	LookupInContext(tokens []string, index int, maxEditDistance int) ([]items.SuggestItem, error)
+	// LookupWithNeighbors corrects word by bigram context with the words
+	// before and after it; pass "" at the edges of a sentence. It is the
+	// (prev, word, next) form of LookupInContext, named apart because
+	// LookupInContext takes a token slice and an index.
+	LookupWithNeighbors(prev, word, next string, maxEditDistance int) ([]items.SuggestItem, error)
+	// SplitCompound returns the dictionary words a compound word is made of,
+	// or nil; see options.WithCompoundSplitting.
+	SplitCompound(word string) []string
+	// WordSegmentation splits a string without spaces into words, correcting
+	// misspelled parts on the way.
+	WordSegmentation(phrase string, maxEditDistance, maxSegmentationWordLength int) (items.Composition, error)
+	// WordSegmentationContext works like WordSegmentation but returns
+	// ctx.Err() once ctx is done.
+	WordSegmentationContext(ctx context.Context, phrase string, maxEditDistance, maxSegmentationWordLength int) (items.Composition, error)
+	// WordSegmentationNBest returns up to n alternative segmentations with
+	// their log10 probabilities, best first.
+	WordSegmentationNBest(phrase string, maxEditDistance, maxSegmentationWordLength, n int) ([]items.Composition, error)
+	// LookupWithBoost works like Lookup but ranks with the named boost list applied.
+	LookupWithBoost(phrase string, verbosity verbosity.Verbosity, maxEditDistance int, boostListName string) ([]items.SuggestItem, error)
+	// Annotate returns standoff corrections for text without modifying it.
+	Annotate(text string, maxEditDistance int) ([]items.Annotation, error)
+
+	// RegisterBoostList registers a named set of terms whose counts are
+	// multiplied at ranking time by LookupWithBoost.
+	RegisterBoostList(name string, terms []string, multiplier float64) error
+	// RemoveBoostList unregisters a boost list.
+	RemoveBoostList(name string)
+	// AddToBlacklist adds words that must never be suggested.
+	AddToBlacklist(words ...string)
+	// RemoveFromBlacklist removes words from the suggestion blacklist.
+	RemoveFromBlacklist(words ...string)
+	// IsBlacklisted reports whether word is on the suggestion blacklist.
+	IsBlacklisted(word string) bool
+	// LoadProtectedWords reads words, one per line, that Lookup and
+	// LookupCompound return verbatim instead of correcting them.
+	LoadProtectedWords(r io.Reader) error
+	// IsProtected reports whether word is never corrected.
+	IsProtected(word string) bool
+
+	// LoadDictionary loads "term count" entries from a file and builds the
+	// index. Repeated calls merge sources, see options.WithDictionarySourceWeight.
+	LoadDictionary(corpusPath string, termIndex int, countIndex int, separator string, opts ...options.LoadOption) (bool, error)
+	// LoadDictionaryContext works like LoadDictionary, reporting progress to
+	// the optional progress callback and stopping with ctx.Err() once ctx is
+	// done. Words read before cancellation stay in the dictionary.
+	LoadDictionaryContext(ctx context.Context, corpusPath string, termIndex int, countIndex int, separator string, progress func(linesRead, wordsAdded int), opts ...options.LoadOption) (bool, error)
+	// LoadDictionaryStream works like LoadDictionary but reads from a reader.
+	LoadDictionaryStream(corpusStream io.Reader, termIndex int, countIndex int, separator string, opts ...options.LoadOption) (bool, error)
+	// LoadHunspell loads a Hunspell .dic/.aff dictionary, expanding affix
+	// rules into surface forms. Stems get count 2, generated forms count 1.
+	LoadHunspell(dicPath, affPath string, opts ...options.LoadOption) (bool, error)
+	// LoadHunspellStream works like LoadHunspell but reads from readers.
+	LoadHunspellStream(dic, aff io.Reader, opts ...options.LoadOption) (bool, error)
+	// CreateDictionary counts the words of running text and adds them to the
+	// dictionary, for corpora without precomputed frequencies.
+	CreateDictionary(corpus io.Reader) (bool, error)
+	// CreateDictionaryEntry adds a word at runtime or increments its count.
+	CreateDictionaryEntry(key string, count uint64) bool
+	// AddWord adds a word to a loaded dictionary through a delta index that is
+	// merged in bulk, so runtime additions stay cheap on large dictionaries.
+	AddWord(term string, count uint64) (bool, error)
+	// DeleteDictionaryEntry removes a word and its postings from the index.
+	DeleteDictionaryEntry(term string) bool
+	// UpdateWordFrequency sets the count of an existing word.
+	UpdateWordFrequency(term string, count uint64) bool
+	// IncrementCount adds delta to the count of an existing word and returns
+	// the new count.
+	IncrementCount(term string, delta uint64) (uint64, bool)
+	// AddUserWord adds a word to the user dictionary layered over the base
+	// dictionary. User words rank before base words at the same distance.
+	AddUserWord(term string, count uint64) (bool, error)
+	// RemoveUserWord removes a word from the user dictionary.
+	RemoveUserWord(term string) bool
+	// IsUserWord reports whether term is in the user dictionary.
+	IsUserWord(term string) bool
+	// ClearUserDictionary removes every user word, restoring the base dictionary.
+	ClearUserDictionary()
+	// SaveUserDictionary writes the user dictionary as "term count" lines.
+	SaveUserDictionary(w io.Writer) error
+	// LoadUserDictionary adds words written by SaveUserDictionary.
+	LoadUserDictionary(r io.Reader) error
+	// LoadBigramDictionary loads bigram counts used by LookupCompound,
+	// LookupInContext and WordSegmentation.
 	LoadBigramDictionary(corpusPath string, termIndex, countIndex int, separator string) (bool, error)
-	LoadDictionary(corpusPath string, termIndex int, countIndex int, separator string) (bool, error)
+	// LoadBigramDictionaryStream loads bigram counts from a reader.
+	LoadBigramDictionaryStream(corpusStream io.Reader, termIndex, countIndex int, separator string) (bool, error)
+	// LoadExactDictionary loads exact "from to" replacements applied by LookupCompound.
 	LoadExactDictionary(corpusPath string, separator string) (bool, error)
+	// LoadExactDictionaryStream loads exact replacements from a reader.
+	LoadExactDictionaryStream(corpusStream io.Reader, separator string) (bool, error)
+	// SaveIndex writes the prebuilt index in a versioned binary format.
+	SaveIndex(w io.Writer) error
+	// LoadIndex restores an index written by SaveIndex.
+	LoadIndex(r io.Reader) error
+	// SaveMappedIndex writes the index in a fixed layout that
+	// LoadMappedIndex maps into memory instead of decoding.
+	SaveMappedIndex(w io.Writer) error
+	// LoadMappedIndex maps an index file written by SaveMappedIndex
+	// read-only into memory, so startup does not decode it and processes
+	// share one copy. Close the returned closer only once the instance is no
+	// longer used.
+	LoadMappedIndex(path string) (io.Closer, error)
+	// ClearTransformData releases the bigram and exact-transform maps.
 	ClearTransformData()
+	// Compact drops deleted words and rebuilds the deletes index from the
+	// live words. Thread-safe instances rebuild without holding their lock.
+	Compact() stats.CompactStats
+	// PruneDictionary removes words rarer than minCount and compacts the index.
+	PruneDictionary(minCount uint64) stats.CompactStats
+	// Close releases the store of options.StorageDisk; the instance must not
+	// be used afterwards. It does nothing for in-memory instances.
+	Close() error
+
+	// ContainsWord reports whether term is a dictionary word.
+	ContainsWord(term string) bool
+	// WordFrequency returns the count of a dictionary word.
+	WordFrequency(term string) (uint64, bool)
+	// WordCount returns the number of dictionary words.
+	WordCount() int
+	// Entries iterates over dictionary words and their counts. The dictionary
+	// must not be modified during the iteration.
+	Entries() iter.Seq2[string, uint64]
+	// TopWords returns the n most frequent dictionary words.
+	TopWords(n int) []items.SuggestItem
+	// TopWordsWithPrefix returns the n most frequent words starting with prefix.
+	TopWordsWithPrefix(prefix string, n int) []items.SuggestItem
+	// Stats reports the size of the dictionary and the deletes index.
+	Stats() stats.IndexStats
+	// CacheStats reports hits and misses of the Top lookup cache.
+	CacheStats() stats.CacheStats
+	// InvalidateCache drops all cached Top lookup results.
+	InvalidateCache()
+	// SkipStats returns why candidates were skipped, aggregated over lookups.
+	SkipStats() stats.SkipStats
+	// ResetSkipStats zeroes the skip counters.
+	ResetSkipStats()
 }
@@ -0,0 +1,59 @@
+package symspell_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+)
+
+// wantTopWithPrefix filters TopWords down to the words starting with prefix.
+func wantTopWithPrefix(s symspell.SymSpell, prefix string, n int) []items.SuggestItem {
+	var want []items.SuggestItem
+	for _, word := range s.TopWords(1000) {
+		if strings.HasPrefix(word.Term, prefix) && len(want) < n {
+			want = append(want, word)
+		}
+	}
+	return want
+}
+
+func TestTopWordsWithPrefix(t *testing.T) {
+	for _, compact := range []bool{false, true} {
+		var opts []options.Options
+		if compact {
+			opts = append(opts, options.WithCompactStorage())
+		}
+		s := newGoldenSymSpell(t, opts...)
+		check := func(prefix string, n int) {
+			t.Helper()
+			got, want := s.TopWordsWithPrefix(prefix, n), wantTopWithPrefix(s, prefix, n)
+			if len(got) != len(want) || len(want) != 0 && !reflect.DeepEqual(got, want) {
+				t.Errorf("compact=%v: TopWordsWithPrefix(%q, %d) = %v, want %v", compact, prefix, n, got, want)
+			}
+		}
+		for _, prefix := range []string{"", "t", "th", "the", "wor", "пр", "zzz", "~"} {
+			for _, n := range []int{1, 3, 100} {
+				check(prefix, n)
+			}
+		}
+
+		s.CreateDictionaryEntry("thx", 5_000_000_000)
+		if got := s.TopWordsWithPrefix("th", 1); len(got) != 1 || got[0].Term != "thx" {
+			t.Errorf("compact=%v: TopWordsWithPrefix(th, 1) = %v after adding thx, want thx", compact, got)
+		}
+		check("th", 3)
+		s.DeleteDictionaryEntry("thx")
+		check("th", 3)
+		for _, word := range s.TopWordsWithPrefix("th", 100) {
+			if word.Term == "thx" {
+				t.Errorf("compact=%v: deleted thx is still returned", compact)
+			}
+		}
+		s.Compact()
+		check("th", 3)
+	}
+}
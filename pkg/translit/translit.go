@@ -0,0 +1,74 @@
+// Package translit converts words between scripts, so that lookups can match
+// "privet" typed without a Cyrillic keyboard against "привет".
+package translit
+
+import "strings"
+
+// Transliterator converts a lowercase word to another script. Characters it
+// has no mapping for are kept.
+type Transliterator interface {
+	Transliterate(word string) string
+}
+
+// Table transliterates by replacing, at every position, the longest key that
+// matches there.
+type Table struct {
+	mapping   map[string]string
+	maxKeyLen int
+}
+
+func NewTable(mapping map[string]string) *Table {
+	t := &Table{mapping: mapping}
+	for key := range mapping {
+		t.maxKeyLen = max(t.maxKeyLen, len(key))
+	}
+	return t
+}
+
+func (t *Table) Transliterate(word string) string {
+	var b strings.Builder
+	b.Grow(len(word))
+	for i := 0; i < len(word); {
+		matched := false
+		for n := min(t.maxKeyLen, len(word)-i); n > 0; n-- {
+			if to, ok := t.mapping[word[i:i+n]]; ok {
+				b.WriteString(to)
+				i += n
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			// copy the whole rune, keys never start inside one
+			j := i + 1
+			for j < len(word) && word[j]&0xC0 == 0x80 {
+				j++
+			}
+			b.WriteString(word[i:j])
+			i = j
+		}
+	}
+	return b.String()
+}
+
+// LatinToCyrillic reads the informal Latin spelling of Russian used in chats:
+// "privet" -> "привет", "shchuka" -> "щука". "y" is read as "ы", so "kakoy"
+// becomes "какоы", one edit away from "какой".
+var LatinToCyrillic = NewTable(map[string]string{
+	"a": "а", "b": "б", "v": "в", "w": "в", "g": "г", "d": "д", "e": "е",
+	"z": "з", "i": "и", "j": "й", "k": "к", "q": "к", "l": "л", "m": "м",
+	"n": "н", "o": "о", "p": "п", "r": "р", "s": "с", "t": "т", "u": "у",
+	"f": "ф", "h": "х", "c": "ц", "x": "кс", "y": "ы", "'": "ь",
+	"yo": "ё", "jo": "ё", "zh": "ж", "kh": "х", "ts": "ц", "ch": "ч",
+	"sh": "ш", "sch": "щ", "shch": "щ", "yu": "ю", "ju": "ю", "ya": "я",
+	"ja": "я",
+})
+
+// CyrillicToLatin spells Russian words in Latin letters: "щука" -> "shchuka".
+var CyrillicToLatin = NewTable(map[string]string{
+	"а": "a", "б": "b", "в": "v", "г": "g", "д": "d", "е": "e", "ё": "yo",
+	"ж": "zh", "з": "z", "и": "i", "й": "y", "к": "k", "л": "l", "м": "m",
+	"н": "n", "о": "o", "п": "p", "р": "r", "с": "s", "т": "t", "у": "u",
+	"ф": "f", "х": "kh", "ц": "ts", "ч": "ch", "ш": "sh", "щ": "shch",
+	"ъ": "", "ы": "y", "ь": "", "э": "e", "ю": "yu", "я": "ya",
+})
@@ -0,0 +1,58 @@
+package symspell_test
+
+import (
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/options"
+	"symspell/pkg/translit"
+	"symspell/pkg/verbosity"
+)
+
+func TestTransliteration(t *testing.T) {
+	s, err := symspell.New(options.WithTransliteration(translit.LatinToCyrillic, translit.CyrillicToLatin))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, word := range []string{"привет", "щука", "какой", "hello", "private"} {
+		s.CreateDictionaryEntry(word, 1000)
+	}
+
+	tests := []struct{ input, want string }{
+		{"privet", "привет"},
+		{"shchuka", "щука"},
+		{"kakoy", "какой"}, // "какоы" is one edit away
+		{"хелло", "hello"},
+		{"privte", "private"},
+	}
+	for _, tt := range tests {
+		got, err := s.Lookup(tt.input, verbosity.Top, 2)
+		if err != nil || len(got) != 1 || got[0].Term != tt.want {
+			t.Errorf("Lookup(%q) = %v, %v; want %q", tt.input, got, err, tt.want)
+		}
+	}
+	got, err := s.Lookup("privet", verbosity.All, 2)
+	if err != nil || len(got) != 2 || got[0].Term != "привет" || got[1].Term != "private" {
+		t.Errorf("Lookup(privet, All) = %v, %v; want [привет private]", got, err)
+	}
+}
+
+func TestTransliterationLowersDistance(t *testing.T) {
+	s, err := symspell.New(options.WithTransliteration(translit.LatinToCyrillic))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("привет", 1000)
+	s.CreateDictionaryEntry("првет", 5000)
+
+	// "прiвет" has a Latin "i": both words are one edit away, but its
+	// transliteration is "привет" itself.
+	got, err := s.Lookup("прiвет", verbosity.All, 2)
+	if err != nil || len(got) != 2 || got[0].Term != "привет" || got[0].Distance != 0 {
+		t.Errorf("Lookup(прiвет, All) = %v, %v; want привет at distance 0 first", got, err)
+	}
+	got, err = s.Lookup("прiвет", verbosity.Closest, 2)
+	if err != nil || len(got) != 1 || got[0].Term != "привет" {
+		t.Errorf("Lookup(прiвет, Closest) = %v, %v; want [привет]", got, err)
+	}
+}
@@ -0,0 +1,52 @@
+package symspell_test
+
+import (
+	"reflect"
+	"testing"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/items"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestTwoStageLookup(t *testing.T) {
+	newSymSpell := func(policy options.EscalationPolicy) symspell.SymSpell {
+		s, err := symspell.New(options.WithTwoStageLookup(1, policy))
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.CreateDictionaryEntry("wood", 5)
+		s.CreateDictionaryEntry("world", 1000)
+		return s
+	}
+	for _, tt := range []struct {
+		name   string
+		policy options.EscalationPolicy
+		input  string
+		want   []string
+	}{
+		// wood is one edit from wrod, world two
+		{"on empty", options.EscalateOnEmpty, "wrod", []string{"wood"}},
+		{"below count", options.EscalateBelowCount(100), "wrod", []string{"wood", "world"}},
+		{"above count", options.EscalateBelowCount(5), "wrod", []string{"wood"}},
+		// nothing is one edit from wrlod
+		{"empty coarse pass", options.EscalateOnEmpty, "wrlod", []string{"world", "wood"}},
+	} {
+		got, err := newSymSpell(tt.policy).Lookup(tt.input, verbosity.All, 2)
+		if err != nil || !reflect.DeepEqual(termsOf(got), tt.want) {
+			t.Errorf("%s: Lookup(%q) = %v, %v, want %v", tt.name, tt.input, got, err, tt.want)
+		}
+	}
+
+	var coarse [][]string
+	s := newSymSpell(func(result []items.SuggestItem) bool {
+		coarse = append(coarse, termsOf(result))
+		return false
+	})
+	s.Lookup("wrod", verbosity.All, 2)
+	s.Lookup("wrod", verbosity.All, 1)
+	if want := [][]string{{"wood"}}; !reflect.DeepEqual(coarse, want) {
+		t.Errorf("policy saw %v, want %v: a lookup within the coarse distance has no second pass", coarse, want)
+	}
+}
@@ -0,0 +1,171 @@
+package symspell
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"symspell/internal"
+)
+
+// DictionaryWatcher reloads a dictionary file into a live instance whenever
+// the file changes, see WatchDictionary.
+type DictionaryWatcher struct {
+	target   *lockedSymSpell
+	path     string
+	load     func(path string) (SymSpell, error)
+	settle   time.Duration
+	onReload func(error)
+	watcher  *fsnotify.Watcher
+	reloadMu sync.Mutex // serializes reloads
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// WatchDictionary watches path and, once a change has settled for the
+// settle duration, calls load in the background to build a new instance and
+// moves its dictionary into target. Only the words, counts and index are
+// replaced; the user dictionary, blacklist, protected words, boost lists and
+// bigrams of target are kept. Lookups in flight during the replacement finish
+// on the old dictionary and later calls use the new one, so no lookup is
+// dropped. load must build the instance with the edit distance, prefix
+// length and index backend of target.
+//
+// target must be concurrent, created with options.WithThreadSafe or wrapped
+// with NewConcurrent, and may be wrapped again by a type with an Unwrap
+// method returning the wrapped SymSpell, like metrics.Instrument. onReload,
+// if not nil, is called after every reload with its error; on error target
+// keeps the old dictionary.
+//
+// The directory of path is watched rather than the file itself, so that
+// editors and deploy tools that replace the file instead of writing to it
+// are noticed as well.
+func WatchDictionary(target SymSpell, path string, load func(path string) (SymSpell, error), settle time.Duration, onReload func(error)) (*DictionaryWatcher, error) {
+	locked, ok := unwrap(target).(*lockedSymSpell)
+	if !ok {
+		return nil, errors.New("watching dictionary: target must be a concurrent instance")
+	}
+	if settle <= 0 {
+		return nil, errors.New("watching dictionary: settle duration must be positive")
+	}
+	path = filepath.Clean(path)
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("watching dictionary: %w", err)
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("watching dictionary: %w", err)
+	}
+	w := &DictionaryWatcher{
+		target:   locked,
+		path:     path,
+		load:     load,
+		settle:   settle,
+		onReload: onReload,
+		watcher:  watcher,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go w.run()
+	return w, nil
+}
+
+// Reload loads the file right away and moves its dictionary into the target.
+func (w *DictionaryWatcher) Reload() error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+	next, err := w.load(w.path)
+	if err == nil {
+		err = w.replace(next)
+	}
+	if w.onReload != nil {
+		w.onReload(err)
+	}
+	return err
+}
+
+// Close stops watching and waits for a running reload to finish. The last
+// loaded dictionary stays in place.
+func (w *DictionaryWatcher) Close() error {
+	w.once.Do(func() { close(w.stop) })
+	<-w.done
+	return nil
+}
+
+func (w *DictionaryWatcher) run() {
+	defer close(w.done)
+	defer w.watcher.Close()
+	settled := time.NewTimer(w.settle)
+	settled.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) == w.path && !event.Has(fsnotify.Chmod) {
+				settled.Reset(w.settle)
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			if w.onReload != nil {
+				w.onReload(fmt.Errorf("watching dictionary: %w", err))
+			}
+		case <-settled.C:
+			_ = w.Reload()
+		}
+	}
+}
+
+// replace moves the dictionary of next into the target. The indexes derived
+// from it are built under the read lock, so lookups go on meanwhile; the write
+// lock is only held to swap them in, once the lookups holding the read lock
+// have finished.
+func (w *DictionaryWatcher) replace(next SymSpell) error {
+	if locked, ok := next.(*lockedSymSpell); ok {
+		locked.mu.RLock()
+		next = locked.s
+		locked.mu.RUnlock()
+	}
+	dictionary, ok := next.(*internal.SymSpell)
+	if !ok {
+		return fmt.Errorf("reloading dictionary: cannot take the dictionary of %T", next)
+	}
+	w.target.mu.RLock()
+	s, ok := w.target.s.(*internal.SymSpell)
+	if !ok {
+		w.target.mu.RUnlock()
+		return fmt.Errorf("reloading dictionary: cannot replace the dictionary of %T", w.target.s)
+	}
+	replacement, err := s.PrepareReplacement(dictionary)
+	w.target.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("reloading dictionary: %w", err)
+	}
+	w.target.mu.Lock()
+	defer w.target.mu.Unlock()
+	s.CommitReplacement(replacement)
+	return nil
+}
+
+// unwrap strips the wrappers with an Unwrap method, like the one of
+// metrics.Instrument, off s.
+func unwrap(s SymSpell) SymSpell {
+	for {
+		wrapper, ok := s.(interface{ Unwrap() SymSpell })
+		if !ok {
+			return s
+		}
+		s = wrapper.Unwrap()
+	}
+}
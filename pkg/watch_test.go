@@ -0,0 +1,150 @@
+package symspell_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	symspell "symspell/pkg"
+	"symspell/pkg/metrics"
+	"symspell/pkg/options"
+	"symspell/pkg/verbosity"
+)
+
+func TestWatchDictionaryReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dictionary.txt")
+	if err := os.WriteFile(path, []byte("hello 10\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	load := func(path string) (symspell.SymSpell, error) {
+		s, err := symspell.New(options.WithThreadSafe())
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.LoadDictionary(path, 0, 1, " "); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	live, err := load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := live.AddUserWord("symspell", 5); err != nil {
+		t.Fatal(err)
+	}
+	live.AddToBlacklist("hellp")
+	live = metrics.Instrument(live, prometheus.NewRegistry())
+	reloaded := make(chan error, 1)
+	w, err := symspell.WatchDictionary(live, path, load, 10*time.Millisecond, func(err error) { reloaded <- err })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	if err := os.WriteFile(path, []byte("hello 10\nhellp 1\nworld 20\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-reloaded:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("dictionary was not reloaded")
+	}
+	if !live.ContainsWord("world") {
+		t.Error("reloaded word missing from the live instance")
+	}
+	if !live.IsUserWord("symspell") || !live.ContainsWord("symspell") {
+		t.Error("user word lost by the reload")
+	}
+	suggestions, err := live.Lookup("hellp", verbosity.All, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, suggestion := range suggestions {
+		if suggestion.Term == "hellp" {
+			t.Error("blacklisted word suggested after the reload")
+		}
+	}
+
+	if _, err := symspell.WatchDictionary(symspell.NewSymSpell(), path, load, time.Second, nil); err == nil {
+		t.Error("watching into a non-concurrent instance succeeded")
+	}
+}
+
+// blockingEncoder stops in Encode of word until release is closed.
+type blockingEncoder struct {
+	word    string
+	once    sync.Once
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (e *blockingEncoder) Encode(word string) []string {
+	if word == e.word {
+		e.once.Do(func() {
+			close(e.entered)
+			<-e.release
+		})
+	}
+	return []string{word[:1]}
+}
+
+func TestReloadDoesNotBlockLookups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dictionary.txt")
+	if err := os.WriteFile(path, []byte("hello 10\nworld 20\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	encoder := &blockingEncoder{word: "world", entered: make(chan struct{}), release: make(chan struct{})}
+	live, err := symspell.New(options.WithThreadSafe(), options.WithPhoneticIndex(encoder, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	live.CreateDictionaryEntry("hello", 10)
+	// the phonetic index of the reloaded dictionary is built with the encoder of live
+	load := func(path string) (symspell.SymSpell, error) {
+		s, err := symspell.New()
+		if err != nil {
+			return nil, err
+		}
+		_, err = s.LoadDictionary(path, 0, 1, " ")
+		return s, err
+	}
+	w, err := symspell.WatchDictionary(live, path, load, time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	reloaded := make(chan error, 1)
+	go func() { reloaded <- w.Reload() }()
+	select {
+	case <-encoder.entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reload did not build the phonetic index")
+	}
+	looked := make(chan struct{})
+	go func() {
+		live.Lookup("helo", verbosity.Top, 1)
+		close(looked)
+	}()
+	select {
+	case <-looked:
+	case <-time.After(5 * time.Second):
+		t.Error("lookup blocked while the reload built its indexes")
+	}
+	close(encoder.release)
+	if err := <-reloaded; err != nil {
+		t.Fatal(err)
+	}
+	<-looked
+	if !live.ContainsWord("world") {
+		t.Error("reloaded word missing from the live instance")
+	}
+}
@@ -0,0 +1,70 @@
+package symspell_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	symspell "symspell/pkg"
+)
+
+func TestWordSegmentationNBest(t *testing.T) {
+	s := newGoldenSymSpell(t)
+	best, err := s.WordSegmentation("thereis", 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.WordSegmentationNBest("thereis", 2, 0, 3)
+	if err != nil || len(got) != 3 {
+		t.Fatalf("WordSegmentationNBest(thereis) = %+v, %v, want 3 segmentations", got, err)
+	}
+	if got[0] != best {
+		t.Errorf("WordSegmentationNBest(thereis)[0] = %+v, want %+v", got[0], best)
+	}
+	for i := 1; i < len(got); i++ {
+		prev, c := got[i-1], got[i]
+		if c.CorrectedString == prev.CorrectedString ||
+			c.DistanceSum < prev.DistanceSum ||
+			c.DistanceSum == prev.DistanceSum && c.LogProbSum > prev.LogProbSum {
+			t.Errorf("WordSegmentationNBest(thereis) is not ranked: %+v before %+v", prev, c)
+		}
+	}
+
+	if got, err := s.WordSegmentationNBest("thereis", 2, 0, 0); got != nil || err != nil {
+		t.Errorf("WordSegmentationNBest(n=0) = %v, %v, want nil", got, err)
+	}
+	if _, err := s.WordSegmentationNBest("thereis", 3, 0, 3); !errors.Is(err, symspell.ErrDistanceTooLarge) {
+		t.Errorf("WordSegmentationNBest(distance 3) error = %v, want ErrDistanceTooLarge", err)
+	}
+}
+
+func TestWordSegmentationBigrams(t *testing.T) {
+	s, err := symspell.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.CreateDictionaryEntry("nowhere", 1000000)
+	s.CreateDictionaryEntry("now", 100000000)
+	s.CreateDictionaryEntry("here", 100000000)
+
+	// the unigrams prefer the single word
+	got, err := s.WordSegmentation("nowhere", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.CorrectedString != "nowhere" {
+		t.Errorf("WordSegmentation without bigrams = %q, want nowhere", got.CorrectedString)
+	}
+
+	// a frequent bigram outweighs them
+	if _, err := s.LoadBigramDictionaryStream(strings.NewReader("now here 10000000\n"), 0, 2, ""); err != nil {
+		t.Fatal(err)
+	}
+	got, err = s.WordSegmentation("nowhere", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.CorrectedString != "now here" {
+		t.Errorf("WordSegmentation with bigrams = %q, want \"now here\"", got.CorrectedString)
+	}
+}